@@ -3,12 +3,15 @@ package api
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"net/http"
 	"sort"
 	"strconv"
+	"strings"
 	"sync/atomic"
 	"time"
 
+	"github.com/civilware/Gnomon/indexer"
 	store "github.com/civilware/Gnomon/storage"
 	"github.com/civilware/Gnomon/structures"
 	"github.com/gorilla/mux"
@@ -16,27 +19,24 @@ import (
 )
 
 type ApiServer struct {
-	Config        *structures.APIConfig
-	Stats         atomic.Value
-	StatsIntv     time.Duration
-	GravDBBackend *store.GravitonStore
-	BBSBackend    *store.BboltStore
-	DBType        string
+	Config    *structures.APIConfig
+	Stats     atomic.Value
+	StatsIntv time.Duration
+	Backend   store.Storage
+	Mempool   *indexer.Mempool // nil unless the mempool watcher is enabled
 }
 
 // local logger
 var logger *logrus.Entry
 
 // Configures a new API server to be used
-func NewApiServer(cfg *structures.APIConfig, gravdbbackend *store.GravitonStore, bbsbackend *store.BboltStore, dbtype string) *ApiServer {
+func NewApiServer(cfg *structures.APIConfig, backend store.Storage) *ApiServer {
 
 	logger = structures.Logger.WithFields(logrus.Fields{})
 
 	return &ApiServer{
-		Config:        cfg,
-		GravDBBackend: gravdbbackend,
-		BBSBackend:    bbsbackend,
-		DBType:        dbtype,
+		Config:  cfg,
+		Backend: backend,
 	}
 }
 
@@ -78,10 +78,23 @@ func (apiServer *ApiServer) listen() {
 	router.HandleFunc("/api/scvarsbyheight", apiServer.InvokeSCVarsByHeight)
 	router.HandleFunc("/api/invalidscids", apiServer.InvalidSCIDStats)
 	router.HandleFunc("/api/scidprivtx", apiServer.NormalTxWithSCID)
+	router.HandleFunc("/api/addressactivity", apiServer.AddressActivity)
+	router.HandleFunc("/api/burntxs", apiServer.BurnTxs)
+	router.HandleFunc("/api/scbalances", apiServer.SCBalances)
+	router.HandleFunc("/api/sccode", apiServer.SCCode)
+	router.HandleFunc("/api/assets", apiServer.Assets)
+	router.HandleFunc("/api/names", apiServer.Names)
+	router.HandleFunc("/api/scidsbytemplate", apiServer.SCIDsByTemplate)
+	if apiServer.Config.RegTxLookup {
+		router.HandleFunc("/api/getregistrations", apiServer.RegistrationLookup)
+	}
 	if apiServer.Config.MBLLookup {
 		router.HandleFunc("/api/getmbladdrsbyhash", apiServer.MBLLookupByHash)
 		router.HandleFunc("/api/getmblcountbyaddr", apiServer.MBLLookupByAddr)
 	}
+	if apiServer.Mempool != nil {
+		router.HandleFunc("/api/mempool", apiServer.MempoolSCIDTxs)
+	}
 	router.HandleFunc("/api/getinfo", apiServer.GetInfo)
 	router.NotFoundHandler = http.HandlerFunc(notFound)
 	err := http.ListenAndServe(apiServer.Config.Listen, router)
@@ -99,10 +112,23 @@ func (apiServer *ApiServer) listenSSL() {
 	routerSSL.HandleFunc("/api/scvarsbyheight", apiServer.InvokeSCVarsByHeight)
 	routerSSL.HandleFunc("/api/invalidscids", apiServer.InvalidSCIDStats)
 	routerSSL.HandleFunc("/api/scidprivtx", apiServer.NormalTxWithSCID)
+	routerSSL.HandleFunc("/api/addressactivity", apiServer.AddressActivity)
+	routerSSL.HandleFunc("/api/burntxs", apiServer.BurnTxs)
+	routerSSL.HandleFunc("/api/scbalances", apiServer.SCBalances)
+	routerSSL.HandleFunc("/api/sccode", apiServer.SCCode)
+	routerSSL.HandleFunc("/api/assets", apiServer.Assets)
+	routerSSL.HandleFunc("/api/names", apiServer.Names)
+	routerSSL.HandleFunc("/api/scidsbytemplate", apiServer.SCIDsByTemplate)
+	if apiServer.Config.RegTxLookup {
+		routerSSL.HandleFunc("/api/getregistrations", apiServer.RegistrationLookup)
+	}
 	if apiServer.Config.MBLLookup {
 		routerSSL.HandleFunc("/api/getmbladdrsbyhash", apiServer.MBLLookupByHash)
 		routerSSL.HandleFunc("/api/getmblcountbyaddr", apiServer.MBLLookupByAddr)
 	}
+	if apiServer.Mempool != nil {
+		routerSSL.HandleFunc("/api/mempool", apiServer.MempoolSCIDTxs)
+	}
 	routerSSL.HandleFunc("/api/getinfo", apiServer.GetInfo)
 	routerSSL.NotFoundHandler = http.HandlerFunc(notFound)
 	err := http.ListenAndServeTLS(apiServer.Config.SSLListen, apiServer.Config.CertFile, apiServer.Config.KeyFile, routerSSL)
@@ -133,15 +159,8 @@ func notFound(writer http.ResponseWriter, _ *http.Request) {
 
 // Continuous check on number of validated scs etc. for base stats of service.
 func (apiServer *ApiServer) collectStats() {
-	switch apiServer.DBType {
-	case "gravdb":
-		if apiServer.GravDBBackend.Closing {
-			return
-		}
-	case "boltdb":
-		if apiServer.BBSBackend.Closing {
-			return
-		}
+	if apiServer.Backend.IsClosing() {
+		return
 	}
 
 	stats := make(map[string]interface{})
@@ -149,31 +168,13 @@ func (apiServer *ApiServer) collectStats() {
 
 	// TODO: Removeme
 	var scinstalls []*structures.SCTXParse
-	switch apiServer.DBType {
-	case "gravdb":
-		sclist = apiServer.GravDBBackend.GetAllOwnersAndSCIDs()
-	case "boltdb":
-		sclist = apiServer.BBSBackend.GetAllOwnersAndSCIDs()
-	}
+	sclist = apiServer.Backend.GetAllOwnersAndSCIDs()
 	for k, _ := range sclist {
-		switch apiServer.DBType {
-		case "gravdb":
-			if apiServer.GravDBBackend.Closing {
-				return
-			}
-		case "boltdb":
-			if apiServer.BBSBackend.Closing {
-				return
-			}
+		if apiServer.Backend.IsClosing() {
+			return
 		}
 
-		var invokedetails []*structures.SCTXParse
-		switch apiServer.DBType {
-		case "gravdb":
-			invokedetails = apiServer.GravDBBackend.GetAllSCIDInvokeDetails(k)
-		case "boltdb":
-			invokedetails = apiServer.BBSBackend.GetAllSCIDInvokeDetails(k)
-		}
+		invokedetails := apiServer.Backend.GetAllSCIDInvokeDetails(k)
 		i := 0
 		for _, v := range invokedetails {
 			sc_action := fmt.Sprintf("%v", v.Sc_args.Value("SC_ACTION", "U"))
@@ -202,16 +203,9 @@ func (apiServer *ApiServer) collectStats() {
 	// TODO: Re-add
 	//sclist := apiServer.Backend.GetAllOwnersAndSCIDs()
 	var regTxCount, burnTxCount, normTxCount int64
-	switch apiServer.DBType {
-	case "gravdb":
-		regTxCount = apiServer.GravDBBackend.GetTxCount("registration")
-		burnTxCount = apiServer.GravDBBackend.GetTxCount("burn")
-		normTxCount = apiServer.GravDBBackend.GetTxCount("normal")
-	case "boltdb":
-		regTxCount = apiServer.BBSBackend.GetTxCount("registration")
-		burnTxCount = apiServer.BBSBackend.GetTxCount("burn")
-		normTxCount = apiServer.BBSBackend.GetTxCount("normal")
-	}
+	regTxCount = apiServer.Backend.GetTxCount("registration")
+	burnTxCount = apiServer.Backend.GetTxCount("burn")
+	normTxCount = apiServer.Backend.GetTxCount("normal")
 
 	stats["numscs"] = len(sclist)
 	stats["indexedscs"] = sclist
@@ -289,27 +283,60 @@ func (apiServer *ApiServer) InvokeIndexBySCID(writer http.ResponseWriter, r *htt
 		address = addresskeys[0]
 	}
 
-	// Get all scid:owner
-	sclist := make(map[string]string)
-	switch apiServer.DBType {
-	case "gravdb":
-		sclist = apiServer.GravDBBackend.GetAllOwnersAndSCIDs()
-	case "boltdb":
-		sclist = apiServer.BBSBackend.GetAllOwnersAndSCIDs()
+	// Query for argname/argvalue
+	var argname, argvalue string
+	argnamekeys, ok := r.URL.Query()["argname"]
+
+	if !ok || len(argnamekeys[0]) < 1 {
+		logger.Debugf("[API] URL Param 'argname' is missing.")
+	} else {
+		argname = argnamekeys[0]
+	}
+
+	argvaluekeys, ok := r.URL.Query()["argvalue"]
+
+	if ok && len(argvaluekeys) > 0 {
+		argvalue = argvaluekeys[0]
 	}
 
-	if address != "" && scid != "" {
+	// Get all scid:owner
+	sclist := apiServer.Backend.GetAllOwnersAndSCIDs()
+
+	if argname != "" && scid != "" {
+		// Return invokes of scid whose arg matches, optionally also by signer
+		scidinvokes := apiServer.Backend.GetAllSCIDInvokeDetailsByArg(scid, argname, argvalue)
+		if address != "" {
+			var addrscidinvokes []*structures.SCTXParse
+			for _, v := range scidinvokes {
+				if strings.Contains(v.Sender, address) {
+					addrscidinvokes = append(addrscidinvokes, v)
+				}
+			}
+			scidinvokes = addrscidinvokes
+		}
+
+		// Case to ignore large variable returns
+		if len(scidinvokes) > structures.MAX_API_VAR_RETURN && apiServer.Config.ApiThrottle {
+			logger.Printf("[API-InvokeIndexBySCID] Tried to return more than %d sc indexes for %s... DENIED! Too much data...", structures.MAX_API_VAR_RETURN, scid)
+			reply["scidinvokesbyargcount"] = 0
+			reply["scidinvokesbyarg"] = nil
+
+			err := json.NewEncoder(writer).Encode(reply)
+			if err != nil {
+				logger.Errorf("[API] Error serializing API response: %v", err)
+			}
+			return
+		}
+
+		reply["scidinvokesbyargcount"] = len(scidinvokes)
+		reply["scidinvokesbyarg"] = scidinvokes
+	} else if address != "" && scid != "" {
 		// Return results that match both address and scid
 		var addrscidinvokes []*structures.SCTXParse
 
 		for k := range sclist {
 			if k == scid {
-				switch apiServer.DBType {
-				case "gravdb":
-					addrscidinvokes = apiServer.GravDBBackend.GetAllSCIDInvokeDetailsBySigner(scid, address)
-				case "boltdb":
-					addrscidinvokes = apiServer.BBSBackend.GetAllSCIDInvokeDetailsBySigner(scid, address)
-				}
+				addrscidinvokes = apiServer.Backend.GetAllSCIDInvokeDetailsBySigner(scid, address)
 				break
 			}
 		}
@@ -333,13 +360,7 @@ func (apiServer *ApiServer) InvokeIndexBySCID(writer http.ResponseWriter, r *htt
 		var addrinvokes [][]*structures.SCTXParse
 
 		for k := range sclist {
-			var currinvokedetails []*structures.SCTXParse
-			switch apiServer.DBType {
-			case "gravdb":
-				currinvokedetails = apiServer.GravDBBackend.GetAllSCIDInvokeDetailsBySigner(k, address)
-			case "boltdb":
-				currinvokedetails = apiServer.BBSBackend.GetAllSCIDInvokeDetailsBySigner(k, address)
-			}
+			currinvokedetails := apiServer.Backend.GetAllSCIDInvokeDetailsBySigner(k, address)
 
 			if currinvokedetails != nil {
 				addrinvokes = append(addrinvokes, currinvokedetails)
@@ -363,13 +384,7 @@ func (apiServer *ApiServer) InvokeIndexBySCID(writer http.ResponseWriter, r *htt
 		reply["addrinvokes"] = addrinvokes
 	} else if address == "" && scid != "" {
 		// If no address and scid only, return invokes of scid
-		var scidinvokes []*structures.SCTXParse
-		switch apiServer.DBType {
-		case "gravdb":
-			scidinvokes = apiServer.GravDBBackend.GetAllSCIDInvokeDetails(scid)
-		case "boltdb":
-			scidinvokes = apiServer.BBSBackend.GetAllSCIDInvokeDetails(scid)
-		}
+		scidinvokes := apiServer.Backend.GetAllSCIDInvokeDetails(scid)
 
 		// Case to ignore large variable returns
 		if len(scidinvokes) > structures.MAX_API_VAR_RETURN && apiServer.Config.ApiThrottle {
@@ -398,7 +413,7 @@ func (apiServer *ApiServer) InvokeSCVarsByHeight(writer http.ResponseWriter, r *
 	writer.Header().Set("Content-Type", "application/json; charset=UTF-8")
 	writer.Header().Set("Access-Control-Allow-Origin", "*")
 	writer.Header().Set("Cache-Control", "no-cache")
-	writer.WriteHeader(http.StatusOK)
+	// Status goes out with the reply, 200 unless the lookup is below the earliest indexed height
 
 	reply := make(map[string]interface{})
 
@@ -457,45 +472,35 @@ func (apiServer *ApiServer) InvokeSCVarsByHeight(writer http.ResponseWriter, r *
 			}
 		}
 
-		switch apiServer.DBType {
-		case "gravdb":
-			scidInteractionHeights = apiServer.GravDBBackend.GetSCIDInteractionHeight(scid)
-
-			interactionHeight = apiServer.GravDBBackend.GetInteractionIndex(topoheight, scidInteractionHeights, false)
-
-			// TODO: If there's no interaction height, do we go get scvars against daemon and store?
-			variables = apiServer.GravDBBackend.GetSCIDVariableDetailsAtTopoheight(scid, interactionHeight)
-
-			// Case to ignore large variable returns
-			if len(variables) > structures.MAX_API_VAR_RETURN && apiServer.Config.ApiThrottle {
-				logger.Printf("[API-InvokeSCVarsByHeight] Tried to return more than %d sc vars for %s... DENIED! Too much data...", structures.MAX_API_VAR_RETURN, scid)
-				reply["variables"] = nil
+		if err = indexer.CheckEarliestHeight(apiServer.Backend, topoheight); err != nil {
+			writer.WriteHeader(http.StatusGone)
+			reply["error"] = err.Error()
+			reply["variables"] = nil
 
-				err := json.NewEncoder(writer).Encode(reply)
-				if err != nil {
-					logger.Errorf("[API] Error serializing API response: %v", err)
-				}
-				return
+			err := json.NewEncoder(writer).Encode(reply)
+			if err != nil {
+				logger.Errorf("[API] Error serializing API response: %v", err)
 			}
-		case "boltdb":
-			scidInteractionHeights = apiServer.BBSBackend.GetSCIDInteractionHeight(scid)
+			return
+		}
 
-			interactionHeight = apiServer.BBSBackend.GetInteractionIndex(topoheight, scidInteractionHeights, false)
+		scidInteractionHeights = apiServer.Backend.GetSCIDInteractionHeight(scid)
 
-			// TODO: If there's no interaction height, do we go get scvars against daemon and store?
-			variables = apiServer.BBSBackend.GetSCIDVariableDetailsAtTopoheight(scid, interactionHeight)
+		interactionHeight = apiServer.Backend.GetInteractionIndex(topoheight, scidInteractionHeights, false)
 
-			// Case to ignore large variable returns
-			if len(variables) > structures.MAX_API_VAR_RETURN && apiServer.Config.ApiThrottle {
-				logger.Printf("[API-InvokeSCVarsByHeight] Tried to return more than %d sc vars for %s... DENIED! Too much data...", structures.MAX_API_VAR_RETURN, scid)
-				reply["variables"] = nil
+		// TODO: If there's no interaction height, do we go get scvars against daemon and store?
+		variables = apiServer.Backend.GetSCIDVariableDetailsAtTopoheight(scid, interactionHeight)
 
-				err := json.NewEncoder(writer).Encode(reply)
-				if err != nil {
-					logger.Errorf("[API] Error serializing API response: %v", err)
-				}
-				return
+		// Case to ignore large variable returns
+		if len(variables) > structures.MAX_API_VAR_RETURN && apiServer.Config.ApiThrottle {
+			logger.Printf("[API-InvokeSCVarsByHeight] Tried to return more than %d sc vars for %s... DENIED! Too much data...", structures.MAX_API_VAR_RETURN, scid)
+			reply["variables"] = nil
+
+			err := json.NewEncoder(writer).Encode(reply)
+			if err != nil {
+				logger.Errorf("[API] Error serializing API response: %v", err)
 			}
+			return
 		}
 
 		reply["variables"] = variables
@@ -518,37 +523,19 @@ func (apiServer *ApiServer) InvokeSCVarsByHeight(writer http.ResponseWriter, r *
 			return
 		}
 
-		switch apiServer.DBType {
-		case "gravdb":
-			scidInteractionHeights = apiServer.GravDBBackend.GetSCIDInteractionHeight(scid)
-			variables = apiServer.GravDBBackend.GetAllSCIDVariableDetails(scid)
+		scidInteractionHeights = apiServer.Backend.GetSCIDInteractionHeight(scid)
+		variables = apiServer.Backend.GetAllSCIDVariableDetails(scid)
 
-			// Case to ignore large variable returns
-			if len(variables) > structures.MAX_API_VAR_RETURN && apiServer.Config.ApiThrottle {
-				logger.Printf("[API-InvokeSCVarsByHeight] Tried to return more than %d sc vars for %s... DENIED! Too much data...", structures.MAX_API_VAR_RETURN, scid)
-				reply["variables"] = nil
+		// Case to ignore large variable returns
+		if len(variables) > structures.MAX_API_VAR_RETURN && apiServer.Config.ApiThrottle {
+			logger.Printf("[API-InvokeSCVarsByHeight] Tried to return more than %d sc vars for %s... DENIED! Too much data...", structures.MAX_API_VAR_RETURN, scid)
+			reply["variables"] = nil
 
-				err := json.NewEncoder(writer).Encode(reply)
-				if err != nil {
-					logger.Errorf("[API] Error serializing API response: %v", err)
-				}
-				return
-			}
-		case "boltdb":
-			scidInteractionHeights = apiServer.BBSBackend.GetSCIDInteractionHeight(scid)
-			variables = apiServer.BBSBackend.GetAllSCIDVariableDetails(scid)
-
-			// Case to ignore large variable returns
-			if len(variables) > structures.MAX_API_VAR_RETURN && apiServer.Config.ApiThrottle {
-				logger.Printf("[API-InvokeSCVarsByHeight] Tried to return more than %d sc vars for %s... DENIED! Too much data...", structures.MAX_API_VAR_RETURN, scid)
-				reply["variables"] = nil
-
-				err := json.NewEncoder(writer).Encode(reply)
-				if err != nil {
-					logger.Errorf("[API] Error serializing API response: %v", err)
-				}
-				return
+			err := json.NewEncoder(writer).Encode(reply)
+			if err != nil {
+				logger.Errorf("[API] Error serializing API response: %v", err)
 			}
+			return
 		}
 
 		reply["variables"] = variables
@@ -612,14 +599,8 @@ func (apiServer *ApiServer) NormalTxWithSCID(writer http.ResponseWriter, r *http
 	var allNormTxWithSCIDByAddr []*structures.NormalTXWithSCIDParse
 	var allNormTxWithSCIDBySCID []*structures.NormalTXWithSCIDParse
 
-	switch apiServer.DBType {
-	case "gravdb":
-		allNormTxWithSCIDByAddr = apiServer.GravDBBackend.GetAllNormalTxWithSCIDByAddr(address)
-		allNormTxWithSCIDBySCID = apiServer.GravDBBackend.GetAllNormalTxWithSCIDBySCID(scid)
-	case "boltdb":
-		allNormTxWithSCIDByAddr = apiServer.BBSBackend.GetAllNormalTxWithSCIDByAddr(address)
-		allNormTxWithSCIDBySCID = apiServer.BBSBackend.GetAllNormalTxWithSCIDBySCID(scid)
-	}
+	allNormTxWithSCIDByAddr = apiServer.Backend.GetAllNormalTxWithSCIDByAddr(address)
+	allNormTxWithSCIDBySCID = apiServer.Backend.GetAllNormalTxWithSCIDBySCID(scid)
 
 	// Case to ignore large variable returns
 	if (len(allNormTxWithSCIDByAddr) > structures.MAX_API_VAR_RETURN || len(allNormTxWithSCIDBySCID) > structures.MAX_API_VAR_RETURN) && apiServer.Config.ApiThrottle {
@@ -647,35 +628,79 @@ func (apiServer *ApiServer) NormalTxWithSCID(writer http.ResponseWriter, r *http
 	}
 }
 
-func (apiServer *ApiServer) InvalidSCIDStats(writer http.ResponseWriter, _ *http.Request) {
+// Returns a page of an address' SC activity (installs, invokes and scid payload ring memberships) in height order. start is the index of the first entry and limit the page size (all entries if not defined, capped at MAX_API_VAR_RETURN when throttled)
+func (apiServer *ApiServer) AddressActivity(writer http.ResponseWriter, r *http.Request) {
 	writer.Header().Set("Content-Type", "application/json; charset=UTF-8")
 	writer.Header().Set("Access-Control-Allow-Origin", "*")
 	writer.Header().Set("Cache-Control", "no-cache")
 	writer.WriteHeader(http.StatusOK)
 
 	reply := make(map[string]interface{})
-	invalidscids := make(map[string]uint64)
 
-	switch apiServer.DBType {
-	case "gravdb":
-		invalidscids = apiServer.GravDBBackend.GetInvalidSCIDDeploys()
-	case "boltdb":
-		invalidscids = apiServer.BBSBackend.GetInvalidSCIDDeploys()
+	stats := apiServer.getStats()
+	if stats != nil {
+		reply["numscs"] = stats["numscs"]
+		reply["regTxCount"] = stats["regTxCount"]
+		reply["burnTxCount"] = stats["burnTxCount"]
+		reply["normTxCount"] = stats["normTxCount"]
+	} else {
+		// Default reply - for testing, initials etc.
+		reply["hello"] = "world"
 	}
 
-	// Case to ignore large variable returns
-	if len(invalidscids) > structures.MAX_API_VAR_RETURN && apiServer.Config.ApiThrottle {
-		logger.Printf("[API-InvalidSCIDStats] Tried to return more than %d.. DENIED! Too much data...", structures.MAX_API_VAR_RETURN)
-		reply["invalidscids"] = nil
+	// Query for address
+	addresskeys, ok := r.URL.Query()["address"]
+	var address string
+	var start, limit int
 
+	if !ok || len(addresskeys[0]) < 1 {
+		logger.Debugf("[API] URL Param 'address' is missing.")
+		reply["addressactivity"] = nil
 		err := json.NewEncoder(writer).Encode(reply)
 		if err != nil {
 			logger.Errorf("[API] Error serializing API response: %v", err)
 		}
 		return
+	} else {
+		address = addresskeys[0]
 	}
 
-	reply["invalidscids"] = invalidscids
+	// Query for start
+	startkey, ok := r.URL.Query()["start"]
+
+	if !ok || len(startkey[0]) < 1 {
+		logger.Debugf("[API] URL Param 'start' is missing. Starting from the first entry.")
+	} else {
+		var err error
+		start, err = strconv.Atoi(startkey[0])
+		if err != nil {
+			logger.Errorf("[API] Err converting '%v' to int - %v", startkey[0], err)
+		}
+	}
+
+	// Query for limit
+	limitkey, ok := r.URL.Query()["limit"]
+
+	if !ok || len(limitkey[0]) < 1 {
+		logger.Debugf("[API] URL Param 'limit' is missing. Returning all entries.")
+	} else {
+		var err error
+		limit, err = strconv.Atoi(limitkey[0])
+		if err != nil {
+			logger.Errorf("[API] Err converting '%v' to int - %v", limitkey[0], err)
+		}
+	}
+
+	// Case to ignore large variable returns, pages are capped rather than denied
+	if (limit <= 0 || limit > structures.MAX_API_VAR_RETURN) && apiServer.Config.ApiThrottle {
+		limit = structures.MAX_API_VAR_RETURN
+	}
+
+	activity, total := apiServer.Backend.GetAddressActivity(address, start, limit)
+
+	reply["addressactivity"] = activity
+	reply["addressactivitycount"] = len(activity)
+	reply["addressactivitytotal"] = total
 
 	err := json.NewEncoder(writer).Encode(reply)
 	if err != nil {
@@ -683,7 +708,8 @@ func (apiServer *ApiServer) InvalidSCIDStats(writer http.ResponseWriter, _ *http
 	}
 }
 
-func (apiServer *ApiServer) MBLLookupByHash(writer http.ResponseWriter, r *http.Request) {
+// Returns the scids (and their owners) whose code matches a template id, or the template id a scid matches
+func (apiServer *ApiServer) SCIDsByTemplate(writer http.ResponseWriter, r *http.Request) {
 	writer.Header().Set("Content-Type", "application/json; charset=UTF-8")
 	writer.Header().Set("Access-Control-Allow-Origin", "*")
 	writer.Header().Set("Cache-Control", "no-cache")
@@ -702,56 +728,49 @@ func (apiServer *ApiServer) MBLLookupByHash(writer http.ResponseWriter, r *http.
 		reply["hello"] = "world"
 	}
 
-	// Query for SCID
-	blidkeys, ok := r.URL.Query()["blid"]
-	var blid string
+	var template, scid string
 
-	if !ok || len(blidkeys[0]) < 1 {
-		logger.Debugf("[API] URL Param 'blid' is missing. Debugging only.")
-		reply["mbl"] = nil
-		err := json.NewEncoder(writer).Encode(reply)
-		if err != nil {
-			logger.Errorf("[API] Error serializing API response: %v", err)
-		}
-		return
+	// Query for template
+	templatekeys, ok := r.URL.Query()["template"]
+	if !ok || len(templatekeys[0]) < 1 {
+		logger.Debugf("[API] URL Param 'template' is missing.")
 	} else {
-		blid = blidkeys[0]
+		template = templatekeys[0]
 	}
 
-	var allMiniBlocksByBlid []*structures.MBLInfo
-
-	switch apiServer.DBType {
-	case "gravdb":
-		allMiniBlocksByBlid = apiServer.GravDBBackend.GetMiniblockDetailsByHash(blid)
-	case "boltdb":
-		allMiniBlocksByBlid = apiServer.BBSBackend.GetMiniblockDetailsByHash(blid)
+	// Query for SCID
+	scidkeys, ok := r.URL.Query()["scid"]
+	if !ok || len(scidkeys[0]) < 1 {
+		logger.Debugf("[API] URL Param 'scid' is missing.")
+	} else {
+		scid = scidkeys[0]
 	}
 
-	// Case to ignore large variable returns
-	if len(allMiniBlocksByBlid) > structures.MAX_API_VAR_RETURN && apiServer.Config.ApiThrottle {
-		logger.Printf("[API-MBLLookupByHash] Tried to return more than %d.. DENIED! Too much data...", structures.MAX_API_VAR_RETURN)
-		reply["mbl"] = nil
+	if scid != "" {
+		reply["template"] = apiServer.Backend.GetSCIDTemplate(scid)
+	}
 
-		err := json.NewEncoder(writer).Encode(reply)
-		if err != nil {
-			logger.Errorf("[API] Error serializing API response: %v", err)
+	if template != "" {
+		scids := make(map[string]string)
+		for _, v := range apiServer.Backend.GetSCIDsByTemplate(template) {
+			scids[v] = apiServer.Backend.GetOwner(v)
 		}
-		return
+		reply["scids"] = scids
+		reply["scidscount"] = len(scids)
 	}
 
-	reply["mbl"] = allMiniBlocksByBlid
-
 	err := json.NewEncoder(writer).Encode(reply)
 	if err != nil {
 		logger.Errorf("[API] Error serializing API response: %v", err)
 	}
 }
 
-func (apiServer *ApiServer) MBLLookupByAddr(writer http.ResponseWriter, r *http.Request) {
+// Returns the stored DERO and asset balances of a scid at a given height, or its balance history if no height is defined
+func (apiServer *ApiServer) SCBalances(writer http.ResponseWriter, r *http.Request) {
 	writer.Header().Set("Content-Type", "application/json; charset=UTF-8")
 	writer.Header().Set("Access-Control-Allow-Origin", "*")
 	writer.Header().Set("Cache-Control", "no-cache")
-	writer.WriteHeader(http.StatusOK)
+	// Status goes out with the reply, 200 unless the lookup is below the earliest indexed height
 
 	reply := make(map[string]interface{})
 
@@ -767,30 +786,55 @@ func (apiServer *ApiServer) MBLLookupByAddr(writer http.ResponseWriter, r *http.
 	}
 
 	// Query for SCID
-	addrkeys, ok := r.URL.Query()["address"]
-	var addr string
+	scidkeys, ok := r.URL.Query()["scid"]
+	var scid string
+	var height string
 
-	if !ok || len(addrkeys[0]) < 1 {
-		logger.Debugf("[API] URL Param 'address' is missing. Debugging only.")
-		reply["mbl"] = nil
+	if !ok || len(scidkeys[0]) < 1 {
+		logger.Debugf("[API] URL Param 'scid' is missing. Debugging only.")
+		reply["balances"] = nil
 		err := json.NewEncoder(writer).Encode(reply)
 		if err != nil {
 			logger.Errorf("[API] Error serializing API response: %v", err)
 		}
 		return
 	} else {
-		addr = addrkeys[0]
+		scid = scidkeys[0]
 	}
 
-	var allMiniBlocksByAddr int64
-	switch apiServer.DBType {
-	case "gravdb":
-		allMiniBlocksByAddr = apiServer.GravDBBackend.GetMiniblockCountByAddress(addr)
-	case "boltdb":
-		allMiniBlocksByAddr = apiServer.BBSBackend.GetMiniblockCountByAddress(addr)
+	// Query for height
+	heightkey, ok := r.URL.Query()["height"]
+
+	if !ok || len(heightkey[0]) < 1 {
+		logger.Debugf("[API] URL Param 'height' is missing. Returning balance history.")
+	} else {
+		height = heightkey[0]
 	}
 
-	reply["mbl"] = allMiniBlocksByAddr
+	if height != "" {
+		topoheight, err := strconv.ParseInt(height, 10, 64)
+		if err != nil {
+			logger.Errorf("[API] Err converting '%v' to int64 - %v", height, err)
+			reply["balances"] = nil
+		} else if err = indexer.CheckEarliestHeight(apiServer.Backend, topoheight); err != nil {
+			writer.WriteHeader(http.StatusGone)
+			reply["error"] = err.Error()
+			reply["balances"] = nil
+		} else {
+			reply["balances"] = apiServer.Backend.GetSCIDBalanceDetailsAtTopoheight(scid, topoheight)
+		}
+	} else {
+		hBalances := apiServer.Backend.GetAllSCIDBalanceDetails(scid)
+
+		// Case to ignore large variable returns
+		if len(hBalances) > structures.MAX_API_VAR_RETURN && apiServer.Config.ApiThrottle {
+			logger.Printf("[API-SCBalances] Tried to return more than %d balance heights... DENIED! Too much data...", structures.MAX_API_VAR_RETURN)
+			reply["balancehistory"] = nil
+		} else {
+			reply["balancehistory"] = hBalances
+		}
+		reply["balancehistorycount"] = len(hBalances)
+	}
 
 	err := json.NewEncoder(writer).Encode(reply)
 	if err != nil {
@@ -798,11 +842,11 @@ func (apiServer *ApiServer) MBLLookupByAddr(writer http.ResponseWriter, r *http.
 	}
 }
 
-func (apiServer *ApiServer) MBLLookupAll(writer http.ResponseWriter, r *http.Request) {
+func (apiServer *ApiServer) SCCode(writer http.ResponseWriter, r *http.Request) {
 	writer.Header().Set("Content-Type", "application/json; charset=UTF-8")
 	writer.Header().Set("Access-Control-Allow-Origin", "*")
 	writer.Header().Set("Cache-Control", "no-cache")
-	writer.WriteHeader(http.StatusOK)
+	// Status goes out with the reply, 200 unless the lookup is below the earliest indexed height
 
 	reply := make(map[string]interface{})
 
@@ -817,27 +861,56 @@ func (apiServer *ApiServer) MBLLookupAll(writer http.ResponseWriter, r *http.Req
 		reply["hello"] = "world"
 	}
 
-	allMiniBlocks := make(map[string][]*structures.MBLInfo)
-	switch apiServer.DBType {
-	case "gravdb":
-		allMiniBlocks = apiServer.GravDBBackend.GetAllMiniblockDetails()
-	case "boltdb":
-		allMiniBlocks = apiServer.BBSBackend.GetAllMiniblockDetails()
-	}
-
-	// Case to ignore large variable returns
-	if len(allMiniBlocks) > structures.MAX_API_VAR_RETURN && apiServer.Config.ApiThrottle {
-		logger.Printf("[API-MBLLookupAll] Tried to return more than %d.. DENIED! Too much data...", structures.MAX_API_VAR_RETURN)
-		reply["mbl"] = nil
+	// Query for SCID
+	scidkeys, ok := r.URL.Query()["scid"]
+	var scid string
+	var height string
 
+	if !ok || len(scidkeys[0]) < 1 {
+		logger.Debugf("[API] URL Param 'scid' is missing. Debugging only.")
+		reply["code"] = nil
 		err := json.NewEncoder(writer).Encode(reply)
 		if err != nil {
 			logger.Errorf("[API] Error serializing API response: %v", err)
 		}
 		return
+	} else {
+		scid = scidkeys[0]
 	}
 
-	reply["mbl"] = allMiniBlocks
+	// Query for height
+	heightkey, ok := r.URL.Query()["height"]
+
+	if !ok || len(heightkey[0]) < 1 {
+		logger.Debugf("[API] URL Param 'height' is missing. Returning code history.")
+	} else {
+		height = heightkey[0]
+	}
+
+	if height != "" {
+		topoheight, err := strconv.ParseInt(height, 10, 64)
+		if err != nil {
+			logger.Errorf("[API] Err converting '%v' to int64 - %v", height, err)
+			reply["code"] = nil
+		} else if err = indexer.CheckEarliestHeight(apiServer.Backend, topoheight); err != nil {
+			writer.WriteHeader(http.StatusGone)
+			reply["error"] = err.Error()
+			reply["code"] = nil
+		} else {
+			reply["code"] = apiServer.Backend.GetSCIDCodeAtTopoheight(scid, topoheight)
+		}
+	} else {
+		hCode := apiServer.Backend.GetAllSCIDCode(scid)
+
+		// Case to ignore large variable returns
+		if len(hCode) > structures.MAX_API_VAR_RETURN && apiServer.Config.ApiThrottle {
+			logger.Printf("[API-SCCode] Tried to return more than %d code versions... DENIED! Too much data...", structures.MAX_API_VAR_RETURN)
+			reply["codehistory"] = nil
+		} else {
+			reply["codehistory"] = hCode
+		}
+		reply["codehistorycount"] = len(hCode)
+	}
 
 	err := json.NewEncoder(writer).Encode(reply)
 	if err != nil {
@@ -845,23 +918,733 @@ func (apiServer *ApiServer) MBLLookupAll(writer http.ResponseWriter, r *http.Req
 	}
 }
 
-func (apiServer *ApiServer) GetInfo(writer http.ResponseWriter, _ *http.Request) {
+// Returns the burn txs of a txid or of an asset scid, optionally within minheight/maxheight. With only heights defined, all burn txs in that range are returned
+func (apiServer *ApiServer) BurnTxs(writer http.ResponseWriter, r *http.Request) {
 	writer.Header().Set("Content-Type", "application/json; charset=UTF-8")
 	writer.Header().Set("Access-Control-Allow-Origin", "*")
 	writer.Header().Set("Cache-Control", "no-cache")
-	writer.WriteHeader(http.StatusOK)
+	// Status goes out with the reply, 200 unless the lookup is below the earliest indexed height
 
 	reply := make(map[string]interface{})
 
-	var info *structures.GetInfo
-	switch apiServer.DBType {
-	case "gravdb":
-		info = apiServer.GravDBBackend.GetGetInfoDetails()
-	case "boltdb":
-		info = apiServer.BBSBackend.GetGetInfoDetails()
+	stats := apiServer.getStats()
+	if stats != nil {
+		reply["numscs"] = stats["numscs"]
+		reply["regTxCount"] = stats["regTxCount"]
+		reply["burnTxCount"] = stats["burnTxCount"]
+		reply["normTxCount"] = stats["normTxCount"]
+	} else {
+		// Default reply - for testing, initials etc.
+		reply["hello"] = "world"
+	}
+
+	var txid, scid string
+	var minheight, maxheight int64 = 0, -1
+	var err error
+
+	// Query for txid
+	txidkeys, ok := r.URL.Query()["txid"]
+	if !ok || len(txidkeys[0]) < 1 {
+		logger.Debugf("[API] URL Param 'txid' is missing.")
+	} else {
+		txid = txidkeys[0]
 	}
 
-	reply["getinfo"] = info
+	// Query for SCID
+	scidkeys, ok := r.URL.Query()["scid"]
+	if !ok || len(scidkeys[0]) < 1 {
+		logger.Debugf("[API] URL Param 'scid' is missing.")
+	} else {
+		scid = scidkeys[0]
+	}
+
+	// Query for height range
+	minheightkeys, ok := r.URL.Query()["minheight"]
+	if !ok || len(minheightkeys[0]) < 1 {
+		logger.Debugf("[API] URL Param 'minheight' is missing.")
+	} else {
+		minheight, err = strconv.ParseInt(minheightkeys[0], 10, 64)
+		if err != nil {
+			logger.Errorf("[API] Err converting '%v' to int64 - %v", minheightkeys[0], err)
+			minheight = 0
+		}
+	}
+
+	maxheightkeys, ok := r.URL.Query()["maxheight"]
+	if !ok || len(maxheightkeys[0]) < 1 {
+		logger.Debugf("[API] URL Param 'maxheight' is missing.")
+	} else {
+		maxheight, err = strconv.ParseInt(maxheightkeys[0], 10, 64)
+		if err != nil {
+			logger.Errorf("[API] Err converting '%v' to int64 - %v", maxheightkeys[0], err)
+			maxheight = -1
+		}
+	}
+
+	if txid == "" && scid == "" && maxheight < 0 && minheight == 0 {
+		reply["burntxs"] = nil
+		reply["burntxscount"] = 0
+		err := json.NewEncoder(writer).Encode(reply)
+		if err != nil {
+			logger.Errorf("[API] Error serializing API response: %v", err)
+		}
+		return
+	}
+
+	// A range entirely below the earliest indexed height has no data rather than no burns
+	if maxheight >= 0 {
+		if err = indexer.CheckEarliestHeight(apiServer.Backend, maxheight); err != nil {
+			writer.WriteHeader(http.StatusGone)
+			reply["error"] = err.Error()
+			reply["burntxs"] = nil
+			reply["burntxscount"] = 0
+			err := json.NewEncoder(writer).Encode(reply)
+			if err != nil {
+				logger.Errorf("[API] Error serializing API response: %v", err)
+			}
+			return
+		}
+	}
+
+	if maxheight < 0 {
+		maxheight = math.MaxInt64
+	}
+
+	var allBurnTxs, burnTxs []*structures.BurnTXParse
+	switch {
+	case txid != "":
+		allBurnTxs = apiServer.Backend.GetBurnTxByTxid(txid)
+	case scid != "":
+		allBurnTxs = apiServer.Backend.GetBurnTxsBySCID(scid)
+	default:
+		allBurnTxs = apiServer.Backend.GetBurnTxsByHeightRange(minheight, maxheight)
+	}
+
+	for _, v := range allBurnTxs {
+		if (scid == "" || v.Scid == scid) && v.Height >= minheight && v.Height <= maxheight {
+			burnTxs = append(burnTxs, v)
+		}
+	}
+
+	// Case to ignore large variable returns
+	if len(burnTxs) > structures.MAX_API_VAR_RETURN && apiServer.Config.ApiThrottle {
+		logger.Printf("[API-BurnTxs] Tried to return more than %d... DENIED! Too much data...", structures.MAX_API_VAR_RETURN)
+		reply["burntxs"] = nil
+		reply["burntxscount"] = len(burnTxs)
+
+		err := json.NewEncoder(writer).Encode(reply)
+		if err != nil {
+			logger.Errorf("[API] Error serializing API response: %v", err)
+		}
+		return
+	}
+
+	reply["burntxs"] = burnTxs
+	reply["burntxscount"] = len(burnTxs)
+
+	err = json.NewEncoder(writer).Encode(reply)
+	if err != nil {
+		logger.Errorf("[API] Error serializing API response: %v", err)
+	}
+}
+
+func (apiServer *ApiServer) Assets(writer http.ResponseWriter, r *http.Request) {
+	writer.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	writer.Header().Set("Access-Control-Allow-Origin", "*")
+	writer.Header().Set("Cache-Control", "no-cache")
+	writer.WriteHeader(http.StatusOK)
+
+	reply := make(map[string]interface{})
+
+	stats := apiServer.getStats()
+	if stats != nil {
+		reply["numscs"] = stats["numscs"]
+		reply["regTxCount"] = stats["regTxCount"]
+		reply["burnTxCount"] = stats["burnTxCount"]
+		reply["normTxCount"] = stats["normTxCount"]
+	} else {
+		// Default reply - for testing, initials etc.
+		reply["hello"] = "world"
+	}
+
+	var scid, collection, owner, standard string
+
+	// Query for SCID
+	scidkeys, ok := r.URL.Query()["scid"]
+	if !ok || len(scidkeys[0]) < 1 {
+		logger.Debugf("[API] URL Param 'scid' is missing.")
+	} else {
+		scid = scidkeys[0]
+	}
+
+	// Query for collection
+	collectionkeys, ok := r.URL.Query()["collection"]
+	if !ok || len(collectionkeys[0]) < 1 {
+		logger.Debugf("[API] URL Param 'collection' is missing.")
+	} else {
+		collection = collectionkeys[0]
+	}
+
+	// Query for owner
+	ownerkeys, ok := r.URL.Query()["owner"]
+	if !ok || len(ownerkeys[0]) < 1 {
+		logger.Debugf("[API] URL Param 'owner' is missing.")
+	} else {
+		owner = ownerkeys[0]
+	}
+
+	// Query for standard
+	standardkeys, ok := r.URL.Query()["standard"]
+	if !ok || len(standardkeys[0]) < 1 {
+		logger.Debugf("[API] URL Param 'standard' is missing.")
+	} else {
+		standard = standardkeys[0]
+	}
+
+	if scid != "" {
+		reply["asset"] = apiServer.Backend.GetSCAsset(scid)
+		err := json.NewEncoder(writer).Encode(reply)
+		if err != nil {
+			logger.Errorf("[API] Error serializing API response: %v", err)
+		}
+		return
+	}
+
+	if collection == "" && owner == "" && standard == "" {
+		reply["assets"] = nil
+		reply["assetscount"] = 0
+		err := json.NewEncoder(writer).Encode(reply)
+		if err != nil {
+			logger.Errorf("[API] Error serializing API response: %v", err)
+		}
+		return
+	}
+
+	var allAssets, assets []*structures.SCAsset
+	switch {
+	case collection != "":
+		allAssets = apiServer.Backend.GetSCAssetsByCollection(collection)
+	case owner != "":
+		allAssets = apiServer.Backend.GetSCAssetsByOwner(owner)
+	default:
+		allAssets = apiServer.Backend.GetSCAssetsByStandard(standard)
+	}
+
+	// Params can be combined, e.g. the assets of a collection held by an owner
+	for _, v := range allAssets {
+		if (owner == "" || v.Owner == owner) && (standard == "" || v.Standard == standard) {
+			assets = append(assets, v)
+		}
+	}
+
+	// Case to ignore large variable returns
+	if len(assets) > structures.MAX_API_VAR_RETURN && apiServer.Config.ApiThrottle {
+		logger.Printf("[API-Assets] Tried to return more than %d... DENIED! Too much data...", structures.MAX_API_VAR_RETURN)
+		reply["assets"] = nil
+	} else {
+		reply["assets"] = assets
+	}
+	reply["assetscount"] = len(assets)
+
+	err := json.NewEncoder(writer).Encode(reply)
+	if err != nil {
+		logger.Errorf("[API] Error serializing API response: %v", err)
+	}
+}
+
+func (apiServer *ApiServer) Names(writer http.ResponseWriter, r *http.Request) {
+	writer.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	writer.Header().Set("Access-Control-Allow-Origin", "*")
+	writer.Header().Set("Cache-Control", "no-cache")
+	// Status goes out with the reply, 200 unless the lookup is below the earliest indexed height
+
+	reply := make(map[string]interface{})
+
+	stats := apiServer.getStats()
+	if stats != nil {
+		reply["numscs"] = stats["numscs"]
+		reply["regTxCount"] = stats["regTxCount"]
+		reply["burnTxCount"] = stats["burnTxCount"]
+		reply["normTxCount"] = stats["normTxCount"]
+	} else {
+		// Default reply - for testing, initials etc.
+		reply["hello"] = "world"
+	}
+
+	var name string
+	var addresses []string
+	var minheight, maxheight int64 = 0, -1
+	var err error
+
+	// Query for name
+	namekeys, ok := r.URL.Query()["name"]
+	if !ok || len(namekeys[0]) < 1 {
+		logger.Debugf("[API] URL Param 'name' is missing.")
+	} else {
+		name = namekeys[0]
+	}
+
+	// Query for address(es), can be defined more than once to get the names of several addresses in one call
+	addresskeys, ok := r.URL.Query()["address"]
+	if !ok || len(addresskeys[0]) < 1 {
+		logger.Debugf("[API] URL Param 'address' is missing.")
+	} else {
+		for _, v := range addresskeys {
+			if v != "" {
+				addresses = append(addresses, v)
+			}
+		}
+	}
+
+	// Query for height range
+	minheightkeys, ok := r.URL.Query()["minheight"]
+	if !ok || len(minheightkeys[0]) < 1 {
+		logger.Debugf("[API] URL Param 'minheight' is missing.")
+	} else {
+		minheight, err = strconv.ParseInt(minheightkeys[0], 10, 64)
+		if err != nil {
+			logger.Errorf("[API] Err converting '%v' to int64 - %v", minheightkeys[0], err)
+			minheight = 0
+		}
+	}
+
+	maxheightkeys, ok := r.URL.Query()["maxheight"]
+	if !ok || len(maxheightkeys[0]) < 1 {
+		logger.Debugf("[API] URL Param 'maxheight' is missing.")
+	} else {
+		maxheight, err = strconv.ParseInt(maxheightkeys[0], 10, 64)
+		if err != nil {
+			logger.Errorf("[API] Err converting '%v' to int64 - %v", maxheightkeys[0], err)
+			maxheight = -1
+		}
+	}
+
+	// A range entirely below the earliest indexed height has no data rather than no names
+	var rangeErr error
+	if maxheight >= 0 {
+		rangeErr = indexer.CheckEarliestHeight(apiServer.Backend, maxheight)
+	}
+
+	switch {
+	case name != "":
+		reply["name"] = apiServer.Backend.GetNameRecord(name)
+		reply["namehistory"] = apiServer.Backend.GetNameHistory(name)
+	case len(addresses) > 0:
+		// Case to ignore large variable returns
+		if len(addresses) > structures.MAX_API_VAR_RETURN && apiServer.Config.ApiThrottle {
+			logger.Printf("[API-Names] Tried to return more than %d addresses... DENIED! Too much data...", structures.MAX_API_VAR_RETURN)
+			reply["namesbyaddress"] = nil
+			break
+		}
+
+		namesbyaddress := make(map[string][]string)
+		for _, a := range addresses {
+			names := []string{}
+			for _, v := range apiServer.Backend.GetNamesByAddress(a) {
+				names = append(names, v.Name)
+			}
+			namesbyaddress[a] = names
+		}
+		reply["namesbyaddress"] = namesbyaddress
+	case rangeErr != nil:
+		writer.WriteHeader(http.StatusGone)
+		reply["error"] = rangeErr.Error()
+		reply["namehistory"] = nil
+		reply["namehistorycount"] = 0
+	case minheight > 0 || maxheight >= 0:
+		if maxheight < 0 {
+			maxheight = math.MaxInt64
+		}
+		history := apiServer.Backend.GetNameHistoryByHeightRange(minheight, maxheight)
+
+		// Case to ignore large variable returns
+		if len(history) > structures.MAX_API_VAR_RETURN && apiServer.Config.ApiThrottle {
+			logger.Printf("[API-Names] Tried to return more than %d... DENIED! Too much data...", structures.MAX_API_VAR_RETURN)
+			reply["namehistory"] = nil
+		} else {
+			reply["namehistory"] = history
+		}
+		reply["namehistorycount"] = len(history)
+	default:
+		reply["name"] = nil
+	}
+
+	err = json.NewEncoder(writer).Encode(reply)
+	if err != nil {
+		logger.Errorf("[API] Error serializing API response: %v", err)
+	}
+}
+
+// Returns the registration of an address, or the registrations within minheight/maxheight. If interval is defined, the number of registrations per interval blocks is returned as well for graphing
+func (apiServer *ApiServer) RegistrationLookup(writer http.ResponseWriter, r *http.Request) {
+	writer.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	writer.Header().Set("Access-Control-Allow-Origin", "*")
+	writer.Header().Set("Cache-Control", "no-cache")
+	// Status goes out with the reply, 200 unless the lookup is below the earliest indexed height
+
+	reply := make(map[string]interface{})
+
+	stats := apiServer.getStats()
+	if stats != nil {
+		reply["numscs"] = stats["numscs"]
+		reply["regTxCount"] = stats["regTxCount"]
+		reply["burnTxCount"] = stats["burnTxCount"]
+		reply["normTxCount"] = stats["normTxCount"]
+	} else {
+		// Default reply - for testing, initials etc.
+		reply["hello"] = "world"
+	}
+
+	var address string
+	var minheight, maxheight, interval int64 = 0, -1, 0
+	var err error
+
+	// Query for address
+	addresskeys, ok := r.URL.Query()["address"]
+	if !ok || len(addresskeys[0]) < 1 {
+		logger.Debugf("[API] URL Param 'address' is missing.")
+	} else {
+		address = addresskeys[0]
+	}
+
+	if address != "" {
+		reply["registration"] = apiServer.Backend.GetRegTxByAddress(address)
+		err = json.NewEncoder(writer).Encode(reply)
+		if err != nil {
+			logger.Errorf("[API] Error serializing API response: %v", err)
+		}
+		return
+	}
+
+	// Query for height range
+	minheightkeys, ok := r.URL.Query()["minheight"]
+	if !ok || len(minheightkeys[0]) < 1 {
+		logger.Debugf("[API] URL Param 'minheight' is missing.")
+	} else {
+		minheight, err = strconv.ParseInt(minheightkeys[0], 10, 64)
+		if err != nil {
+			logger.Errorf("[API] Err converting '%v' to int64 - %v", minheightkeys[0], err)
+			minheight = 0
+		}
+	}
+
+	maxheightkeys, ok := r.URL.Query()["maxheight"]
+	if !ok || len(maxheightkeys[0]) < 1 {
+		logger.Debugf("[API] URL Param 'maxheight' is missing.")
+	} else {
+		maxheight, err = strconv.ParseInt(maxheightkeys[0], 10, 64)
+		if err != nil {
+			logger.Errorf("[API] Err converting '%v' to int64 - %v", maxheightkeys[0], err)
+			maxheight = -1
+		}
+	}
+	// A range entirely below the earliest indexed height has no data rather than no registrations
+	if maxheight >= 0 {
+		if err = indexer.CheckEarliestHeight(apiServer.Backend, maxheight); err != nil {
+			writer.WriteHeader(http.StatusGone)
+			reply["error"] = err.Error()
+			reply["registrations"] = nil
+			reply["registrationscount"] = 0
+			err := json.NewEncoder(writer).Encode(reply)
+			if err != nil {
+				logger.Errorf("[API] Error serializing API response: %v", err)
+			}
+			return
+		}
+	}
+	if maxheight < 0 {
+		maxheight = math.MaxInt64
+	}
+
+	intervalkeys, ok := r.URL.Query()["interval"]
+	if !ok || len(intervalkeys[0]) < 1 {
+		logger.Debugf("[API] URL Param 'interval' is missing.")
+	} else {
+		interval, err = strconv.ParseInt(intervalkeys[0], 10, 64)
+		if err != nil {
+			logger.Errorf("[API] Err converting '%v' to int64 - %v", intervalkeys[0], err)
+			interval = 0
+		}
+	}
+
+	regTxs := apiServer.Backend.GetRegTxsByHeightRange(minheight, maxheight)
+
+	// Registrations per interval, keyed by the first height of each interval
+	if interval > 0 {
+		regTxsByInterval := make(map[int64]int64)
+		for _, v := range regTxs {
+			regTxsByInterval[v.Height-(v.Height%interval)]++
+		}
+		reply["registrationsbyinterval"] = regTxsByInterval
+	}
+
+	// Case to ignore large variable returns
+	if len(regTxs) > structures.MAX_API_VAR_RETURN && apiServer.Config.ApiThrottle {
+		logger.Printf("[API-RegistrationLookup] Tried to return more than %d... DENIED! Too much data...", structures.MAX_API_VAR_RETURN)
+		reply["registrations"] = nil
+		reply["registrationscount"] = len(regTxs)
+
+		err := json.NewEncoder(writer).Encode(reply)
+		if err != nil {
+			logger.Errorf("[API] Error serializing API response: %v", err)
+		}
+		return
+	}
+
+	reply["registrations"] = regTxs
+	reply["registrationscount"] = len(regTxs)
+
+	err = json.NewEncoder(writer).Encode(reply)
+	if err != nil {
+		logger.Errorf("[API] Error serializing API response: %v", err)
+	}
+}
+
+// Returns the pending (not yet mined) sc installs/invokes of a scid, or of all scids if no scid is defined
+func (apiServer *ApiServer) MempoolSCIDTxs(writer http.ResponseWriter, r *http.Request) {
+	writer.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	writer.Header().Set("Access-Control-Allow-Origin", "*")
+	writer.Header().Set("Cache-Control", "no-cache")
+	writer.WriteHeader(http.StatusOK)
+
+	reply := make(map[string]interface{})
+
+	// Query for SCID
+	scidkeys, ok := r.URL.Query()["scid"]
+	var scid string
+
+	if !ok || len(scidkeys[0]) < 1 {
+		logger.Debugf("[API] URL Param 'scid' is missing. Returning all pending scid txns.")
+	} else {
+		scid = scidkeys[0]
+	}
+
+	if scid != "" {
+		pending := apiServer.Mempool.GetPendingSCIDTxs(scid)
+
+		reply["pendingscidtxs"] = pending
+		reply["pendingscidtxscount"] = len(pending)
+	} else {
+		pending := apiServer.Mempool.GetAllPendingSCIDTxs()
+
+		var count int
+		for _, v := range pending {
+			count += len(v)
+		}
+
+		// Case to ignore large variable returns
+		if count > structures.MAX_API_VAR_RETURN && apiServer.Config.ApiThrottle {
+			logger.Printf("[API-MempoolSCIDTxs] Tried to return more than %d pending txns... DENIED! Too much data...", structures.MAX_API_VAR_RETURN)
+			reply["pendingtxs"] = nil
+			reply["pendingtxscount"] = count
+
+			err := json.NewEncoder(writer).Encode(reply)
+			if err != nil {
+				logger.Errorf("[API] Error serializing API response: %v", err)
+			}
+			return
+		}
+
+		reply["pendingtxs"] = pending
+		reply["pendingtxscount"] = count
+	}
+
+	err := json.NewEncoder(writer).Encode(reply)
+	if err != nil {
+		logger.Errorf("[API] Error serializing API response: %v", err)
+	}
+}
+
+func (apiServer *ApiServer) InvalidSCIDStats(writer http.ResponseWriter, _ *http.Request) {
+	writer.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	writer.Header().Set("Access-Control-Allow-Origin", "*")
+	writer.Header().Set("Cache-Control", "no-cache")
+	writer.WriteHeader(http.StatusOK)
+
+	reply := make(map[string]interface{})
+	invalidscids := make(map[string]uint64)
+
+	invalidscids = apiServer.Backend.GetInvalidSCIDDeploys()
+
+	// Case to ignore large variable returns
+	if len(invalidscids) > structures.MAX_API_VAR_RETURN && apiServer.Config.ApiThrottle {
+		logger.Printf("[API-InvalidSCIDStats] Tried to return more than %d.. DENIED! Too much data...", structures.MAX_API_VAR_RETURN)
+		reply["invalidscids"] = nil
+
+		err := json.NewEncoder(writer).Encode(reply)
+		if err != nil {
+			logger.Errorf("[API] Error serializing API response: %v", err)
+		}
+		return
+	}
+
+	reply["invalidscids"] = invalidscids
+
+	err := json.NewEncoder(writer).Encode(reply)
+	if err != nil {
+		logger.Errorf("[API] Error serializing API response: %v", err)
+	}
+}
+
+func (apiServer *ApiServer) MBLLookupByHash(writer http.ResponseWriter, r *http.Request) {
+	writer.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	writer.Header().Set("Access-Control-Allow-Origin", "*")
+	writer.Header().Set("Cache-Control", "no-cache")
+	writer.WriteHeader(http.StatusOK)
+
+	reply := make(map[string]interface{})
+
+	stats := apiServer.getStats()
+	if stats != nil {
+		reply["numscs"] = stats["numscs"]
+		reply["regTxCount"] = stats["regTxCount"]
+		reply["burnTxCount"] = stats["burnTxCount"]
+		reply["normTxCount"] = stats["normTxCount"]
+	} else {
+		// Default reply - for testing, initials etc.
+		reply["hello"] = "world"
+	}
+
+	// Query for SCID
+	blidkeys, ok := r.URL.Query()["blid"]
+	var blid string
+
+	if !ok || len(blidkeys[0]) < 1 {
+		logger.Debugf("[API] URL Param 'blid' is missing. Debugging only.")
+		reply["mbl"] = nil
+		err := json.NewEncoder(writer).Encode(reply)
+		if err != nil {
+			logger.Errorf("[API] Error serializing API response: %v", err)
+		}
+		return
+	} else {
+		blid = blidkeys[0]
+	}
+
+	var allMiniBlocksByBlid []*structures.MBLInfo
+
+	allMiniBlocksByBlid = apiServer.Backend.GetMiniblockDetailsByHash(blid)
+
+	// Case to ignore large variable returns
+	if len(allMiniBlocksByBlid) > structures.MAX_API_VAR_RETURN && apiServer.Config.ApiThrottle {
+		logger.Printf("[API-MBLLookupByHash] Tried to return more than %d.. DENIED! Too much data...", structures.MAX_API_VAR_RETURN)
+		reply["mbl"] = nil
+
+		err := json.NewEncoder(writer).Encode(reply)
+		if err != nil {
+			logger.Errorf("[API] Error serializing API response: %v", err)
+		}
+		return
+	}
+
+	reply["mbl"] = allMiniBlocksByBlid
+
+	err := json.NewEncoder(writer).Encode(reply)
+	if err != nil {
+		logger.Errorf("[API] Error serializing API response: %v", err)
+	}
+}
+
+func (apiServer *ApiServer) MBLLookupByAddr(writer http.ResponseWriter, r *http.Request) {
+	writer.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	writer.Header().Set("Access-Control-Allow-Origin", "*")
+	writer.Header().Set("Cache-Control", "no-cache")
+	writer.WriteHeader(http.StatusOK)
+
+	reply := make(map[string]interface{})
+
+	stats := apiServer.getStats()
+	if stats != nil {
+		reply["numscs"] = stats["numscs"]
+		reply["regTxCount"] = stats["regTxCount"]
+		reply["burnTxCount"] = stats["burnTxCount"]
+		reply["normTxCount"] = stats["normTxCount"]
+	} else {
+		// Default reply - for testing, initials etc.
+		reply["hello"] = "world"
+	}
+
+	// Query for SCID
+	addrkeys, ok := r.URL.Query()["address"]
+	var addr string
+
+	if !ok || len(addrkeys[0]) < 1 {
+		logger.Debugf("[API] URL Param 'address' is missing. Debugging only.")
+		reply["mbl"] = nil
+		err := json.NewEncoder(writer).Encode(reply)
+		if err != nil {
+			logger.Errorf("[API] Error serializing API response: %v", err)
+		}
+		return
+	} else {
+		addr = addrkeys[0]
+	}
+
+	allMiniBlocksByAddr := apiServer.Backend.GetMiniblockCountByAddress(addr)
+
+	reply["mbl"] = allMiniBlocksByAddr
+
+	err := json.NewEncoder(writer).Encode(reply)
+	if err != nil {
+		logger.Errorf("[API] Error serializing API response: %v", err)
+	}
+}
+
+func (apiServer *ApiServer) MBLLookupAll(writer http.ResponseWriter, r *http.Request) {
+	writer.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	writer.Header().Set("Access-Control-Allow-Origin", "*")
+	writer.Header().Set("Cache-Control", "no-cache")
+	writer.WriteHeader(http.StatusOK)
+
+	reply := make(map[string]interface{})
+
+	stats := apiServer.getStats()
+	if stats != nil {
+		reply["numscs"] = stats["numscs"]
+		reply["regTxCount"] = stats["regTxCount"]
+		reply["burnTxCount"] = stats["burnTxCount"]
+		reply["normTxCount"] = stats["normTxCount"]
+	} else {
+		// Default reply - for testing, initials etc.
+		reply["hello"] = "world"
+	}
+
+	allMiniBlocks := apiServer.Backend.GetAllMiniblockDetails()
+
+	// Case to ignore large variable returns
+	if len(allMiniBlocks) > structures.MAX_API_VAR_RETURN && apiServer.Config.ApiThrottle {
+		logger.Printf("[API-MBLLookupAll] Tried to return more than %d.. DENIED! Too much data...", structures.MAX_API_VAR_RETURN)
+		reply["mbl"] = nil
+
+		err := json.NewEncoder(writer).Encode(reply)
+		if err != nil {
+			logger.Errorf("[API] Error serializing API response: %v", err)
+		}
+		return
+	}
+
+	reply["mbl"] = allMiniBlocks
+
+	err := json.NewEncoder(writer).Encode(reply)
+	if err != nil {
+		logger.Errorf("[API] Error serializing API response: %v", err)
+	}
+}
+
+func (apiServer *ApiServer) GetInfo(writer http.ResponseWriter, _ *http.Request) {
+	writer.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	writer.Header().Set("Access-Control-Allow-Origin", "*")
+	writer.Header().Set("Cache-Control", "no-cache")
+	writer.WriteHeader(http.StatusOK)
+
+	reply := make(map[string]interface{})
+
+	info := apiServer.Backend.GetGetInfoDetails()
+
+	reply["getinfo"] = info
+	if earliest := apiServer.Backend.GetEarliestHeight(); earliest > 0 {
+		reply["earliestheight"] = earliest
+	}
 
 	err := json.NewEncoder(writer).Encode(reply)
 	if err != nil {
@@ -45,22 +45,33 @@ Usage:
 
 Options:
   -h --help     Show this screen.
-  --daemon-rpc-address=<127.0.0.1:40402>    Connect to daemon.
+  --daemon-rpc-address=<127.0.0.1:40402>    Connect to daemon. Multiple daemons can be defined comma separated (e.g. 127.0.0.1:40402,192.168.1.10:40402), calls are spread across the healthy ones and failed over from any that go down or fall behind.
+  --wallet-rpc-address=<127.0.0.1:40403>    Connect to wallet rpc. Used with --runmode=wallet to index the wallet's transfer history, the daemon is still used for txn/SC details. The wallet rpc server must be run without --rpc-login.
   --api-address=<127.0.0.1:8082>     Host api.
   --enable-api-ssl     Enable ssl.
   --api-ssl-address=<127.0.0.1:9092>     Host ssl api.
   --get-info-ssl-address=<127.0.0.1:9394>     Host GetInfo ssl api. This is to completely isolate it from gnomon api results as a whole. Normal api endpoints also surface the getinfo call if needed.
   --start-topoheight=<31170>     Define a start topoheight other than 1 if required to index at a higher block (pruned db etc.).
-  --search-filter=<"Function InputStr(input String, varname String) Uint64">     Defines a search filter to match on installed SCs to add to validated list and index all actions, this will most likely change in the future but can allow for some small variability. Include escapes etc. if required. If nothing is defined, it will pull all (minus hardcoded sc).
-  --runmode=<daemon>     Defines the runmode of gnomon (daemon/wallet/asset). By default this is daemon mode which indexes directly from the chain. Wallet mode indexes from wallet tx history (use/store with caution).
+  --search-filter=<"Function InputStr(input String, varname String) Uint64">     Defines a search filter to match on installed SCs to add to validated list and index all actions, this will most likely change in the future but can allow for some small variability. Include escapes etc. if required. If nothing is defined, it will pull all (minus hardcoded sc). Multiple filters (use const separator [default ';;;']) match if any of them match. A filter can also be built from code:<substring>, re:<regex> and func:<signature> terms (e.g. "func:InputStr(input String, varname String) Uint64 && !code:InitializePrivate"), negated with ! and joined with && / ||. func terms match the parsed SC functions so code formatting does not matter.
+  --runmode=<daemon>     Defines the runmode of gnomon (daemon/wallet/asset). By default this is daemon mode which indexes directly from the chain. Wallet mode indexes from wallet tx history of --wallet-rpc-address (use/store with caution).
   --enable-miniblock-lookup     True/false value to store all miniblocks and their respective details and miner addresses who found them. This currently REQUIRES a full node db in same directory
-  --close-on-disconnect     True/false value to close out indexers in the event of daemon disconnect. Daemon will fail connections for 30 seconds and then close the indexer. This is for HA pairs or wanting services off on disconnect.
+  --close-on-disconnect     True/false value to close out indexers in the event of daemon disconnect. Daemon(s) will fail connections for 30 seconds and then close the indexer. With multiple daemons, this only happens once all of them are down. This is for HA pairs or wanting services off on disconnect.
   --fastsync     True/false value to define loading at chain height and only keeping track of list of SCIDs and their respective up-to-date variable stores as it hits them. NOTE: You will not get all information and may rely on manual scid additions.
   --dbtype=<boltdb>     Defines type of database. 'gravdb' or 'boltdb'. If gravdb, expect LARGE local storage if running in daemon mode until further optimized later. [--ramstore can only be valid with gravdb]. Defaults to boltdb.
   --ramstore     True/false value to define if the db [only if gravdb] will be used in RAM or on disk. Keep in mind on close, the RAM store will be non-persistent.
   --num-parallel-blocks=<5>     Defines the number of parallel blocks to index in daemonmode. While a lower limit of 1 is defined, there is no hardcoded upper limit. Be mindful the higher set, the greater the daemon load potentially (highly recommend local nodes if this is greater than 1-5)
   --remove-api-throttle     Removes the api throttle against number of sc variables, sc invoke data etc. to return
   --sf-scid-exclusions=<"a05395bb0cf77adc850928b0db00eb5ca7a9ccbafd9a38d021c8d299ad5ce1a4;;;c9d23d2fc3aaa8e54e238a2218c0e5176a6e48780920fd8474fac5b0576110a2">     Defines a scid or scids (use const separator [default ';;;']) to be excluded from indexing regardless of search-filter. If nothing is defined, all scids that match the search-filter will be indexed.
+  --enable-regtx-lookup     True/false value to decode registration txns and store each registered address with its registration height and txid. Available via the /api/getregistrations endpoint and the getregistration_byaddr command.
+  --enable-mempool     True/false value to watch the daemon txpool for pending sc installs/invokes. Pending txns are available via the /api/mempool endpoint until they are mined or evicted.
+  --sc-templates=<"templates/mydapp.bas;;;templates/mytoken.bas">     Defines reference SC code file(s) (use const separator [default ';;;']). Installed SCs that match the search filter are classified against these and the matching template id (file name without extension) is stored with the scid, to list every deployment of a given dApp via listsc_bytemplate or /api/scidsbytemplate. Whitespace, comments and line numbers do not matter.
+  --sc-template-ignore-functions=<"InitializePrivate;;;Initialize">     Defines function name(s) (use const separator [default ';;;']) to leave out when comparing SCs against --sc-templates, for functions that differ per deploy.
+  --sc-arg-index=<"<scid>:name,owner;;;<scid>:bet">     Defines invoke arg name(s) to keep a secondary index on per scid (use const separator [default ';;;'] between scids). Invokes of the scid can then be looked up by arg value via listsc_byarg or /api/indexbyscid?scid=<scid>&argname=<name>&argvalue=<value> without checking every invoke. Invokes already stored are indexed on startup.
+  --retain-variables=<50000>     Defines the number of blocks below the indexed height to keep sc variable history for. The variables as of that height are kept so lookups within the window still work, older heights are pruned in the background. Must be at least 100, by default all history is kept.
+  --retain-invokes=<50000>     Defines the number of blocks below the indexed height to keep sc invokes for. Sc installs are always kept. Must be at least 100, by default all invokes are kept.
+  --retain-miniblocks=<50000>     Defines the number of blocks below the indexed height to keep miniblock details for (with --enable-miniblock-lookup). Miniblock counts per address are kept. Must be at least 100, by default all miniblock details are kept.
+  --retain-scid=<"<scid>:variables=5000,invokes=5000;;;<scid>:invokes=0">     Defines --retain-variables/--retain-invokes per scid (use const separator [default ';;;'] between scids), windows not defined for a scid are the global ones. 0 keeps all history of the scid.
+  --import-snapshot=<gnomon.snapshot>     Loads an archive written by the export_snapshot command into the (new) db on startup, indexing then continues from the height it was exported at. Archives can be imported into either --dbtype regardless of the one they were exported from.
   --skip-gnomonsc-index     If the gnomonsc is caught within the supplied search filter, you can skip indexing that SC given the size/depth of calls to that SC for increased sync times.
   --debug     Enables debug logging`
 
@@ -119,6 +130,11 @@ func main() {
 
 	logger.Printf("[Main] Using daemon RPC endpoint %s", daemon_endpoint)
 
+	wallet_endpoint := "127.0.0.1:40403"
+	if arguments["--wallet-rpc-address"] != nil {
+		wallet_endpoint = arguments["--wallet-rpc-address"].(string)
+	}
+
 	api_endpoint := "127.0.0.1:8082"
 	if arguments["--api-address"] != nil {
 		api_endpoint = arguments["--api-address"].(string)
@@ -166,6 +182,9 @@ func main() {
 	} else {
 		logger.Printf("[Main] No search filter defined.. grabbing all.")
 	}
+	if _, err := indexer.NewSearchFilter(search_filter); err != nil {
+		logger.Fatalf("[Main] ERR - %v", err)
+	}
 
 	var sf_scid_exclusions []string
 	if arguments["--sf-scid-exclusions"] != nil {
@@ -213,6 +232,67 @@ func main() {
 		closeondisconnect = true
 	}
 
+	// Reference SC code to classify installed SCs against
+	var sc_templates, sc_template_ignore []string
+	if arguments["--sc-templates"] != nil {
+		sc_templates = strings.Split(arguments["--sc-templates"].(string), sf_separator)
+		logger.Printf("[Main] Using sc templates: %v", sc_templates)
+	}
+	if arguments["--sc-template-ignore-functions"] != nil {
+		sc_template_ignore = strings.Split(arguments["--sc-template-ignore-functions"].(string), sf_separator)
+	}
+	var templates *indexer.TemplateMatcher
+	if len(sc_templates) > 0 {
+		templates, err = indexer.NewTemplateMatcher(sc_templates, sc_template_ignore)
+		if err != nil {
+			logger.Fatalf("[Main] ERR - %v", err)
+		}
+	}
+
+	// Invoke args to keep a secondary index on
+	var sc_arg_index map[string][]string
+	if arguments["--sc-arg-index"] != nil {
+		sc_arg_index, err = indexer.ParseSCArgIndex(strings.Split(arguments["--sc-arg-index"].(string), sf_separator))
+		if err != nil {
+			logger.Fatalf("[Main] ERR - %v", err)
+		}
+		logger.Printf("[Main] Using sc arg indexes: %v", sc_arg_index)
+	}
+
+	// History retention windows, all history is kept unless defined
+	var retain_policy structures.RetentionPolicy
+	for flag, window := range map[string]*int64{"--retain-variables": &retain_policy.Variables, "--retain-invokes": &retain_policy.Invokes, "--retain-miniblocks": &retain_policy.Miniblocks} {
+		if arguments[flag] != nil {
+			*window, err = strconv.ParseInt(arguments[flag].(string), 10, 64)
+			if err != nil {
+				logger.Fatalf("[Main] ERR converting '%v' to int64 for %v.", arguments[flag].(string), flag)
+			}
+		}
+	}
+	var retain_scid []string
+	if arguments["--retain-scid"] != nil {
+		retain_scid = strings.Split(arguments["--retain-scid"].(string), sf_separator)
+	}
+	retention, err := indexer.ParseRetention(retain_policy, retain_scid)
+	if err != nil {
+		logger.Fatalf("[Main] ERR - %v", err)
+	}
+	if retention.Enabled() {
+		logger.Printf("[Main] Using retention windows: %+v, per scid: %v", retention.Default, retention.SCIDs)
+	}
+
+	// Decodes and stores registration txns rather than only counting them
+	var regtxlookup bool
+	if arguments["--enable-regtx-lookup"] != nil && arguments["--enable-regtx-lookup"].(bool) == true {
+		regtxlookup = true
+	}
+
+	// Watches the daemon txpool for pending sc txns
+	var mempool bool
+	if arguments["--enable-mempool"] != nil && arguments["--enable-mempool"].(bool) == true {
+		mempool = true
+	}
+
 	// Starts at current chainheight and retrieves a list of SCIDs to auto-add to index validation list
 	var fastsync bool
 	if arguments["--fastsync"] != nil && arguments["--fastsync"].(bool) == true {
@@ -242,14 +322,13 @@ func main() {
 	}
 
 	// Database
-	var Graviton_backend *storage.GravitonStore
-	var Bbs_backend *storage.BboltStore
+	var backend storage.Storage
 	var csearch_filter string
 
 	switch Gnomon.DBType {
 	case "gravdb":
 		if ramstore {
-			Graviton_backend, err = storage.NewGravDBRAM("25ms")
+			backend, err = storage.NewGravDBRAM("25ms")
 			if err != nil {
 				logger.Fatalf("[Main] Err creating gravdb: %v", err)
 			}
@@ -267,7 +346,7 @@ func main() {
 				logger.Fatalf("[Main] Err getting working directory: %v", err)
 			}
 			db_path := filepath.Join(current_path, db_folder)
-			Graviton_backend, err = storage.NewGravDB(db_path, "25ms")
+			backend, err = storage.NewGravDB(db_path, "25ms")
 			if err != nil {
 				logger.Fatalf("[Main] Err creating gravdb: %v", err)
 			}
@@ -287,12 +366,23 @@ func main() {
 			logger.Fatalf("[Main] Err getting working directory: %v", err)
 		}
 		db_path := filepath.Join(wd, "gnomondb")
-		Bbs_backend, err = storage.NewBBoltDB(db_path, db_name)
+		backend, err = storage.NewBBoltDB(db_path, db_name)
 		if err != nil {
 			logger.Fatalf("[Main] Err creating boltdb: %v", err)
 		}
 	}
 
+	// Index snapshot to bootstrap from
+	if arguments["--import-snapshot"] != nil {
+		snapshot_path := arguments["--import-snapshot"].(string)
+		logger.Printf("[Main] Importing snapshot '%v'...", snapshot_path)
+		header, err := storage.ImportSnapshot(backend, snapshot_path)
+		if err != nil {
+			logger.Fatalf("[Main] ERR importing snapshot: %v", err)
+		}
+		logger.Printf("[Main] Imported snapshot '%v', continuing from height %v", snapshot_path, header.Height)
+	}
+
 	// API
 	apic := &structures.APIConfig{
 		Enabled:              true,
@@ -306,19 +396,37 @@ func main() {
 		KeyFile:              "cert.key",
 		GetInfoKeyFile:       "getinfocert.key",
 		MBLLookup:            mbl,
+		RegTxLookup:          regtxlookup,
 		ApiThrottle:          api_throttle,
 	}
-	// TODO: Add default search filter index of sorts, rather than passing through Graviton_backend object as a whole
-	apis := api.NewApiServer(apic, Graviton_backend, Bbs_backend, Gnomon.DBType)
-	go apis.Start()
+	// TODO: Add default search filter index of sorts, rather than passing through backend object as a whole
+	apis := api.NewApiServer(apic, backend)
 
 	// Start default indexer based on search_filter params
-	defaultIndexer := indexer.NewIndexer(Graviton_backend, Bbs_backend, Gnomon.DBType, search_filter, last_indexedheight, daemon_endpoint, Gnomon.RunMode, mbl, closeondisconnect, fastsync, sf_scid_exclusions)
+	defaultIndexer := indexer.NewIndexer(backend, Gnomon.DBType, search_filter, last_indexedheight, daemon_endpoint, Gnomon.RunMode, mbl, closeondisconnect, fastsync, sf_scid_exclusions)
+	defaultIndexer.RegTxLookup = regtxlookup
+	defaultIndexer.Templates = templates
+	defaultIndexer.ArgIndex = sc_arg_index
+
+	if retention.Enabled() {
+		defaultIndexer.Retention = retention
+		go defaultIndexer.StartRetentionPruner()
+	}
+
+	if mempool {
+		defaultIndexer.Mempool = indexer.NewMempool()
+		apis.Mempool = defaultIndexer.Mempool
+		go defaultIndexer.StartMempoolWatcher()
+	}
+
+	go apis.Start()
 
 	switch Gnomon.RunMode {
 	case "daemon":
 		go defaultIndexer.StartDaemonMode(numParallelBlocks)
 	case "wallet":
+		logger.Printf("[Main] Using wallet RPC endpoint %s", wallet_endpoint)
+		defaultIndexer.WalletEndpoint = wallet_endpoint
 		go defaultIndexer.StartWalletMode("")
 	case "asset":
 		go defaultIndexer.StartDaemonMode(numParallelBlocks)
@@ -348,14 +456,15 @@ func main() {
 				return
 			}
 
-			validatedSCIDs := make(map[string]string)
-			switch Gnomon.DBType {
-			case "gravdb":
-				validatedSCIDs = Graviton_backend.GetAllOwnersAndSCIDs()
-			case "boltdb":
-				validatedSCIDs = Bbs_backend.GetAllOwnersAndSCIDs()
+			// Indexer closed itself (daemon disconnect with --close-on-disconnect, or an unresolved chain reorg), so the api is taken down with it rather than serving stale data
+			if defaultIndexer.Closing {
+				logger.Errorf("[Main] Indexer has closed, closing Gnomon")
+				Gnomon.Close()
+				return
 			}
 
+			validatedSCIDs := backend.GetAllOwnersAndSCIDs()
+
 			gnomon_count := int64(len(validatedSCIDs))
 
 			currheight := defaultIndexer.LastIndexedHeight
@@ -444,13 +553,7 @@ func (g *GnomonServer) readline_loop(l *readline.Instance) (err error) {
 		case command == "listsc":
 			for ki, vi := range g.Indexers {
 				logger.Printf("- Indexer '%v'", ki)
-				sclist := make(map[string]string)
-				switch vi.DBType {
-				case "gravdb":
-					sclist = vi.GravDBBackend.GetAllOwnersAndSCIDs()
-				case "boltdb":
-					sclist = vi.BBSBackend.GetAllOwnersAndSCIDs()
-				}
+				sclist := vi.Backend.GetAllOwnersAndSCIDs()
 				for k, v := range sclist {
 					logger.Printf("SCID: %v ; Owner: %v", k, v)
 				}
@@ -466,13 +569,7 @@ func (g *GnomonServer) readline_loop(l *readline.Instance) (err error) {
 				i := 0
 				for ki, vi := range g.Indexers {
 					logger.Printf("- Indexer '%v'", ki)
-					var owner string
-					switch vi.DBType {
-					case "gravdb":
-						owner = vi.GravDBBackend.GetOwner(line_parts[1])
-					case "boltdb":
-						owner = vi.BBSBackend.GetOwner(line_parts[1])
-					}
+					owner := vi.Backend.GetOwner(line_parts[1])
 					_, sccode, _, err := vi.RPC.GetSCVariables(line_parts[1], vi.ChainHeight, nil, nil, nil, true)
 					if err != nil {
 						logger.Errorf("%v", err)
@@ -496,13 +593,7 @@ func (g *GnomonServer) readline_loop(l *readline.Instance) (err error) {
 					i := 0
 					for ki, vi := range g.Indexers {
 						logger.Printf("- Indexer '%v'", ki)
-						var owner string
-						switch vi.DBType {
-						case "gravdb":
-							owner = vi.GravDBBackend.GetOwner(line_parts[1])
-						case "boltdb":
-							owner = vi.BBSBackend.GetOwner(line_parts[1])
-						}
+						owner := vi.Backend.GetOwner(line_parts[1])
 						_, sccode, _, err := vi.RPC.GetSCVariables(line_parts[1], int64(s), nil, nil, nil, true)
 						if err != nil {
 							logger.Errorf("%v", err)
@@ -534,13 +625,7 @@ func (g *GnomonServer) readline_loop(l *readline.Instance) (err error) {
 				i := 0
 				for ki, vi := range g.Indexers {
 					logger.Printf("- Indexer '%v'", ki)
-					var owner string
-					switch vi.DBType {
-					case "gravdb":
-						owner = vi.GravDBBackend.GetOwner(line_parts[1])
-					case "boltdb":
-						owner = vi.BBSBackend.GetOwner(line_parts[1])
-					}
+					owner := vi.Backend.GetOwner(line_parts[1])
 					vars, _, _, err := vi.RPC.GetSCVariables(line_parts[1], vi.ChainHeight, nil, nil, nil, false)
 					if err != nil {
 						logger.Errorf("%v", err)
@@ -569,13 +654,7 @@ func (g *GnomonServer) readline_loop(l *readline.Instance) (err error) {
 					i := 0
 					for ki, vi := range g.Indexers {
 						logger.Printf("- Indexer '%v'", ki)
-						var owner string
-						switch vi.DBType {
-						case "gravdb":
-							owner = vi.GravDBBackend.GetOwner(line_parts[1])
-						case "boltdb":
-							owner = vi.BBSBackend.GetOwner(line_parts[1])
-						}
+						owner := vi.Backend.GetOwner(line_parts[1])
 						vars, _, _, err := vi.RPC.GetSCVariables(line_parts[1], int64(s), nil, nil, nil, false)
 						if err != nil {
 							logger.Errorf("%v", err)
@@ -610,24 +689,12 @@ func (g *GnomonServer) readline_loop(l *readline.Instance) (err error) {
 			if len(line_parts) == 2 && len(line_parts[1]) == 66 {
 				for ki, vi := range g.Indexers {
 					logger.Printf("- Indexer '%v'", ki)
-					sclist := make(map[string]string)
-					switch vi.DBType {
-					case "gravdb":
-						sclist = vi.GravDBBackend.GetAllOwnersAndSCIDs()
-					case "boltdb":
-						sclist = vi.BBSBackend.GetAllOwnersAndSCIDs()
-					}
+					sclist := vi.Backend.GetAllOwnersAndSCIDs()
 					var count int64
 					for k, v := range sclist {
 						if v == line_parts[1] {
 							logger.Printf("SCID: %v ; Owner: %v", k, v)
-							var invokedetails []*structures.SCTXParse
-							switch vi.DBType {
-							case "gravdb":
-								invokedetails = vi.GravDBBackend.GetAllSCIDInvokeDetails(k)
-							case "boltdb":
-								invokedetails = vi.BBSBackend.GetAllSCIDInvokeDetails(k)
-							}
+							invokedetails := vi.Backend.GetAllSCIDInvokeDetails(k)
 							for _, invoke := range invokedetails {
 								logger.Printf("Sender: %v ; topoheight : %v ; args: %v ; burnValue: %v", invoke.Sender, invoke.Height, invoke.Sc_args, invoke.Payloads[0].BurnValue)
 							}
@@ -646,24 +713,12 @@ func (g *GnomonServer) readline_loop(l *readline.Instance) (err error) {
 			if len(line_parts) >= 2 && len(line_parts[1]) == 64 {
 				for ki, vi := range g.Indexers {
 					logger.Printf("- Indexer '%v'", ki)
-					var sclist map[string]string
-					switch vi.DBType {
-					case "gravdb":
-						sclist = vi.GravDBBackend.GetAllOwnersAndSCIDs()
-					case "boltdb":
-						sclist = vi.BBSBackend.GetAllOwnersAndSCIDs()
-					}
+					sclist := vi.Backend.GetAllOwnersAndSCIDs()
 					var count int64
 					for k, v := range sclist {
 						if k == line_parts[1] {
 							logger.Printf("SCID: %v ; Owner: %v", k, v)
-							var invokedetails []*structures.SCTXParse
-							switch vi.DBType {
-							case "gravdb":
-								invokedetails = vi.GravDBBackend.GetAllSCIDInvokeDetails(k)
-							case "boltdb":
-								invokedetails = vi.BBSBackend.GetAllSCIDInvokeDetails(k)
-							}
+							invokedetails := vi.Backend.GetAllSCIDInvokeDetails(k)
 							for _, invoke := range invokedetails {
 								if len(line_parts) == 3 {
 									ca, _ := strconv.Atoi(line_parts[2])
@@ -691,21 +746,9 @@ func (g *GnomonServer) readline_loop(l *readline.Instance) (err error) {
 					for ki, vi := range g.Indexers {
 						logger.Printf("- Indexer '%v'", ki)
 						var scinstalls []*structures.SCTXParse
-						var sclist map[string]string
-						switch vi.DBType {
-						case "gravdb":
-							sclist = vi.GravDBBackend.GetAllOwnersAndSCIDs()
-						case "boltdb":
-							sclist = vi.BBSBackend.GetAllOwnersAndSCIDs()
-						}
+						sclist := vi.Backend.GetAllOwnersAndSCIDs()
 						for k, _ := range sclist {
-							var invokedetails []*structures.SCTXParse
-							switch vi.DBType {
-							case "gravdb":
-								invokedetails = vi.GravDBBackend.GetAllSCIDInvokeDetails(k)
-							case "boltdb":
-								invokedetails = vi.BBSBackend.GetAllSCIDInvokeDetails(k)
-							}
+							invokedetails := vi.Backend.GetAllSCIDInvokeDetails(k)
 							i := 0
 							for _, v := range invokedetails {
 								sc_action := fmt.Sprintf("%v", v.Sc_args.Value("SC_ACTION", "U"))
@@ -741,13 +784,7 @@ func (g *GnomonServer) readline_loop(l *readline.Instance) (err error) {
 			if len(line_parts) == 1 {
 				for ki, vi := range g.Indexers {
 					logger.Printf("- Indexer '%v'", ki)
-					var sclist map[string]string
-					switch vi.DBType {
-					case "gravdb":
-						sclist = vi.GravDBBackend.GetAllOwnersAndSCIDs()
-					case "boltdb":
-						sclist = vi.BBSBackend.GetAllOwnersAndSCIDs()
-					}
+					sclist := vi.Backend.GetAllOwnersAndSCIDs()
 					var count int64
 					for k, _ := range sclist {
 						_, _, cbal, _ := vi.RPC.GetSCVariables(k, vi.ChainHeight, nil, nil, nil, false)
@@ -775,13 +812,7 @@ func (g *GnomonServer) readline_loop(l *readline.Instance) (err error) {
 			} else if len(line_parts) == 2 && len(line_parts[1]) == 64 {
 				for ki, vi := range g.Indexers {
 					logger.Printf("- Indexer '%v'", ki)
-					var sclist map[string]string
-					switch vi.DBType {
-					case "gravdb":
-						sclist = vi.GravDBBackend.GetAllOwnersAndSCIDs()
-					case "boltdb":
-						sclist = vi.BBSBackend.GetAllOwnersAndSCIDs()
-					}
+					sclist := vi.Backend.GetAllOwnersAndSCIDs()
 					var count int64
 					for k, _ := range sclist {
 						if k != line_parts[1] {
@@ -812,17 +843,114 @@ func (g *GnomonServer) readline_loop(l *readline.Instance) (err error) {
 			} else {
 				logger.Printf("listsc_byscid needs a single scid or no SCIDs as argument")
 			}
+		case command == "listsc_balancesstored":
+			if (len(line_parts) == 2 || len(line_parts) == 3) && len(line_parts[1]) == 64 {
+				for ki, vi := range g.Indexers {
+					logger.Printf("- Indexer '%v'", ki)
+					height := vi.LastIndexedHeight
+					if len(line_parts) == 3 {
+						s, err := strconv.ParseInt(line_parts[2], 10, 64)
+						if err != nil {
+							logger.Errorf("Err converting '%v' to int64 - %v", line_parts[2], err)
+							continue
+						}
+						height = s
+						if err := indexer.CheckEarliestHeight(vi.Backend, height); err != nil {
+							logger.Errorf("%v", err)
+							continue
+						}
+					}
+
+					cbal := vi.Backend.GetSCIDBalanceDetailsAtTopoheight(line_parts[1], height)
+					if cbal == nil {
+						logger.Printf("No balances stored for '%v' at or below height %v", line_parts[1], height)
+						continue
+					}
+					fmt.Printf("%v @ %v:\n", line_parts[1], height)
+					for kb, vb := range cbal {
+						if kb == "0000000000000000000000000000000000000000000000000000000000000000" {
+							fmt.Printf("_DERO: %v\n", vb)
+						} else {
+							fmt.Printf("_Asset: %v:%v\n", kb, vb)
+						}
+					}
+				}
+			} else {
+				logger.Printf("listsc_balancesstored needs a single scid and optionally a height as argument")
+			}
+		case command == "listsc_balancehistory":
+			if len(line_parts) == 2 && len(line_parts[1]) == 64 {
+				for ki, vi := range g.Indexers {
+					logger.Printf("- Indexer '%v'", ki)
+					hBalances := vi.Backend.GetAllSCIDBalanceDetails(line_parts[1])
+					for _, v := range hBalances {
+						fmt.Printf("%v:\n", v.Height)
+						for kb, vb := range v.Balances {
+							if kb == "0000000000000000000000000000000000000000000000000000000000000000" {
+								fmt.Printf("_DERO: %v\n", vb)
+							} else {
+								fmt.Printf("_Asset: %v:%v\n", kb, vb)
+							}
+						}
+					}
+
+					if len(hBalances) == 0 {
+						logger.Printf("No balances stored for '%v'", line_parts[1])
+					}
+				}
+			} else {
+				logger.Printf("listsc_balancehistory needs a single scid as argument")
+			}
+		case command == "listsc_codestored":
+			if (len(line_parts) == 2 || len(line_parts) == 3) && len(line_parts[1]) == 64 {
+				for ki, vi := range g.Indexers {
+					logger.Printf("- Indexer '%v'", ki)
+					height := vi.LastIndexedHeight
+					if len(line_parts) == 3 {
+						s, err := strconv.ParseInt(line_parts[2], 10, 64)
+						if err != nil {
+							logger.Errorf("Err converting '%v' to int64 - %v", line_parts[2], err)
+							continue
+						}
+						height = s
+						if err := indexer.CheckEarliestHeight(vi.Backend, height); err != nil {
+							logger.Errorf("%v", err)
+							continue
+						}
+					}
+
+					sccode := vi.Backend.GetSCIDCodeAtTopoheight(line_parts[1], height)
+					if sccode == nil {
+						logger.Printf("No code stored for '%v' at or below height %v", line_parts[1], height)
+						continue
+					}
+					logger.Printf("SCID: %v ; Height: %v ; Txid: %v", line_parts[1], sccode.Height, sccode.Txid)
+					logger.Printf("%s", sccode.Code)
+				}
+			} else {
+				logger.Printf("listsc_codestored needs a single scid and optionally a height as argument")
+			}
+		case command == "listsc_codehistory":
+			if len(line_parts) == 2 && len(line_parts[1]) == 64 {
+				for ki, vi := range g.Indexers {
+					logger.Printf("- Indexer '%v'", ki)
+					hCode := vi.Backend.GetAllSCIDCode(line_parts[1])
+					for _, v := range hCode {
+						logger.Printf("Height: %v ; Txid: %v ; Code length: %v", v.Height, v.Txid, len(v.Code))
+					}
+
+					if len(hCode) == 0 {
+						logger.Printf("No code stored for '%v'", line_parts[1])
+					}
+				}
+			} else {
+				logger.Printf("listsc_codehistory needs a single scid as argument")
+			}
 		case command == "listsc_byentrypoint":
 			if len(line_parts) == 3 && len(line_parts[1]) == 64 {
 				for ki, vi := range g.Indexers {
 					logger.Printf("- Indexer '%v'", ki)
-					var indexbyentry []*structures.SCTXParse
-					switch vi.DBType {
-					case "gravdb":
-						indexbyentry = vi.GravDBBackend.GetAllSCIDInvokeDetailsByEntrypoint(line_parts[1], line_parts[2])
-					case "boltdb":
-						indexbyentry = vi.BBSBackend.GetAllSCIDInvokeDetailsByEntrypoint(line_parts[1], line_parts[2])
-					}
+					indexbyentry := vi.Backend.GetAllSCIDInvokeDetailsByEntrypoint(line_parts[1], line_parts[2])
 					var count int64
 					for _, v := range indexbyentry {
 						logger.Printf("Sender: %v ; topoheight : %v ; args: %v ; burnValue: %v", v.Sender, v.Height, v.Sc_args, v.Payloads[0].BurnValue)
@@ -836,26 +964,32 @@ func (g *GnomonServer) readline_loop(l *readline.Instance) (err error) {
 			} else {
 				logger.Printf("listsc_byscid needs a single scid and entrypoint as argument")
 			}
+		case command == "listsc_byarg":
+			if len(line_parts) >= 4 && len(line_parts[1]) == 64 {
+				// Values can contain spaces
+				value := strings.Join(line_parts[3:], " ")
+				for ki, vi := range g.Indexers {
+					logger.Printf("- Indexer '%v'", ki)
+					indexbyarg := vi.Backend.GetAllSCIDInvokeDetailsByArg(line_parts[1], line_parts[2], value)
+					for _, v := range indexbyarg {
+						logger.Printf("Sender: %v ; topoheight : %v ; entrypoint: %v ; args: %v ; txid: %v", v.Sender, v.Height, v.Entrypoint, v.Args, v.Txid)
+					}
+
+					if len(indexbyarg) == 0 {
+						logger.Printf("No SCID invokes with arg '%v' = '%v' for %v", line_parts[2], value, line_parts[1])
+					}
+				}
+			} else {
+				logger.Printf("listsc_byarg needs a single scid, arg name and value as argument")
+			}
 		case command == "listsc_byinitialize":
 			if len(line_parts) == 1 { //&& len(line_parts[1]) == 64 {
 				for ki, vi := range g.Indexers {
 					logger.Printf("- Indexer '%v'", ki)
-					var sclist map[string]string
-					switch vi.DBType {
-					case "gravdb":
-						sclist = vi.GravDBBackend.GetAllOwnersAndSCIDs()
-					case "boltdb":
-						sclist = vi.BBSBackend.GetAllOwnersAndSCIDs()
-					}
+					sclist := vi.Backend.GetAllOwnersAndSCIDs()
 					var count, count2 int64
 					for k, _ := range sclist {
-						var indexbyentry []*structures.SCTXParse
-						switch vi.DBType {
-						case "gravdb":
-							indexbyentry = vi.GravDBBackend.GetAllSCIDInvokeDetailsByEntrypoint(k, "Initialize")
-						case "boltdb":
-							indexbyentry = vi.BBSBackend.GetAllSCIDInvokeDetailsByEntrypoint(k, "Initialize")
-						}
+						indexbyentry := vi.Backend.GetAllSCIDInvokeDetailsByEntrypoint(k, "Initialize")
 						for _, v := range indexbyentry {
 							sc_action := fmt.Sprintf("%v", v.Sc_args.Value("SC_ACTION", "U"))
 							// If action is 'installsc' we don't need to return results for this
@@ -865,13 +999,7 @@ func (g *GnomonServer) readline_loop(l *readline.Instance) (err error) {
 							logger.Printf("Sender: %v ; topoheight : %v ; args: %v ; burnValue: %v", v.Sender, v.Height, v.Sc_args, v.Payloads[0].BurnValue)
 							count++
 						}
-						var indexbyentry2 []*structures.SCTXParse
-						switch vi.DBType {
-						case "gravdb":
-							indexbyentry2 = vi.GravDBBackend.GetAllSCIDInvokeDetailsByEntrypoint(k, "InitializePrivate")
-						case "boltdb":
-							indexbyentry2 = vi.BBSBackend.GetAllSCIDInvokeDetailsByEntrypoint(k, "InitializePrivate")
-						}
+						indexbyentry2 := vi.Backend.GetAllSCIDInvokeDetailsByEntrypoint(k, "InitializePrivate")
 						for _, v := range indexbyentry2 {
 							sc_action := fmt.Sprintf("%v", v.Sc_args.Value("SC_ACTION", "U"))
 							// If action is 'installsc' we don't need to return results for this
@@ -890,25 +1018,13 @@ func (g *GnomonServer) readline_loop(l *readline.Instance) (err error) {
 			} else if len(line_parts) == 2 && len(line_parts[1]) == 64 {
 				for ki, vi := range g.Indexers {
 					logger.Printf("- Indexer '%v'", ki)
-					var sclist map[string]string
-					switch vi.DBType {
-					case "gravdb":
-						sclist = vi.GravDBBackend.GetAllOwnersAndSCIDs()
-					case "boltdb":
-						sclist = vi.BBSBackend.GetAllOwnersAndSCIDs()
-					}
+					sclist := vi.Backend.GetAllOwnersAndSCIDs()
 					var count, count2 int64
 					for k, _ := range sclist {
 						if k != line_parts[1] {
 							continue
 						}
-						var indexbyentry []*structures.SCTXParse
-						switch vi.DBType {
-						case "gravdb":
-							indexbyentry = vi.GravDBBackend.GetAllSCIDInvokeDetailsByEntrypoint(k, "Initialize")
-						case "boltdb":
-							indexbyentry = vi.BBSBackend.GetAllSCIDInvokeDetailsByEntrypoint(k, "Initialize")
-						}
+						indexbyentry := vi.Backend.GetAllSCIDInvokeDetailsByEntrypoint(k, "Initialize")
 						for _, v := range indexbyentry {
 							sc_action := fmt.Sprintf("%v", v.Sc_args.Value("SC_ACTION", "U"))
 							// If action is 'installsc' we don't need to return results for this
@@ -918,13 +1034,7 @@ func (g *GnomonServer) readline_loop(l *readline.Instance) (err error) {
 							logger.Printf("Sender: %v ; topoheight : %v ; args: %v ; burnValue: %v", v.Sender, v.Height, v.Sc_args, v.Payloads[0].BurnValue)
 							count++
 						}
-						var indexbyentry2 []*structures.SCTXParse
-						switch vi.DBType {
-						case "gravdb":
-							indexbyentry2 = vi.GravDBBackend.GetAllSCIDInvokeDetailsByEntrypoint(k, "InitializePrivate")
-						case "boltdb":
-							indexbyentry2 = vi.BBSBackend.GetAllSCIDInvokeDetailsByEntrypoint(k, "InitializePrivate")
-						}
+						indexbyentry2 := vi.Backend.GetAllSCIDInvokeDetailsByEntrypoint(k, "InitializePrivate")
 						for _, v := range indexbyentry2 {
 							sc_action := fmt.Sprintf("%v", v.Sc_args.Value("SC_ACTION", "U"))
 							// If action is 'installsc' we don't need to return results for this
@@ -948,26 +1058,14 @@ func (g *GnomonServer) readline_loop(l *readline.Instance) (err error) {
 				if len(line_parts) >= 2 {
 					for ki, vi := range g.Indexers {
 						logger.Printf("- Indexer '%v'", ki)
-						var sclist map[string]string
-						switch vi.DBType {
-						case "gravdb":
-							sclist = vi.GravDBBackend.GetAllOwnersAndSCIDs()
-						case "boltdb":
-							sclist = vi.BBSBackend.GetAllOwnersAndSCIDs()
-						}
+						sclist := vi.Backend.GetAllOwnersAndSCIDs()
 						for k, v := range sclist {
 							if len(line_parts) > 2 && len(line_parts[2]) == 64 {
 								if k != line_parts[2] {
 									continue
 								}
 							}
-							var indexbypartialsigner []*structures.SCTXParse
-							switch vi.DBType {
-							case "gravdb":
-								indexbypartialsigner = vi.GravDBBackend.GetAllSCIDInvokeDetailsBySigner(k, line_parts[1])
-							case "boltdb":
-								indexbypartialsigner = vi.BBSBackend.GetAllSCIDInvokeDetailsBySigner(k, line_parts[1])
-							}
+							indexbypartialsigner := vi.Backend.GetAllSCIDInvokeDetailsBySigner(k, line_parts[1])
 							if len(indexbypartialsigner) > 0 {
 								logger.Printf("SCID: %v ; Owner: %v", k, v)
 							}
@@ -984,34 +1082,18 @@ func (g *GnomonServer) readline_loop(l *readline.Instance) (err error) {
 			if len(line_parts) >= 3 && len(line_parts[1]) == 64 {
 				for ki, vi := range g.Indexers {
 					logger.Printf("- Indexer '%v'", ki)
-					var sclist map[string]string
-					switch vi.DBType {
-					case "gravdb":
-						sclist = vi.GravDBBackend.GetAllOwnersAndSCIDs()
-					case "boltdb":
-						sclist = vi.BBSBackend.GetAllOwnersAndSCIDs()
-					}
+					sclist := vi.Backend.GetAllOwnersAndSCIDs()
 					var count int64
 					for k, _ := range sclist {
 						if k == line_parts[1] {
 							var keysstringbyvalue []string
 							var keysuint64byvalue []uint64
 
-							switch vi.DBType {
-							case "gravdb":
-								intCheck, err := strconv.Atoi(line_parts[2])
-								if err != nil {
-									keysstringbyvalue, keysuint64byvalue = vi.GravDBBackend.GetSCIDKeysByValue(k, strings.Join(line_parts[2:], " "), vi.ChainHeight, true)
-								} else {
-									keysstringbyvalue, keysuint64byvalue = vi.GravDBBackend.GetSCIDKeysByValue(k, uint64(intCheck), vi.ChainHeight, true)
-								}
-							case "boltdb":
-								intCheck, err := strconv.Atoi(line_parts[2])
-								if err != nil {
-									keysstringbyvalue, keysuint64byvalue = vi.BBSBackend.GetSCIDKeysByValue(k, strings.Join(line_parts[2:], " "), vi.ChainHeight, true)
-								} else {
-									keysstringbyvalue, keysuint64byvalue = vi.BBSBackend.GetSCIDKeysByValue(k, uint64(intCheck), vi.ChainHeight, true)
-								}
+							intCheck, err := strconv.Atoi(line_parts[2])
+							if err != nil {
+								keysstringbyvalue, keysuint64byvalue = vi.Backend.GetSCIDKeysByValue(k, strings.Join(line_parts[2:], " "), vi.ChainHeight, true)
+							} else {
+								keysstringbyvalue, keysuint64byvalue = vi.Backend.GetSCIDKeysByValue(k, uint64(intCheck), vi.ChainHeight, true)
 							}
 
 							for _, skey := range keysstringbyvalue {
@@ -1062,34 +1144,18 @@ func (g *GnomonServer) readline_loop(l *readline.Instance) (err error) {
 			if len(line_parts) >= 3 && len(line_parts[1]) == 64 {
 				for ki, vi := range g.Indexers {
 					logger.Printf("- Indexer '%v'", ki)
-					var sclist map[string]string
-					switch vi.DBType {
-					case "gravdb":
-						sclist = vi.GravDBBackend.GetAllOwnersAndSCIDs()
-					case "boltdb":
-						sclist = vi.BBSBackend.GetAllOwnersAndSCIDs()
-					}
+					sclist := vi.Backend.GetAllOwnersAndSCIDs()
 					var count int64
 					for k, _ := range sclist {
 						if k == line_parts[1] {
 							var valuesstringbykey []string
 							var valuesuint64bykey []uint64
 
-							switch vi.DBType {
-							case "gravdb":
-								intCheck, err := strconv.Atoi(line_parts[2])
-								if err != nil {
-									valuesstringbykey, valuesuint64bykey = vi.GravDBBackend.GetSCIDValuesByKey(k, strings.Join(line_parts[2:], " "), vi.ChainHeight, true)
-								} else {
-									valuesstringbykey, valuesuint64bykey = vi.GravDBBackend.GetSCIDValuesByKey(k, uint64(intCheck), vi.ChainHeight, true)
-								}
-							case "boltdb":
-								intCheck, err := strconv.Atoi(line_parts[2])
-								if err != nil {
-									valuesstringbykey, valuesuint64bykey = vi.BBSBackend.GetSCIDValuesByKey(k, strings.Join(line_parts[2:], " "), vi.ChainHeight, true)
-								} else {
-									valuesstringbykey, valuesuint64bykey = vi.BBSBackend.GetSCIDValuesByKey(k, uint64(intCheck), vi.ChainHeight, true)
-								}
+							intCheck, err := strconv.Atoi(line_parts[2])
+							if err != nil {
+								valuesstringbykey, valuesuint64bykey = vi.Backend.GetSCIDValuesByKey(k, strings.Join(line_parts[2:], " "), vi.ChainHeight, true)
+							} else {
+								valuesstringbykey, valuesuint64bykey = vi.Backend.GetSCIDValuesByKey(k, uint64(intCheck), vi.ChainHeight, true)
 							}
 
 							for _, sval := range valuesstringbykey {
@@ -1175,6 +1241,74 @@ func (g *GnomonServer) readline_loop(l *readline.Instance) (err error) {
 					break
 				}
 			}
+		case command == "simulatesc":
+			if len(line_parts) >= 3 && len(line_parts[1]) == 64 {
+				// Remaining args are height:<height>, signer:<address>, transfer:<amount> (DERO) or transfer:<assetscid>:<amount> and <param>=<value> for the entrypoint params
+				var height int64
+				var signer string
+				args := make(map[string]interface{})
+				transfers := make(map[string]uint64)
+				var perr error
+				for _, a := range line_parts[3:] {
+					switch {
+					case strings.HasPrefix(a, "height:"):
+						height, perr = strconv.ParseInt(strings.TrimPrefix(a, "height:"), 10, 64)
+					case strings.HasPrefix(a, "signer:"):
+						signer = strings.TrimPrefix(a, "signer:")
+					case strings.HasPrefix(a, "transfer:"):
+						var asset string
+						amount := strings.TrimPrefix(a, "transfer:")
+						if i := strings.LastIndex(amount, ":"); i >= 0 {
+							asset, amount = amount[:i], amount[i+1:]
+						}
+						var u uint64
+						u, perr = strconv.ParseUint(amount, 10, 64)
+						transfers[asset] += u
+					case strings.Contains(a, "="):
+						kv := strings.SplitN(a, "=", 2)
+						args[kv[0]] = kv[1]
+					default:
+						perr = fmt.Errorf("unknown argument '%v'", a)
+					}
+					if perr != nil {
+						break
+					}
+				}
+				if perr != nil {
+					logger.Errorf("[simulatesc] ERR - %v", perr)
+					break
+				}
+
+				for ki, vi := range g.Indexers {
+					logger.Printf("- Indexer '%v'", ki)
+					sim, err := vi.InterpretSC(line_parts[1], line_parts[2], height, args, signer, transfers)
+					if err != nil {
+						logger.Errorf("[simulatesc] ERR - %v", err)
+						break
+					}
+
+					logger.Printf("%v @ %v - %v", sim.Scid, sim.Height, sim.Entrypoint)
+					if sim.Error != "" {
+						logger.Printf("Error: %v", sim.Error)
+					} else {
+						logger.Printf("Return: %v ; Committed: %v", sim.Return, sim.Committed)
+					}
+					for _, v := range sim.Writes {
+						logger.Printf("Write: %v = %v", v.Key, v.Value)
+					}
+					for _, v := range sim.Deletes {
+						logger.Printf("Delete: %v", v)
+					}
+					for _, v := range sim.Transfers {
+						logger.Printf("Transfer: %v of %v to %v", v.Amount, v.Asset, v.Address)
+					}
+					logger.Printf("Gas compute: %v ; Gas storage: %v", sim.GasCompute, sim.GasStorage)
+					// Same state for every indexer of the scid, one run is enough
+					break
+				}
+			} else {
+				logger.Printf("simulatesc needs a scid and entrypoint as arguments")
+			}
 		case command == "addscid_toindex":
 			// TODO: Perhaps add indexer id to a param so you can add it to specific search_filter/indexer. Supported by a 'status' (tbd) command which returns details of each indexer
 			if len(line_parts) == 2 && len(line_parts[1]) == 64 {
@@ -1190,6 +1324,26 @@ func (g *GnomonServer) readline_loop(l *readline.Instance) (err error) {
 			} else {
 				logger.Printf("addscid_toindex needs 1 values: single scid to match as arguments")
 			}
+		case command == "rescan_scid":
+			if len(line_parts) >= 2 {
+				var scids []string
+				for _, scid := range line_parts[1:] {
+					if len(scid) != 64 {
+						logger.Printf("rescan_scid '%v' is not a scid", scid)
+						continue
+					}
+					scids = append(scids, scid)
+				}
+				for ki, vi := range g.Indexers {
+					logger.Printf("- Indexer '%v'", ki)
+					err = vi.RescanSCIDs(scids)
+					if err != nil {
+						logger.Printf("Err - %v", err)
+					}
+				}
+			} else {
+				logger.Printf("rescan_scid needs 1 or more values: scid(s) to rescan as arguments")
+			}
 			/*
 				case command == "index_txn":
 					// TODO: Perhaps add indexer id to a param so you can add it to specific search_filter/indexer. Supported by a 'status' (tbd) command which returns details of each indexer
@@ -1218,13 +1372,7 @@ func (g *GnomonServer) readline_loop(l *readline.Instance) (err error) {
 			if len(line_parts) == 2 && len(line_parts[1]) == 66 {
 				for ki, vi := range g.Indexers {
 					logger.Printf("- Indexer '%v'", ki)
-					var scidinteracts []string
-					switch vi.DBType {
-					case "gravdb":
-						scidinteracts = vi.GravDBBackend.GetSCIDInteractionByAddr(line_parts[1])
-					case "boltdb":
-						scidinteracts = vi.BBSBackend.GetSCIDInteractionByAddr(line_parts[1])
-					}
+					scidinteracts := vi.Backend.GetSCIDInteractionByAddr(line_parts[1])
 					for _, v := range scidinteracts {
 						logger.Printf("%v", v)
 					}
@@ -1232,6 +1380,131 @@ func (g *GnomonServer) readline_loop(l *readline.Instance) (err error) {
 			} else {
 				logger.Printf("getscidlist_byaddr needs 1 values: single address to match as arguments")
 			}
+		case command == "listaddress_activity":
+			if (len(line_parts) >= 2 && len(line_parts) <= 4) && len(line_parts[1]) == 66 {
+				var start, limit int
+				var err error
+				if len(line_parts) >= 3 {
+					start, err = strconv.Atoi(line_parts[2])
+					if err != nil {
+						logger.Errorf("Err converting '%v' to int - %v", line_parts[2], err)
+						break
+					}
+				}
+				if len(line_parts) == 4 {
+					limit, err = strconv.Atoi(line_parts[3])
+					if err != nil {
+						logger.Errorf("Err converting '%v' to int - %v", line_parts[3], err)
+						break
+					}
+				}
+
+				for ki, vi := range g.Indexers {
+					logger.Printf("- Indexer '%v'", ki)
+					activity, total := vi.Backend.GetAddressActivity(line_parts[1], start, limit)
+					for _, v := range activity {
+						logger.Printf("Height: %v ; SCID: %v ; Role: %v ; Txid: %v", v.Height, v.Scid, v.Role, v.Txid)
+					}
+					logger.Printf("Showing %v of %v activity entries", len(activity), total)
+				}
+			} else {
+				logger.Printf("listaddress_activity needs an address and optionally a start index and limit as arguments")
+			}
+		case command == "getasset":
+			if len(line_parts) == 2 && len(line_parts[1]) == 64 {
+				for ki, vi := range g.Indexers {
+					logger.Printf("- Indexer '%v'", ki)
+					asset := vi.Backend.GetSCAsset(line_parts[1])
+					if asset != nil {
+						logger.Printf("SCID: %v ; Standard: %v ; Name: %v ; Symbol: %v ; Supply: %v ; Owner: %v ; Collection: %v ; File: %v ; Height: %v", asset.Scid, asset.Standard, asset.Name, asset.Symbol, asset.Supply, asset.Owner, asset.Collection, asset.FileURL, asset.Height)
+					} else {
+						logger.Printf("'%v' is not a recognized asset", line_parts[1])
+					}
+				}
+			} else {
+				logger.Printf("getasset needs a single scid as argument")
+			}
+		case command == "listassets_bycollection" || command == "listassets_byowner" || command == "listassets_bystandard":
+			if len(line_parts) == 2 {
+				for ki, vi := range g.Indexers {
+					logger.Printf("- Indexer '%v'", ki)
+					var assets []*structures.SCAsset
+					switch command {
+					case "listassets_bycollection":
+						assets = vi.Backend.GetSCAssetsByCollection(line_parts[1])
+					case "listassets_byowner":
+						assets = vi.Backend.GetSCAssetsByOwner(line_parts[1])
+					default:
+						assets = vi.Backend.GetSCAssetsByStandard(line_parts[1])
+					}
+					for _, asset := range assets {
+						logger.Printf("SCID: %v ; Standard: %v ; Name: %v ; Owner: %v ; Collection: %v", asset.Scid, asset.Standard, asset.Name, asset.Owner, asset.Collection)
+					}
+					logger.Printf("Total assets: %v", len(assets))
+				}
+			} else {
+				logger.Printf("%v needs a single value as argument", command)
+			}
+		case command == "getname":
+			if len(line_parts) == 2 {
+				for ki, vi := range g.Indexers {
+					logger.Printf("- Indexer '%v'", ki)
+					record := vi.Backend.GetNameRecord(line_parts[1])
+					if record == nil {
+						logger.Printf("No name record stored for '%v'", line_parts[1])
+						continue
+					}
+					logger.Printf("Name: %v ; Address: %v", record.Name, record.Address)
+					for _, v := range vi.Backend.GetNameHistory(line_parts[1]) {
+						logger.Printf("Height: %v ; Action: %v ; Address: %v ; Txid: %v", v.Height, v.Action, v.Address, v.Txid)
+					}
+				}
+			} else {
+				logger.Printf("getname needs a single name as argument")
+			}
+		case command == "getnames_byaddr":
+			if len(line_parts) == 2 && len(line_parts[1]) == 66 {
+				for ki, vi := range g.Indexers {
+					logger.Printf("- Indexer '%v'", ki)
+					records := vi.Backend.GetNamesByAddress(line_parts[1])
+					for _, v := range records {
+						logger.Printf("Name: %v ; Since height: %v", v.Name, v.Height)
+					}
+
+					if len(records) == 0 {
+						logger.Printf("No names stored for '%v'", line_parts[1])
+					}
+				}
+			} else {
+				logger.Printf("getnames_byaddr needs 1 values: single address to match as arguments")
+			}
+		case command == "listsc_bytemplate":
+			if len(line_parts) == 2 {
+				for ki, vi := range g.Indexers {
+					logger.Printf("- Indexer '%v'", ki)
+					scids := vi.Backend.GetSCIDsByTemplate(line_parts[1])
+					for _, v := range scids {
+						logger.Printf("SCID: %v ; Owner: %v", v, vi.Backend.GetOwner(v))
+					}
+					logger.Printf("Total SCs matching template '%v': %v", line_parts[1], len(scids))
+				}
+			} else {
+				logger.Printf("listsc_bytemplate needs a single template id as argument")
+			}
+		case command == "getregistration_byaddr":
+			if len(line_parts) == 2 && len(line_parts[1]) == 66 {
+				for ki, vi := range g.Indexers {
+					logger.Printf("- Indexer '%v'", ki)
+					regtx := vi.Backend.GetRegTxByAddress(line_parts[1])
+					if regtx != nil {
+						logger.Printf("Registered at height %v - txid %v", regtx.Height, regtx.Txid)
+					} else {
+						logger.Printf("No registration stored for '%v'. Registrations are only stored with --enable-regtx-lookup", line_parts[1])
+					}
+				}
+			} else {
+				logger.Printf("getregistration_byaddr needs 1 values: single address to match as arguments")
+			}
 		case command == "pop":
 			switch len(line_parts) {
 			case 1:
@@ -1265,38 +1538,54 @@ func (g *GnomonServer) readline_loop(l *readline.Instance) (err error) {
 			default:
 				logger.Printf("POP needs argument n to pop this many blocks from the top")
 			}
+		case command == "export_snapshot":
+			if len(line_parts) == 2 {
+				for ki, vi := range g.Indexers {
+					logger.Printf("- Indexer '%v'", ki)
+					path := line_parts[1]
+					if len(g.Indexers) > 1 {
+						path = fmt.Sprintf("%s_%x", path, sha1.Sum([]byte(ki)))
+					}
+
+					// Indexing is paused while exporting, so the export is not holding a read open against its writes. Once the indexer is closed there are no writes to pause
+					var header *storage.SnapshotHeader
+					err := vi.Writer.Pause(func() (err error) {
+						header, err = storage.ExportSnapshot(vi.Backend, path)
+						return
+					})
+					if err == storage.ErrWriterClosed {
+						header, err = storage.ExportSnapshot(vi.Backend, path)
+					}
+					if err != nil {
+						logger.Errorf("[export_snapshot] ERR - exporting snapshot to '%v': %v", path, err)
+						continue
+					}
+					logger.Printf("Exported snapshot at height %v to '%v'", header.Height, path)
+				}
+			} else {
+				logger.Printf("export_snapshot needs a single file path as argument")
+			}
 		case line == "status":
 			for ki, vi := range g.Indexers {
 				logger.Printf("- Indexer '%v' - Generating status metrics...", ki)
 				var validatedSCIDs map[string]string
 				var regTxCount, burnTxCount, normTxCount, gnomon_count, scTxCount int64
 
-				switch vi.DBType {
-				case "gravdb":
-					validatedSCIDs = vi.GravDBBackend.GetAllOwnersAndSCIDs()
-					gnomon_count = int64(len(validatedSCIDs))
+				validatedSCIDs = vi.Backend.GetAllOwnersAndSCIDs()
+				gnomon_count = int64(len(validatedSCIDs))
 
-					regTxCount = vi.GravDBBackend.GetTxCount("registration")
-					burnTxCount = vi.GravDBBackend.GetTxCount("burn")
-					normTxCount = vi.GravDBBackend.GetTxCount("normal")
-
-					for sc, _ := range validatedSCIDs {
-						scTxCount += int64(len(vi.GravDBBackend.GetAllSCIDInvokeDetails(sc)))
-					}
-				case "boltdb":
-					validatedSCIDs = vi.BBSBackend.GetAllOwnersAndSCIDs()
-					gnomon_count = int64(len(validatedSCIDs))
+				regTxCount = vi.Backend.GetTxCount("registration")
+				burnTxCount = vi.Backend.GetTxCount("burn")
+				normTxCount = vi.Backend.GetTxCount("normal")
 
-					regTxCount = vi.BBSBackend.GetTxCount("registration")
-					burnTxCount = vi.BBSBackend.GetTxCount("burn")
-					normTxCount = vi.BBSBackend.GetTxCount("normal")
-
-					for sc, _ := range validatedSCIDs {
-						scTxCount += int64(len(vi.BBSBackend.GetAllSCIDInvokeDetails(sc)))
-					}
+				for sc, _ := range validatedSCIDs {
+					scTxCount += int64(len(vi.Backend.GetAllSCIDInvokeDetails(sc)))
 				}
 
 				logger.Printf("GNOMON [%d/%d] R:%d >>", vi.LastIndexedHeight, vi.ChainHeight, gnomon_count)
+				if earliest := vi.Backend.GetEarliestHeight(); earliest > 0 {
+					logger.Printf("EARLIEST [%d/%d] >> %d (daemon is pruned below this height)", vi.LastIndexedHeight, vi.ChainHeight, earliest)
+				}
 				logger.Printf("TXCOUNTS [%d/%d] R:%d B:%d N:%d S:%d >>", vi.LastIndexedHeight, vi.ChainHeight, regTxCount, burnTxCount, normTxCount, scTxCount)
 				if len(vi.SearchFilter) == 0 {
 					logger.Printf("SEARCHFILTER(S) [%d/%d] >> %s", vi.LastIndexedHeight, vi.ChainHeight, "ALL SCs")
@@ -1338,7 +1627,12 @@ func usage(w io.Writer) {
 	io.WriteString(w, "\t\033[1mlistsc_byscid\033[0m\tList a scid/owner pair by scid and optionally at a specified height and higher, listsc_byscid <scid> <minheight>\n")
 	io.WriteString(w, "\t\033[1mlistsc_byheight\033[0m\tList all indexed scids that match original search filter including height deployed, listsc_byheight\n")
 	io.WriteString(w, "\t\033[1mlistsc_balances\033[0m\tLists balances of SCIDs that are greater than 0 or of a specific scid if specified, listsc_balances || listsc_balances <scid>\n")
+	io.WriteString(w, "\t\033[1mlistsc_balancesstored\033[0m\tLists the stored balances of a SCID at latest indexed height unless optionally defining a height, listsc_balancesstored <scid> || listsc_balancesstored <scid> <height>\n")
+	io.WriteString(w, "\t\033[1mlistsc_balancehistory\033[0m\tLists the stored balances of a SCID at each of its interaction heights, listsc_balancehistory <scid>\n")
+	io.WriteString(w, "\t\033[1mlistsc_codestored\033[0m\tLists the stored code of a SCID at latest indexed height unless optionally defining a height, listsc_codestored <scid> || listsc_codestored <scid> <height>\n")
+	io.WriteString(w, "\t\033[1mlistsc_codehistory\033[0m\tLists the stored code versions of a SCID with the height and txid that installed or updated each, listsc_codehistory <scid>\n")
 	io.WriteString(w, "\t\033[1mlistsc_byentrypoint\033[0m\tLists sc invokes by entrypoint, listsc_byentrypoint <scid> <entrypoint>\n")
+	io.WriteString(w, "\t\033[1mlistsc_byarg\033[0m\tLists sc invokes whose arg of a given name has a given value (uses the --sc-arg-index of the scid if defined), listsc_byarg <scid> <argname> <value>\n")
 	io.WriteString(w, "\t\033[1mlistsc_byinitialize\033[0m\tLists all calls to SCs that attempted to run Initialize or InitializePrivate() or to a specific SC is defined, listsc_byinitialize || listsc_byinitialize <scid>\n")
 	io.WriteString(w, "\t\033[1mlistscinvoke_bysigner\033[0m\tLists all sc invokes that match a given signer or partial signer address and optionally by scid, listscinvoke_bysigner <signerstring> || listscinvoke_bysigner <signerstring> <scid>\n")
 	io.WriteString(w, "\t\033[1mlistscidkey_byvaluestored\033[0m\tList keys in a SC that match a given value by pulling from gnomon database, listscidkey_byvaluestored <scid> <value>\n")
@@ -1346,10 +1640,22 @@ func usage(w io.Writer) {
 	io.WriteString(w, "\t\033[1mlistscidvalue_bykeystored\033[0m\tList keys in a SC that match a given value by pulling from gnomon database, listscidvalue_bykeystored <scid> <key>\n")
 	io.WriteString(w, "\t\033[1mlistscidvalue_bykeylive\033[0m\tList keys in a SC that match a given value by pulling from daemon, listscidvalue_bykeylive <scid> <key>\n")
 	io.WriteString(w, "\t\033[1mvalidatesc\033[0m\tValidates a SC looking for a 'signature' k/v pair containing DERO signature validating the code matches the signature, validatesc <scid>\n")
+	io.WriteString(w, "\t\033[1msimulatesc\033[0m\tDry-runs a SC entrypoint against the indexed state (latest indexed height unless defined) and prints the return value, variable writes and transfers without sending anything, simulatesc <scid> <entrypoint> [height:<height>] [signer:<address>] [transfer:<amount>] [transfer:<assetscid>:<amount>] [<param>=<value> ...]\n")
 	io.WriteString(w, "\t\033[1maddscid_toindex\033[0m\tAdd a SCID to index list/validation filter manually, addscid_toindex <scid>\n")
+	io.WriteString(w, "\t\033[1mrescan_scid\033[0m\tRebuilds the invokes, variables, interaction heights, balances, code versions, arg indexes, asset/template and invoker activity of indexed SCID(s) from their deploy height, for SCIDs added with addscid_toindex or whose data looks off (ring member activity and name records are not rebuilt, hardcoded SCIDs cannot be rescanned), rescan_scid <scid> [<scid> ...]\n")
 	//io.WriteString(w, "\t\033[1mindex_txn\033[0m\tIndex a specific txid (alpha), addscid_toindex <scid>\n")
 	io.WriteString(w, "\t\033[1mgetscidlist_byaddr\033[0m\tGets list of scids that addr has interacted with, getscidlist_byaddr <addr>\n")
+	io.WriteString(w, "\t\033[1mlistaddress_activity\033[0m\tLists the SC installs, invokes and scid payload ring memberships of addr in height order, optionally paged from a start index with a limit, listaddress_activity <addr> || listaddress_activity <addr> <start> <limit>\n")
+	io.WriteString(w, "\t\033[1mgetasset\033[0m\tGets the registry details (standard, name, symbol, supply, owner, collection, file) of a SCID recognized as a token/NFA standard, getasset <scid>\n")
+	io.WriteString(w, "\t\033[1mlistassets_bycollection\033[0m\tLists assets of a given collection scid, listassets_bycollection <collectionscid>\n")
+	io.WriteString(w, "\t\033[1mlistassets_byowner\033[0m\tLists assets owned by a given address, listassets_byowner <address>\n")
+	io.WriteString(w, "\t\033[1mlistassets_bystandard\033[0m\tLists assets of a given standard (e.g. G45-NFT, G45-AT, G45-C, ART-NFA, TOKEN), listassets_bystandard <standard>\n")
+	io.WriteString(w, "\t\033[1mgetname\033[0m\tGets the address a name service name is registered to along with its registration/transfer history, getname <name>\n")
+	io.WriteString(w, "\t\033[1mgetnames_byaddr\033[0m\tGets the name service names registered to an address, getnames_byaddr <address>\n")
+	io.WriteString(w, "\t\033[1mlistsc_bytemplate\033[0m\tLists SCIDs whose code matches a given --sc-templates template id, listsc_bytemplate <templateid>\n")
+	io.WriteString(w, "\t\033[1mgetregistration_byaddr\033[0m\tGets the registration height and txid of addr, getregistration_byaddr <addr>\n")
 	io.WriteString(w, "\t\033[1mpop\033[0m\tRolls back lastindexheight, pop <100>\n")
+	io.WriteString(w, "\t\033[1mexport_snapshot\033[0m\tWrites the whole index to a versioned and checksummed archive that new nodes can load with --import-snapshot and continue indexing from, export_snapshot <file>\n")
 	io.WriteString(w, "\t\033[1mstatus\033[0m\t\tShow general information\n")
 	io.WriteString(w, "\t\033[1mgnomonsc\033[0m\t\tShow scid of gnomon index scs\n")
 
@@ -48,7 +48,7 @@ Options:
   --wallet-rpc-address=<127.0.0.1:40403>	Connect to wallet rpc.
   --gnomon-api-address=<127.0.0.1:8082>	Gnomon api to connect to.
   --block-deploy-buffer=<10>	Block buffer inbetween SC calls. This is for safety, will be hardcoded to minimum of 2 but can define here any amount (10 default).
-  --search-filter=<"Function InputStr(input String, varname String) Uint64">	Defines a search filter to match on installed SCs to add to validated list and index all actions, this will most likely change in the future but can allow for some small variability. Include escapes etc. if required. If nothing is defined, it will pull all (minus hardcoded sc).
+  --search-filter=<"Function InputStr(input String, varname String) Uint64">	Defines a search filter to match on installed SCs to add to validated list and index all actions, this will most likely change in the future but can allow for some small variability. Include escapes etc. if required. If nothing is defined, it will pull all (minus hardcoded sc). Multiple filters (use const separator [default ';;;']) match if any of them match. A filter can also be built from code:<substring>, re:<regex> and func:<signature> terms (e.g. "func:InputStr(input String, varname String) Uint64 && !code:InitializePrivate"), negated with ! and joined with && / ||. func terms match the parsed SC functions so code formatting does not matter.
   --sf-scid-exclusions=<"a05395bb0cf77adc850928b0db00eb5ca7a9ccbafd9a38d021c8d299ad5ce1a4;;;c9d23d2fc3aaa8e54e238a2218c0e5176a6e48780920fd8474fac5b0576110a2">     Defines a scid or scids (use const separator [default ';;;']) to be excluded from indexing regardless of search-filter. If nothing is defined, all scids that match the search-filter will be indexed.
   --skip-gnomonsc-index     If the gnomonsc is caught within the supplied search filter, you can skip indexing that SC given the size/depth of calls to that SC for increased sync times.
   --debug     Enables debug logging`
@@ -121,6 +121,10 @@ func main() {
 	} else {
 		logger.Printf("[Main] No search filter defined.. grabbing all.")
 	}
+	searchFilter, err := indexer.NewSearchFilter(search_filter)
+	if err != nil {
+		logger.Fatalf("[Main] ERR - %v", err)
+	}
 
 	var sf_scid_exclusions []string
 	if arguments["--sf-scid-exclusions"] != nil {
@@ -166,7 +170,7 @@ func main() {
 
 	for {
 		fetchGnomonIndexes(gnomon_api_endpoint)
-		runGnomonIndexer(daemon_rpc_endpoint, gnomon_api_endpoint, search_filter, sf_scid_exclusions)
+		runGnomonIndexer(daemon_rpc_endpoint, gnomon_api_endpoint, searchFilter, sf_scid_exclusions)
 		logger.Printf("[Main] Round completed. Sleeping 1 minute for next round.")
 		time.Sleep(60 * time.Second)
 	}
@@ -205,7 +209,7 @@ func fetchGnomonIndexes(gnomonendpoint string) {
 	}
 }
 
-func runGnomonIndexer(derodendpoint string, gnomonendpoint string, search_filter []string, sf_scid_exclusions []string) {
+func runGnomonIndexer(derodendpoint string, gnomonendpoint string, search_filter *indexer.SearchFilter, sf_scid_exclusions []string) {
 	mux.Lock()
 	defer mux.Unlock()
 	var lastQuery map[string]interface{}
@@ -246,10 +250,10 @@ func runGnomonIndexer(derodendpoint string, gnomonendpoint string, search_filter
 
 	// If we can gather the current height from /api/getinfo then start-topoheight will be passed and fastsync not used. This saves time to not check all SCIDs from gnomon SC. Otherwise default back to "slow and steady" method.
 	if currheight > 0 {
-		defaultIndexer = indexer.NewIndexer(graviton_backend, nil, "gravdb", nil, currheight, derodendpoint, "daemon", false, false, false, sf_scid_exclusions)
+		defaultIndexer = indexer.NewIndexer(graviton_backend, "gravdb", nil, currheight, derodendpoint, "daemon", false, false, false, sf_scid_exclusions)
 		defaultIndexer.StartDaemonMode(1)
 	} else {
-		defaultIndexer = indexer.NewIndexer(graviton_backend, nil, "gravdb", nil, int64(1), derodendpoint, "daemon", false, false, true, sf_scid_exclusions)
+		defaultIndexer = indexer.NewIndexer(graviton_backend, "gravdb", nil, int64(1), derodendpoint, "daemon", false, false, true, sf_scid_exclusions)
 		defaultIndexer.StartDaemonMode(1)
 	}
 
@@ -299,20 +303,11 @@ func runGnomonIndexer(derodendpoint string, gnomonendpoint string, search_filter
 
 		if i == 0 {
 			// If we can get the SC and searchfilter is "" (get all), contains is true. Otherwise evaluate code against searchfilter
-			if len(search_filter) == 0 {
+			if search_filter.Empty() {
 				contains = true
 			} else {
 				_, code, _, _ = defaultIndexer.RPC.GetSCVariables(v.SCID, defaultIndexer.ChainHeight, nil, nil, nil, true)
-				// Ensure scCode is not blank (e.g. an invalid scid)
-				if code != "" {
-					for _, sfv := range search_filter {
-						contains = strings.Contains(code, sfv)
-						if contains {
-							// Break b/c we want to ensure contains remains true. Only care if it matches at least 1 case
-							break
-						}
-					}
-				}
+				contains = search_filter.Match(code)
 			}
 
 			if contains {
@@ -0,0 +1,30 @@
+package indexer
+
+import (
+	"github.com/civilware/Gnomon/storage"
+	"github.com/civilware/Gnomon/structures"
+)
+
+// Address activity roles
+const (
+	AddressRoleInstaller  = "installer"
+	AddressRoleInvoker    = "invoker"
+	AddressRoleRingMember = "ringmember"
+)
+
+// Records addr's interaction with scid to the address activity index. Anonymous (ringsize > 2) signers come through as blank and are not recorded
+func (indexer *Indexer) indexAddressActivity(addr string, scid string, txid string, topoheight int64, role string, wb *storage.WriteBatch) (err error) {
+	if addr == "" {
+		return
+	}
+
+	activity := &structures.AddressActivity{Scid: scid, Txid: txid, Height: topoheight, Role: role}
+
+	return indexer.stageWrite(wb, func(s storage.Storage) error {
+		_, err := s.StoreAddressActivity(addr, activity)
+		if err != nil {
+			logger.Errorf("[indexAddressActivity] ERR - storing address activity for '%v': %v", addr, err)
+		}
+		return err
+	})
+}
@@ -0,0 +1,77 @@
+package indexer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/civilware/Gnomon/storage"
+	"github.com/civilware/Gnomon/structures"
+	"github.com/deroproject/derohe/cryptography/crypto"
+)
+
+// Parses invoke arg index definitions of the form <scid>:<argname>[,<argname>...] into scid -> arg names
+func ParseSCArgIndex(defs []string) (argindex map[string][]string, err error) {
+	argindex = make(map[string][]string)
+	for _, def := range defs {
+		def = strings.TrimSpace(def)
+		if def == "" {
+			continue
+		}
+
+		split := strings.SplitN(def, ":", 2)
+		if len(split) != 2 || split[1] == "" {
+			return nil, fmt.Errorf("sc arg index '%v' should be of the form <scid>:<argname>[,<argname>...]", def)
+		}
+
+		scid := strings.TrimSpace(split[0])
+		if len(scid) != 64 || crypto.HashHexToHash(scid) == (crypto.Hash{}) {
+			return nil, fmt.Errorf("sc arg index '%v' has an invalid scid", def)
+		}
+
+		for _, name := range strings.Split(split[1], ",") {
+			name = strings.TrimSpace(name)
+			if name != "" && !scidExist(argindex[scid], name) {
+				argindex[scid] = append(argindex[scid], name)
+			}
+		}
+	}
+
+	return
+}
+
+// Builds the arg indexes of indexer.ArgIndex that are not yet kept from the invokes already stored, so indexes defined after a scid was indexed still cover all of its invokes
+func (indexer *Indexer) backfillArgIndex() {
+	for scid, names := range indexer.ArgIndex {
+		indexed := indexer.Backend.GetSCIDIndexedArgs(scid)
+		for _, name := range names {
+			if scidExist(indexed, name) {
+				continue
+			}
+
+			name := name
+			scid := scid
+			err := indexer.Writer.Write(func(s storage.Storage) error {
+				_, err := s.StoreSCIDArgIndex(scid, name, s.GetAllSCIDInvokeDetails(scid))
+				return err
+			})
+			if err != nil {
+				logger.Errorf("[backfillArgIndex] ERR - indexing arg '%v' of '%v': %v", name, scid, err)
+				continue
+			}
+			logger.Printf("[backfillArgIndex] Indexed arg '%v' of '%v'", name, scid)
+		}
+	}
+}
+
+// Adds an invoke to the arg indexes defined for its scid. Called within a write
+func (indexer *Indexer) storeArgIndex(s storage.Storage, invokedetails *structures.SCTXParse) (err error) {
+	for _, name := range indexer.ArgIndex[invokedetails.Scid] {
+		_, err = s.StoreSCIDArgIndex(invokedetails.Scid, name, []*structures.SCTXParse{invokedetails})
+		if err != nil {
+			logger.Errorf("[storeArgIndex] ERR - indexing arg '%v' of '%v': %v", name, invokedetails.Scid, err)
+			return
+		}
+	}
+
+	return
+}
@@ -0,0 +1,139 @@
+package indexer
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/civilware/Gnomon/structures"
+)
+
+// Token/NFA standards recognized by ClassifyAsset. G45 SCs are stored with the standard defined in their 'type' variable (G45-NFT, G45-AT, G45-FAT, G45-C etc.)
+const (
+	AssetStandardG45   = "G45"
+	AssetStandardNFA   = "ART-NFA"
+	AssetStandardToken = "TOKEN"
+)
+
+// A SC minting its own asset, e.g. SEND_ASSET_TO_ADDRESS(SIGNER(), 1000, SCID())
+var token_mint_regex = regexp.MustCompile(`SEND_ASSET_TO_ADDRESS\([^\n]*SCID\(\)`)
+
+// Recognizes token/NFA standards from the code and variables of a SC and pulls the standard's metadata out of the variables. Returns nil if scid is not a recognized standard
+func ClassifyAsset(scid string, code string, variables []*structures.SCIDVariable, height int64) (asset *structures.SCAsset) {
+	vars := assetVariables(variables)
+
+	switch {
+	case strings.HasPrefix(vars.str("type"), AssetStandardG45+"-"):
+		// G45 keeps its metadata as a (json) string, with the supply being defined by the asset type
+		asset = &structures.SCAsset{
+			Standard:   vars.str("type"),
+			Owner:      vars.str("owner"),
+			Collection: vars.str("collection"),
+			Supply:     vars.num("maxSupply", "totalSupply", "supply"),
+		}
+		if asset.Standard == AssetStandardG45+"-NFT" && asset.Supply == 0 {
+			asset.Supply = 1
+		}
+		if asset.Owner == "" {
+			asset.Owner = vars.str("minter")
+		}
+
+		var metadata map[string]interface{}
+		if err := json.Unmarshal([]byte(vars.str("metadata")), &metadata); err == nil {
+			asset.Name = metadataStr(metadata, "name")
+			asset.Symbol = metadataStr(metadata, "symbol")
+			asset.FileURL = metadataStr(metadata, "file", "fileURL", "image")
+		}
+	case vars.has("nameHdr") && vars.has("typeHdr") && vars.has("fileURL"), strings.Contains(code, AssetStandardNFA+"-"):
+		// Artificer NFAs are a single asset, metadata is in the header variables
+		asset = &structures.SCAsset{
+			Standard:   AssetStandardNFA,
+			Name:       vars.str("nameHdr"),
+			Supply:     1,
+			Owner:      vars.str("owner"),
+			Collection: vars.str("collection"),
+			FileURL:    vars.str("fileURL"),
+		}
+	case token_mint_regex.MatchString(code):
+		asset = &structures.SCAsset{
+			Standard: AssetStandardToken,
+			Name:     vars.str("name", "nameHdr"),
+			Symbol:   vars.str("symbol", "ticker"),
+			Supply:   vars.num("totalSupply", "totalsupply", "maxSupply", "supply"),
+			Owner:    vars.str("owner"),
+		}
+	default:
+		return nil
+	}
+
+	asset.Scid = scid
+	asset.Height = height
+
+	return
+}
+
+// Returns true if the asset details, other than the height they were taken at, differ
+func assetChanged(prev *structures.SCAsset, curr *structures.SCAsset) bool {
+	if prev == nil || curr == nil {
+		return prev != curr
+	}
+
+	p := *prev
+	p.Height = curr.Height
+
+	return p != *curr
+}
+
+type assetVars map[string]interface{}
+
+// Variables by (string) key, assets do not keep their metadata under uint64 keys
+func assetVariables(variables []*structures.SCIDVariable) assetVars {
+	vars := make(assetVars)
+	for _, v := range variables {
+		if k, ok := v.Key.(string); ok {
+			vars[k] = v.Value
+		}
+	}
+
+	return vars
+}
+
+func (vars assetVars) has(key string) bool {
+	_, ok := vars[key]
+	return ok
+}
+
+// Returns the first of keys that has a string value
+func (vars assetVars) str(keys ...string) string {
+	for _, k := range keys {
+		if s, ok := vars[k].(string); ok {
+			return s
+		}
+	}
+
+	return ""
+}
+
+// Returns the first of keys that has a uint64 value
+func (vars assetVars) num(keys ...string) uint64 {
+	for _, k := range keys {
+		switch cval := vars[k].(type) {
+		case uint64:
+			return cval
+		case float64:
+			return uint64(cval)
+		}
+	}
+
+	return 0
+}
+
+func metadataStr(metadata map[string]interface{}, keys ...string) string {
+	for _, k := range keys {
+		if s, ok := metadata[k].(string); ok {
+			return s
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,166 @@
+package indexer
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/civilware/Gnomon/storage"
+	"github.com/civilware/Gnomon/structures"
+)
+
+type EventType int
+
+const (
+	EventSCInstalled EventType = iota
+	EventSCInvoked
+	EventSCVariablesChanged
+	EventInvalidDeploy
+	EventHeightIndexed
+)
+
+// Event is passed to subscribers once the data it describes has been committed to the db
+type Event struct {
+	Type       EventType
+	Topoheight int64
+	SCID       string
+	SCTX       *structures.SCTXParse      // EventSCInstalled, EventSCInvoked
+	Variables  []*structures.SCIDVariable // EventSCInstalled (initial variables), EventSCVariablesChanged (DiffSCIDVariables diffset)
+	Fees       uint64                     // EventInvalidDeploy
+}
+
+// Subscription receives events on Events in block order until Unsubscribe() is called or the indexer is closed
+type Subscription struct {
+	Events   <-chan *Event
+	events   chan *Event
+	types    map[EventType]bool
+	blocking bool
+	dropped  uint64
+	done     chan struct{}
+	once     sync.Once
+	indexer  *Indexer
+}
+
+type eventHub struct {
+	subs []*Subscription
+	sync.RWMutex
+}
+
+func (t EventType) String() string {
+	switch t {
+	case EventSCInstalled:
+		return "scinstalled"
+	case EventSCInvoked:
+		return "scinvoked"
+	case EventSCVariablesChanged:
+		return "scvariableschanged"
+	case EventInvalidDeploy:
+		return "invaliddeploy"
+	case EventHeightIndexed:
+		return "heightindexed"
+	default:
+		return "unknown"
+	}
+}
+
+// Subscribes to the given event types, or all of them if none are defined. buffer is the number of events that can be queued for the subscriber.
+// If blocking, indexing waits on the subscriber when its buffer is full (nothing is missed). Otherwise events are dropped when the buffer is full and counted in Dropped()
+func (indexer *Indexer) Subscribe(buffer int, blocking bool, types ...EventType) *Subscription {
+	if buffer < 0 {
+		buffer = 0
+	}
+
+	events := make(chan *Event, buffer)
+	sub := &Subscription{
+		Events:   events,
+		events:   events,
+		types:    make(map[EventType]bool),
+		blocking: blocking,
+		done:     make(chan struct{}),
+		indexer:  indexer,
+	}
+	for _, t := range types {
+		sub.types[t] = true
+	}
+
+	indexer.events.Lock()
+	indexer.events.subs = append(indexer.events.subs, sub)
+	indexer.events.Unlock()
+
+	return sub
+}
+
+// Stops the subscription and closes its Events channel
+func (sub *Subscription) Unsubscribe() {
+	sub.once.Do(func() {
+		// Closing done first lets any publish blocked on this subscriber move on, so the lock below can be had
+		close(sub.done)
+
+		hub := &sub.indexer.events
+		hub.Lock()
+		for i, v := range hub.subs {
+			if v == sub {
+				hub.subs = append(hub.subs[:i], hub.subs[i+1:]...)
+				break
+			}
+		}
+		close(sub.events)
+		hub.Unlock()
+	})
+}
+
+// Returns the number of events dropped because the subscriber's buffer was full (non-blocking subscriptions only)
+func (sub *Subscription) Dropped() uint64 {
+	return atomic.LoadUint64(&sub.dropped)
+}
+
+// Passes ev to each subscriber of its type
+func (indexer *Indexer) publish(ev *Event) {
+	indexer.events.RLock()
+	defer indexer.events.RUnlock()
+
+	for _, sub := range indexer.events.subs {
+		if len(sub.types) > 0 && !sub.types[ev.Type] {
+			continue
+		}
+
+		if sub.blocking {
+			select {
+			case sub.events <- ev:
+			case <-sub.done:
+			}
+		} else {
+			select {
+			case sub.events <- ev:
+			case <-sub.done:
+			default:
+				atomic.AddUint64(&sub.dropped, 1)
+			}
+		}
+	}
+}
+
+// Publishes the event built by fn once wb has been committed. If wb is nil, it is published right away. fn can return nil if there ends up being nothing to publish
+func (indexer *Indexer) stageEvent(wb *storage.WriteBatch, fn func() *Event) {
+	publish := func() {
+		if ev := fn(); ev != nil {
+			indexer.publish(ev)
+		}
+	}
+
+	if wb == nil {
+		publish()
+		return
+	}
+	wb.OnCommit(publish)
+}
+
+// Ends all subscriptions
+func (indexer *Indexer) closeSubscriptions() {
+	indexer.events.RLock()
+	subs := append([]*Subscription(nil), indexer.events.subs...)
+	indexer.events.RUnlock()
+
+	for _, sub := range subs {
+		sub.Unsubscribe()
+	}
+}
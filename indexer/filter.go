@@ -0,0 +1,240 @@
+package indexer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/deroproject/derohe/dvm"
+)
+
+// SearchFilter decides which SCs get indexed, based on their code. Each search filter entry is an alternative, the SC is matched if any of them match.
+//
+// An entry is either a plain substring of the SC code (as search filters have always been), or an expression of terms joined with && and ||, && binding tighter than ||. A term is one of:
+//
+//	code:<substring>     the SC code contains substring
+//	re:<regex>           the SC code matches regex
+//	func:<signature>     the SC has a function matching signature, e.g. func:InputStr(input String, varname String) Uint64 or just func:InputStr for any function by that name
+//
+// and may be negated with a leading !. Functions are matched on the parsed SC (dvm.ParseSmartContract) rather than its text, so spacing/casing of the code does not matter.
+// If any part of an entry is not one of the terms above, the whole entry is taken as a plain substring. This keeps existing filters that happen to contain && or || (e.g. IF conditions) working as before
+type SearchFilter struct {
+	groups [][]*filterTerm // OR of ANDs
+	funcs  bool            // any func: terms, so the code needs to be parsed
+}
+
+type filterTerm struct {
+	negate bool
+	substr string
+	re     *regexp.Regexp
+	fn     *funcSignature
+}
+
+type funcSignature struct {
+	name      string
+	params    []dvm.Variable
+	hasParams bool
+	ret       dvm.Vtype
+	hasRet    bool
+}
+
+// Defines the term prefixes of a structured search filter entry
+const (
+	filter_code_prefix  = "code:"
+	filter_regex_prefix = "re:"
+	filter_func_prefix  = "func:"
+)
+
+var func_signature_regex = regexp.MustCompile(`^(?i:function\s+)?([A-Za-z_][A-Za-z0-9_]*)\s*(?:\(([^)]*)\)\s*([A-Za-z0-9]*))?$`)
+
+// Compiles the search filter entries. No entries matches every SC
+func NewSearchFilter(filters []string) (sf *SearchFilter, err error) {
+	sf = &SearchFilter{}
+
+	for _, entry := range filters {
+		if !isStructuredFilter(entry) {
+			sf.groups = append(sf.groups, []*filterTerm{{substr: entry}})
+			continue
+		}
+
+		for _, alt := range strings.Split(entry, "||") {
+			var group []*filterTerm
+			for _, t := range strings.Split(alt, "&&") {
+				var term *filterTerm
+				term, err = parseFilterTerm(strings.TrimSpace(t))
+				if err != nil {
+					return nil, fmt.Errorf("search filter '%v': %v", entry, err)
+				}
+				if term.fn != nil {
+					sf.funcs = true
+				}
+				group = append(group, term)
+			}
+			sf.groups = append(sf.groups, group)
+		}
+	}
+
+	return
+}
+
+// Returns true if there is no filter, i.e. every SC is matched
+func (sf *SearchFilter) Empty() bool {
+	return sf == nil || len(sf.groups) == 0
+}
+
+// Returns true if the code matches the search filter. Blank code (e.g. an invalid scid) never matches, unless there is no filter at all
+func (sf *SearchFilter) Match(code string) bool {
+	if sf.Empty() {
+		return true
+	}
+
+	if code == "" {
+		return false
+	}
+
+	// Only parse the SC once, and only if a function needs to be matched
+	var functions map[string]dvm.Function
+	if sf.funcs {
+		if SC, _, err := dvm.ParseSmartContract(code); err == nil {
+			functions = SC.Functions
+		}
+	}
+
+	for _, group := range sf.groups {
+		matched := true
+		for _, term := range group {
+			if term.match(code, functions) == term.negate {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns true if the term (disregarding negation) is satisfied
+func (term *filterTerm) match(code string, functions map[string]dvm.Function) bool {
+	switch {
+	case term.re != nil:
+		return term.re.MatchString(code)
+	case term.fn != nil:
+		for _, f := range functions {
+			if term.fn.match(f) {
+				return true
+			}
+		}
+		return false
+	default:
+		return strings.Contains(code, term.substr)
+	}
+}
+
+func (sig *funcSignature) match(f dvm.Function) bool {
+	if f.Name != sig.name {
+		return false
+	}
+
+	if sig.hasParams {
+		if len(f.Params) != len(sig.params) {
+			return false
+		}
+		for i, p := range sig.params {
+			if f.Params[i].Name != p.Name || f.Params[i].Type != p.Type {
+				return false
+			}
+		}
+	}
+
+	if sig.hasRet && f.ReturnValue.Type != sig.ret {
+		return false
+	}
+
+	return true
+}
+
+// An entry is structured if every one of its terms has a known prefix
+func isStructuredFilter(entry string) bool {
+	for _, alt := range strings.Split(entry, "||") {
+		for _, t := range strings.Split(alt, "&&") {
+			t = strings.TrimPrefix(strings.TrimSpace(t), "!")
+			if !strings.HasPrefix(t, filter_code_prefix) && !strings.HasPrefix(t, filter_regex_prefix) && !strings.HasPrefix(t, filter_func_prefix) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func parseFilterTerm(t string) (term *filterTerm, err error) {
+	term = &filterTerm{}
+	if strings.HasPrefix(t, "!") {
+		term.negate = true
+		t = t[1:]
+	}
+
+	switch {
+	case strings.HasPrefix(t, filter_code_prefix):
+		term.substr = strings.TrimPrefix(t, filter_code_prefix)
+	case strings.HasPrefix(t, filter_regex_prefix):
+		term.re, err = regexp.Compile(strings.TrimPrefix(t, filter_regex_prefix))
+	case strings.HasPrefix(t, filter_func_prefix):
+		term.fn, err = parseFuncSignature(strings.TrimSpace(strings.TrimPrefix(t, filter_func_prefix)))
+	}
+
+	return
+}
+
+// Parses a DVM function signature such as 'InputStr(input String, varname String) Uint64'. The leading 'Function' keyword and the return type are optional, as are the params altogether
+func parseFuncSignature(s string) (sig *funcSignature, err error) {
+	m := func_signature_regex.FindStringSubmatch(s)
+	if m == nil {
+		return nil, fmt.Errorf("invalid function signature '%v'", s)
+	}
+
+	sig = &funcSignature{name: m[1]}
+
+	// m[2] is only empty for both 'Name' and 'Name()', so look for the paren to tell them apart
+	if strings.Contains(s, "(") {
+		sig.hasParams = true
+		if strings.TrimSpace(m[2]) != "" {
+			for _, p := range strings.Split(m[2], ",") {
+				fields := strings.Fields(p)
+				if len(fields) != 2 {
+					return nil, fmt.Errorf("invalid function param '%v' in '%v'", strings.TrimSpace(p), s)
+				}
+				vtype := filterVtype(fields[1])
+				if vtype == dvm.Invalid {
+					return nil, fmt.Errorf("invalid function param type '%v' in '%v'", fields[1], s)
+				}
+				sig.params = append(sig.params, dvm.Variable{Name: fields[0], Type: vtype})
+			}
+		}
+	}
+
+	if m[3] != "" {
+		sig.hasRet = true
+		sig.ret = filterVtype(m[3])
+		if sig.ret == dvm.Invalid {
+			return nil, fmt.Errorf("invalid function return type '%v' in '%v'", m[3], s)
+		}
+	}
+
+	return
+}
+
+// Same as the dvm, types are not case sensitive
+func filterVtype(name string) dvm.Vtype {
+	switch strings.ToLower(name) {
+	case "uint64":
+		return dvm.Uint64
+	case "string":
+		return dvm.String
+	}
+
+	return dvm.Invalid
+}
@@ -0,0 +1,82 @@
+package indexer
+
+import "testing"
+
+const filter_test_code = `Function InitializePrivate() Uint64
+10 STORE("owner", SIGNER())
+20 RETURN 0
+End Function
+
+Function InputStr(input String, varname String) Uint64
+10 IF SIGNER() != LOAD("owner") THEN GOTO 30
+20 STORE(varname, input)
+30 RETURN 0
+End Function
+`
+
+func TestSearchFilterMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		filters []string
+		code    string
+		want    bool
+	}{
+		{name: "no filter", filters: nil, code: filter_test_code, want: true},
+		{name: "no filter blank code", filters: nil, code: "", want: true},
+		{name: "blank code", filters: []string{"STORE"}, code: "", want: false},
+		{name: "substring", filters: []string{`STORE("owner"`}, code: filter_test_code, want: true},
+		{name: "substring miss", filters: []string{"SEND_DERO_TO_ADDRESS"}, code: filter_test_code, want: false},
+		{name: "substring with operators", filters: []string{`IF SIGNER() != LOAD("owner") THEN`}, code: filter_test_code, want: true},
+		{name: "any entry", filters: []string{"SEND_DERO_TO_ADDRESS", "InputStr"}, code: filter_test_code, want: true},
+		{name: "code", filters: []string{"code:SIGNER()"}, code: filter_test_code, want: true},
+		{name: "not code", filters: []string{"!code:SIGNER()"}, code: filter_test_code, want: false},
+		{name: "re", filters: []string{`re:STORE\("owner",\s*SIGNER\(\)\)`}, code: filter_test_code, want: true},
+		{name: "re miss", filters: []string{`re:^Function Initialize\(`}, code: filter_test_code, want: false},
+		{name: "func name", filters: []string{"func:InputStr"}, code: filter_test_code, want: true},
+		{name: "func signature", filters: []string{"func:InputStr(input String, varname String) Uint64"}, code: filter_test_code, want: true},
+		{name: "func signature keyword and case", filters: []string{"func:Function InputStr(input string, varname string) uint64"}, code: filter_test_code, want: true},
+		{name: "func wrong param type", filters: []string{"func:InputStr(input Uint64, varname String)"}, code: filter_test_code, want: false},
+		{name: "func wrong return", filters: []string{"func:InputStr(input String, varname String) String"}, code: filter_test_code, want: false},
+		{name: "func no params", filters: []string{"func:InputStr()"}, code: filter_test_code, want: false},
+		{name: "func missing", filters: []string{"func:Withdraw"}, code: filter_test_code, want: false},
+		{name: "and", filters: []string{"func:InputStr && code:SIGNER()"}, code: filter_test_code, want: true},
+		{name: "and not", filters: []string{"func:InputStr && !func:InitializePrivate"}, code: filter_test_code, want: false},
+		{name: "or", filters: []string{"func:Withdraw || !code:SEND_DERO_TO_ADDRESS"}, code: filter_test_code, want: true},
+		{name: "and binds tighter", filters: []string{"func:Withdraw && code:STORE || func:Deposit"}, code: filter_test_code, want: false},
+		{name: "unparsable code", filters: []string{"func:InputStr"}, code: "not a smart contract", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sf, err := NewSearchFilter(tt.filters)
+			if err != nil {
+				t.Fatalf("NewSearchFilter(%q) err = %v", tt.filters, err)
+			}
+			if got := sf.Match(tt.code); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewSearchFilterErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		filters []string
+	}{
+		{name: "bad regex", filters: []string{"re:("}},
+		{name: "bad signature", filters: []string{"func:1InputStr"}},
+		{name: "bad param", filters: []string{"func:InputStr(input)"}},
+		{name: "bad param type", filters: []string{"func:InputStr(input Blob)"}},
+		{name: "bad return type", filters: []string{"func:InputStr() Blob"}},
+		{name: "bad term in expression", filters: []string{"code:STORE && re:["}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewSearchFilter(tt.filters); err == nil {
+				t.Errorf("NewSearchFilter(%q) err = nil, want an error", tt.filters)
+			}
+		})
+	}
+}
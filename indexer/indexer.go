@@ -1,10 +1,10 @@
 package indexer
 
 import (
-	"context"
 	"encoding/hex"
 	"encoding/json"
 	"encoding/pem"
+	"errors"
 	"fmt"
 	"io"
 	"math/big"
@@ -16,7 +16,6 @@ import (
 	"time"
 
 	"github.com/civilware/Gnomon/mbllookup"
-	"github.com/civilware/Gnomon/rwc"
 	"github.com/civilware/Gnomon/storage"
 	"github.com/civilware/Gnomon/structures"
 
@@ -27,26 +26,19 @@ import (
 	"github.com/deroproject/derohe/transaction"
 	"github.com/deroproject/graviton"
 
-	"github.com/creachadair/jrpc2"
-	"github.com/creachadair/jrpc2/channel"
-	"github.com/gorilla/websocket"
-
 	"github.com/sirupsen/logrus"
 	prefixed "github.com/x-cray/logrus-prefixed-formatter"
 )
 
-type Client struct {
-	WS  *websocket.Conn
-	RPC *jrpc2.Client
-	sync.RWMutex
-}
-
 type SCIDToIndexStage struct {
-	scid     string
-	fsi      *structures.FastSyncImport
-	scVars   []*structures.SCIDVariable
-	scCode   string
-	contains bool
+	scid       string
+	fsi        *structures.FastSyncImport
+	scVars     []*structures.SCIDVariable
+	scBalances map[string]uint64
+	scCode     string
+	templateID string
+	asset      *structures.SCAsset
+	contains   bool
 }
 
 type Indexer struct {
@@ -54,39 +46,59 @@ type Indexer struct {
 	ChainHeight       int64
 	SearchFilter      []string
 	SFSCIDExclusion   []string
-	GravDBBackend     *storage.GravitonStore
-	BBSBackend        *storage.BboltStore
+	Backend           storage.Storage
 	DBType            string
 	Closing           bool
 	RPC               *Client
 	Endpoint          string
+	WalletEndpoint    string
+	WalletRPC         *Client
 	RunMode           string
 	MBLLookup         bool
+	RegTxLookup       bool
 	ValidatedSCs      []string
 	CloseOnDisconnect bool
 	Fastsync          bool
+	Writer            *storage.Writer
+	Mempool           *Mempool
+	Templates         *TemplateMatcher    // nil unless sc templates are defined
+	ArgIndex          map[string][]string // scid -> invoke arg names a secondary index is kept on, nil unless sc arg indexes are defined
+	Retention         *Retention          // nil unless retention windows are defined
+	searchFilter      *SearchFilter
+	events            eventHub
 	sync.RWMutex
 }
 
-// Defines the number of blocks to jump when testing pruned nodes.
-const block_jump = int64(10000)
+// Defines the max number of blocks to walk back when looking for the fork point of a chain reorg.
+const max_reorg_depth = int64(100)
+
+// Returned (wrapped) by checkReorg when the fork point of a reorg could not be confirmed within max_reorg_depth. The index no longer matches the chain and the indexer is closed, a resync is required
+var ErrReorgUnresolved = errors.New("reorg fork point not found")
 
 var Connected bool = false
 
 // local logger
 var logger *logrus.Entry
 
-func NewIndexer(Graviton_backend *storage.GravitonStore, Bbs_backend *storage.BboltStore, dbtype string, search_filter []string, last_indexedheight int64, endpoint string, runmode string, mbllookup bool, closeondisconnect bool, fastsync bool, sfscidexclusion []string) *Indexer {
+func NewIndexer(backend storage.Storage, dbtype string, search_filter []string, last_indexedheight int64, endpoint string, runmode string, mbllookup bool, closeondisconnect bool, fastsync bool, sfscidexclusion []string) *Indexer {
 	logger = structures.Logger.WithFields(logrus.Fields{})
 
+	// Filters are expected to have been checked with NewSearchFilter beforehand. Should one not compile, fall back to no filter rather than indexing nothing
+	searchFilter, err := NewSearchFilter(search_filter)
+	if err != nil {
+		logger.Errorf("[NewIndexer] ERR - %v. Indexing with no search filter", err)
+	}
+
 	return &Indexer{
 		LastIndexedHeight: last_indexedheight,
 		SearchFilter:      search_filter,
+		searchFilter:      searchFilter,
 		SFSCIDExclusion:   sfscidexclusion,
-		GravDBBackend:     Graviton_backend,
-		BBSBackend:        Bbs_backend,
+		Backend:           backend,
+		Writer:            storage.NewWriter(backend),
 		DBType:            dbtype,
 		RPC:               &Client{},
+		WalletRPC:         &Client{},
 		Endpoint:          endpoint,
 		RunMode:           runmode,
 		MBLLookup:         mbllookup,
@@ -131,18 +143,9 @@ func (indexer *Indexer) StartDaemonMode(blockParallelNum int) {
 		break
 	}
 
-	var storedindex int64
-	switch indexer.DBType {
-	case "gravdb":
-		storedindex, err = indexer.GravDBBackend.GetLastIndexHeight()
-		if err != nil {
-			logger.Fatalf("[gravdb-StartDaemonMode] Could not get last index height - %v", err)
-		}
-	case "boltdb":
-		storedindex, err = indexer.BBSBackend.GetLastIndexHeight()
-		if err != nil {
-			logger.Fatalf("[bbs-StartDaemonMode] Could not get last index height - %v", err)
-		}
+	storedindex, err := indexer.Backend.GetLastIndexHeight()
+	if err != nil {
+		logger.Fatalf("[StartDaemonMode] Could not get last index height - %v", err)
 	}
 
 	// If storedindex returns 0, first opening, and fastsync is enabled set index to current chain height
@@ -155,27 +158,13 @@ func (indexer *Indexer) StartDaemonMode(blockParallelNum int) {
 	}
 
 	// We can also assume this check to mean we have stored validated SCs potentially. TODO: Do we just get stored SCs regardless of sync cycle?
-	pre_validatedSCIDs := make(map[string]string)
-	switch indexer.DBType {
-	case "gravdb":
-		pre_validatedSCIDs = indexer.GravDBBackend.GetAllOwnersAndSCIDs()
-	case "boltdb":
-		pre_validatedSCIDs = indexer.BBSBackend.GetAllOwnersAndSCIDs()
-	}
-
-	if len(pre_validatedSCIDs) > 0 {
-		logger.Printf("[StartDaemonMode] Appending pre-validated SCIDs from store to memory.")
+	indexer.loadValidatedSCs()
 
-		for k := range pre_validatedSCIDs {
-			if scidExist(indexer.SFSCIDExclusion, k) {
-				logger.Debugf("[StartDaemonMode] Not appending pre-validated SCID '%s' as it resides within SFSCIDExclusion - '%v'.", k, indexer.SFSCIDExclusion)
-				continue
-			}
-			indexer.Lock()
-			indexer.ValidatedSCs = append(indexer.ValidatedSCs, k)
-			indexer.Unlock()
-		}
-	}
+	indexer.migrateSCIDVariables()
+	indexer.backfillArgIndex()
+	indexer.backfillTemplates()
+	indexer.indexBurnTxs()
+	indexer.indexNameAddresses()
 
 	for _, vi := range structures.Hardcoded_SCIDS {
 		if scidExist(indexer.ValidatedSCs, vi) {
@@ -183,112 +172,95 @@ func (indexer *Indexer) StartDaemonMode(blockParallelNum int) {
 			continue
 		}
 
-		if scidExist(indexer.SFSCIDExclusion, vi) {
+		// The name service index is kept regardless of the search filter and sfscidexclusion, so its names are seeded even when the scid itself is not indexed
+		nameService := vi == structures.NAMESERVICE_SCID && !(indexer.RunMode == "asset")
+
+		excluded := scidExist(indexer.SFSCIDExclusion, vi)
+		if excluded {
 			logger.Debugf("[StartDaemonMode] Not appending hardcoded SCID '%s' as it resides within SFSCIDExclusion - '%v'.", vi, indexer.SFSCIDExclusion)
-			continue
+			if !nameService {
+				continue
+			}
 		}
 
 		scVars, scCode, _, _ := indexer.RPC.GetSCVariables(vi, storedindex, nil, nil, nil, false)
 
-		var contains bool
-
 		// If we can get the SC and searchfilter is "" (get all), contains is true. Otherwise evaluate code against searchfilter
-		if len(indexer.SearchFilter) == 0 {
-			contains = true
-		} else {
-			// Ensure scCode is not blank (e.g. an invalid scid)
-			if scCode != "" {
-				for _, sfv := range indexer.SearchFilter {
-					contains = strings.Contains(scCode, sfv)
-					if contains {
-						// Break b/c we want to ensure contains remains true. Only care if it matches at least 1 case
-						break
-					}
-				}
+		contains := !excluded && indexer.searchFilter.Match(scCode)
+
+		if !contains && nameService && len(scVars) > 0 {
+			err = indexer.Writer.Write(func(s storage.Storage) error {
+				return seedNameRecords(s, scVars, storedindex)
+			})
+			if err != nil {
+				logger.Errorf("[StartDaemonMode-hardcodedscids] ERR - storing name records: %v", err)
 			}
+			continue
 		}
 
 		if contains {
 			//logger.Debugf("[AddSCIDToIndex] Hardcoded SCID matches search filter. Adding SCID %v", vi)
-			indexer.Lock()
-			indexer.ValidatedSCs = append(indexer.ValidatedSCs, vi)
-			indexer.Unlock()
-			writeWait, _ := time.ParseDuration("20ms")
-			switch indexer.DBType {
-			case "gravdb":
-				for indexer.GravDBBackend.Writing == 1 {
-					if indexer.Closing {
-						return
-					}
-					//logger.Debugf("[Indexer-NewIndexer] GravitonDB is writing... sleeping for %v...", writeWait)
-					time.Sleep(writeWait)
-				}
-				indexer.GravDBBackend.Writing = 1
-				var ctrees []*graviton.Tree
-				sotree, sochanges, err := indexer.GravDBBackend.StoreOwner(vi, "", true)
+			templateID := indexer.Templates.Match(scCode)
+			asset := ClassifyAsset(vi, scCode, scVars, storedindex)
+			// Any failed store aborts the batch, so the scid is only validated once all of its writes are committed
+			err = indexer.Writer.Write(func(s storage.Storage) error {
+				_, err := s.StoreOwner(vi, "")
 				if err != nil {
 					logger.Errorf("[StartDaemonMode-hardcodedscids] Error storing owner: %v", err)
-				} else {
-					if sochanges {
-						ctrees = append(ctrees, sotree)
+					return err
+				}
+				if templateID != "" {
+					_, err = s.StoreSCIDTemplate(vi, templateID)
+					if err != nil {
+						logger.Errorf("[StartDaemonMode-hardcodedscids] ERR - storing scid template: %v", err)
+						return err
 					}
 				}
 				// If scVarsStore length is greater than 0, we can assume there were diffs. Otherwise the varstores are equal and move on.
 				if len(scVars) > 0 {
-					svdtree, svdchanges, err := indexer.GravDBBackend.StoreSCIDVariableDetails(vi, scVars, storedindex, true)
+					_, err = s.StoreSCIDVariableDetails(vi, scVars, storedindex)
 					if err != nil {
 						logger.Errorf("[StartDaemonMode-hardcodedscids] ERR - storing scid variable details: %v", err)
-					} else {
-						if svdchanges {
-							ctrees = append(ctrees, svdtree)
-						}
+						return err
 					}
-					sihtree, sihchanges, err := indexer.GravDBBackend.StoreSCIDInteractionHeight(vi, storedindex, true)
+					_, err = s.StoreSCIDInteractionHeight(vi, storedindex)
 					if err != nil {
 						logger.Errorf("[StartDaemonMode-hardcodedscids] ERR - storing scid interaction height: %v", err)
-					} else {
-						if sihchanges {
-							ctrees = append(ctrees, sihtree)
-						}
+						return err
 					}
 				}
-				if len(ctrees) > 0 {
-					_, err := indexer.GravDBBackend.CommitTrees(ctrees)
+				if vi == structures.NAMESERVICE_SCID {
+					err = seedNameRecords(s, scVars, storedindex)
 					if err != nil {
-						logger.Errorf("[StartDaemonMode-hardcodedscids] ERR - committing trees: %v", err)
-					} else {
-						//logger.Debugf("[StartDaemonMode-hardcodedscids] DEBUG - cv [%v]", cv)
+						logger.Errorf("[StartDaemonMode-hardcodedscids] ERR - storing name records: %v", err)
+						return err
 					}
 				}
-				indexer.GravDBBackend.Writing = 0
-			case "boltdb":
-				for indexer.BBSBackend.Writing == 1 {
-					if indexer.Closing {
-						return
-					}
-					//logger.Debugf("[Indexer-StartDaemonMode-hardcodedscids] BoltDB is writing... sleeping for %v... writer %v...", writeWait, indexer.BBSBackend.Writer)
-					time.Sleep(writeWait)
-				}
-				indexer.BBSBackend.Writing = 1
-				//indexer.BBSBackend.Writer = "StartDaemonMode"
-				_, err := indexer.BBSBackend.StoreOwner(vi, "")
-				if err != nil {
-					logger.Errorf("[StartDaemonMode-hardcodedscids] Error storing owner: %v", err)
-				}
-				// If scVarsStore length is greater than 0, we can assume there were diffs. Otherwise the varstores are equal and move on.
-				if len(scVars) > 0 {
-					_, err = indexer.BBSBackend.StoreSCIDVariableDetails(vi, scVars, storedindex)
+				if asset != nil && assetChanged(s.GetSCAsset(vi), asset) {
+					_, err = s.StoreSCAsset(asset)
 					if err != nil {
-						logger.Errorf("[StartDaemonMode-hardcodedscids] ERR - storing scid variable details: %v", err)
+						logger.Errorf("[StartDaemonMode-hardcodedscids] ERR - storing scid asset: %v", err)
+						return err
 					}
-					_, err = indexer.BBSBackend.StoreSCIDInteractionHeight(vi, storedindex)
+				}
+				// Install txn is not known, so the code is kept from when it was first seen
+				if scCode != "" && s.GetSCIDCodeAtTopoheight(vi, storedindex) == nil {
+					_, err = s.StoreSCIDCode(vi, &structures.SCIDCode{Height: storedindex, Code: scCode})
 					if err != nil {
-						logger.Errorf("[StartDaemonMode-hardcodedscids] ERR - storing scid interaction height: %v", err)
+						logger.Errorf("[StartDaemonMode-hardcodedscids] ERR - storing scid code: %v", err)
+						return err
 					}
 				}
-				indexer.BBSBackend.Writing = 0
-				//indexer.BBSBackend.Writer = ""
+				return nil
+			})
+			if err != nil {
+				logger.Errorf("[StartDaemonMode-hardcodedscids] ERR - committing writes: %v", err)
+				continue
 			}
+
+			indexer.Lock()
+			indexer.ValidatedSCs = append(indexer.ValidatedSCs, vi)
+			indexer.Unlock()
 		}
 	}
 
@@ -298,13 +270,7 @@ func (indexer *Indexer) StartDaemonMode(blockParallelNum int) {
 		indexer.LastIndexedHeight = storedindex
 		indexer.Unlock()
 
-		var getinfo *structures.GetInfo
-		switch indexer.DBType {
-		case "gravdb":
-			getinfo = indexer.GravDBBackend.GetGetInfoDetails()
-		case "boltdb":
-			getinfo = indexer.BBSBackend.GetGetInfoDetails()
-		}
+		getinfo := indexer.Backend.GetGetInfoDetails()
 
 		// Only pull in gnomonsc data if fastsync is defined. TODO: Maybe extra flag for checking this on startup as well.
 		if getinfo != nil && indexer.Fastsync {
@@ -414,6 +380,19 @@ func (indexer *Indexer) StartDaemonMode(blockParallelNum int) {
 				break
 			}
 
+			// Check that the last indexed block is still on the daemon's chain, otherwise rollback to the fork point before continuing. Also ran ahead of the first batch, for reorgs that happened while stopped
+			err = indexer.checkReorg()
+			if errors.Is(err, ErrReorgUnresolved) {
+				// Index no longer matches the chain, so the indexer is closed rather than left serving stale data
+				logger.Errorf("[StartDaemonMode-mainFOR-checkReorg] ERROR - %v", err)
+				indexer.Close()
+				return
+			} else if err != nil {
+				logger.Errorf("[StartDaemonMode-mainFOR-checkReorg] ERROR - %v", err)
+				time.Sleep(1 * time.Second)
+				continue
+			}
+
 			if indexer.LastIndexedHeight >= indexer.ChainHeight {
 				time.Sleep(1 * time.Second)
 				continue
@@ -424,117 +403,28 @@ func (indexer *Indexer) StartDaemonMode(blockParallelNum int) {
 				_, err := indexer.RPC.getBlockHash(uint64(indexer.LastIndexedHeight))
 				if err != nil {
 					// Handle pruned nodes index errors... find height that they have blocks able to be indexed
-					if strings.Contains(err.Error(), "err occured empty block") || strings.Contains(err.Error(), "err occured file does not exist") {
-						currIndex := indexer.LastIndexedHeight
-						rewindIndex := int64(0)
-						for {
-							if indexer.Closing {
-								// If we do concurrent blocks in the future, this will need to move/be modified to be *after* all concurrent blocks are done incase exit etc.
-								writeWait, _ := time.ParseDuration("20ms")
-								switch indexer.DBType {
-								case "gravdb":
-									for indexer.GravDBBackend.Writing == 1 {
-										if indexer.Closing {
-											return
-										}
-										//logger.Debugf("[Indexer-NewIndexer] GravitonDB is writing... sleeping for %v...", writeWait)
-										time.Sleep(writeWait)
-									}
-									indexer.GravDBBackend.Writing = 1
-									indexer.GravDBBackend.StoreLastIndexHeight(currIndex, false)
-									indexer.GravDBBackend.Writing = 0
-								case "boltdb":
-									for indexer.BBSBackend.Writing == 1 {
-										if indexer.Closing {
-											return
-										}
-										//logger.Debugf("[Indexer-StartDaemonMode-StoreLastIndexHeight] BoltDB is writing... sleeping for %v... writer %v...", writeWait, indexer.BBSBackend.Writer)
-										time.Sleep(writeWait)
-									}
-									indexer.BBSBackend.Writing = 1
-									//indexer.BBSBackend.Writer = "StartDaemonMode"
-									indexer.BBSBackend.StoreLastIndexHeight(currIndex)
-									indexer.BBSBackend.Writing = 0
-									//indexer.BBSBackend.Writer = ""
-								}
-								// Break out on closing call
-								break
-							}
-							_, err = indexer.RPC.getBlockHash(uint64(currIndex))
-							if err != nil {
-								//if strings.Contains(err.Error(), "err occured empty block") {
-								//time.Sleep(200 * time.Millisecond)	// sleep for node spam, not *required* but can be useful for lesser nodes in brief catchup time.
-								// Increase block by 10 to not spam the daemon at every single block, but skip along a little bit to move faster/more less impact to node. This can be modified if required.
-								if (currIndex + block_jump) > indexer.ChainHeight {
-									currIndex = indexer.ChainHeight
-								} else {
-									currIndex += block_jump
-								}
-								// Should this be an err? We'll get this at least when pinpointing the pruned node states
-								logger.Errorf("GetBlock failed - checking %v", currIndex)
-								//}
-							} else {
-								// Self-contain and loop through at most 10 or X blocks
-								logger.Printf("GetBlock worked at %v", currIndex)
-								for {
-									if indexer.Closing {
-										// If we do concurrent blocks in the future, this will need to move/be modified to be *after* all concurrent blocks are done incase exit etc.
-										writeWait, _ := time.ParseDuration("20ms")
-										switch indexer.DBType {
-										case "gravdb":
-											for indexer.GravDBBackend.Writing == 1 {
-												if indexer.Closing {
-													return
-												}
-												//logger.Debugf("[Indexer-NewIndexer] GravitonDB is writing... sleeping for %v...", writeWait)
-												time.Sleep(writeWait)
-											}
-											indexer.GravDBBackend.Writing = 1
-											indexer.GravDBBackend.StoreLastIndexHeight(rewindIndex, false)
-											indexer.GravDBBackend.Writing = 0
-										case "boltdb":
-											for indexer.BBSBackend.Writing == 1 {
-												if indexer.Closing {
-													return
-												}
-												//logger.Debugf("[Indexer-StartDaemonMode-StoreLastIndexHeight] BoltDB is writing... sleeping for %v... writer %v...", writeWait, indexer.BBSBackend.Writer)
-												time.Sleep(writeWait)
-											}
-											indexer.BBSBackend.Writing = 1
-											//indexer.BBSBackend.Writer = "StartDaemonMode"
-											indexer.BBSBackend.StoreLastIndexHeight(rewindIndex)
-											indexer.BBSBackend.Writing = 0
-											//indexer.BBSBackend.Writer = ""
-										}
-
-										// Break out on closing call
-										break
-									}
-									if rewindIndex == 0 {
-										rewindIndex = currIndex - block_jump + 1
-										if rewindIndex < 0 {
-											rewindIndex = 1
-										}
-									} else {
-										logger.Printf("Checking GetBlock at %v", rewindIndex)
-										_, err = indexer.RPC.getBlockHash(uint64(rewindIndex))
-										if err != nil {
-											rewindIndex++
-											// TODO: If lowcpuram option defined, uncomment below to add w/ such clause
-											//time.Sleep(200 * time.Millisecond)	// sleep for node spam, not *required* but can be useful for lesser nodes in brief catchup time.
-										} else {
-											logger.Printf("GetBlock worked at %v - continuing as normal", rewindIndex+1)
-											// Break out, we found the earliest block detail
-											indexer.Lock()
-											indexer.LastIndexedHeight = rewindIndex + 1
-											indexer.Unlock()
-											break
-										}
-									}
+					if errors.Is(err, ErrRPCPruned) {
+						earliest, ferr := indexer.findEarliestHeight(indexer.LastIndexedHeight, indexer.ChainHeight)
+						if ferr == nil {
+							logger.Printf("[StartDaemonMode] Daemon is pruned below height %v - continuing from there", earliest)
+							ferr = indexer.Writer.Write(func(s storage.Storage) error {
+								_, err := s.StoreEarliestHeight(earliest)
+								if err != nil {
+									return err
 								}
-								break
-							}
+								_, err = s.StoreLastIndexHeight(earliest - 1)
+								return err
+							})
+						}
+						if ferr == nil {
+							indexer.Lock()
+							indexer.LastIndexedHeight = earliest - 1
+							indexer.Unlock()
+							// Height before earliest is pruned, so do not check it again
+							k++
+							continue
 						}
+						err = ferr
 					}
 
 					logger.Errorf("[mainFOR] ERROR - %v", err)
@@ -553,14 +443,16 @@ func (indexer *Indexer) StartDaemonMode(blockParallelNum int) {
 				}
 			}
 
-			var regTxCount int64
-			var burnTxCount int64
-			var normTxCount int64
 			var wg sync.WaitGroup
 			wg.Add(blockParallelNum)
 
 			var blsctxnsLock sync.RWMutex
 			var blIndexTxns []*structures.BlockTxns
+			var blFailed bool
+			blHashes := make(map[int64]string)
+
+			// All writes for this batch of blocks are staged here and committed together with the last indexed height, so the store is always consistent at a block boundary
+			wb := &storage.WriteBatch{}
 
 			for i := 1; i <= blockParallelNum; i++ {
 				go func(i int) {
@@ -573,17 +465,27 @@ func (indexer *Indexer) StartDaemonMode(blockParallelNum int) {
 					blid, err := indexer.RPC.getBlockHash(uint64(currBlHeight))
 					if err != nil {
 						logger.Errorf("[StartDaemonMode-mainFOR-getBlockHash] %v - ERROR - getBlockHash(%v) - %v", currBlHeight, uint64(currBlHeight), err)
+						blsctxnsLock.Lock()
+						blFailed = true
+						blsctxnsLock.Unlock()
 						wg.Done()
 						return
 					}
 
-					blockTxns, err := indexer.indexBlock(blid, currBlHeight)
+					blockTxns, err := indexer.indexBlock(blid, currBlHeight, wb)
 					if err != nil {
 						logger.Errorf("[StartDaemonMode-mainFOR-indexBlock] %v - ERROR - indexBlock(%v) - %v", currBlHeight, blid, err)
+						blsctxnsLock.Lock()
+						blFailed = true
+						blsctxnsLock.Unlock()
 						wg.Done()
 						return
 					}
 
+					blsctxnsLock.Lock()
+					blHashes[currBlHeight] = blid
+					blsctxnsLock.Unlock()
+
 					if len(blockTxns.Tx_hashes) > 0 {
 						blsctxnsLock.Lock()
 						blIndexTxns = append(blIndexTxns, blockTxns)
@@ -600,25 +502,44 @@ func (indexer *Indexer) StartDaemonMode(blockParallelNum int) {
 				break
 			}
 
+			// If any block of the batch could not be indexed, nothing is committed and the batch is tried again rather than skipping over the block
+			if blFailed {
+				time.Sleep(1 * time.Second)
+				continue
+			}
+
 			// Arrange blIndexTxns by height so processed linearly
 			sort.SliceStable(blIndexTxns, func(i, j int) bool {
 				return blIndexTxns[i].Topoheight < blIndexTxns[j].Topoheight
 			})
 
+			// Keep the validated scids from before the batch, so they can be put back if the batch is not committed
+			indexer.RLock()
+			validatedSCs := append([]string(nil), indexer.ValidatedSCs...)
+			indexer.RUnlock()
+
+			err = nil
 			// Run through blocks one at a time here to max cpu on a given block if large txns rather than split cpu across go routines of multiple blocks
 			for _, v := range blIndexTxns {
 				if len(v.Tx_hashes) > 0 {
-					c_sctxs, cregTxCount, cburnTxCount, cnormTxCount, err := indexer.IndexTxn(v, false)
+					var c_sctxs []structures.SCTXParse
+					var cregTxCount, cburnTxCount, cnormTxCount int64
+					c_sctxs, cregTxCount, cburnTxCount, cnormTxCount, err = indexer.indexTxn(v, false, wb)
 					if err != nil {
 						logger.Errorf("[StartDaemonMode-mainFOR-IndexTxn] %v - ERROR - IndexTxn(%v) - %v", v.Topoheight, v.Tx_hashes, err)
-						return
+						break
 					}
 
-					regTxCount += cregTxCount
-					burnTxCount += cburnTxCount
-					normTxCount += cnormTxCount
+					// Counts are kept by the height of each block, so those of rolled back blocks can be taken back off
+					if (cregTxCount > 0 || cburnTxCount > 0 || cnormTxCount > 0) && !(indexer.RunMode == "asset") {
+						err = indexer.indexTxCounts(v.Topoheight, cregTxCount, cburnTxCount, cnormTxCount, wb)
+						if err != nil {
+							logger.Errorf("[StartDaemonMode-mainFOR-indexTxCounts] ERROR - %v", err)
+							break
+						}
+					}
 
-					err = indexer.indexInvokes(c_sctxs, v)
+					err = indexer.indexInvokes(c_sctxs, v, wb)
 					if err != nil {
 						logger.Errorf("[StartDaemonMode-mainFOR-indexInvokes]  ERROR - %v", err)
 						break
@@ -627,62 +548,157 @@ func (indexer *Indexer) StartDaemonMode(blockParallelNum int) {
 			}
 			if err != nil {
 				logger.Errorf("[StartDaemonMode-mainFOR-TxnIndexErrs] ERROR - %v", err)
+				indexer.Lock()
+				indexer.ValidatedSCs = validatedSCs
+				indexer.Unlock()
 				continue
 			}
 
-			if (regTxCount > 0 || burnTxCount > 0 || normTxCount > 0) && !(indexer.RunMode == "asset") {
-				err = indexer.indexTxCounts(regTxCount, burnTxCount, normTxCount)
-				if err != nil {
-					logger.Errorf("[StartDaemonMode-mainFOR-indexTxCounts] ERROR - %v", err)
-					continue
-				}
-			}
-
 			if indexer.LastIndexedHeight <= indexer.LastIndexedHeight+int64(blockParallelNum) {
-				indexer.Lock()
-				indexer.LastIndexedHeight += int64(blockParallelNum)
-				indexer.Unlock()
+				lastIndexedHeight := indexer.LastIndexedHeight + int64(blockParallelNum)
 
-				writeWait, _ := time.ParseDuration("20ms")
-				switch indexer.DBType {
-				case "gravdb":
-					for indexer.GravDBBackend.Writing == 1 {
-						if indexer.Closing {
-							return
-						}
-						//logger.Debugf("[Indexer-NewIndexer] GravitonDB is writing... sleeping for %v...", writeWait)
-						time.Sleep(writeWait)
+				wb.Add(func(s storage.Storage) error {
+					_, err := s.StoreBlockHashes(blHashes)
+					if err != nil {
+						logger.Errorf("[StartDaemonMode-mainFOR-StoreBlockHashes] ERROR - %v", err)
+						return err
 					}
-					indexer.GravDBBackend.Writing = 1
-					_, _, err := indexer.GravDBBackend.StoreLastIndexHeight(indexer.LastIndexedHeight, false)
+					_, err = s.StoreLastIndexHeight(lastIndexedHeight)
 					if err != nil {
 						logger.Errorf("[StartDaemonMode-mainFOR-StoreLastIndexHeight] ERROR - %v", err)
+						return err
 					}
-					indexer.GravDBBackend.Writing = 0
-				case "boltdb":
-					for indexer.BBSBackend.Writing == 1 {
-						if indexer.Closing {
-							return
-						}
-						//logger.Debugf("[Indexer-StartDaemonMode-StoreLastIndexHeight] BoltDB is writing... sleeping for %v... writer %v...", writeWait, indexer.BBSBackend.Writer)
-						time.Sleep(writeWait)
-					}
-					indexer.BBSBackend.Writing = 1
-					//indexer.BBSBackend.Writer = "StartDaemonMode"
-					indexer.BBSBackend.StoreLastIndexHeight(indexer.LastIndexedHeight)
-					indexer.BBSBackend.Writing = 0
-					//indexer.BBSBackend.Writer = ""
+					return nil
+				})
+
+				indexer.stageEvent(wb, func() *Event {
+					return &Event{Type: EventHeightIndexed, Topoheight: lastIndexedHeight}
+				})
+
+				// Commit the batch of blocks in one go. LastIndexedHeight is only moved forward once it is committed, otherwise the batch is indexed again
+				err = indexer.Writer.Write(wb.Run)
+				if err == storage.ErrWriterClosed {
+					return
+				} else if err != nil {
+					logger.Errorf("[StartDaemonMode-mainFOR] ERR - committing writes for heights %v - %v: %v", indexer.LastIndexedHeight+1, lastIndexedHeight, err)
+					indexer.Lock()
+					indexer.ValidatedSCs = validatedSCs
+					indexer.Unlock()
+					time.Sleep(5 * time.Second)
+					continue
 				}
+
+				indexer.Lock()
+				indexer.LastIndexedHeight = lastIndexedHeight
+				indexer.Unlock()
+
+				// Let subscribers know of what was just committed, in block order
+				wb.Committed()
 			}
 		}
 	}()
 }
 
-// Potential future item - may be removed as primary srevice of Gnomon is against daemon and not wallet due to security
+// Compares the stored block hash of the last indexed height against the daemon. If they differ the chain has reorganized, so walk back to the fork point and rollback all data indexed above it. If no stored hash within max_reorg_depth matches the daemon, nothing is rolled back and ErrReorgUnresolved is returned
+func (indexer *Indexer) checkReorg() (err error) {
+	height := indexer.LastIndexedHeight
+
+	storedHash := indexer.Backend.GetBlockHash(height)
+
+	// No stored hash (e.g. fastsync start point or a db from before hashes were stored), nothing to compare against
+	if storedHash == "" {
+		return
+	}
+
+	daemonHash, err := indexer.RPC.getBlockHash(uint64(height))
+	if errors.Is(err, ErrRPCPruned) {
+		// Daemon no longer holds the block (e.g. a pruned daemon swapped in while stopped), which is handled ahead of the first batch
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("[checkReorg] could not get block hash at height %v: %v", height, err)
+	}
+
+	if storedHash == daemonHash {
+		return
+	}
+
+	logger.Printf("[checkReorg] Block hash mismatch at height %v (stored: %v ; daemon: %v). Finding fork point...", height, storedHash, daemonHash)
+
+	// Only roll back to a height whose stored hash is confirmed to match the daemon's
+	var found bool
+	forkHeight := height - 1
+	for ; forkHeight > 0 && height-forkHeight <= max_reorg_depth; forkHeight-- {
+		storedHash = indexer.Backend.GetBlockHash(forkHeight)
+		if storedHash == "" {
+			break
+		}
+
+		daemonHash, err = indexer.RPC.getBlockHash(uint64(forkHeight))
+		if err != nil {
+			return fmt.Errorf("[checkReorg] could not get block hash at height %v: %v", forkHeight, err)
+		}
+		if storedHash == daemonHash {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("[checkReorg] %w within %v blocks of height %v, stopping indexing. Resync the index", ErrReorgUnresolved, max_reorg_depth, height)
+	}
+
+	// Records are stamped with the block height rather than the topoheight, and a block height can be shared by consecutive topoheights. So the rollback goes back to the last topoheight below the fork block's height, the blocks at that height being indexed again
+	forkBlockHeight, err := indexer.RPC.getBlockHeight(uint64(forkHeight))
+	if err != nil {
+		return fmt.Errorf("[checkReorg] could not get block height at topoheight %v: %v", forkHeight, err)
+	}
+	for forkHeight > 0 {
+		var blheight int64
+		blheight, err = indexer.RPC.getBlockHeight(uint64(forkHeight - 1))
+		if err != nil {
+			return fmt.Errorf("[checkReorg] could not get block height at topoheight %v: %v", forkHeight-1, err)
+		}
+		forkHeight--
+		if blheight < forkBlockHeight {
+			break
+		}
+	}
+	forkBlockHeight--
+
+	logger.Printf("[checkReorg] Chain reorg detected. Rolling back indexed data from height %v to fork point %v (block height %v)", height, forkHeight, forkBlockHeight)
+
+	var rolledbackSCIDs []string
+	err = indexer.Writer.Write(func(s storage.Storage) (err error) {
+		rolledbackSCIDs, err = s.RollbackToHeight(forkHeight, forkBlockHeight)
+		return
+	})
+	if err != nil {
+		return fmt.Errorf("[checkReorg] could not rollback to height %v: %v", forkHeight, err)
+	}
+
+	indexer.Lock()
+	// SCs that were installed within the orphaned blocks are no longer valid
+	if len(rolledbackSCIDs) > 0 {
+		var validatedSCs []string
+		for _, v := range indexer.ValidatedSCs {
+			if !scidExist(rolledbackSCIDs, v) {
+				validatedSCs = append(validatedSCs, v)
+			}
+		}
+		indexer.ValidatedSCs = validatedSCs
+	}
+	indexer.LastIndexedHeight = forkHeight
+	indexer.Unlock()
+
+	logger.Printf("[checkReorg] Rollback complete. Continuing from height %v", forkHeight)
+
+	return
+}
+
+// Indexes from the wallet's transfer history (indexer.WalletEndpoint) rather than every block. The daemon (indexer.Endpoint) is still used to pull the txns/SC details of the wallet's entries
 func (indexer *Indexer) StartWalletMode(runType string) {
 	var err error
 
-	// Simple connect loop .. if connection fails initially then keep trying, else break out and continue on. Connect() is handled in getInfo() for retries later on if connection ceases again
+	// Simple connect loop .. if connection fails initially then keep trying, else break out and continue on. Connect() is handled in rpc calls for retries later on if connection ceases again
 	/*
 		TODO:
 		var astr []string
@@ -694,7 +710,12 @@ func (indexer *Indexer) StartWalletMode(runType string) {
 			// Break out on closing call
 			break
 		}
-		logger.Printf("[StartDaemonMode] Trying to connect...")
+		logger.Printf("[StartWalletMode] Trying to connect...")
+		err = indexer.WalletRPC.Connect(indexer.WalletEndpoint)
+		if err != nil {
+			time.Sleep(1 * time.Second)
+			continue
+		}
 		err = indexer.RPC.Connect(indexer.Endpoint)
 		if err != nil {
 			time.Sleep(1 * time.Second)
@@ -704,13 +725,33 @@ func (indexer *Indexer) StartWalletMode(runType string) {
 	}
 	time.Sleep(1 * time.Second)
 
-	// Continuously getInfo from daemon to update topoheight globally
+	// Resume from the last processed wallet height, along with the scids validated by then
+	storedindex, err := indexer.Backend.GetLastIndexHeight()
+	if err != nil {
+		logger.Fatalf("[StartWalletMode] Could not get last index height - %v", err)
+	}
+
+	indexer.loadValidatedSCs()
+	indexer.migrateSCIDVariables()
+	indexer.backfillArgIndex()
+	indexer.backfillTemplates()
+	indexer.indexBurnTxs()
+	indexer.indexNameAddresses()
+
+	if storedindex > indexer.LastIndexedHeight {
+		logger.Printf("[StartWalletMode-storedIndex] Continuing from last indexed height %v", storedindex)
+		indexer.Lock()
+		indexer.LastIndexedHeight = storedindex
+		indexer.Unlock()
+	}
+
 	switch runType {
 	case "receive":
 		// do receive actions here (e.g. from data source via API/WS)
 		// TODO: is there anything we need to do within indexer itself if just receiving?
 	default:
 		// 'retrieve'/etc.
+		// Continuously get wallet height to update chain height globally
 		go indexer.getWalletHeight()
 		time.Sleep(1 * time.Second)
 
@@ -721,17 +762,30 @@ func (indexer *Indexer) StartWalletMode(runType string) {
 					break
 				}
 
-				if indexer.LastIndexedHeight > indexer.ChainHeight {
+				if indexer.LastIndexedHeight >= indexer.ChainHeight {
 					time.Sleep(1 * time.Second)
 					continue
 				}
 
-				// Do indexing calls here
+				// Resume from the last processed wallet height, through to the current wallet height
+				startHeight := indexer.LastIndexedHeight + 1
+				endHeight := indexer.ChainHeight
 
-				// TODO: Modify this to be the height of the *next* tx index etc.
-				indexer.Lock()
-				indexer.LastIndexedHeight++
-				indexer.Unlock()
+				entries, err := indexer.getWalletTransfers(uint64(startHeight), uint64(endHeight))
+				if err != nil {
+					logger.Errorf("[StartWalletMode] ERROR - GetTransfers %v - %v: %v", startHeight, endHeight, err)
+					time.Sleep(1 * time.Second)
+					continue
+				}
+
+				err = indexer.indexWalletEntries(entries, endHeight)
+				if err == storage.ErrWriterClosed {
+					return
+				} else if err != nil {
+					logger.Errorf("[StartWalletMode] ERROR - indexing wallet entries %v - %v: %v", startHeight, endHeight, err)
+					time.Sleep(1 * time.Second)
+					continue
+				}
 			}
 		}()
 	}
@@ -740,6 +794,44 @@ func (indexer *Indexer) StartWalletMode(runType string) {
 	select {}
 }
 
+// Appends the scids stored as validated (minus any within SFSCIDExclusion) to ValidatedSCs
+func (indexer *Indexer) loadValidatedSCs() {
+	pre_validatedSCIDs := indexer.Backend.GetAllOwnersAndSCIDs()
+
+	if len(pre_validatedSCIDs) > 0 {
+		logger.Printf("[loadValidatedSCs] Appending pre-validated SCIDs from store to memory.")
+
+		for k := range pre_validatedSCIDs {
+			if scidExist(indexer.SFSCIDExclusion, k) {
+				logger.Debugf("[loadValidatedSCs] Not appending pre-validated SCID '%s' as it resides within SFSCIDExclusion - '%v'.", k, indexer.SFSCIDExclusion)
+				continue
+			}
+			indexer.Lock()
+			if !scidExist(indexer.ValidatedSCs, k) {
+				indexer.ValidatedSCs = append(indexer.ValidatedSCs, k)
+			}
+			indexer.Unlock()
+		}
+	}
+}
+
+// Indexes the burn txs stored before they were kept by height and scid, a no-op once done
+func (indexer *Indexer) indexBurnTxs() {
+	var indexed int
+	err := indexer.Writer.Write(func(s storage.Storage) (err error) {
+		indexed, err = s.IndexBurnTxs()
+		return
+	})
+	if err != nil {
+		logger.Errorf("[indexBurnTxs] ERR - indexing burn txs: %v", err)
+		return
+	}
+
+	if indexed > 0 {
+		logger.Printf("[indexBurnTxs] Indexed %v burn txs by height and scid", indexed)
+	}
+}
+
 // Manually add/inject a SCID to be indexed. Checks validity and then stores within owner tree (no signer addr) and stores a set of current variables.
 func (indexer *Indexer) AddSCIDToIndex(scidstoadd map[string]*structures.FastSyncImport) (err error) {
 	var wg sync.WaitGroup
@@ -774,28 +866,20 @@ func (indexer *Indexer) AddSCIDToIndex(scidstoadd map[string]*structures.FastSyn
 				return
 			} else {
 				// Validate SCID is *actually* a valid SCID
-				scVars, scCode, _, _ := indexer.RPC.GetSCVariables(scid, indexer.ChainHeight, nil, nil, nil, false)
-
-				var contains bool
+				scVars, scCode, scBalances, _ := indexer.RPC.GetSCVariables(scid, indexer.ChainHeight, nil, nil, nil, false)
 
 				// If we can get the SC and searchfilter is "" (get all), contains is true. Otherwise evaluate code against searchfilter
-				if len(indexer.SearchFilter) == 0 {
-					contains = true
-				} else {
-					// Ensure scCode is not blank (e.g. an invalid scid)
-					if scCode != "" {
-						for _, sfv := range indexer.SearchFilter {
-							contains = strings.Contains(scCode, sfv)
-							if contains {
-								// Break b/c we want to ensure contains remains true. Only care if it matches at least 1 case
-								break
-							}
-						}
-					}
+				contains := indexer.searchFilter.Match(scCode)
+
+				var templateID string
+				var asset *structures.SCAsset
+				if contains {
+					templateID = indexer.Templates.Match(scCode)
+					asset = ClassifyAsset(scid, scCode, scVars, indexer.ChainHeight)
 				}
 
 				scilock.Lock()
-				scidstoindexstage = append(scidstoindexstage, SCIDToIndexStage{scid: scid, fsi: fsi, scVars: scVars, scCode: scCode, contains: contains})
+				scidstoindexstage = append(scidstoindexstage, SCIDToIndexStage{scid: scid, fsi: fsi, scVars: scVars, scBalances: scBalances, scCode: scCode, templateID: templateID, asset: asset, contains: contains})
 				scilock.Unlock()
 			}
 			wg.Done()
@@ -803,79 +887,95 @@ func (indexer *Indexer) AddSCIDToIndex(scidstoadd map[string]*structures.FastSyn
 	}
 	wg.Wait()
 
+	var added []string
 	for _, v := range scidstoindexstage {
 		if v.contains {
 			// By returning valid variables of a given Scid (GetSC --> parse vars), we can conclude it is a valid SCID. Otherwise, skip adding to validated scids
 			if len(v.scVars) > 0 {
-				indexer.Lock()
-				indexer.ValidatedSCs = append(indexer.ValidatedSCs, v.scid)
-				indexer.Unlock()
 				if v.fsi != nil {
 					logger.Debugf("[AddSCIDToIndex] SCID matches search filter. Adding SCID %v / Signer %v", v.scid, v.fsi.Owner)
 				} else {
 					logger.Debugf("[AddSCIDToIndex] SCID matches search filter. Adding SCID %v", v.scid)
 				}
 
-				writeWait, _ := time.ParseDuration("20ms")
-				for tempdb.Writing == 1 {
-					if indexer.Closing {
-						return
-					}
-					//logger.Debugf("[Indexer-NewIndexer] GravitonDB is writing... sleeping for %v...", writeWait)
-					time.Sleep(writeWait)
-				}
-
 				if indexer.Closing {
 					return
 				}
-				tempdb.Writing = 1
-				var ctrees []*graviton.Tree
-
-				var sochanges bool
-				var sotree *graviton.Tree
-				if v.fsi != nil {
-					sotree, sochanges, err = tempdb.StoreOwner(v.scid, v.fsi.Owner, true)
-				} else {
-					sotree, sochanges, err = tempdb.StoreOwner(v.scid, "", true)
-				}
-				if err != nil {
-					logger.Errorf("[AddSCIDToIndex] ERR - storing owner: %v", err)
-				} else {
-					if sochanges {
-						ctrees = append(ctrees, sotree)
+				// tempdb is only written to from here, so the scid's writes are batched straight against it rather than through a Writer
+				err = tempdb.Batch(func(s storage.Storage) error {
+					var err error
+					if v.fsi != nil {
+						_, err = s.StoreOwner(v.scid, v.fsi.Owner)
+					} else {
+						_, err = s.StoreOwner(v.scid, "")
 					}
-				}
-				svdtree, svdchanges, err := tempdb.StoreSCIDVariableDetails(v.scid, v.scVars, indexer.ChainHeight, true)
-				if err != nil {
-					logger.Errorf("[AddSCIDToIndex] ERR - storing scid variable details: %v", err)
-				} else {
-					if svdchanges {
-						ctrees = append(ctrees, svdtree)
+					if err != nil {
+						logger.Errorf("[AddSCIDToIndex] ERR - storing owner: %v", err)
+						return err
+					}
+					_, err = s.StoreSCIDVariableDetails(v.scid, v.scVars, indexer.ChainHeight)
+					if err != nil {
+						logger.Errorf("[AddSCIDToIndex] ERR - storing scid variable details: %v", err)
+						return err
+					}
+					_, err = s.StoreSCIDBalanceDetails(v.scid, v.scBalances, indexer.ChainHeight)
+					if err != nil {
+						logger.Errorf("[AddSCIDToIndex] ERR - storing scid balance details: %v", err)
+						return err
+					}
+					if v.templateID != "" {
+						_, err = s.StoreSCIDTemplate(v.scid, v.templateID)
+						if err != nil {
+							logger.Errorf("[AddSCIDToIndex] ERR - storing scid template: %v", err)
+							return err
+						}
+					}
+					if v.asset != nil {
+						_, err = s.StoreSCAsset(v.asset)
+						if err != nil {
+							logger.Errorf("[AddSCIDToIndex] ERR - storing scid asset: %v", err)
+							return err
+						}
+					}
+					// Install txn is not known, so the code is kept from when it was first seen
+					if v.scCode != "" {
+						_, err = s.StoreSCIDCode(v.scid, &structures.SCIDCode{Height: indexer.ChainHeight, Code: v.scCode})
+						if err != nil {
+							logger.Errorf("[AddSCIDToIndex] ERR - storing scid code: %v", err)
+							return err
+						}
 					}
+					_, err = s.StoreSCIDInteractionHeight(v.scid, indexer.ChainHeight)
+					if err != nil {
+						logger.Errorf("[AddSCIDToIndex] ERR - storing scid interaction height: %v", err)
+						return err
+					}
+					return nil
+				})
+				if err != nil {
+					// The scid's writes were not committed, so it is left out rather than added part way
+					logger.Errorf("[AddSCIDToIndex] ERR - committing scid to RAM store: %v", err)
+					continue
 				}
+				added = append(added, v.scid)
 				if !scidExist(treenames, v.scid+"vars") {
 					treenames = append(treenames, v.scid+"vars")
 				}
-				sihtree, sihchanges, err := tempdb.StoreSCIDInteractionHeight(v.scid, indexer.ChainHeight, true)
-				if err != nil {
-					logger.Errorf("[AddSCIDToIndex] ERR - storing scid interaction height: %v", err)
-				} else {
-					if sihchanges {
-						ctrees = append(ctrees, sihtree)
-					}
+				if v.templateID != "" && !scidExist(treenames, "templates") {
+					treenames = append(treenames, "templates")
+				}
+				if v.asset != nil && !scidExist(treenames, "assets") {
+					treenames = append(treenames, "assets")
+				}
+				if !scidExist(treenames, v.scid+"balances") {
+					treenames = append(treenames, v.scid+"balances")
+				}
+				if v.scCode != "" && !scidExist(treenames, v.scid+"code") {
+					treenames = append(treenames, v.scid+"code")
 				}
 				if !scidExist(treenames, v.scid+"heights") {
 					treenames = append(treenames, v.scid+"heights")
 				}
-				if len(ctrees) > 0 {
-					_, err := tempdb.CommitTrees(ctrees)
-					if err != nil {
-						logger.Errorf("[AddSCIDToIndex] ERR - committing trees: %v", err)
-					} else {
-						//logger.Debugf("[AddSCIDToIndex] DEBUG - cv [%v]", cv)
-					}
-				}
-				tempdb.Writing = 0
 			} else {
 				logger.Debugf("[AddSCIDToIndex] ERR - SCID '%v' doesn't exist at height %v", v.scid, indexer.ChainHeight)
 			}
@@ -883,95 +983,43 @@ func (indexer *Indexer) AddSCIDToIndex(scidstoadd map[string]*structures.FastSyn
 	}
 
 	logger.Printf("[AddSCIDToIndex] Done - Sorting %v SCIDs to index", len(scidstoadd))
-	switch indexer.DBType {
-	case "gravdb":
-		logger.Printf("[AddSCIDToIndex] Current stored disk: %v", len(indexer.GravDBBackend.GetAllOwnersAndSCIDs()))
-		logger.Printf("[AddSCIDToIndex] Current stored ram: %v", len(tempdb.GetAllOwnersAndSCIDs()))
-
-		logger.Printf("[AddSCIDToIndex] Starting - Committing RAM SCID sort to disk storage...")
-		writeWait, _ := time.ParseDuration("10ms")
-		for tempdb.Writing == 1 || indexer.GravDBBackend.Writing == 1 {
-			if indexer.Closing {
-				return
-			}
-			//logger.Debugf("[AddSCIDToIndex-StoreAltDBInput] GravitonDB is writing... sleeping for %v...", writeWait)
-			time.Sleep(writeWait)
-		}
-		tempdb.Writing = 1
-		indexer.GravDBBackend.Writing = 1
-		indexer.GravDBBackend.StoreAltDBInput(treenames, tempdb)
-		tempdb.Writing = 0
-		indexer.GravDBBackend.Writing = 0
-		logger.Printf("[AddSCIDToIndex] Done - Committing RAM SCID sort to disk storage...")
-		logger.Printf("[AddSCIDToIndex] New stored disk: %v", len(indexer.GravDBBackend.GetAllOwnersAndSCIDs()))
-	case "boltdb":
-		logger.Printf("[AddSCIDToIndex] Current stored disk: %v", len(indexer.BBSBackend.GetAllOwnersAndSCIDs()))
-		logger.Printf("[AddSCIDToIndex] Current stored ram: %v", len(tempdb.GetAllOwnersAndSCIDs()))
-
-		logger.Printf("[AddSCIDToIndex] Starting - Committing RAM SCID sort to disk storage...")
-		writeWait, _ := time.ParseDuration("10ms")
-		for tempdb.Writing == 1 || indexer.BBSBackend.Writing == 1 {
-			if indexer.Closing {
-				return
-			}
-			//logger.Debugf("[AddSCIDToIndex-StoreAltDBInput] GravitonDB is writing... sleeping for %v...", writeWait)
-			time.Sleep(writeWait)
-		}
-		tempdb.Writing = 1
-		indexer.BBSBackend.Writing = 1
-		indexer.BBSBackend.StoreAltDBInput(treenames, tempdb)
-		tempdb.Writing = 0
-		indexer.BBSBackend.Writing = 0
-		logger.Printf("[AddSCIDToIndex] Done - Committing RAM SCID sort to disk storage...")
-		logger.Printf("[AddSCIDToIndex] New stored disk: %v", len(indexer.BBSBackend.GetAllOwnersAndSCIDs()))
-	}
+	logger.Printf("[AddSCIDToIndex] Current stored disk: %v", len(indexer.Backend.GetAllOwnersAndSCIDs()))
+	logger.Printf("[AddSCIDToIndex] Current stored ram: %v", len(tempdb.GetAllOwnersAndSCIDs()))
 
-	return err
-}
+	logger.Printf("[AddSCIDToIndex] Starting - Committing RAM SCID sort to disk storage...")
+	err = indexer.Writer.Write(func(s storage.Storage) error {
+		return s.StoreAltDBInput(treenames, tempdb)
+	})
+	if err != nil {
+		logger.Errorf("[AddSCIDToIndex] ERR - committing RAM SCID sort to disk storage: %v", err)
+		return err
+	}
 
-func (client *Client) Connect(endpoint string) (err error) {
-	// Used to check if the endpoint has changed.. if so, then close WS to current and update WS
-	if client.WS != nil {
-		remAddr := client.WS.RemoteAddr()
-		var pingpong string
-		err2 := client.RPC.CallResult(context.Background(), "DERO.Ping", nil, &pingpong)
-		if strings.Contains(remAddr.String(), endpoint) && err2 == nil {
-			// Endpoint is the same, continue on
-			return
-		} else {
-			// Remote addr (current ws connection endpoint) does not match indexer endpoint - re-connecting
-			client.Lock()
-			defer client.Unlock()
-			client.WS.Close()
+	// Scids are only validated once they have been committed to the backend
+	indexer.Lock()
+	for _, scid := range added {
+		if !scidExist(indexer.ValidatedSCs, scid) {
+			indexer.ValidatedSCs = append(indexer.ValidatedSCs, scid)
 		}
 	}
+	indexer.Unlock()
+	logger.Printf("[AddSCIDToIndex] Done - Committing RAM SCID sort to disk storage...")
+	logger.Printf("[AddSCIDToIndex] New stored disk: %v", len(indexer.Backend.GetAllOwnersAndSCIDs()))
 
-	client.WS, _, err = websocket.DefaultDialer.Dial("ws://"+endpoint+"/ws", nil)
-
-	// notify user of any state change
-	// if daemon connection breaks or comes live again
-	if err == nil {
-		if !Connected {
-			logger.Printf("[Connect] Connection to RPC server successful - ws://%s/ws", endpoint)
-			Connected = true
-		}
-	} else {
-		logger.Errorf("[Connect] ERROR connecting to endpoint %v", err)
+	return err
+}
 
-		if Connected {
-			logger.Errorf("[Connect] ERROR - Connection to RPC server Failed - ws://%s/ws", endpoint)
-		}
-		Connected = false
-		return err
+// Stages fn into wb to be committed along with the rest of the block(s) writes. If wb is nil, fn is committed right away
+func (indexer *Indexer) stageWrite(wb *storage.WriteBatch, fn func(s storage.Storage) error) (err error) {
+	if wb == nil {
+		return indexer.Writer.Write(fn)
 	}
+	wb.Add(fn)
 
-	input_output := rwc.New(client.WS)
-	client.RPC = jrpc2.NewClient(channel.RawJSON(input_output, input_output), nil)
-
-	return err
+	return
 }
 
-func (indexer *Indexer) indexBlock(blid string, topoheight int64) (blockTxns *structures.BlockTxns, err error) {
+func (indexer *Indexer) indexBlock(blid string, topoheight int64, wb *storage.WriteBatch) (blockTxns *structures.BlockTxns, err error) {
 	blockTxns = &structures.BlockTxns{}
 
 	var io rpc.GetBlock_Result
@@ -981,22 +1029,8 @@ func (indexer *Indexer) indexBlock(blid string, topoheight int64) (blockTxns *st
 		return
 	}
 
-	// TODO: Make this a consumable func with rpc calls and timeout / wait / retry logic for deduplication of code. Or use alternate method of checking [primary use case is remote nodes]
-	var reconnect_count int
-	for {
-		if err = indexer.RPC.RPC.CallResult(context.Background(), "DERO.GetBlock", ip, &io); err != nil {
-			logger.Debugf("[indexBlock] ERROR - GetBlock failed: %v . Trying again (%v / 5) ", err, reconnect_count)
-			if reconnect_count >= 5 {
-				return blockTxns, fmt.Errorf("[indexBlock] ERROR - GetBlock failed: %v", err)
-			}
-			time.Sleep(1 * time.Second)
-
-			reconnect_count++
-
-			continue
-		}
-
-		break
+	if err = indexer.RPC.Call("DERO.GetBlock", ip, &io); err != nil {
+		return blockTxns, fmt.Errorf("[indexBlock] ERROR - GetBlock failed: %w", err)
 	}
 
 	var bl block.Block
@@ -1012,48 +1046,14 @@ func (indexer *Indexer) indexBlock(blid string, topoheight int64) (blockTxns *st
 			return blockTxns, err2
 		}
 
-		writeWait, _ := time.ParseDuration("20ms")
-		switch indexer.DBType {
-		case "gravdb":
-			if !(indexer.RunMode == "asset") {
-				for indexer.GravDBBackend.Writing == 1 {
-					if indexer.Closing {
-						return
-					}
-					//logger.Debugf("[Indexer-NewIndexer] GravitonDB is writing... sleeping for %v...", writeWait)
-					time.Sleep(writeWait)
-				}
-
-				indexer.GravDBBackend.Writing = 1
-				_, _, err2 = indexer.GravDBBackend.StoreMiniblockDetailsByHash(blid, mbldetails, false)
-				if err2 != nil {
-					logger.Errorf("[indexBlock] Error storing miniblock details for blid %v", err2)
-					indexer.GravDBBackend.Writing = 0
-					return blockTxns, err2
-				}
-				indexer.GravDBBackend.Writing = 0
-			}
-		case "boltdb":
-			if !(indexer.RunMode == "asset") {
-				for indexer.BBSBackend.Writing == 1 {
-					if indexer.Closing {
-						return
-					}
-					//logger.Debugf("[Indexer-IndexBlock-StoreMiniblockDetailsByHash] BoltDB is writing... sleeping for %v... writer %v...", writeWait, indexer.BBSBackend.Writer)
-					time.Sleep(writeWait)
-				}
-
-				indexer.BBSBackend.Writing = 1
-				//indexer.BBSBackend.Writer = "IndexBlock"
-				_, err2 = indexer.BBSBackend.StoreMiniblockDetailsByHash(blid, mbldetails)
-				if err2 != nil {
-					logger.Errorf("[indexBlock] Error storing miniblock details for blid %v", err2)
-					indexer.BBSBackend.Writing = 0
-					//indexer.BBSBackend.Writer = ""
-					return blockTxns, err2
-				}
-				indexer.BBSBackend.Writing = 0
-				//indexer.BBSBackend.Writer = ""
+		if !(indexer.RunMode == "asset") {
+			err2 = indexer.stageWrite(wb, func(s storage.Storage) error {
+				_, err := s.StoreMiniblockDetailsByHash(blid, topoheight, mbldetails)
+				return err
+			})
+			if err2 != nil {
+				logger.Errorf("[indexBlock] Error storing miniblock details for blid %v", err2)
+				return blockTxns, err2
 			}
 		}
 	}
@@ -1065,7 +1065,22 @@ func (indexer *Indexer) indexBlock(blid string, topoheight int64) (blockTxns *st
 }
 
 func (indexer *Indexer) IndexTxn(blTxns *structures.BlockTxns, noStore bool) (bl_sctxs []structures.SCTXParse, regTxCount int64, burnTxCount int64, normTxCount int64, err error) {
+	return indexer.indexTxn(blTxns, noStore, nil)
+}
+
+// Indexes the txns of a block. Writes are staged into wb to be committed with the rest of the block(s), or committed right away if wb is nil
+func (indexer *Indexer) indexTxn(blTxns *structures.BlockTxns, noStore bool, wb *storage.WriteBatch) (bl_sctxs []structures.SCTXParse, regTxCount int64, burnTxCount int64, normTxCount int64, err error) {
 	var txslock sync.RWMutex
+	var txErr error
+
+	// Registration txns are only decoded (rather than just counted) if they are being stored
+	storeRegTxs := indexer.RegTxLookup && !noStore && !(indexer.RunMode == "asset")
+	mainnet := true
+	if storeRegTxs {
+		if getinfo := indexer.Backend.GetGetInfoDetails(); getinfo != nil {
+			mainnet = !getinfo.Testnet
+		}
+	}
 
 	var wg sync.WaitGroup
 	wg.Add(len(blTxns.Tx_hashes))
@@ -1078,7 +1093,8 @@ func (indexer *Indexer) IndexTxn(blTxns *structures.BlockTxns, noStore bool) (bl
 			}
 
 			// We can match the PoW scheme result to filter out reg txns without needing to waste GetTransaction calls against saving time - https://github.com/deroproject/derohe/blob/main/cmd/dero-wallet-cli/easymenu_post_open.go#L150
-			if blTxns.Tx_hashes[i][0] == 0 && blTxns.Tx_hashes[i][1] == 0 && blTxns.Tx_hashes[i][2] == 0 {
+			isRegTx := blTxns.Tx_hashes[i][0] == 0 && blTxns.Tx_hashes[i][1] == 0 && blTxns.Tx_hashes[i][2] == 0
+			if isRegTx && !storeRegTxs {
 				txslock.Lock()
 				regTxCount++
 				txslock.Unlock()
@@ -1096,27 +1112,30 @@ func (indexer *Indexer) IndexTxn(blTxns *structures.BlockTxns, noStore bool) (bl
 
 			inputparam.Tx_Hashes = append(inputparam.Tx_Hashes, blTxns.Tx_hashes[i].String())
 
-			// TODO: Make this a consumable func with rpc calls and timeout / wait / retry logic for deduplication of code. Or use alternate method of checking [primary use case is remote nodes]
-			var reconnect_count int
-			for {
-				if err = indexer.RPC.RPC.CallResult(context.Background(), "DERO.GetTransaction", inputparam, &output); err != nil {
-					logger.Debugf("[IndexTxn] ERROR - GetTransaction for txid '%v' failed: %v . Trying again (%v / 5)", inputparam.Tx_Hashes, err, reconnect_count)
-					if reconnect_count >= 5 {
-						// TODO - In event indexer.Endpoint is being swapped, this case will fail and you could miss a txn. Need another handle rather than just "assume" skip/move on.
-						wg.Done()
-						// If we error, this could be due to regtxn not valid on pruned node or other reasons. We will just nil the err and then return and move on.
-						err = nil
-						logger.Errorf("[IndexTxn] ERROR - GetTransaction for txid '%v' failed: %v . (%v / 5 times)", inputparam.Tx_Hashes, err, reconnect_count)
-						return
+			// A not found txn is returned as an empty hex string, so it is checked for here to have the other endpoints tried rather than skipping the txn
+			err := indexer.RPC.call("DERO.GetTransaction", inputparam, &output, func() error {
+				if len(output.Txs_as_hex) == 0 || output.Txs_as_hex[0] == "" {
+					return &RPCError{Method: "DERO.GetTransaction", Kind: ErrRPCNotFound, Err: fmt.Errorf("txid %v", inputparam.Tx_Hashes)}
+				}
+				return nil
+			})
+			if err != nil {
+				// Pruned or missing txns (e.g. regtxn not valid on pruned node) are skipped. Anything else is returned so that the block(s) are tried again rather than missing a txn
+				if errors.Is(err, ErrRPCPruned) || errors.Is(err, ErrRPCNotFound) {
+					logger.Errorf("[IndexTxn] ERROR - GetTransaction for txid '%v' failed, skipping: %v", inputparam.Tx_Hashes, err)
+					if isRegTx {
+						txslock.Lock()
+						regTxCount++
+						txslock.Unlock()
 					}
-					time.Sleep(1 * time.Second)
-
-					reconnect_count++
-
-					continue
+				} else {
+					logger.Errorf("[IndexTxn] ERROR - GetTransaction for txid '%v' failed: %v", inputparam.Tx_Hashes, err)
+					txslock.Lock()
+					txErr = err
+					txslock.Unlock()
 				}
-
-				break
+				wg.Done()
+				return
 			}
 
 			tx_bin, _ := hex.DecodeString(output.Txs_as_hex[0])
@@ -1162,17 +1181,53 @@ func (indexer *Indexer) IndexTxn(blTxns *structures.BlockTxns, noStore bool) (bl
 				}
 				//time.Sleep(2 * time.Second)
 				txslock.Lock()
-				bl_sctxs = append(bl_sctxs, structures.SCTXParse{Txid: blTxns.Tx_hashes[i].String(), Scid: scid, Scid_hex: scid_hex, Entrypoint: entrypoint, Method: method, Sc_args: sc_args, Sender: sender, Payloads: tx.Payloads, Fees: sc_fees, Height: blTxns.Topoheight})
+				bl_sctxs = append(bl_sctxs, structures.SCTXParse{Txid: blTxns.Tx_hashes[i].String(), Scid: scid, Scid_hex: scid_hex, Entrypoint: entrypoint, Method: method, Sc_args: sc_args, Sender: sender, Payloads: tx.Payloads, Fees: sc_fees, Height: blTxns.Topoheight, Args: structures.DecodeSCArgs(sc_args)})
 				txslock.Unlock()
 			} else if tx.TransactionType == transaction.REGISTRATION {
 				txslock.Lock()
 				regTxCount++
 				txslock.Unlock()
+
+				if storeRegTxs {
+					// The registered address' public key is carried within MinerAddress of a registration txn
+					addr, aerr := rpc.NewAddressFromCompressedKeys(tx.MinerAddress[:])
+					if aerr != nil {
+						logger.Errorf("[IndexTxn] ERR - decoding registration address of txid '%v': %v", blTxns.Tx_hashes[i].String(), aerr)
+					} else {
+						addr.Mainnet = mainnet
+						regtx := &structures.RegTXParse{Txid: blTxns.Tx_hashes[i].String(), Address: addr.String(), Height: blTxns.Topoheight}
+						err := indexer.stageWrite(wb, func(s storage.Storage) error {
+							_, err := s.StoreRegTx(regtx)
+							return err
+						})
+						if err != nil {
+							logger.Errorf("[IndexTxn] ERR - storing registration tx for '%v': %v", regtx.Address, err)
+						}
+					}
+				}
 			} else if tx.TransactionType == transaction.BURN_TX {
-				// TODO: Handle burn_tx here
 				txslock.Lock()
 				burnTxCount++
 				txslock.Unlock()
+
+				// Each payload burns its own asset, DERO burns have the zero hash as scid
+				if !noStore && !(indexer.RunMode == "asset") {
+					for j := 0; j < len(tx.Payloads); j++ {
+						// The fees are of the whole txn, so are only kept on its first payload
+						var fees uint64
+						if j == 0 {
+							fees = tx.Fees()
+						}
+						burntx := &structures.BurnTXParse{Txid: blTxns.Tx_hashes[i].String(), Scid: tx.Payloads[j].SCID.String(), Fees: fees, Burn: tx.Payloads[j].BurnValue, Height: blTxns.Topoheight}
+						err := indexer.stageWrite(wb, func(s storage.Storage) error {
+							_, err := s.StoreBurnTx(burntx)
+							return err
+						})
+						if err != nil {
+							logger.Errorf("[IndexTxn] ERR - storing burn tx '%v': %v", burntx.Txid, err)
+						}
+					}
+				}
 			} else if tx.TransactionType == transaction.NORMAL {
 				// TODO: Handle normal tx here
 				txslock.Lock()
@@ -1186,36 +1241,17 @@ func (indexer *Indexer) IndexTxn(blTxns *structures.BlockTxns, noStore bool) (bl
 						for _, v := range output.Txs[0].Ring[j] {
 							//bl_normtxs = append(bl_normtxs, structures.NormalTXWithSCIDParse{Txid: blTxns.Tx_hashes[i].String(), Scid: tx.Payloads[j].SCID.String(), Fees: tx_fees, Height: int64(bl.Height)})
 							if !noStore {
-								writeWait, _ := time.ParseDuration("20ms")
-								switch indexer.DBType {
-								case "gravdb":
-									if !(indexer.RunMode == "asset") {
-										for indexer.GravDBBackend.Writing == 1 {
-											if indexer.Closing {
-												return
-											}
-											//logger.Debugf("[Indexer-NewIndexer] GravitonDB is writing... sleeping for %v...", writeWait)
-											time.Sleep(writeWait)
-										}
-										indexer.GravDBBackend.Writing = 1
-										indexer.GravDBBackend.StoreNormalTxWithSCIDByAddr(v, &structures.NormalTXWithSCIDParse{Txid: blTxns.Tx_hashes[i].String(), Scid: tx.Payloads[j].SCID.String(), Fees: sc_fees, Height: int64(blTxns.Topoheight)}, false)
-										indexer.GravDBBackend.Writing = 0
-									}
-								case "boltdb":
-									if !(indexer.RunMode == "asset") {
-										for indexer.BBSBackend.Writing == 1 {
-											if indexer.Closing {
-												return
-											}
-											//logger.Debugf("[Indexer-IndexTxn-StoreNormalTxWithSCIDByAddr] BoltDB is writing... sleeping for %v... writer %v...", writeWait, indexer.BBSBackend.Writer)
-											time.Sleep(writeWait)
-										}
-										indexer.BBSBackend.Writing = 1
-										//indexer.BBSBackend.Writer = "IndexTxn"
-										indexer.BBSBackend.StoreNormalTxWithSCIDByAddr(v, &structures.NormalTXWithSCIDParse{Txid: blTxns.Tx_hashes[i].String(), Scid: tx.Payloads[j].SCID.String(), Fees: sc_fees, Height: int64(blTxns.Topoheight)})
-										indexer.BBSBackend.Writing = 0
-										//indexer.BBSBackend.Writer = ""
+								if !(indexer.RunMode == "asset") {
+									addr := v
+									normTxWithSCID := &structures.NormalTXWithSCIDParse{Txid: blTxns.Tx_hashes[i].String(), Scid: tx.Payloads[j].SCID.String(), Fees: sc_fees, Height: int64(blTxns.Topoheight)}
+									err := indexer.stageWrite(wb, func(s storage.Storage) error {
+										_, err := s.StoreNormalTxWithSCIDByAddr(addr, normTxWithSCID)
+										return err
+									})
+									if err != nil {
+										logger.Errorf("[IndexTxn] ERR - storing normal tx with scid for '%v': %v", addr, err)
 									}
+									_ = indexer.indexAddressActivity(addr, normTxWithSCID.Scid, normTxWithSCID.Txid, normTxWithSCID.Height, AddressRoleRingMember, wb)
 								}
 							}
 						}
@@ -1229,78 +1265,38 @@ func (indexer *Indexer) IndexTxn(blTxns *structures.BlockTxns, noStore bool) (bl
 	}
 	wg.Wait()
 
+	if txErr != nil {
+		return bl_sctxs, regTxCount, burnTxCount, normTxCount, txErr
+	}
+
 	return bl_sctxs, regTxCount, burnTxCount, normTxCount, err
 }
 
-func (indexer *Indexer) indexTxCounts(regTxCount int64, burnTxCount int64, normTxCount int64) (err error) {
+// Adds the tx counts of the block(s) at height to the stored totals
+func (indexer *Indexer) indexTxCounts(height int64, regTxCount int64, burnTxCount int64, normTxCount int64, wb *storage.WriteBatch) (err error) {
 	if indexer.Closing {
 		return
 	}
-	var ctrees []*graviton.Tree
-
-	writeWait, _ := time.ParseDuration("20ms")
-	switch indexer.DBType {
-	case "gravdb":
-		for indexer.GravDBBackend.Writing == 1 {
-			if indexer.Closing {
-				return
-			}
-			//logger.Debugf("[Indexer-NewIndexer] GravitonDB is writing... sleeping for %v...", writeWait)
-			time.Sleep(writeWait)
-		}
-		indexer.GravDBBackend.Writing = 1
+	// Counts are read and stored within the same batch so that the writer is the only one doing the read-modify-write
+	return indexer.stageWrite(wb, func(s storage.Storage) error {
 		if regTxCount > 0 && !indexer.Fastsync {
 			// Load from mem existing regTxCount and append new value
-			currRegTxCount := indexer.GravDBBackend.GetTxCount("registration")
-			/*
-				writeWait, _ := time.ParseDuration("50ms")
-				for indexer.GravDBBackend.Writing == 1 {
-					if indexer.Closing {
-						return
-					}
-					//logger.Debugf("[Indexer-indexBlock-regTxCount] GravitonDB is writing... sleeping for %v...", writeWait)
-					time.Sleep(writeWait)
-				}
-				indexer.GravDBBackend.Writing = 1
-			*/
-			rtxtree, rtxchanges, err := indexer.GravDBBackend.StoreTxCount(regTxCount+currRegTxCount, "registration", true)
-			//indexer.GravDBBackend.Writing = 0
+			currRegTxCount := s.GetTxCount("registration")
+			_, err := s.StoreTxCount(regTxCount+currRegTxCount, "registration")
 			if err != nil {
 				logger.Errorf("[indexBlock] ERROR - Error storing registration tx count. DB '%v' - this block count '%v' - total '%v'", currRegTxCount, regTxCount, regTxCount+currRegTxCount)
-				indexer.GravDBBackend.Writing = 0
 				return err
-			} else {
-				if rtxchanges {
-					ctrees = append(ctrees, rtxtree)
-				}
 			}
 		}
 
 		if burnTxCount > 0 && !indexer.Fastsync {
 			// Load from mem existing burnTxCount and append new value
-			currBurnTxCount := indexer.GravDBBackend.GetTxCount("burn")
-			/*
-				writeWait, _ := time.ParseDuration("50ms")
-				for indexer.GravDBBackend.Writing == 1 {
-					if indexer.Closing {
-						return
-					}
-					//logger.Debugf("[Indexer-indexBlock-burnTxCount] GravitonDB is writing... sleeping for %v...", writeWait)
-					time.Sleep(writeWait)
-				}
-				indexer.GravDBBackend.Writing = 1
-			*/
-			btxtree, btxchanges, err := indexer.GravDBBackend.StoreTxCount(burnTxCount+currBurnTxCount, "burn", true)
+			currBurnTxCount := s.GetTxCount("burn")
+			_, err := s.StoreTxCount(burnTxCount+currBurnTxCount, "burn")
 			if err != nil {
 				logger.Errorf("[indexBlock] ERROR - Error storing burn tx count. DB '%v' - this block count '%v' - total '%v'", currBurnTxCount, burnTxCount, regTxCount+currBurnTxCount)
-				indexer.GravDBBackend.Writing = 0
 				return err
-			} else {
-				if btxchanges {
-					ctrees = append(ctrees, btxtree)
-				}
 			}
-			//indexer.GravDBBackend.Writing = 0
 		}
 
 		if normTxCount > 0 && !indexer.Fastsync {
@@ -1362,91 +1358,38 @@ func (indexer *Indexer) indexTxCounts(regTxCount int64, burnTxCount int64, normT
 			*/
 
 			// Load from mem existing normTxCount and append new value
-			currNormTxCount := indexer.GravDBBackend.GetTxCount("normal")
-			/*
-				writeWait, _ := time.ParseDuration("50ms")
-				for indexer.GravDBBackend.Writing == 1 {
-					if indexer.Closing {
-						return
-					}
-					//logger.Debugf("[Indexer-indexBlock-normTxCount] GravitonDB is writing... sleeping for %v...", writeWait)
-					time.Sleep(writeWait)
-				}
-				indexer.GravDBBackend.Writing = 1
-			*/
-			ntxtree, ntxchanges, err := indexer.GravDBBackend.StoreTxCount(normTxCount+currNormTxCount, "normal", true)
+			currNormTxCount := s.GetTxCount("normal")
+			_, err := s.StoreTxCount(normTxCount+currNormTxCount, "normal")
 			if err != nil {
 				logger.Errorf("[indexBlock] ERROR - Error storing normal tx count. DB '%v' - this block count '%v' - total '%v'", currNormTxCount, currNormTxCount, normTxCount+currNormTxCount)
-				indexer.GravDBBackend.Writing = 0
 				return err
-			} else {
-				if ntxchanges {
-					ctrees = append(ctrees, ntxtree)
-				}
-			}
-		}
-		if len(ctrees) > 0 {
-			_, err := indexer.GravDBBackend.CommitTrees(ctrees)
-			if err != nil {
-				logger.Errorf("[indexBlock-indexTxCounts] ERR - committing trees: %v", err)
-			} else {
-				//logger.Debugf("[indexBlock-installsc] DEBUG - cv [%v]", cv)
 			}
 		}
-		indexer.GravDBBackend.Writing = 0
-	case "boltdb":
-		for indexer.BBSBackend.Writing == 1 {
-			if indexer.Closing {
-				return
+
+		// What was added at height is kept to be taken back off the totals should the block be rolled back
+		if !indexer.Fastsync {
+			counts := make(map[string]int64)
+			if regTxCount > 0 {
+				counts["registration"] = regTxCount
 			}
-			//logger.Debugf("[Indexer-IndexTxCounts] BoltDB is writing... sleeping for %v... writer %v...", writeWait, indexer.BBSBackend.Writer)
-			time.Sleep(writeWait)
-		}
-		indexer.BBSBackend.Writing = 1
-		//indexer.BBSBackend.Writer = "IndexTxCounts"
-		if regTxCount > 0 && !indexer.Fastsync {
-			// Load from mem existing regTxCount and append new value
-			currRegTxCount := indexer.BBSBackend.GetTxCount("registration")
-			_, err := indexer.BBSBackend.StoreTxCount(regTxCount+currRegTxCount, "registration")
-			if err != nil {
-				logger.Errorf("[indexBlock] ERROR - Error storing registration tx count. DB '%v' - this block count '%v' - total '%v'", currRegTxCount, regTxCount, regTxCount+currRegTxCount)
-				indexer.BBSBackend.Writing = 0
-				//indexer.BBSBackend.Writer = ""
-				return err
+			if burnTxCount > 0 {
+				counts["burn"] = burnTxCount
 			}
-		}
-
-		if burnTxCount > 0 && !indexer.Fastsync {
-			// Load from mem existing burnTxCount and append new value
-			currBurnTxCount := indexer.BBSBackend.GetTxCount("burn")
-			_, err := indexer.BBSBackend.StoreTxCount(burnTxCount+currBurnTxCount, "burn")
-			if err != nil {
-				logger.Errorf("[indexBlock] ERROR - Error storing burn tx count. DB '%v' - this block count '%v' - total '%v'", currBurnTxCount, burnTxCount, regTxCount+currBurnTxCount)
-				indexer.BBSBackend.Writing = 0
-				//indexer.BBSBackend.Writer = ""
-				return err
+			if normTxCount > 0 {
+				counts["normal"] = normTxCount
 			}
-		}
-
-		if normTxCount > 0 && !indexer.Fastsync {
-			// Load from mem existing normTxCount and append new value
-			currNormTxCount := indexer.BBSBackend.GetTxCount("normal")
-			_, err := indexer.BBSBackend.StoreTxCount(normTxCount+currNormTxCount, "normal")
+			_, err := s.StoreTxCountsByHeight(height, counts)
 			if err != nil {
-				logger.Errorf("[indexBlock] ERROR - Error storing normal tx count. DB '%v' - this block count '%v' - total '%v'", currNormTxCount, currNormTxCount, normTxCount+currNormTxCount)
-				indexer.BBSBackend.Writing = 0
-				//indexer.BBSBackend.Writer = ""
+				logger.Errorf("[indexTxCounts] ERROR - Error storing tx counts of height %v: %v", height, err)
 				return err
 			}
 		}
-		indexer.BBSBackend.Writing = 0
-		//indexer.BBSBackend.Writer = ""
-	}
 
-	return nil
+		return nil
+	})
 }
 
-func (indexer *Indexer) indexInvokes(bl_sctxs []structures.SCTXParse, bl_txns *structures.BlockTxns) (err error) {
+func (indexer *Indexer) indexInvokes(bl_sctxs []structures.SCTXParse, bl_txns *structures.BlockTxns, wb *storage.WriteBatch) (err error) {
 
 	if indexer.Closing {
 		return
@@ -1457,6 +1400,17 @@ func (indexer *Indexer) indexInvokes(bl_sctxs []structures.SCTXParse, bl_txns *s
 
 		// TODO: Go routine possible for pre-storage components given the number of 'potential' getscvar calls that may be required.. could speed up indexing some more.
 		for i := 0; i < len(bl_sctxs); i++ {
+			// Staged writes may run after the loop has moved on, so they need their own copy of i
+			i := i
+
+			// The name service index is kept regardless of the search filter, sfscidexclusion and fastsync, as api responses show names next to addresses
+			if bl_sctxs[i].Scid == structures.NAMESERVICE_SCID && !(indexer.RunMode == "asset") {
+				err = indexer.indexNameService(&bl_sctxs[i], bl_txns.Topoheight, wb)
+				if err != nil {
+					return err
+				}
+			}
+
 			// Go ahead and skip any in sfscidexclusion ahead of looking at method. Doesn't matter as we won't store it at all.
 			if scidExist(indexer.SFSCIDExclusion, bl_sctxs[i].Scid) {
 				logger.Debugf("[indexInvokes] Not appending invoke data SCID '%s' as it resides within SFSCIDExclusion - '%v'.", bl_sctxs[i].Scid, indexer.SFSCIDExclusion)
@@ -1464,30 +1418,16 @@ func (indexer *Indexer) indexInvokes(bl_sctxs []structures.SCTXParse, bl_txns *s
 			}
 
 			if bl_sctxs[i].Method == "installsc" {
-				var contains bool
-
 				code := fmt.Sprintf("%v", bl_sctxs[i].Sc_args.Value("SC_CODE", "S"))
 
-				// Temporary check - will need something more robust to code compare potentially all except InitializePrivate() with a given template file or other filter inputs.
-				//contains := strings.Contains(code, "200 STORE(\"somevar\", 1)")
-				if len(indexer.SearchFilter) == 0 {
-					contains = true
-				} else {
-					for _, sfv := range indexer.SearchFilter {
-						contains = strings.Contains(code, sfv)
-						if contains {
-							// Break b/c we want to ensure contains remains true. Only care if it matches at least 1 case
-							break
-						}
-					}
-				}
+				contains := indexer.searchFilter.Match(code)
 
 				if !contains {
 					// Then reject the validation that this is an installsc action and move on
 					logger.Debugf("[indexInvokes-installsc] SCID %v does not contain the search filter string, moving on.", bl_sctxs[i].Scid)
 				} else {
 					// Gets the SC variables (key/value) at a given topoheight and then stores them
-					scVars, _, _, _ := indexer.RPC.GetSCVariables(bl_sctxs[i].Scid, bl_txns.Topoheight, nil, nil, nil, false)
+					scVars, _, scBalances, _ := indexer.RPC.GetSCVariables(bl_sctxs[i].Scid, bl_txns.Topoheight, nil, nil, nil, false)
 
 					if len(scVars) > 0 {
 						// Append into db for validated SC
@@ -1496,131 +1436,90 @@ func (indexer *Indexer) indexInvokes(bl_sctxs []structures.SCTXParse, bl_txns *s
 						indexer.ValidatedSCs = append(indexer.ValidatedSCs, bl_sctxs[i].Scid)
 						indexer.Unlock()
 
-						writeWait, _ := time.ParseDuration("20ms")
-						switch indexer.DBType {
-						case "gravdb":
-							for indexer.GravDBBackend.Writing == 1 {
-								if indexer.Closing {
-									return
-								}
-								//logger.Debugf("[Indexer-NewIndexer] GravitonDB is writing... sleeping for %v...", writeWait)
-								time.Sleep(writeWait)
-							}
-							indexer.GravDBBackend.Writing = 1
-							var ctrees []*graviton.Tree
-							sotree, sochanges, err := indexer.GravDBBackend.StoreOwner(bl_sctxs[i].Scid, bl_sctxs[i].Sender, true)
+						templateID := indexer.Templates.Match(code)
+						asset := ClassifyAsset(bl_sctxs[i].Scid, code, scVars, bl_txns.Topoheight)
+
+						err = indexer.stageWrite(wb, func(s storage.Storage) error {
+							_, err := s.StoreOwner(bl_sctxs[i].Scid, bl_sctxs[i].Sender)
 							if err != nil {
 								logger.Errorf("[indexInvokes-installsc] Error storing owner: %v", err)
-							} else {
-								if sochanges {
-									ctrees = append(ctrees, sotree)
+								return err
+							}
+
+							if templateID != "" {
+								_, err = s.StoreSCIDTemplate(bl_sctxs[i].Scid, templateID)
+								if err != nil {
+									logger.Errorf("[indexInvokes-installsc] ERR - storing scid template: %v", err)
+									return err
 								}
 							}
 
-							sidtree, sidchanges, err := indexer.GravDBBackend.StoreInvokeDetails(bl_sctxs[i].Scid, bl_sctxs[i].Sender, bl_sctxs[i].Entrypoint, bl_txns.Topoheight, &bl_sctxs[i], true)
+							_, err = s.StoreInvokeDetails(bl_sctxs[i].Scid, bl_sctxs[i].Sender, bl_sctxs[i].Entrypoint, bl_txns.Topoheight, &bl_sctxs[i])
 							if err != nil {
 								logger.Errorf("[indexInvokes-installsc] Err storing invoke details. Err: %v", err)
-								time.Sleep(5 * time.Second)
 								return err
-							} else {
-								if sidchanges {
-									ctrees = append(ctrees, sidtree)
-								}
 							}
 
-							svdtree, svdchanges, err := indexer.GravDBBackend.StoreSCIDVariableDetails(bl_sctxs[i].Scid, scVars, bl_txns.Topoheight, true)
+							_, err = s.StoreSCIDVariableDetails(bl_sctxs[i].Scid, scVars, bl_txns.Topoheight)
 							if err != nil {
 								logger.Errorf("[indexInvokes-installsc] ERR - storing scid variable details: %v", err)
-							} else {
-								if svdchanges {
-									ctrees = append(ctrees, svdtree)
-								}
+								return err
 							}
-							sihtree, sihchanges, err := indexer.GravDBBackend.StoreSCIDInteractionHeight(bl_sctxs[i].Scid, bl_txns.Topoheight, true)
+							_, err = s.StoreSCIDBalanceDetails(bl_sctxs[i].Scid, scBalances, bl_txns.Topoheight)
 							if err != nil {
-								logger.Errorf("[indexInvokes-installsc] ERR - storing scid interaction height: %v", err)
-							} else {
-								if sihchanges {
-									ctrees = append(ctrees, sihtree)
-								}
+								logger.Errorf("[indexInvokes-installsc] ERR - storing scid balance details: %v", err)
+								return err
 							}
-							if len(ctrees) > 0 {
-								_, err := indexer.GravDBBackend.CommitTrees(ctrees)
-								if err != nil {
-									logger.Errorf("[indexInvokes-installsc] ERR - committing trees: %v", err)
-								} else {
-									//logger.Debugf("[indexInvokes-installsc] DEBUG - cv [%v]", cv)
-								}
+							_, err = s.StoreSCIDCode(bl_sctxs[i].Scid, &structures.SCIDCode{Height: bl_txns.Topoheight, Txid: bl_sctxs[i].Txid, Code: code})
+							if err != nil {
+								logger.Errorf("[indexInvokes-installsc] ERR - storing scid code: %v", err)
+								return err
 							}
-							indexer.GravDBBackend.Writing = 0
-						case "boltdb":
-							for indexer.BBSBackend.Writing == 1 {
-								if indexer.Closing {
-									return
+							if asset != nil {
+								_, err = s.StoreSCAsset(asset)
+								if err != nil {
+									logger.Errorf("[indexInvokes-installsc] ERR - storing scid asset: %v", err)
+									return err
 								}
-								//logger.Debugf("[Indexer-IndexInvokes] BoltDB is writing... sleeping for %v... writer %v...", writeWait, indexer.BBSBackend.Writer)
-								time.Sleep(writeWait)
 							}
-							indexer.BBSBackend.Writing = 1
-							//indexer.BBSBackend.Writer = "IndexInvokes"
-
-							_, err := indexer.BBSBackend.StoreOwner(bl_sctxs[i].Scid, bl_sctxs[i].Sender)
+							_, err = s.StoreSCIDInteractionHeight(bl_sctxs[i].Scid, bl_txns.Topoheight)
 							if err != nil {
-								logger.Errorf("[indexInvokes-installsc] Error storing owner: %v", err)
+								logger.Errorf("[indexInvokes-installsc] ERR - storing scid interaction height: %v", err)
+								return err
 							}
-
-							_, err = indexer.BBSBackend.StoreInvokeDetails(bl_sctxs[i].Scid, bl_sctxs[i].Sender, bl_sctxs[i].Entrypoint, bl_txns.Topoheight, &bl_sctxs[i])
-							if err != nil {
-								logger.Errorf("[indexInvokes-installsc] Err storing invoke details. Err: %v", err)
+							return nil
+						})
+						if err != nil {
+							if err != storage.ErrWriterClosed {
 								time.Sleep(5 * time.Second)
-								return err
 							}
+							return err
+						}
 
-							_, err = indexer.BBSBackend.StoreSCIDVariableDetails(bl_sctxs[i].Scid, scVars, bl_txns.Topoheight)
-							if err != nil {
-								logger.Errorf("[indexInvokes-installsc] ERR - storing scid variable details: %v", err)
-							}
-							_, err = indexer.BBSBackend.StoreSCIDInteractionHeight(bl_sctxs[i].Scid, bl_txns.Topoheight)
-							if err != nil {
-								logger.Errorf("[indexInvokes-installsc] ERR - storing scid interaction height: %v", err)
-							}
-							indexer.BBSBackend.Writing = 0
-							//indexer.BBSBackend.Writer = ""
+						err = indexer.indexAddressActivity(bl_sctxs[i].Sender, bl_sctxs[i].Scid, bl_sctxs[i].Txid, bl_txns.Topoheight, AddressRoleInstaller, wb)
+						if err != nil {
+							return err
 						}
 
+						indexer.stageEvent(wb, func() *Event {
+							return &Event{Type: EventSCInstalled, Topoheight: bl_txns.Topoheight, SCID: bl_sctxs[i].Scid, SCTX: &bl_sctxs[i], Variables: scVars}
+						})
+
 						//logger.Debugf("[IndexInvokes] SCID: %v ; Sender: %v ; Entrypoint: %v ; topoheight : %v ; info: %v", bl_sctxs[i].Scid, bl_sctxs[i].Sender, bl_sctxs[i].Entrypoint, topoheight, &bl_sctxs[i])
 						logger.Debugf("[IndexInvokes] Sender: %v ; topoheight : %v ; args: %v ; burnValue: %v", bl_sctxs[i].Sender, bl_txns.Topoheight, bl_sctxs[i].Sc_args, bl_sctxs[i].Payloads[0].BurnValue)
 					} else {
 						logger.Debugf("[indexInvokes-installsc] SCID '%v' appears to be invalid.", bl_sctxs[i].Scid)
-						writeWait, _ := time.ParseDuration("20ms")
-						switch indexer.DBType {
-						case "gravdb":
-							if !(indexer.RunMode == "asset") {
-								for indexer.GravDBBackend.Writing == 1 {
-									if indexer.Closing {
-										return
-									}
-									//logger.Debugf("[Indexer-NewIndexer] GravitonDB is writing... sleeping for %v...", writeWait)
-									time.Sleep(writeWait)
-								}
-								indexer.GravDBBackend.Writing = 1
-								indexer.GravDBBackend.StoreInvalidSCIDDeploys(bl_sctxs[i].Scid, bl_sctxs[i].Fees, false)
-								indexer.GravDBBackend.Writing = 0
-							}
-						case "boltdb":
-							if !(indexer.RunMode == "asset") {
-								for indexer.BBSBackend.Writing == 1 {
-									if indexer.Closing {
-										return
-									}
-									//logger.Debugf("[Indexer-IndexInvokes-StoreInvalidSCIDDeploys] BoltDB is writing... sleeping for %v... writer %v...", writeWait, indexer.BBSBackend.Writer)
-									time.Sleep(writeWait)
-								}
-								indexer.BBSBackend.Writing = 1
-								//indexer.BBSBackend.Writer = "IndexInvokes"
-								indexer.BBSBackend.StoreInvalidSCIDDeploys(bl_sctxs[i].Scid, bl_sctxs[i].Fees)
-								indexer.BBSBackend.Writing = 0
-								//indexer.BBSBackend.Writer = ""
+						if !(indexer.RunMode == "asset") {
+							err = indexer.stageWrite(wb, func(s storage.Storage) error {
+								_, err := s.StoreInvalidSCIDDeploys(bl_sctxs[i].Scid, bl_sctxs[i].Fees, bl_txns.Topoheight)
+								return err
+							})
+							if err != nil {
+								logger.Errorf("[indexInvokes-installsc] ERR - storing invalid scid deploy: %v", err)
+							} else {
+								indexer.stageEvent(wb, func() *Event {
+									return &Event{Type: EventInvalidDeploy, Topoheight: bl_txns.Topoheight, SCID: bl_sctxs[i].Scid, Fees: bl_sctxs[i].Fees}
+								})
 							}
 						}
 					}
@@ -1640,40 +1539,12 @@ func (indexer *Indexer) indexInvokes(bl_sctxs []structures.SCTXParse, bl_txns *s
 						indexer.ValidatedSCs = append(indexer.ValidatedSCs, bl_sctxs[i].Scid)
 						indexer.Unlock()
 
-						writeWait, _ := time.ParseDuration("20ms")
-						switch indexer.DBType {
-						case "gravdb":
-							for indexer.GravDBBackend.Writing == 1 {
-								if indexer.Closing {
-									return
-								}
-								//logger.Debugf("[Indexer-NewIndexer] GravitonDB is writing... sleeping for %v...", writeWait)
-								time.Sleep(writeWait)
-							}
-							indexer.GravDBBackend.Writing = 1
-							_, _, err = indexer.GravDBBackend.StoreOwner(bl_sctxs[i].Scid, "", false)
-							if err != nil {
-								logger.Errorf("[indexInvokes] Error storing owner: %v", err)
-							}
-							indexer.GravDBBackend.Writing = 0
-						case "boltdb":
-							for indexer.BBSBackend.Writing == 1 {
-								if indexer.Closing {
-									return
-								}
-								//logger.Debugf("[Indexer-IndexInvokes-StoreOwner] BoltDB is writing... sleeping for %v... writer %v...", writeWait, indexer.BBSBackend.Writer)
-								time.Sleep(writeWait)
-							}
-							indexer.BBSBackend.Writing = 1
-							//indexer.BBSBackend.Writer = "IndexInvokesOwnerStore"
-
-							_, err = indexer.BBSBackend.StoreOwner(bl_sctxs[i].Scid, "")
-							if err != nil {
-								logger.Errorf("[indexInvokes] Error storing owner: %v", err)
-							}
-
-							indexer.BBSBackend.Writing = 0
-							//indexer.BBSBackend.Writer = ""
+						err = indexer.stageWrite(wb, func(s storage.Storage) error {
+							_, err := s.StoreOwner(bl_sctxs[i].Scid, "")
+							return err
+						})
+						if err != nil {
+							logger.Errorf("[indexInvokes] Error storing owner: %v", err)
 						}
 					}
 				}
@@ -1688,142 +1559,123 @@ func (indexer *Indexer) indexInvokes(bl_sctxs []structures.SCTXParse, bl_txns *s
 
 						//logger.Debugf("Tx %v matches scinvoke call filter(s). Adding %v to DB.", bl_sctxs[i].Txid, currsctx)
 
-						writeWait, _ := time.ParseDuration("20ms")
-						switch indexer.DBType {
-						case "gravdb":
-							if !(indexer.RunMode == "asset") {
-								// We can pre-get the relevant scvar details outside a write block due to daemon lookup and nothing relevant to db stores
-								var scVarsDiff []*structures.SCIDVariable
-								var scVars []*structures.SCIDVariable
-								var scCode string
-
-								// If a hardcodedscid invoke + fastsync is enabled, do not log any new details. We will only retain within DB on-launch data.
-								if scidExist(structures.Hardcoded_SCIDS, bl_sctxs[i].Scid) && indexer.Fastsync {
-									logger.Debugf("[indexInvokes] Skipping invoke detail store of '%v' since fastsync is '%v'.", bl_sctxs[i].Scid, indexer.Fastsync)
-									return
-								} else {
-									// Gets the SC variables (key/value) at a given topoheight -1 and then will compare differences to executed height and store the diffs
-									scVarsDiff = indexer.GravDBBackend.GetAllSCIDVariableDetails(bl_sctxs[i].Scid)
-
-									// Gets the SC variables (key/value) at a given topoheight
-									scVars, scCode, _, _ = indexer.RPC.GetSCVariables(bl_sctxs[i].Scid, bl_txns.Topoheight, nil, nil, nil, false)
-								}
+						if !(indexer.RunMode == "asset") {
+							// We can pre-get the relevant scvar details outside a write block due to daemon lookup and nothing relevant to db stores
+							var scVars []*structures.SCIDVariable
+							var scCode string
+							var scBalances map[string]uint64
 
-								for indexer.GravDBBackend.Writing == 1 {
-									if indexer.Closing {
-										return
-									}
-									//logger.Debugf("[Indexer-NewIndexer] GravitonDB is writing... sleeping for %v...", writeWait)
-									time.Sleep(writeWait)
-								}
-								indexer.GravDBBackend.Writing = 1
-								var ctrees []*graviton.Tree
+							// Address activity is kept regardless of fastsync
+							err = indexer.indexAddressActivity(bl_sctxs[i].Sender, bl_sctxs[i].Scid, bl_sctxs[i].Txid, bl_txns.Topoheight, AddressRoleInvoker, wb)
+							if err != nil {
+								return err
+							}
+
+							// If a hardcodedscid invoke + fastsync is enabled, do not log any new details. We will only retain within DB on-launch data.
+							if scidExist(structures.Hardcoded_SCIDS, bl_sctxs[i].Scid) && indexer.Fastsync {
+								logger.Debugf("[indexInvokes] Skipping invoke detail store of '%v' since fastsync is '%v'.", bl_sctxs[i].Scid, indexer.Fastsync)
+								return
+							} else {
+								// Gets the SC variables (key/value) at a given topoheight
+								scVars, scCode, scBalances, _ = indexer.RPC.GetSCVariables(bl_sctxs[i].Scid, bl_txns.Topoheight, nil, nil, nil, false)
+							}
+
+							// Ownership/metadata of assets change through invokes, so they are re-classified against the current variables
+							var asset *structures.SCAsset
+							if len(scVars) > 0 {
+								asset = ClassifyAsset(bl_sctxs[i].Scid, scCode, scVars, bl_txns.Topoheight)
+							}
+
+							// Set within the write below, once the diff against the stored variables is known
+							var scVarsStore []*structures.SCIDVariable
 
-								sidtree, sidchanges, err := indexer.GravDBBackend.StoreInvokeDetails(bl_sctxs[i].Scid, bl_sctxs[i].Sender, bl_sctxs[i].Entrypoint, bl_txns.Topoheight, &currsctx, true)
+							err = indexer.stageWrite(wb, func(s storage.Storage) error {
+								_, err := s.StoreInvokeDetails(bl_sctxs[i].Scid, bl_sctxs[i].Sender, bl_sctxs[i].Entrypoint, bl_txns.Topoheight, &currsctx)
 								if err != nil {
 									logger.Errorf("[indexInvokes] Err storing invoke details. Err: %v", err)
-									time.Sleep(5 * time.Second)
-									indexer.GravDBBackend.Writing = 0
 									return err
-								} else {
-									if sidchanges {
-										ctrees = append(ctrees, sidtree)
-									}
 								}
+								err = indexer.storeArgIndex(s, &currsctx)
+								if err != nil {
+									return err
+								}
+
+								// Gets the SC variables (key/value) at a given topoheight -1 and then will compare differences to executed height and store the diffs. Read within the write batch so prior writes are seen
+								scVarsDiff := s.GetAllSCIDVariableDetails(bl_sctxs[i].Scid)
 
-								indexer.InterpretSC(bl_sctxs[i].Scid, scCode)
-								scVarsStore, err := indexer.DiffSCIDVariables(scVarsDiff, scVars, bl_sctxs[i].Scid, bl_txns.Topoheight)
+								scVarsStore, err = indexer.DiffSCIDVariables(scVarsDiff, scVars, bl_sctxs[i].Scid, bl_txns.Topoheight)
 								if err != nil {
 									// This could be flagged as 'err' if say there were no variables to begin with and still. Is that necessary?
 									logger.Errorf("[indexInvokes-installsc] ERR - %v", err)
+									scVarsStore = nil
 								} else if len(scVarsStore) > 0 {
-									// If scVarsStore length is greater than 0, we can assume there were diffs. Otherwise the varstores are equal and move on.
-									svdtree, svdchanges, err := indexer.GravDBBackend.StoreSCIDVariableDetails(bl_sctxs[i].Scid, scVarsStore, bl_txns.Topoheight, true)
+									// If scVarsStore length is greater than 0, we can assume there were diffs. Otherwise the varstores are equal and move on. The full set is passed as the store keeps the changes itself (along with periodic keyframes)
+									_, err = s.StoreSCIDVariableDetails(bl_sctxs[i].Scid, scVars, bl_txns.Topoheight)
 									if err != nil {
-										logger.Errorf("[indexInvokes-installsc] ERR - storing scid variable details: %v", err)
-									} else {
-										if svdchanges {
-											ctrees = append(ctrees, svdtree)
-										}
+										logger.Errorf("[indexInvokes] ERR - storing scid variable details: %v", err)
+										return err
 									}
 								}
-								sihtree, sihchanges, err := indexer.GravDBBackend.StoreSCIDInteractionHeight(bl_sctxs[i].Scid, bl_txns.Topoheight, true)
-								if err != nil {
-									logger.Errorf("[indexInvokes] ERR - storing scid interaction height: %v", err)
-								} else {
-									if sihchanges {
-										ctrees = append(ctrees, sihtree)
+								// Balances are nil if GetSC failed, in which case the last stored balances are left to stand
+								if scBalances != nil {
+									_, err = s.StoreSCIDBalanceDetails(bl_sctxs[i].Scid, scBalances, bl_txns.Topoheight)
+									if err != nil {
+										logger.Errorf("[indexInvokes] ERR - storing scid balance details: %v", err)
+										return err
 									}
 								}
-
-								if len(ctrees) > 0 {
-									_, err := indexer.GravDBBackend.CommitTrees(ctrees)
+								if asset != nil && assetChanged(s.GetSCAsset(bl_sctxs[i].Scid), asset) {
+									_, err = s.StoreSCAsset(asset)
 									if err != nil {
-										logger.Errorf("[indexInvokes] ERR - committing trees: %v", err)
-									} else {
-										//logger.Debugf("[indexInvokes] DEBUG - cv [%v]", cv)
+										logger.Errorf("[indexInvokes] ERR - storing scid asset: %v", err)
+										return err
 									}
 								}
-								indexer.GravDBBackend.Writing = 0
-							}
-						case "boltdb":
-							if !(indexer.RunMode == "asset") {
-								// We can pre-get the relevant scvar details outside a write block due to daemon lookup and nothing relevant to db stores
-								var scVarsDiff []*structures.SCIDVariable
-								//var scVarsDiff2 []*structures.SCIDVariable
-								var scVars []*structures.SCIDVariable
-								var scCode string
-
-								// If a hardcodedscid invoke + fastsync is enabled, do not log any new details. We will only retain within DB on-launch data.
-								if scidExist(structures.Hardcoded_SCIDS, bl_sctxs[i].Scid) && indexer.Fastsync {
-									logger.Debugf("[indexInvokes] Skipping invoke detail store of '%v' since fastsync is '%v'.", bl_sctxs[i].Scid, indexer.Fastsync)
-									return
-								} else {
-									// Gets the SC variables (key/value) at a given topoheight -1 and then will compare differences to executed height and store the diffs
-									scVarsDiff = indexer.BBSBackend.GetAllSCIDVariableDetails(bl_sctxs[i].Scid)
-
-									// Gets the SC variables (key/value) at a given topoheight
-									scVars, scCode, _, _ = indexer.RPC.GetSCVariables(bl_sctxs[i].Scid, bl_txns.Topoheight, nil, nil, nil, false)
-								}
-
-								for indexer.BBSBackend.Writing == 1 {
-									if indexer.Closing {
-										return
+								// UPDATE_SC_CODE can rewrite the SC, keep a new version when the code differs from the last stored one. Without a stored version (e.g. indexed before code versions were kept) it is kept from when it was first seen
+								if scCode != "" {
+									var scCodeStore *structures.SCIDCode
+									if prevCode := s.GetSCIDCodeAtTopoheight(bl_sctxs[i].Scid, bl_txns.Topoheight); prevCode == nil {
+										scCodeStore = &structures.SCIDCode{Height: bl_txns.Topoheight, Code: scCode}
+									} else if prevCode.Code != scCode {
+										logger.Printf("[indexInvokes] SCID '%v' code updated by txid '%v' at height %v", bl_sctxs[i].Scid, bl_sctxs[i].Txid, bl_txns.Topoheight)
+										scCodeStore = &structures.SCIDCode{Height: bl_txns.Topoheight, Txid: bl_sctxs[i].Txid, Code: scCode}
 									}
-									//logger.Debugf("[Indexer-IndexInvokes] BoltDB is writing... sleeping for %v... writer %v...", writeWait, indexer.BBSBackend.Writer)
-									time.Sleep(writeWait)
-								}
-								indexer.BBSBackend.Writing = 1
-								//indexer.BBSBackend.Writer = "IndexInvokesDetailsStore"
-
-								_, err := indexer.BBSBackend.StoreInvokeDetails(bl_sctxs[i].Scid, bl_sctxs[i].Sender, bl_sctxs[i].Entrypoint, bl_txns.Topoheight, &currsctx)
-								if err != nil {
-									logger.Errorf("[indexInvokes] Err storing invoke details. Err: %v", err)
-									time.Sleep(5 * time.Second)
-									indexer.BBSBackend.Writing = 0
-									//indexer.BBSBackend.Writer = ""
-									return err
-								}
-								indexer.InterpretSC(bl_sctxs[i].Scid, scCode)
-								scVarsStore, err := indexer.DiffSCIDVariables(scVarsDiff, scVars, bl_sctxs[i].Scid, bl_txns.Topoheight)
-								if err != nil {
-									// This could be flagged as 'err' if say there were no variables to begin with and still. Is that necessary?
-									logger.Errorf("[indexInvokes-installsc] ERR - %v", err)
-								} else if len(scVarsStore) > 0 {
-									// If scVarsStore length is greater than 0, we can assume there were diffs. Otherwise the varstores are equal and move on.
-									_, err = indexer.BBSBackend.StoreSCIDVariableDetails(bl_sctxs[i].Scid, scVarsStore, bl_txns.Topoheight)
-									if err != nil {
-										logger.Errorf("[indexInvokes] ERR - storing scid variable details: %v", err)
+									if scCodeStore != nil {
+										_, err = s.StoreSCIDCode(bl_sctxs[i].Scid, scCodeStore)
+										if err != nil {
+											logger.Errorf("[indexInvokes] ERR - storing scid code: %v", err)
+											return err
+										}
+										err = indexer.storeSCIDTemplate(s, bl_sctxs[i].Scid, scCode)
+										if err != nil {
+											logger.Errorf("[indexInvokes] ERR - storing scid template: %v", err)
+											return err
+										}
 									}
 								}
-								_, err = indexer.BBSBackend.StoreSCIDInteractionHeight(bl_sctxs[i].Scid, bl_txns.Topoheight)
+								_, err = s.StoreSCIDInteractionHeight(bl_sctxs[i].Scid, bl_txns.Topoheight)
 								if err != nil {
 									logger.Errorf("[indexInvokes] ERR - storing scid interaction height: %v", err)
+									return err
 								}
-
-								indexer.BBSBackend.Writing = 0
-								//indexer.BBSBackend.Writer = ""
+								return nil
+							})
+							if err != nil {
+								if err != storage.ErrWriterClosed {
+									time.Sleep(5 * time.Second)
+								}
+								return err
 							}
+
+							indexer.stageEvent(wb, func() *Event {
+								return &Event{Type: EventSCInvoked, Topoheight: bl_txns.Topoheight, SCID: currsctx.Scid, SCTX: &currsctx}
+							})
+							indexer.stageEvent(wb, func() *Event {
+								if len(scVarsStore) == 0 {
+									return nil
+								}
+								return &Event{Type: EventSCVariablesChanged, Topoheight: bl_txns.Topoheight, SCID: currsctx.Scid, Variables: scVarsStore}
+							})
 						}
 
 						//logger.Debugf("[IndexInvokes] SCID: %v ; Sender: %v ; Entrypoint: %v ; topoheight : %v ; info: %v", bl_sctxs[i].Scid, bl_sctxs[i].Sender, bl_sctxs[i].Entrypoint, topoheight, &currsctx)
@@ -1856,71 +1708,49 @@ func scidExist(s []string, str string) bool {
 
 // DERO.GetTxPool rpc call for returning current mempool txns
 func (client *Client) GetTxPool() (txlist []string, err error) {
-	// TODO: Make this a consumable func with rpc calls and timeout / wait / retry logic for deduplication of code. Or use alternate method of checking [primary use case is remote nodes]
-	var reconnect_count int
-	for {
-		var err error
-
-		var io rpc.GetTxPool_Result
-
-		if err = client.RPC.CallResult(context.Background(), "DERO.GetTxPool", nil, &io); err != nil {
-			if reconnect_count >= 5 {
-				logger.Errorf("[getTxPool] GetTxPool failed: %v . (%v / 5 times)", err, reconnect_count)
-				break
-			}
-			time.Sleep(1 * time.Second)
-
-			reconnect_count++
-
-			continue
-		}
+	var io rpc.GetTxPool_Result
 
-		txlist = io.Tx_list
-		break
+	if err = client.Call("DERO.GetTxPool", nil, &io); err != nil {
+		logger.Errorf("[getTxPool] GetTxPool failed: %v", err)
+		return
 	}
 
+	txlist = io.Tx_list
+
 	return
 }
 
 // DERO.GetBlockHeaderByTopoHeight rpc call for returning block hash at a particular topoheight
 func (client *Client) getBlockHash(height uint64) (hash string, err error) {
-	//logger.Debugf("[getBlockHash] Attempting to get block details at topoheight %v", height)
-	// TODO: Make this a consumable func with rpc calls and timeout / wait / retry logic for deduplication of code. Or use alternate method of checking [primary use case is remote nodes]
-	var reconnect_count int
-	for {
-		var err error
-
-		var io rpc.GetBlockHeaderByHeight_Result
-		var ip = rpc.GetBlockHeaderByTopoHeight_Params{TopoHeight: height}
+	var io rpc.GetBlockHeaderByHeight_Result
+	var ip = rpc.GetBlockHeaderByTopoHeight_Params{TopoHeight: height}
 
-		if err = client.RPC.CallResult(context.Background(), "DERO.GetBlockHeaderByTopoHeight", ip, &io); err != nil {
-			logger.Debugf("[getBlockHash] %v - GetBlockHeaderByTopoHeight failed: %v . Trying again (%v / 5)", height, err, reconnect_count)
-			//return hash, fmt.Errorf("GetBlockHeaderByTopoHeight failed: %v", err)
+	if err = client.Call("DERO.GetBlockHeaderByTopoHeight", ip, &io); err != nil {
+		logger.Debugf("[getBlockHash] %v - GetBlockHeaderByTopoHeight failed: %v", height, err)
+		return
+	}
 
-			// TODO: Perhaps just a .Closing = true call here and then gnomonserver can be polling for any indexers with .Closing then close the rest cleanly. If packaged, then just have to handle themselves w/ .Close()
-			if reconnect_count >= 5 {
-				logger.Errorf("[getBlockHash] %v - GetBlockHeaderByTopoHeight failed: %v . (%v / 5 times)", height, err, reconnect_count)
-				break
-			}
-			time.Sleep(1 * time.Second)
+	hash = io.Block_Header.Hash
 
-			reconnect_count++
+	return
+}
 
-			continue
-		} else {
-			//logger.Debugf("[getBlockHash] Retrieved block header from topoheight %v", height)
-			//mainnet = !info.Testnet // inverse of testnet is mainnet
-			//logger.Debugf("%v", io)
-		}
+// DERO.GetBlockHeaderByTopoHeight rpc call for returning the block height of the block at a particular topoheight
+func (client *Client) getBlockHeight(topoheight uint64) (height int64, err error) {
+	var io rpc.GetBlockHeaderByHeight_Result
+	var ip = rpc.GetBlockHeaderByTopoHeight_Params{TopoHeight: topoheight}
 
-		hash = io.Block_Header.Hash
-		break
+	if err = client.Call("DERO.GetBlockHeaderByTopoHeight", ip, &io); err != nil {
+		logger.Debugf("[getBlockHeight] %v - GetBlockHeaderByTopoHeight failed: %v", topoheight, err)
+		return
 	}
 
-	return hash, err
+	height = io.Block_Header.Height
+
+	return
 }
 
-// Looped interval to probe DERO.GetInfo rpc call for updating chain topoheight. Also handles keeping connection to daemon via RPC.Connect() calls
+// Looped interval to probe DERO.GetInfo rpc call for updating chain topoheight. Also handles keeping connection to daemon(s) via RPC.Connect() calls and their health checks for failover
 func (indexer *Indexer) getInfo() {
 	var reconnect_count int
 	for {
@@ -1930,23 +1760,21 @@ func (indexer *Indexer) getInfo() {
 		}
 		var err error
 
-		// Check connection to be sure indexer.Endpoint hasn't changed. If it has, then update. Otherwise Connect will just return back no issues
+		// Check connection to be sure indexer.Endpoint hasn't changed. If it has, then update. Otherwise Connect will just re-dial any dropped endpoints
 		indexer.RPC.Connect(indexer.Endpoint)
 
-		var info *structures.GetInfo
-
-		// collect all the data afresh,  execute rpc to service
-		if err = indexer.RPC.RPC.CallResult(context.Background(), "DERO.GetInfo", nil, &info); err != nil {
-			logger.Debugf("[getInfo] ERROR - GetInfo failed: %v . Trying again (%v / 5)", err, reconnect_count)
+		// collect all the data afresh from each endpoint, endpoints that are down or behind are failed over from
+		info, err := indexer.RPC.CheckHealth()
+		if err != nil {
+			logger.Debugf("[getInfo] ERROR - GetInfo failed on all endpoints: %v . Trying again (%v / 5)", err, reconnect_count)
 
 			// TODO: Perhaps just a .Closing = true call here and then gnomonserver can be polling for any indexers with .Closing then close the rest cleanly. If packaged, then just have to handle themselves w/ .Close()
 			if reconnect_count >= 5 && indexer.CloseOnDisconnect {
 				indexer.Close()
-				logger.Errorf("[getInfo] ERROR - GetInfo failed: %v . (%v / 5 times)", err, reconnect_count)
+				logger.Errorf("[getInfo] ERROR - GetInfo failed on all endpoints: %v . (%v / 5 times)", err, reconnect_count)
 				break
 			}
 			time.Sleep(1 * time.Second)
-			indexer.RPC.Connect(indexer.Endpoint) // Attempt to re-connect now
 
 			reconnect_count++
 
@@ -1959,13 +1787,7 @@ func (indexer *Indexer) getInfo() {
 			//logger.Debugf("%v", info)
 		}
 
-		var currStoreGetInfo *structures.GetInfo
-		switch indexer.DBType {
-		case "gravdb":
-			currStoreGetInfo = indexer.GravDBBackend.GetGetInfoDetails()
-		case "boltdb":
-			currStoreGetInfo = indexer.BBSBackend.GetGetInfoDetails()
-		}
+		currStoreGetInfo := indexer.Backend.GetGetInfoDetails()
 
 		if currStoreGetInfo != nil {
 			// Ensure you are not connecting to testnet or mainnet unintentionally based on store getinfo history
@@ -1973,47 +1795,21 @@ func (indexer *Indexer) getInfo() {
 				if currStoreGetInfo.Height < info.Height {
 					structureGetInfo := info
 
-					writeWait, _ := time.ParseDuration("20ms")
-					switch indexer.DBType {
-					case "gravdb":
-						for indexer.GravDBBackend.Writing == 1 {
-							if indexer.Closing {
-								return
-							}
-							//logger.Debugf("[Indexer-NewIndexer] GravitonDB is writing... sleeping for %v...", writeWait)
-							time.Sleep(writeWait)
-						}
-						indexer.GravDBBackend.Writing = 1
-						_, _, err := indexer.GravDBBackend.StoreGetInfoDetails(structureGetInfo, false)
-						if err != nil {
-							logger.Errorf("[getInfo] ERROR - GetInfo store failed: %v", err)
-						}
-						indexer.GravDBBackend.Writing = 0
-					case "boltdb":
-						for indexer.BBSBackend.Writing == 1 {
-							if indexer.Closing {
-								return
-							}
-							//logger.Debugf("[Indexer-getinfo-StoreGetInfoDetails] BoltDB is writing... sleeping for %v... writer %v...", writeWait, indexer.BBSBackend.Writer)
-							time.Sleep(writeWait)
-						}
-						indexer.BBSBackend.Writing = 1
-						//indexer.BBSBackend.Writer = "getInfo"
-						_, err := indexer.BBSBackend.StoreGetInfoDetails(structureGetInfo)
-						if err != nil {
-							logger.Errorf("[getInfo] ERROR - GetInfo store failed: %v", err)
-						}
-						indexer.BBSBackend.Writing = 0
-						//indexer.BBSBackend.Writer = ""
+					err := indexer.Writer.Write(func(s storage.Storage) error {
+						_, err := s.StoreGetInfoDetails(structureGetInfo)
+						return err
+					})
+					if err == storage.ErrWriterClosed {
+						return
+					} else if err != nil {
+						logger.Errorf("[getInfo] ERROR - GetInfo store failed: %v", err)
 					}
 				}
 			} else {
-				if indexer.RPC.WS != nil {
-					// Remote addr (current ws connection endpoint) does not match indexer endpoint - re-connecting
+				if indexer.RPC.IsConnected() {
+					// Endpoint is on a different network than the stored data - drop the connection
 					logger.Errorf("[getInfo] ERROR - Endpoint network (testnet - %v) is not the same as past stored network (testnet - %v)", info.Testnet, currStoreGetInfo.Testnet)
-					indexer.RPC.Lock()
-					indexer.RPC.WS.Close()
-					indexer.RPC.Unlock()
+					indexer.RPC.Disconnect()
 
 					indexer.Lock()
 					indexer.ChainHeight = 0
@@ -2026,38 +1822,14 @@ func (indexer *Indexer) getInfo() {
 		} else {
 			structureGetInfo := info
 
-			writeWait, _ := time.ParseDuration("20ms")
-			switch indexer.DBType {
-			case "gravdb":
-				for indexer.GravDBBackend.Writing == 1 {
-					if indexer.Closing {
-						return
-					}
-					//logger.Debugf("[Indexer-NewIndexer] GravitonDB is writing... sleeping for %v...", writeWait)
-					time.Sleep(writeWait)
-				}
-				indexer.GravDBBackend.Writing = 1
-				_, _, err := indexer.GravDBBackend.StoreGetInfoDetails(structureGetInfo, false)
-				if err != nil {
-					logger.Errorf("[getInfo] ERROR - GetInfo store failed: %v", err)
-				}
-				indexer.GravDBBackend.Writing = 0
-			case "boltdb":
-				for indexer.BBSBackend.Writing == 1 {
-					if indexer.Closing {
-						return
-					}
-					//logger.Debugf("[Indexer-getinfo-StoreGetInfoDetails] BoltDB is writing... sleeping for %v... writer %v...", writeWait, indexer.BBSBackend.Writer)
-					time.Sleep(writeWait)
-				}
-				indexer.BBSBackend.Writing = 1
-				//indexer.BBSBackend.Writer = "getInfo"
-				_, err := indexer.BBSBackend.StoreGetInfoDetails(structureGetInfo)
-				if err != nil {
-					logger.Errorf("[getInfo] ERROR - GetInfo store failed: %v", err)
-				}
-				indexer.BBSBackend.Writing = 0
-				//indexer.BBSBackend.Writer = ""
+			err := indexer.Writer.Write(func(s storage.Storage) error {
+				_, err := s.StoreGetInfoDetails(structureGetInfo)
+				return err
+			})
+			if err == storage.ErrWriterClosed {
+				return
+			} else if err != nil {
+				logger.Errorf("[getInfo] ERROR - GetInfo store failed: %v", err)
 			}
 		}
 		indexer.Lock()
@@ -2080,10 +1852,9 @@ func (indexer *Indexer) getWalletHeight() {
 		var info rpc.GetHeight_Result
 
 		// collect all the data afresh,  execute rpc to service
-		if err = indexer.RPC.RPC.CallResult(context.Background(), "WALLET.GetHeight", nil, &info); err != nil {
+		if err = indexer.WalletRPC.Call("WALLET.GetHeight", nil, &info); err != nil {
 			logger.Errorf("[getWalletHeight] ERROR - GetHeight failed: %v", err)
 			time.Sleep(1 * time.Second)
-			indexer.RPC.Connect(indexer.Endpoint) // Attempt to re-connect now
 			continue
 		} else {
 			//mainnet = !info.Testnet // inverse of testnet is mainnet
@@ -2109,37 +1880,27 @@ func (client *Client) GetSCVariables(scid string, topoheight int64, keysuint64 [
 	} else {
 		getSCParams = rpc.GetSC_Params{SCID: scid, Code: true, Variables: true, TopoHeight: topoheight}
 	}
-	if client.WS == nil {
+	if !client.IsConnected() {
 		return
 	}
 
-	// TODO: Make this a consumable func with rpc calls and timeout / wait / retry logic for deduplication of code. Or use alternate method of checking [primary use case is remote nodes]
-	var reconnect_count int
-	for {
-		if err = client.RPC.CallResult(context.Background(), "DERO.GetSC", getSCParams, &getSCResults); err != nil {
-			// Catch for v139 daemons that reject >1024 var returns and we need to be specific (if defined, otherwise we'll err out after 5 tries)
-			if strings.Contains(err.Error(), "max 1024 variables can be returned") || strings.Contains(err.Error(), "namesc cannot request all variables") {
-				if keysuint64 != nil || keysstring != nil || keysbytes != nil {
-					getSCParams = rpc.GetSC_Params{SCID: scid, Code: true, Variables: false, TopoHeight: topoheight, KeysUint64: keysuint64, KeysString: keysstring, KeysBytes: keysbytes}
-				} else {
-					// Default to at least return code true and variables false if we run into max var can't be returned (derod v139)
-					getSCParams = rpc.GetSC_Params{SCID: scid, Code: true, Variables: false, TopoHeight: topoheight}
-				}
-			}
-
-			logger.Debugf("[GetSCVariables] ERROR - GetSCVariables failed for '%v': %v . Trying again (%v / 5)", scid, err, reconnect_count+1)
-			if reconnect_count >= 5 {
-				logger.Errorf("[GetSCVariables] ERROR - GetSCVariables failed for '%v': %v . (%v / 5 times)", scid, err, reconnect_count)
-				return variables, code, balances, err
+	if err = client.Call("DERO.GetSC", getSCParams, &getSCResults); err != nil {
+		// Catch for v139 daemons that reject >1024 var returns and we need to be specific (if defined, otherwise return the err)
+		if strings.Contains(err.Error(), "max 1024 variables can be returned") || strings.Contains(err.Error(), "namesc cannot request all variables") {
+			if keysuint64 != nil || keysstring != nil || keysbytes != nil {
+				getSCParams = rpc.GetSC_Params{SCID: scid, Code: true, Variables: false, TopoHeight: topoheight, KeysUint64: keysuint64, KeysString: keysstring, KeysBytes: keysbytes}
+			} else {
+				// Default to at least return code true and variables false if we run into max var can't be returned (derod v139)
+				getSCParams = rpc.GetSC_Params{SCID: scid, Code: true, Variables: false, TopoHeight: topoheight}
 			}
-			time.Sleep(1 * time.Second)
-
-			reconnect_count++
 
-			continue
+			err = client.Call("DERO.GetSC", getSCParams, &getSCResults)
 		}
 
-		break
+		if err != nil {
+			logger.Errorf("[GetSCVariables] ERROR - GetSCVariables failed for '%v': %v", scid, err)
+			return variables, code, balances, err
+		}
 	}
 
 	code = getSCResults.Code
@@ -3170,50 +2931,18 @@ func (ind *Indexer) Close() {
 	// Tell indexer a closing operation is happening; this will close out loops on next iteration
 	ind.Closing = true
 
-	switch ind.DBType {
-	case "gravdb":
-		ind.GravDBBackend.Closing = true
-	case "boltdb":
-		ind.BBSBackend.Closing = true
-	}
-
 	// Sleep for safety
 	time.Sleep(time.Second * 1)
 
 	// Close websocket connection cleanly
-	if ind.RPC.WS != nil {
-		ind.RPC.WS.Close()
-	}
+	ind.RPC.Close()
+	ind.WalletRPC.Close()
 
-	// Close out grav db cleanly
-	writeWait, _ := time.ParseDuration("20ms")
-	switch ind.DBType {
-	case "gravdb":
-		for ind.GravDBBackend.Writing == 1 {
-			if ind.Closing {
-				return
-			}
-			//logger.Debugf("[Indexer-NewIndexer] GravitonDB is writing... sleeping for %v...", writeWait)
-			time.Sleep(writeWait)
-		}
-		ind.GravDBBackend.Writing = 1
-		ind.GravDBBackend.DB.Close()
-		ind.GravDBBackend.Writing = 0
-	case "boltdb":
-		for ind.BBSBackend.Writing == 1 {
-			if ind.Closing {
-				return
-			}
-			//logger.Debugf("[Indexer-Close] BoltDB is writing... sleeping for %v... writer %v...", writeWait, ind.BBSBackend.Writer)
-			time.Sleep(writeWait)
-		}
-		ind.BBSBackend.Writing = 1
-		//ind.BBSBackend.Writer = "Close"
-		ind.BBSBackend.DB.Sync()
-		ind.BBSBackend.DB.Close()
-		ind.BBSBackend.Writing = 0
-		//ind.BBSBackend.Writer = ""
-	}
+	// Wait on queued writes to be committed, then close out db cleanly
+	ind.Writer.Close()
+	ind.Backend.Close()
+
+	ind.closeSubscriptions()
 }
 
 func InitLog(args map[string]interface{}, console io.Writer) {
@@ -1,34 +1,320 @@
 package indexer
 
 import (
-	"time"
+	"fmt"
+	"runtime/debug"
+	"strconv"
 
+	"github.com/civilware/Gnomon/structures"
+	"github.com/deroproject/derohe/cryptography/crypto"
 	"github.com/deroproject/derohe/dvm"
+	"github.com/deroproject/derohe/rpc"
 )
 
-func (indexer *Indexer) InterpretSC(scid string, code string) {
+// Same compute gas limit the chain gives every SC call
+const simulation_gas_compute_limit = int64(10000000)
 
-	return
+// Dry-runs entrypoint of scid against the indexed state at topoheight (latest indexed height if <= 0). Nothing is written, neither to the db nor the chain.
+// args are the entrypoint params (uint64 or string), signer is the dero address the call is signed by ("" for an anonymous/ringsize > 2 call) and transfers are the amounts (by asset scid, "" for DERO) sent along with the call.
+// scid must be indexed. The SC code is the stored code version at topoheight (else 'C' of the indexed variables, else from the daemon), the variables are the indexed variables at topoheight and the balances from the indexed balances. BLOCK_HEIGHT(), BLOCK_TIMESTAMP() etc. are those of the block at topoheight.
+// err is only returned if the simulation could not be setup, errors of the SC run itself are within sim.Error
+func (indexer *Indexer) InterpretSC(scid string, entrypoint string, topoheight int64, args map[string]interface{}, signer string, transfers map[string]uint64) (sim *structures.SCSimulation, err error) {
+	if topoheight <= 0 {
+		topoheight = indexer.LastIndexedHeight
+	}
+	if err = CheckEarliestHeight(indexer.Backend, topoheight); err != nil {
+		return nil, err
+	}
+
+	scidHash := crypto.HashHexToHash(scid)
+	if scidHash == (crypto.Hash{}) {
+		return nil, fmt.Errorf("invalid scid '%v'", scid)
+	}
+
+	// Only the indexed state is simulated against, a scid that is not indexed would run against empty variables and balances
+	indexer.RLock()
+	indexed := scidExist(indexer.ValidatedSCs, scid)
+	indexer.RUnlock()
+	if !indexed {
+		return nil, fmt.Errorf("scid '%v' is not indexed", scid)
+	}
+
+	mainnet := true
+	if getinfo := indexer.Backend.GetGetInfoDetails(); getinfo != nil {
+		mainnet = !getinfo.Testnet
+	}
+
+	// Indexed state at topoheight, in the form the DVM loads it from the chain
+	diskVars := make(map[string]dvm.Variable)
+	var code string
+	for _, v := range indexer.Backend.GetSCIDVariableDetailsAtTopoheight(scid, topoheight) {
+		key, kok := simulationVariable(v.Key, false)
+		value, vok := simulationVariable(v.Value, true)
+		if !kok || !vok {
+			continue
+		}
+		if key.Type == dvm.String && key.ValueString == "C" && value.Type == dvm.String {
+			code = value.ValueString
+		}
+		diskVars[string(key.MarshalBinaryPanic())] = value
+	}
+
+	if stored := indexer.Backend.GetSCIDCodeAtTopoheight(scid, topoheight); stored != nil {
+		code = stored.Code
+	}
+
+	if code == "" {
+		_, code, _, err = indexer.RPC.GetSCVariables(scid, topoheight, nil, nil, nil, true)
+		if err != nil {
+			return nil, fmt.Errorf("could not get code of '%v': %v", scid, err)
+		}
+		if code == "" {
+			return nil, fmt.Errorf("no code found for '%v' at height %v", scid, topoheight)
+		}
+	}
 
 	SC, pos, err := dvm.ParseSmartContract(code)
 	if err != nil {
-		logger.Errorf("[InterpretSC] ERR on SC Parse SCID '%s' - %v", scid, err)
-		return
+		return nil, fmt.Errorf("could not parse code of '%v' at %v: %v", scid, pos, err)
 	}
 
-	logger.Debugf("[InterpretSC] SCID '%s' - pos: %v ; SC: %v", scid, pos, SC)
+	function, ok := SC.Functions[entrypoint]
+	if !ok {
+		return nil, fmt.Errorf("'%v' does not contain entrypoint '%v'", scid, entrypoint)
+	}
+
+	var signerKey [33]byte
+	if signer != "" {
+		addr, aerr := rpc.NewAddress(signer)
+		if aerr != nil {
+			return nil, fmt.Errorf("invalid signer '%v': %v", signer, aerr)
+		}
+		copy(signerKey[:], addr.Compressed())
+	}
+
+	assets := make(map[crypto.Hash]uint64)
+	for asset, amount := range transfers {
+		var assetHash crypto.Hash
+		if asset != "" {
+			assetHash = crypto.HashHexToHash(asset)
+			if assetHash == (crypto.Hash{}) {
+				return nil, fmt.Errorf("invalid transfer asset '%v'", asset)
+			}
+		}
+		assets[assetHash] += amount
+	}
+
+	balances := indexer.Backend.GetSCIDBalanceDetailsAtTopoheight(scid, topoheight)
+
+	// Block inputs are best effort, the state does not depend on them
+	var blid crypto.Hash
+	var blHeight, blTimestamp uint64
+	blHeight = uint64(topoheight)
+	var io rpc.GetBlockHeaderByHeight_Result
+	var ip = rpc.GetBlockHeaderByTopoHeight_Params{TopoHeight: uint64(topoheight)}
+	if herr := indexer.RPC.Call("DERO.GetBlockHeaderByTopoHeight", ip, &io); herr != nil {
+		logger.Debugf("[InterpretSC] %v - GetBlockHeaderByTopoHeight failed: %v", topoheight, herr)
+	} else {
+		blid = crypto.HashHexToHash(io.Block_Header.Hash)
+		blHeight = uint64(io.Block_Header.Height)
+		blTimestamp = io.Block_Header.Timestamp / 1000
+	}
+
+	// Setup the same as dvm.Execute_sc_function, less the chain
+	txStore := dvm.Initialize_TX_store()
+	state := &dvm.Shared_State{
+		Store:    txStore,
+		Assets:   assets,
+		RamStore: map[dvm.Variable]dvm.Variable{},
+		SCIDSELF: scidHash,
+		Chain_inputs: &dvm.Blockchain_Input{
+			BL_HEIGHT:     blHeight,
+			BL_TOPOHEIGHT: uint64(topoheight),
+			BL_TIMESTAMP:  blTimestamp,
+			SCID:          scidHash,
+			BLID:          blid,
+			Signer:        string(signerKey[:]),
+		},
+	}
+
+	txStore.DiskLoader = func(key dvm.DataKey, found *uint64) (result dvm.Variable) {
+		if v, ok := diskVars[string(key.MarshalBinaryPanic())]; ok && key.SCID == scidHash {
+			*found = 1
+			result = v
+		}
+		return
+	}
+	txStore.BalanceLoader = func(key dvm.DataKey) uint64 {
+		if key.SCID != scidHash {
+			return 0
+		}
+		return balances[key.Asset.String()] + assets[key.Asset]
+	}
+	txStore.BalanceAtStart = balances[crypto.Hash{}.String()]
+	txStore.SCID = scidHash
+	txStore.State = state
 
 	params := make(map[string]interface{})
-	entrypoint := "InputSCID"
-	state := &dvm.Shared_State{Chain_inputs: &dvm.Blockchain_Input{}}
-	result, err := dvm.RunSmartContract(&SC, entrypoint, state, params)
+	for _, p := range function.Params {
+		switch {
+		case p.Type == dvm.Uint64 && p.Name == "value":
+			// Same as the chain, value is always the DERO sent along
+			params[p.Name] = fmt.Sprintf("%d", assets[crypto.Hash{}])
+		case p.Type == dvm.Uint64:
+			var u uint64
+			u, err = simulationArgUint64(args[p.Name])
+			if err != nil {
+				return nil, fmt.Errorf("entrypoint '%v' param '%v': %v", entrypoint, p.Name, err)
+			}
+			params[p.Name] = fmt.Sprintf("%d", u)
+		case p.Type == dvm.String:
+			s, ok := args[p.Name].(string)
+			if !ok {
+				return nil, fmt.Errorf("entrypoint '%v' param '%v' is missing or not a string", entrypoint, p.Name)
+			}
+			params[p.Name] = s
+		default:
+			return nil, fmt.Errorf("entrypoint '%v' param '%v' type not supported", entrypoint, p.Name)
+		}
+	}
 
-	if err != nil {
-		logger.Errorf("[InterpretSC] ERR on RunSmartContract SCID '%s' - %v", scid, err)
-		time.Sleep(60 * time.Second)
+	state.GasComputeLimit = simulation_gas_compute_limit
+	state.GasComputeCheck = true
+
+	sim = &structures.SCSimulation{Scid: scid, Entrypoint: entrypoint, Height: topoheight, Signer: signer}
+
+	result, rerr := runSimulation(&SC, entrypoint, state, params)
+
+	if state.GasComputeUsed > 0 {
+		sim.GasCompute = uint64(state.GasComputeUsed)
+	}
+	if state.GasStoreUsed > 0 {
+		sim.GasStorage = uint64(state.GasStoreUsed)
+	}
+
+	if rerr != nil {
+		sim.Error = rerr.Error()
 		return
 	}
 
-	logger.Debugf("[InterpetSC] SCID '%s' - entrypoint '%s' - result: %v", scid, entrypoint, result)
-	time.Sleep(60 * time.Second)
+	switch result.Type {
+	case dvm.Uint64:
+		sim.Return = result.ValueUint64
+	case dvm.String:
+		sim.Return = result.ValueString
+	}
+
+	// Writes are kept in the tx store until the chain commits them
+	for k, v := range txStore.RawKeys {
+		var key dvm.Variable
+		if err := key.UnmarshalBinary([]byte(k)); err != nil {
+			logger.Errorf("[InterpretSC] ERR - decoding written key of '%v': %v", scid, err)
+			continue
+		}
+
+		if len(v) == 0 {
+			sim.Deletes = append(sim.Deletes, simulationValue(key, mainnet, false))
+			continue
+		}
+
+		var value dvm.Variable
+		if err := value.UnmarshalBinary(v); err != nil {
+			logger.Errorf("[InterpretSC] ERR - decoding written value of '%v': %v", scid, err)
+			continue
+		}
+		sim.Writes = append(sim.Writes, &structures.SCIDVariable{Key: simulationValue(key, mainnet, false), Value: simulationValue(value, mainnet, true)})
+	}
+
+	// The chain checks the SC can cover its transfers after the run, the DVM does not
+	sent := make(map[crypto.Hash]uint64)
+	for _, t := range txStore.Transfers[scidHash].TransferE {
+		sent[t.Asset] += t.Amount
+
+		address := t.Address
+		if addr, aerr := rpc.NewAddressFromCompressedKeys([]byte(t.Address)); aerr == nil {
+			addr.Mainnet = mainnet
+			address = addr.String()
+		}
+		sim.Transfers = append(sim.Transfers, &structures.SCSimulationTransfer{Address: address, Asset: t.Asset.String(), Amount: t.Amount})
+	}
+	for asset, amount := range sent {
+		if available := balances[asset.String()] + assets[asset]; amount > available {
+			sim.Error = fmt.Sprintf("SC transfers %v of asset %v but only has %v", amount, asset, available)
+			return
+		}
+	}
+
+	sim.Committed = result.Type == dvm.Uint64 && result.ValueUint64 == 0
+
+	return
+}
+
+// Runs the SC, catching any panic of the DVM as an error
+func runSimulation(SC *dvm.SmartContract, entrypoint string, state *dvm.Shared_State, params map[string]interface{}) (result dvm.Variable, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Errorf("[runSimulation] ERR - recovered from DVM panic: %v\n%s", r, debug.Stack())
+			err = fmt.Errorf("recovered from DVM panic: %v", r)
+		}
+	}()
+
+	return dvm.RunSmartContract(SC, entrypoint, state, params)
+}
+
+// Converts an indexed variable key/value to its DVM form. Indexed values that are address strings are converted back to the compressed keys the DVM stores them as (keys are indexed as is)
+func simulationVariable(v interface{}, value bool) (dv dvm.Variable, ok bool) {
+	switch cv := v.(type) {
+	case uint64:
+		return dvm.Variable{Type: dvm.Uint64, ValueUint64: cv}, true
+	case float64:
+		return dvm.Variable{Type: dvm.Uint64, ValueUint64: uint64(cv)}, true
+	case string:
+		if addr, err := rpc.NewAddress(cv); err == nil && value {
+			return dvm.Variable{Type: dvm.String, ValueString: string(addr.Compressed())}, true
+		}
+		return dvm.Variable{Type: dvm.String, ValueString: cv}, true
+	}
+
+	return
+}
+
+// Converts a DVM key/value to its indexed form, the same as GetSCVariables does for the daemon's variables
+func simulationValue(v dvm.Variable, mainnet bool, value bool) interface{} {
+	switch v.Type {
+	case dvm.Uint64:
+		return v.ValueUint64
+	case dvm.String:
+		if !value {
+			return v.ValueString
+		}
+		p := new(crypto.Point)
+		if err := p.DecodeCompressed([]byte(v.ValueString)); err == nil {
+			addr := rpc.NewAddressFromKeys(p)
+			addr.Mainnet = mainnet
+			return addr.String()
+		}
+		return v.ValueString
+	}
+
+	return nil
+}
+
+// Uint64 args can come through as numbers or strings (e.g. from the cli or query params)
+func simulationArgUint64(arg interface{}) (u uint64, err error) {
+	switch carg := arg.(type) {
+	case uint64:
+		return carg, nil
+	case int:
+		return uint64(carg), nil
+	case int64:
+		return uint64(carg), nil
+	case float64:
+		return uint64(carg), nil
+	case string:
+		return strconv.ParseUint(carg, 10, 64)
+	case nil:
+		return 0, fmt.Errorf("missing")
+	}
+
+	return 0, fmt.Errorf("'%v' is not a uint64", arg)
 }
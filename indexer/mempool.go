@@ -0,0 +1,168 @@
+package indexer
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/civilware/Gnomon/structures"
+	"github.com/deroproject/derohe/cryptography/crypto"
+)
+
+// Mempool keeps a live set of pending SC installs/invokes from the daemon txpool, per SCID. Entries are dropped once they are mined or evicted from the txpool
+type Mempool struct {
+	Pending map[string]map[string]*structures.SCTXParse // scid -> txid -> sctx
+	txids   map[string]string                           // txid -> scid (or "" for txns that are not sc txns)
+	sync.RWMutex
+}
+
+// Defines the interval the daemon txpool is polled at
+const mempool_poll_interval = 2 * time.Second
+
+func NewMempool() *Mempool {
+	return &Mempool{
+		Pending: make(map[string]map[string]*structures.SCTXParse),
+		txids:   make(map[string]string),
+	}
+}
+
+// Polls the daemon txpool and keeps indexer.Mempool up to date with the pending sc txns. Only new txns are parsed each poll
+func (indexer *Indexer) StartMempoolWatcher() {
+	if indexer.Mempool == nil {
+		indexer.Mempool = NewMempool()
+	}
+
+	for {
+		if indexer.Closing {
+			// Break out on closing call
+			break
+		}
+
+		// Wait for daemon to be connected/getinfo to be run
+		if indexer.ChainHeight == 0 {
+			time.Sleep(1 * time.Second)
+			continue
+		}
+
+		txpool, err := indexer.RPC.GetTxPool()
+		if err != nil {
+			logger.Errorf("[StartMempoolWatcher] ERROR - GetTxPool: %v", err)
+			time.Sleep(mempool_poll_interval)
+			continue
+		}
+
+		var newtxns []crypto.Hash
+		indexer.Mempool.RLock()
+		for _, tx := range txpool {
+			if _, ok := indexer.Mempool.txids[tx]; !ok {
+				thash := crypto.HashHexToHash(tx)
+				if thash == (crypto.Hash{}) {
+					logger.Debugf("[StartMempoolWatcher] ERROR - Could not decode txid '%v'", tx)
+					continue
+				}
+				newtxns = append(newtxns, thash)
+			}
+		}
+		indexer.Mempool.RUnlock()
+
+		var bl_sctxs []structures.SCTXParse
+		if len(newtxns) > 0 {
+			cIndex := &structures.BlockTxns{Topoheight: indexer.ChainHeight, Tx_hashes: newtxns}
+			bl_sctxs, _, _, _, err = indexer.IndexTxn(cIndex, true)
+			if err != nil {
+				// Try these txns again on the next poll
+				logger.Errorf("[StartMempoolWatcher] ERROR - IndexTxn: %v", err)
+				newtxns = nil
+				bl_sctxs = nil
+			}
+		}
+
+		indexer.Mempool.update(txpool, newtxns, bl_sctxs)
+
+		time.Sleep(mempool_poll_interval)
+	}
+}
+
+// Returns the pending sc txns of scid, or nil if the mempool watcher is not enabled
+func (indexer *Indexer) GetPendingSCIDTxs(scid string) (sctxs []*structures.SCTXParse) {
+	if indexer.Mempool == nil {
+		return
+	}
+
+	return indexer.Mempool.GetPendingSCIDTxs(scid)
+}
+
+// Adds the newly seen txns and drops any that are no longer in the txpool (mined or evicted)
+func (mempool *Mempool) update(txpool []string, newtxns []crypto.Hash, bl_sctxs []structures.SCTXParse) {
+	mempool.Lock()
+	defer mempool.Unlock()
+
+	// Every new txn is tracked, sc txn or not, so that it is not parsed again on the next poll
+	for _, v := range newtxns {
+		mempool.txids[v.String()] = ""
+	}
+
+	for i := range bl_sctxs {
+		sctx := bl_sctxs[i]
+		if mempool.Pending[sctx.Scid] == nil {
+			mempool.Pending[sctx.Scid] = make(map[string]*structures.SCTXParse)
+		}
+		mempool.Pending[sctx.Scid][sctx.Txid] = &sctx
+		mempool.txids[sctx.Txid] = sctx.Scid
+	}
+
+	inpool := make(map[string]bool, len(txpool))
+	for _, tx := range txpool {
+		inpool[tx] = true
+	}
+
+	for txid, scid := range mempool.txids {
+		if inpool[txid] {
+			continue
+		}
+
+		delete(mempool.txids, txid)
+		if scid != "" {
+			delete(mempool.Pending[scid], txid)
+			if len(mempool.Pending[scid]) == 0 {
+				delete(mempool.Pending, scid)
+			}
+		}
+	}
+}
+
+// Returns the pending sc txns of scid, oldest first
+func (mempool *Mempool) GetPendingSCIDTxs(scid string) (sctxs []*structures.SCTXParse) {
+	mempool.RLock()
+	defer mempool.RUnlock()
+
+	for _, v := range mempool.Pending[scid] {
+		sctxs = append(sctxs, v)
+	}
+
+	sort.SliceStable(sctxs, func(i, j int) bool {
+		return sctxs[i].Height < sctxs[j].Height
+	})
+
+	return
+}
+
+// Returns all of the pending sc txns by scid
+func (mempool *Mempool) GetAllPendingSCIDTxs() (sctxs map[string][]*structures.SCTXParse) {
+	sctxs = make(map[string][]*structures.SCTXParse)
+
+	mempool.RLock()
+	var scids []string
+	for k := range mempool.Pending {
+		scids = append(scids, k)
+	}
+	mempool.RUnlock()
+
+	for _, scid := range scids {
+		if pending := mempool.GetPendingSCIDTxs(scid); len(pending) > 0 {
+			sctxs[scid] = pending
+		}
+	}
+
+	return
+}
@@ -0,0 +1,117 @@
+package indexer
+
+import (
+	"fmt"
+
+	"github.com/civilware/Gnomon/storage"
+	"github.com/civilware/Gnomon/structures"
+	"github.com/deroproject/derohe/rpc"
+)
+
+// Name service record actions
+const (
+	NameActionRegister = "register"
+	NameActionTransfer = "transfer"
+	NameActionSnapshot = "snapshot"
+)
+
+// Indexes the name registration/transfer of a name service invoke. The invoke's args say which name it is for, the owner it ended up with comes from the daemon at topoheight. Every invoke gets a history entry (by its txid and height), a register of a taken name or a transfer not signed by the owner leave the name as it was so the entry has the unchanged owner
+func (indexer *Indexer) indexNameService(sctx *structures.SCTXParse, topoheight int64, wb *storage.WriteBatch) (err error) {
+	var action string
+	switch sctx.Entrypoint {
+	case "Register":
+		action = NameActionRegister
+	case "TransferOwnership":
+		action = NameActionTransfer
+	default:
+		return
+	}
+
+	if !sctx.Sc_args.Has("name", rpc.DataString) {
+		return
+	}
+	name, _ := sctx.Sc_args.Value("name", rpc.DataString).(string)
+	if name == "" {
+		return
+	}
+
+	address, err := indexer.getNameAddress(name, topoheight)
+	if err != nil {
+		// Name does not resolve, e.g. a register that was rejected
+		logger.Debugf("[indexNameService] Name '%v' did not resolve at height %v: %v", name, topoheight, err)
+		return nil
+	}
+
+	record := &structures.NameRecord{Name: name, Address: address, Action: action, Txid: sctx.Txid, Height: topoheight}
+
+	return indexer.stageWrite(wb, func(s storage.Storage) error {
+		_, err := s.StoreNameRecord(record)
+		if err != nil {
+			logger.Errorf("[indexNameService] ERR - storing name record: %v", err)
+		}
+		return err
+	})
+}
+
+// Records the names within the name service variables that are not already known. Used with the variables snapshot taken at startup, as names registered before the indexed heights are not seen through invokes
+func seedNameRecords(s storage.Storage, variables []*structures.SCIDVariable, topoheight int64) (err error) {
+	for _, v := range variables {
+		name, ok := v.Key.(string)
+		if !ok || name == "C" {
+			continue
+		}
+		address, ok := v.Value.(string)
+		if !ok {
+			continue
+		}
+		if _, aerr := rpc.NewAddress(address); aerr != nil {
+			continue
+		}
+
+		if s.GetNameRecord(name) != nil {
+			continue
+		}
+
+		_, err = s.StoreNameRecord(&structures.NameRecord{Name: name, Address: address, Action: NameActionSnapshot, Height: topoheight})
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// Indexes the names of each address from the name records stored before they were kept, a no-op once done
+func (indexer *Indexer) indexNameAddresses() {
+	var indexed int
+	err := indexer.Writer.Write(func(s storage.Storage) (err error) {
+		indexed, err = s.IndexNameAddresses()
+		return
+	})
+	if err != nil {
+		logger.Errorf("[indexNameAddresses] ERR - indexing name addresses: %v", err)
+		return
+	}
+
+	if indexed > 0 {
+		logger.Printf("[indexNameAddresses] Indexed the addresses of %v names", indexed)
+	}
+}
+
+// DERO.NameToAddress rpc call for returning the address a name resolves to at topoheight
+func (indexer *Indexer) getNameAddress(name string, topoheight int64) (address string, err error) {
+	var io rpc.NameToAddress_Result
+	var ip = rpc.NameToAddress_Params{Name: name, TopoHeight: topoheight}
+
+	if err = indexer.RPC.Call("DERO.NameToAddress", ip, &io); err != nil {
+		return
+	}
+
+	if io.Address == "" {
+		return "", fmt.Errorf("no address returned")
+	}
+
+	address = io.Address
+
+	return
+}
@@ -0,0 +1,70 @@
+package indexer
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/civilware/Gnomon/storage"
+)
+
+// Returned (wrapped) for lookups at heights below the earliest height held by the index, there is no data for them rather than the data being empty
+var ErrBelowEarliestHeight = errors.New("below earliest indexed height")
+
+// Returns ErrBelowEarliestHeight if topoheight is below the earliest height held by backend (set when indexing from a pruned daemon)
+func CheckEarliestHeight(backend storage.Storage, topoheight int64) error {
+	earliest := backend.GetEarliestHeight()
+	if earliest > 0 && topoheight < earliest {
+		return fmt.Errorf("height %v is %w %v, the daemon it was indexed from is pruned below it", topoheight, ErrBelowEarliestHeight, earliest)
+	}
+
+	return nil
+}
+
+// Finds the first topoheight above lo that the daemon has the block of, lo being a height known to be pruned. Blocks are pruned from the bottom up, so this is a binary search up to hi (the chain height) rather than walking the heights
+func (indexer *Indexer) findEarliestHeight(lo int64, hi int64) (earliest int64, err error) {
+	available, err := indexer.blockAvailable(hi)
+	if err != nil {
+		return
+	}
+	if !available {
+		return 0, fmt.Errorf("no blocks available up to height %v", hi)
+	}
+
+	var probes int
+	for hi-lo > 1 {
+		if indexer.Closing {
+			return 0, fmt.Errorf("closing")
+		}
+
+		mid := lo + (hi-lo)/2
+		available, err = indexer.blockAvailable(mid)
+		if err != nil {
+			return
+		}
+		probes++
+
+		if available {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+
+	logger.Debugf("[findEarliestHeight] Found earliest available height %v in %v probes", hi, probes)
+
+	return hi, nil
+}
+
+// Returns whether the daemon has the block at topoheight. Pruned/not found is reported as unavailable, any other err is returned so the search is not thrown off by e.g. a daemon disconnect
+func (indexer *Indexer) blockAvailable(topoheight int64) (available bool, err error) {
+	_, err = indexer.RPC.getBlockHash(uint64(topoheight))
+	if err == nil {
+		return true, nil
+	}
+
+	if errors.Is(err, ErrRPCPruned) || errors.Is(err, ErrRPCNotFound) {
+		return false, nil
+	}
+
+	return false, err
+}
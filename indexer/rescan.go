@@ -0,0 +1,400 @@
+package indexer
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/civilware/Gnomon/storage"
+	"github.com/civilware/Gnomon/structures"
+	"github.com/deroproject/derohe/block"
+	"github.com/deroproject/derohe/cryptography/crypto"
+	"github.com/deroproject/derohe/rpc"
+)
+
+// Defines the number of blocks fetched in parallel while rescanning
+const rescan_parallel_blocks = int64(10)
+
+// Defines the number of times the rescan catches up with indexing (which moves on while the rescan runs) before giving up on swapping it in
+const rescan_swap_attempts = 10
+
+// Returned within the swap write if indexing moved on since the rescan last caught up, the rest is then walked outside of the write
+var errRescanBehind = errors.New("rescan is behind the indexed height")
+
+// Rescans each of scids in turn, see RescanSCID. Returns the last err, the others having been logged
+func (indexer *Indexer) RescanSCIDs(scids []string) (err error) {
+	for _, scid := range scids {
+		if indexer.Closing {
+			return fmt.Errorf("closing")
+		}
+
+		if rerr := indexer.RescanSCID(scid); rerr != nil {
+			logger.Errorf("[RescanSCIDs] ERR - rescanning '%v': %v", scid, rerr)
+			err = rerr
+		}
+	}
+
+	return
+}
+
+// Rebuilds the history of an indexed scid from the chain. Every block from the scid's deploy height up to the indexed height is walked for txns of the scid, which are stored in a RAM side store and then swapped in for the stored history in one write.
+// The invokes, variables, interaction heights, balances, code versions and arg indexes of the scid are replaced, its asset record and template are reclassified and the installer/invoker address activity is added. Ring member address activity (normal txns carrying the scid) and name service records are not rebuilt.
+// Used for scids added after their deploy (addscid_toindex) or whose stored data is off, without a full re-sync
+func (indexer *Indexer) RescanSCID(scid string) (err error) {
+	if len(scid) != 64 || crypto.HashHexToHash(scid) == (crypto.Hash{}) {
+		return fmt.Errorf("'%v' is not a valid scid", scid)
+	}
+	if _, ok := indexer.Backend.GetAllOwnersAndSCIDs()[scid]; !ok {
+		return fmt.Errorf("scid '%v' is not indexed, add it with addscid_toindex first", scid)
+	}
+
+	// Hardcoded scids have no install txn, they are there from the start of the chain which would have every block walked
+	if scidExist(structures.Hardcoded_SCIDS, scid) {
+		return fmt.Errorf("scid '%v' is hardcoded and has no deploy height to rescan from", scid)
+	}
+
+	deployTopoheight, err := indexer.getDeployTopoheight(scid)
+	if err != nil {
+		return
+	}
+	if deployTopoheight > indexer.LastIndexedHeight {
+		return fmt.Errorf("scid '%v' is deployed at topoheight %v, above the indexed height %v", scid, deployTopoheight, indexer.LastIndexedHeight)
+	}
+
+	logger.Printf("[RescanSCID] Rescanning '%v' from deploy topoheight %v to %v...", scid, deployTopoheight, indexer.LastIndexedHeight)
+
+	rescan := &scidRescan{scid: scid, walked: deployTopoheight - 1}
+	rescan.sidedb, err = storage.NewGravDBRAM("25ms")
+	if err != nil {
+		return fmt.Errorf("[RescanSCID] Error creating new gravdb: %v", err)
+	}
+	defer rescan.sidedb.Close()
+
+	for attempt := 1; ; attempt++ {
+		// Walk up to the indexed height, which keeps moving on while the rescan runs. The writer is only held for the swap itself
+		err = indexer.rescanBlocks(rescan, indexer.LastIndexedHeight)
+		if err != nil {
+			return
+		}
+
+		err = indexer.Writer.Write(func(s storage.Storage) error {
+			return indexer.swapRescan(s, rescan)
+		})
+		if err == errRescanBehind && attempt < rescan_swap_attempts {
+			continue
+		}
+		break
+	}
+	if err != nil {
+		return
+	}
+
+	logger.Printf("[RescanSCID] Rescanned '%v' up to height %v, %v interactions", scid, rescan.walked, len(rescan.heights))
+
+	return
+}
+
+// State of a scid rescan, the walked blocks' data being kept in sidedb
+type scidRescan struct {
+	scid     string
+	sidedb   *storage.GravitonStore
+	walked   int64  // last topoheight walked
+	height   int64  // block height at walked
+	blid     string // block hash at walked
+	heights  []int64
+	owner    string
+	activity []*rescanActivity
+	scVars   []*structures.SCIDVariable // as of the last interaction
+	scCode   string                     // as of the last interaction
+}
+
+type rescanActivity struct {
+	addr     string
+	activity *structures.AddressActivity
+}
+
+// The scid's txns within a block and the scid's state at the end of it. Records are stamped with the block height, as when indexed
+type rescanBlockResult struct {
+	topoheight int64
+	height     int64
+	blid       string
+	sctxs      []structures.SCTXParse
+	scVars     []*structures.SCIDVariable
+	scCode     string
+	scBalances map[string]uint64
+}
+
+// Swaps the rescan in for the stored history of the scid, if it has walked up to the indexed height. Called within a write
+func (indexer *Indexer) swapRescan(s storage.Storage, rescan *scidRescan) (err error) {
+	lastIndexedHeight, err := s.GetLastIndexHeight()
+	if err != nil {
+		return
+	}
+	if lastIndexedHeight > rescan.walked {
+		return errRescanBehind
+	}
+	if lastIndexedHeight < rescan.walked {
+		return fmt.Errorf("index was rolled back to height %v during the rescan, try again", lastIndexedHeight)
+	}
+
+	// If the chain reorganized below the walked height, the stored and walked blocks differ and the rescan is not swapped in
+	if rescan.blid != "" {
+		if stored := s.GetBlockHash(rescan.walked); stored != "" && stored != rescan.blid {
+			return fmt.Errorf("block at height %v changed during the rescan, try again", rescan.walked)
+		}
+	}
+
+	sort.Slice(rescan.heights, func(i, j int) bool {
+		return rescan.heights[i] < rescan.heights[j]
+	})
+
+	history := &storage.SCIDHistory{
+		Invokes:   rescan.sidedb.GetAllSCIDInvokeDetails(rescan.scid),
+		Variables: rescan.sidedb.GetSCIDVariableHistory(rescan.scid),
+		Heights:   rescan.heights,
+		Balances:  rescan.sidedb.GetAllSCIDBalanceDetails(rescan.scid),
+		Code:      rescan.sidedb.GetAllSCIDCode(rescan.scid),
+	}
+	err = s.ReplaceSCIDHistory(rescan.scid, history)
+	if err != nil {
+		return
+	}
+
+	// Scids added through addscid_toindex do not know their owner
+	if rescan.owner != "" && s.GetOwner(rescan.scid) == "" {
+		if _, err = s.StoreOwner(rescan.scid, rescan.owner); err != nil {
+			return
+		}
+	}
+
+	for _, a := range rescan.activity {
+		if _, err = s.StoreAddressActivity(a.addr, a.activity); err != nil {
+			return
+		}
+	}
+
+	if rescan.scCode != "" {
+		if err = indexer.storeSCIDTemplate(s, rescan.scid, rescan.scCode); err != nil {
+			return
+		}
+
+		if len(rescan.scVars) > 0 {
+			asset := ClassifyAsset(rescan.scid, rescan.scCode, rescan.scVars, rescan.height)
+			if asset != nil && assetChanged(s.GetSCAsset(rescan.scid), asset) {
+				if _, err = s.StoreSCAsset(asset); err != nil {
+					return
+				}
+			}
+		}
+	}
+
+	return
+}
+
+// Returns the topoheight of the block the scid was installed within. The scid is the txid of its install, which gives the block it is valid in
+func (indexer *Indexer) getDeployTopoheight(scid string) (topoheight int64, err error) {
+	var inputparam rpc.GetTransaction_Params
+	var output rpc.GetTransaction_Result
+
+	inputparam.Tx_Hashes = append(inputparam.Tx_Hashes, scid)
+
+	err = indexer.RPC.call("DERO.GetTransaction", inputparam, &output, func() error {
+		if len(output.Txs_as_hex) == 0 || output.Txs_as_hex[0] == "" || len(output.Txs) == 0 {
+			return &RPCError{Method: "DERO.GetTransaction", Kind: ErrRPCNotFound, Err: fmt.Errorf("txid %v", inputparam.Tx_Hashes)}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("[getDeployTopoheight] ERROR - GetTransaction for scid '%v' failed: %w", scid, err)
+	}
+
+	if output.Txs[0].Block_Height < 0 || output.Txs[0].ValidBlock == "" {
+		return 0, fmt.Errorf("[getDeployTopoheight] install of scid '%v' is not mined", scid)
+	}
+
+	// Block_Height is the block height, the walk goes by topoheight so it is taken from the block the install is valid in
+	var io rpc.GetBlockHeaderByHash_Result
+	var ip = rpc.GetBlockHeaderByHash_Params{Hash: output.Txs[0].ValidBlock}
+	if err = indexer.RPC.Call("DERO.GetBlockHeaderByHash", ip, &io); err != nil {
+		return 0, fmt.Errorf("[getDeployTopoheight] ERROR - GetBlockHeaderByHash for scid '%v' block '%v' failed: %w", scid, output.Txs[0].ValidBlock, err)
+	}
+
+	return io.Block_Header.TopoHeight, nil
+}
+
+// Walks the blocks above rescan.walked up to height, rescan_parallel_blocks at a time, storing the scid's txns within them to the side store
+func (indexer *Indexer) rescanBlocks(rescan *scidRescan, height int64) (err error) {
+	for rescan.walked < height {
+		if indexer.Closing {
+			return fmt.Errorf("closing")
+		}
+
+		start := rescan.walked + 1
+		end := start + rescan_parallel_blocks - 1
+		if end > height {
+			end = height
+		}
+
+		var wg sync.WaitGroup
+		var errLock sync.Mutex
+		results := make([]*rescanBlockResult, end-start+1)
+		for topoheight := start; topoheight <= end; topoheight++ {
+			wg.Add(1)
+			go func(topoheight int64) {
+				defer wg.Done()
+				result, ferr := indexer.fetchRescanBlock(rescan.scid, topoheight)
+				if ferr != nil {
+					errLock.Lock()
+					err = ferr
+					errLock.Unlock()
+					return
+				}
+				results[topoheight-start] = result
+			}(topoheight)
+		}
+		wg.Wait()
+		if err != nil {
+			return
+		}
+
+		// Applied in height order, so variables and code versions are diffed against the height before
+		for _, result := range results {
+			err = rescan.apply(result)
+			if err != nil {
+				return
+			}
+		}
+
+		if start/10000 != end/10000 {
+			logger.Printf("[rescanBlocks] Rescanning '%v' - at height %v of %v", rescan.scid, end, height)
+		}
+	}
+
+	return
+}
+
+// Gets the txns of scid within the block at topoheight, along with the scid's state at the end of the block if there are any
+func (indexer *Indexer) fetchRescanBlock(scid string, topoheight int64) (result *rescanBlockResult, err error) {
+	result = &rescanBlockResult{topoheight: topoheight}
+
+	result.blid, err = indexer.RPC.getBlockHash(uint64(topoheight))
+	if err != nil {
+		return nil, fmt.Errorf("[fetchRescanBlock] ERROR - getBlockHash(%v) failed: %w", topoheight, err)
+	}
+
+	var io rpc.GetBlock_Result
+	var ip = rpc.GetBlock_Params{Hash: result.blid}
+	if err = indexer.RPC.Call("DERO.GetBlock", ip, &io); err != nil {
+		return nil, fmt.Errorf("[fetchRescanBlock] ERROR - GetBlock failed: %w", err)
+	}
+
+	var bl block.Block
+	block_bin, _ := hex.DecodeString(io.Blob)
+	bl.Deserialize(block_bin)
+
+	result.height = int64(bl.Height)
+
+	if len(bl.Tx_hashes) == 0 {
+		return
+	}
+
+	// Only the sc txns are wanted, nothing else of the block is stored
+	bl_sctxs, _, _, _, err := indexer.indexTxn(&structures.BlockTxns{Topoheight: result.height, Tx_hashes: bl.Tx_hashes}, true, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sctx := range bl_sctxs {
+		if sctx.Scid == scid {
+			result.sctxs = append(result.sctxs, sctx)
+		}
+	}
+
+	if len(result.sctxs) > 0 {
+		// Variables are those at the end of the block, as when indexed
+		result.scVars, result.scCode, result.scBalances, _ = indexer.RPC.GetSCVariables(scid, topoheight, nil, nil, nil, false)
+	}
+
+	return
+}
+
+// Stores the scid's txns and state of a fetched block to the side store
+func (rescan *scidRescan) apply(result *rescanBlockResult) (err error) {
+	if len(result.sctxs) > 0 {
+		err = rescan.sidedb.Batch(func(s storage.Storage) error {
+			for i := range result.sctxs {
+				sctx := &result.sctxs[i]
+				_, err := s.StoreInvokeDetails(rescan.scid, sctx.Sender, sctx.Entrypoint, result.height, sctx)
+				if err != nil {
+					return err
+				}
+
+				role := AddressRoleInvoker
+				if sctx.Method == "installsc" {
+					role = AddressRoleInstaller
+					rescan.owner = sctx.Sender
+					code := fmt.Sprintf("%v", sctx.Sc_args.Value("SC_CODE", "S"))
+					_, err = s.StoreSCIDCode(rescan.scid, &structures.SCIDCode{Height: result.height, Txid: sctx.Txid, Code: code})
+					if err != nil {
+						return err
+					}
+				}
+				if sctx.Sender != "" {
+					rescan.activity = append(rescan.activity, &rescanActivity{addr: sctx.Sender, activity: &structures.AddressActivity{Scid: rescan.scid, Txid: sctx.Txid, Height: result.height, Role: role}})
+				}
+			}
+
+			if len(result.scVars) > 0 {
+				_, err := s.StoreSCIDVariableDetails(rescan.scid, result.scVars, result.height)
+				if err != nil {
+					return err
+				}
+				rescan.scVars = result.scVars
+			}
+			if result.scBalances != nil {
+				_, err := s.StoreSCIDBalanceDetails(rescan.scid, result.scBalances, result.height)
+				if err != nil {
+					return err
+				}
+			}
+			// UPDATE_SC_CODE can rewrite the SC, a new version is kept when the code differs from the last one
+			if result.scCode != "" {
+				if prevCode := s.GetSCIDCodeAtTopoheight(rescan.scid, result.height); prevCode == nil {
+					_, err := s.StoreSCIDCode(rescan.scid, &structures.SCIDCode{Height: result.height, Code: result.scCode})
+					if err != nil {
+						return err
+					}
+				} else if prevCode.Code != result.scCode {
+					var txid string
+					for _, sctx := range result.sctxs {
+						if sctx.Method != "installsc" {
+							txid = sctx.Txid
+						}
+					}
+					_, err := s.StoreSCIDCode(rescan.scid, &structures.SCIDCode{Height: result.height, Txid: txid, Code: result.scCode})
+					if err != nil {
+						return err
+					}
+				}
+				rescan.scCode = result.scCode
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("[rescanBlocks] ERROR - storing txns of height %v to side store: %w", result.height, err)
+		}
+
+		// Consecutive topoheights can share a block height, which is only kept once
+		if n := len(rescan.heights); n == 0 || rescan.heights[n-1] != result.height {
+			rescan.heights = append(rescan.heights, result.height)
+		}
+	}
+
+	rescan.walked = result.topoheight
+	rescan.height = result.height
+	rescan.blid = result.blid
+
+	return
+}
@@ -0,0 +1,200 @@
+package indexer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/civilware/Gnomon/storage"
+	"github.com/civilware/Gnomon/structures"
+	"github.com/deroproject/derohe/cryptography/crypto"
+)
+
+// Retention windows applied by the pruner, globally and per scid
+type Retention struct {
+	Default structures.RetentionPolicy
+	SCIDs   map[string]structures.RetentionPolicy // overrides the variables/invokes windows of Default for a scid
+}
+
+// Defines how often the pruner applies the retention windows
+const retention_prune_interval = 10 * time.Minute
+
+// Defines how many miniblock entries are pruned within one write
+const mbl_prune_chunk = 10000
+
+// Builds the retention windows from the global policy and per scid definitions of the form <scid>:variables=<blocks>[,invokes=<blocks>]. Windows of a scid that are not defined are those of the global policy. Windows must be 0 (keep all) or at least max_reorg_depth, so that reorgs can still be rolled back
+func ParseRetention(def structures.RetentionPolicy, scidDefs []string) (retention *Retention, err error) {
+	retention = &Retention{Default: def, SCIDs: make(map[string]structures.RetentionPolicy)}
+	if err = validateRetentionPolicy(def); err != nil {
+		return nil, err
+	}
+
+	for _, sdef := range scidDefs {
+		sdef = strings.TrimSpace(sdef)
+		if sdef == "" {
+			continue
+		}
+
+		split := strings.SplitN(sdef, ":", 2)
+		if len(split) != 2 || split[1] == "" {
+			return nil, fmt.Errorf("scid retention '%v' should be of the form <scid>:variables=<blocks>[,invokes=<blocks>]", sdef)
+		}
+
+		scid := strings.TrimSpace(split[0])
+		if len(scid) != 64 || crypto.HashHexToHash(scid) == (crypto.Hash{}) {
+			return nil, fmt.Errorf("scid retention '%v' has an invalid scid", sdef)
+		}
+
+		policy := def
+		if p, ok := retention.SCIDs[scid]; ok {
+			policy = p
+		}
+		for _, window := range strings.Split(split[1], ",") {
+			kv := strings.SplitN(strings.TrimSpace(window), "=", 2)
+			if len(kv) != 2 {
+				return nil, fmt.Errorf("scid retention '%v' window '%v' should be of the form <variables|invokes>=<blocks>", sdef, window)
+			}
+			blocks, perr := strconv.ParseInt(strings.TrimSpace(kv[1]), 10, 64)
+			if perr != nil || blocks < 0 {
+				return nil, fmt.Errorf("scid retention '%v' window '%v' is not a number of blocks", sdef, window)
+			}
+
+			switch strings.TrimSpace(kv[0]) {
+			case "variables":
+				policy.Variables = blocks
+			case "invokes":
+				policy.Invokes = blocks
+			default:
+				return nil, fmt.Errorf("scid retention '%v' window '%v' should be variables or invokes", sdef, window)
+			}
+		}
+
+		if err = validateRetentionPolicy(policy); err != nil {
+			return nil, fmt.Errorf("scid retention '%v': %v", sdef, err)
+		}
+		retention.SCIDs[scid] = policy
+	}
+
+	return
+}
+
+func validateRetentionPolicy(policy structures.RetentionPolicy) error {
+	for _, window := range []int64{policy.Variables, policy.Invokes, policy.Miniblocks} {
+		if window < 0 || (window > 0 && window < max_reorg_depth) {
+			return fmt.Errorf("retention windows must be 0 (keep all) or at least %v blocks", max_reorg_depth)
+		}
+	}
+
+	return nil
+}
+
+// Returns the retention windows of scid
+func (retention *Retention) Policy(scid string) structures.RetentionPolicy {
+	if policy, ok := retention.SCIDs[scid]; ok {
+		return policy
+	}
+
+	return retention.Default
+}
+
+// Returns whether any history is to be pruned
+func (retention *Retention) Enabled() bool {
+	if retention == nil {
+		return false
+	}
+	if retention.Default != (structures.RetentionPolicy{}) {
+		return true
+	}
+	for _, policy := range retention.SCIDs {
+		if policy != (structures.RetentionPolicy{}) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Applies indexer.Retention every retention_prune_interval in the background, until the indexer is closed
+func (indexer *Indexer) StartRetentionPruner() {
+	if !indexer.Retention.Enabled() {
+		return
+	}
+
+	next := time.Now()
+	for {
+		if indexer.Closing {
+			// Break out on closing call
+			break
+		}
+
+		// Wait for indexing to have started
+		if indexer.LastIndexedHeight <= 1 || time.Now().Before(next) {
+			time.Sleep(1 * time.Second)
+			continue
+		}
+
+		indexer.pruneHistory(indexer.LastIndexedHeight)
+		next = time.Now().Add(retention_prune_interval)
+	}
+}
+
+// Removes the history outside of the retention windows as of topoheight. Each scid is pruned within its own write so indexing is not held up for long
+func (indexer *Indexer) pruneHistory(topoheight int64) {
+	var prunedVars, prunedInvokes, prunedMbls int
+
+	for scid := range indexer.Backend.GetAllOwnersAndSCIDs() {
+		if indexer.Closing {
+			return
+		}
+
+		policy := indexer.Retention.Policy(scid)
+		if policy.Variables == 0 && policy.Invokes == 0 {
+			continue
+		}
+
+		scid := scid
+		err := indexer.Writer.Write(func(s storage.Storage) (err error) {
+			var pruned int
+			if policy.Variables > 0 {
+				pruned, err = s.PruneSCIDVariableDetails(scid, topoheight-policy.Variables)
+				if err != nil {
+					return
+				}
+				prunedVars += pruned
+			}
+			if policy.Invokes > 0 {
+				pruned, err = s.PruneSCIDInvokeDetails(scid, topoheight-policy.Invokes)
+				if err != nil {
+					return
+				}
+				prunedInvokes += pruned
+			}
+			return
+		})
+		if err != nil {
+			logger.Errorf("[pruneHistory] ERR - pruning history of '%v': %v", scid, err)
+		}
+	}
+
+	// Miniblock details are pruned in chunks, each within its own write, until one comes back short
+	for indexer.Retention.Default.Miniblocks > 0 && !indexer.Closing {
+		var pruned int
+		err := indexer.Writer.Write(func(s storage.Storage) (err error) {
+			pruned, err = s.PruneMiniblockDetails(topoheight-indexer.Retention.Default.Miniblocks, mbl_prune_chunk)
+			return
+		})
+		if err != nil {
+			logger.Errorf("[pruneHistory] ERR - pruning miniblock details: %v", err)
+			break
+		}
+		prunedMbls += pruned
+		if pruned < mbl_prune_chunk {
+			break
+		}
+	}
+
+	if prunedVars > 0 || prunedInvokes > 0 || prunedMbls > 0 {
+		logger.Printf("[pruneHistory] Pruned %v variable entries, %v invokes and %v miniblock entries below the retention windows at height %v", prunedVars, prunedInvokes, prunedMbls, topoheight)
+	}
+}
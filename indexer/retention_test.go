@@ -0,0 +1,62 @@
+package indexer
+
+import (
+	"testing"
+
+	"github.com/civilware/Gnomon/structures"
+)
+
+func TestParseRetention(t *testing.T) {
+	const scid = "0000000000000000000000000000000000000000000000000000000000000001"
+	def := structures.RetentionPolicy{Variables: 1000, Invokes: 2000, Miniblocks: 500}
+
+	tests := []struct {
+		name     string
+		def      structures.RetentionPolicy
+		scidDefs []string
+		want     structures.RetentionPolicy // of scid
+		wantErr  bool
+	}{
+		{name: "default", def: def, want: def},
+		{name: "keep all", def: structures.RetentionPolicy{}, want: structures.RetentionPolicy{}},
+		{name: "blank defs", def: def, scidDefs: []string{"", "  "}, want: def},
+		{name: "variables", def: def, scidDefs: []string{scid + ":variables=200"}, want: structures.RetentionPolicy{Variables: 200, Invokes: 2000, Miniblocks: 500}},
+		{name: "both", def: def, scidDefs: []string{scid + ":variables=0, invokes=300"}, want: structures.RetentionPolicy{Variables: 0, Invokes: 300, Miniblocks: 500}},
+		{name: "repeated scid", def: def, scidDefs: []string{scid + ":variables=200", scid + ":invokes=300"}, want: structures.RetentionPolicy{Variables: 200, Invokes: 300, Miniblocks: 500}},
+		{name: "default below reorg depth", def: structures.RetentionPolicy{Variables: max_reorg_depth - 1}, wantErr: true},
+		{name: "negative default", def: structures.RetentionPolicy{Invokes: -1}, wantErr: true},
+		{name: "scid below reorg depth", def: def, scidDefs: []string{scid + ":invokes=10"}, wantErr: true},
+		{name: "no windows", def: def, scidDefs: []string{scid + ":"}, wantErr: true},
+		{name: "no scid", def: def, scidDefs: []string{"variables=200"}, wantErr: true},
+		{name: "bad scid", def: def, scidDefs: []string{"abc:variables=200"}, wantErr: true},
+		{name: "unknown window", def: def, scidDefs: []string{scid + ":miniblocks=200"}, wantErr: true},
+		{name: "bad blocks", def: def, scidDefs: []string{scid + ":variables=lots"}, wantErr: true},
+		{name: "negative blocks", def: def, scidDefs: []string{scid + ":variables=-200"}, wantErr: true},
+		{name: "no blocks", def: def, scidDefs: []string{scid + ":variables"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retention, err := ParseRetention(tt.def, tt.scidDefs)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseRetention() err = nil, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRetention() err = %v", err)
+			}
+
+			if got := retention.Policy(scid); got != tt.want {
+				t.Errorf("Policy() = %+v, want %+v", got, tt.want)
+			}
+			if got := retention.Policy("other"); got != tt.def {
+				t.Errorf("Policy() of another scid = %+v, want the default %+v", got, tt.def)
+			}
+			if got, want := retention.Enabled(), tt.def != (structures.RetentionPolicy{}) || tt.want != (structures.RetentionPolicy{}); got != want {
+				t.Errorf("Enabled() = %v, want %v", got, want)
+			}
+		})
+	}
+}
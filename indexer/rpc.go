@@ -0,0 +1,457 @@
+package indexer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/civilware/Gnomon/rwc"
+	"github.com/civilware/Gnomon/structures"
+	"github.com/creachadair/jrpc2"
+	"github.com/creachadair/jrpc2/channel"
+	"github.com/creachadair/jrpc2/code"
+	"github.com/gorilla/websocket"
+)
+
+// Client holds the connections to one or more endpoints. Rpc calls should go through Call() which spreads them across the healthy endpoints and handles per-call deadlines, failover, retries with backoff and reconnects
+type Client struct {
+	nodes  []*rpcNode
+	next   int
+	closed bool
+	sync.RWMutex
+}
+
+// A single endpoint connection of a Client
+type rpcNode struct {
+	Endpoint string
+	WS       *websocket.Conn
+	RPC      *jrpc2.Client
+	Height   int64 // topoheight as of the last health check
+	Healthy  bool
+	sync.RWMutex
+}
+
+// RPCError is returned by Client calls. Kind is one of ErrRPCPruned, ErrRPCNotFound or ErrRPCTransport (or nil for any other daemon err) so callers can branch with errors.Is()
+type RPCError struct {
+	Method string
+	Kind   error
+	Err    error
+}
+
+var (
+	// Block (or its txns) has been pruned from the daemon's data
+	ErrRPCPruned = errors.New("pruned by daemon")
+
+	// Requested block, height or txn does not exist on the daemon
+	ErrRPCNotFound = errors.New("not found by daemon")
+
+	// Call did not make it to/from the daemon (connection dropped, deadline hit, not connected etc.)
+	ErrRPCTransport = errors.New("rpc transport failure")
+)
+
+// Defines the separator used to pass multiple endpoints within one endpoint string
+const endpoint_separator = ","
+
+// Defines the deadline of a single rpc call
+const rpc_call_timeout = 30 * time.Second
+
+// Defines the number of times a call is retried on transport failures (after all endpoints have been tried) before returning the err
+const rpc_max_retries = 5
+
+// Defines the wait before the first retry. It doubles on each retry up to rpc_backoff_max
+const rpc_backoff_min = 500 * time.Millisecond
+const rpc_backoff_max = 10 * time.Second
+
+// Defines the number of blocks an endpoint can be behind the highest endpoint before it is no longer used for calls
+const rpc_max_node_lag = int64(10)
+
+func (e *RPCError) Error() string {
+	if e.Kind == nil {
+		return fmt.Sprintf("%s: %v", e.Method, e.Err)
+	}
+
+	return fmt.Sprintf("%s: %v: %v", e.Method, e.Kind, e.Err)
+}
+
+func (e *RPCError) Unwrap() error {
+	return e.Err
+}
+
+func (e *RPCError) Is(target error) bool {
+	return e.Kind != nil && target == e.Kind
+}
+
+// Connects to the endpoint(s), multiple endpoints are separated by endpoint_separator. If the endpoints are the same as the current ones, only dropped connections are re-dialed
+func (client *Client) Connect(endpoint string) (err error) {
+	endpoints := splitEndpoints(endpoint)
+	if len(endpoints) == 0 {
+		return fmt.Errorf("no endpoint defined")
+	}
+
+	client.Lock()
+	if client.closed {
+		client.Unlock()
+		return fmt.Errorf("client is closed")
+	}
+	if !client.sameEndpoints(endpoints) {
+		for _, n := range client.nodes {
+			n.close()
+		}
+		client.nodes = nil
+		for _, e := range endpoints {
+			client.nodes = append(client.nodes, &rpcNode{Endpoint: e})
+		}
+		client.next = 0
+	}
+	nodes := client.nodes
+	client.Unlock()
+
+	var connected int
+	for _, n := range nodes {
+		n.RLock()
+		rpcClient := n.RPC
+		n.RUnlock()
+
+		if rpcClient == nil {
+			if n.reconnect(nil) != nil {
+				continue
+			}
+		}
+		connected++
+	}
+
+	Connected = connected > 0
+	if !Connected {
+		return fmt.Errorf("could not connect to any endpoint of %v", endpoints)
+	}
+
+	return
+}
+
+// Drops all of the connections. The next Connect() will re-dial them
+func (client *Client) Disconnect() {
+	client.RLock()
+	defer client.RUnlock()
+
+	for _, n := range client.nodes {
+		n.close()
+	}
+}
+
+// Closes all of the connections and stops any further reconnects
+func (client *Client) Close() {
+	client.Lock()
+	defer client.Unlock()
+
+	client.closed = true
+	for _, n := range client.nodes {
+		n.close()
+	}
+}
+
+// Returns true if at least one endpoint is connected
+func (client *Client) IsConnected() bool {
+	client.RLock()
+	defer client.RUnlock()
+
+	for _, n := range client.nodes {
+		n.RLock()
+		connected := n.RPC != nil
+		n.RUnlock()
+		if connected {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Probes DERO.GetInfo against each endpoint. Endpoints that fail, are on another network or have fallen more than rpc_max_node_lag blocks behind are not used for calls until they catch up. Returns the getinfo of the highest endpoint
+func (client *Client) CheckHealth() (info *structures.GetInfo, err error) {
+	client.RLock()
+	nodes := client.nodes
+	closed := client.closed
+	client.RUnlock()
+
+	infos := make([]*structures.GetInfo, len(nodes))
+	for i, n := range nodes {
+		n.RLock()
+		rpcClient := n.RPC
+		n.RUnlock()
+
+		var ninfo *structures.GetInfo
+		if cerr := callOnce(rpcClient, "DERO.GetInfo", nil, &ninfo); cerr != nil || ninfo == nil {
+			if cerr == nil {
+				cerr = &RPCError{Method: "DERO.GetInfo", Err: errors.New("empty result")}
+			}
+			err = cerr
+			n.setHealthy(false)
+			if errors.Is(cerr, ErrRPCTransport) && rpcClient != nil && !closed {
+				go n.reconnect(rpcClient)
+			}
+			continue
+		}
+
+		infos[i] = ninfo
+		if info == nil || ninfo.TopoHeight > info.TopoHeight {
+			info = ninfo
+		}
+	}
+
+	if info == nil {
+		if err == nil {
+			err = &RPCError{Method: "DERO.GetInfo", Kind: ErrRPCTransport, Err: errors.New("not connected")}
+		}
+		Connected = false
+		return nil, err
+	}
+	Connected = true
+
+	for i, n := range nodes {
+		if infos[i] == nil {
+			continue
+		}
+
+		healthy := infos[i].Testnet == info.Testnet && info.TopoHeight-infos[i].TopoHeight <= rpc_max_node_lag
+
+		n.Lock()
+		if n.Healthy && !healthy {
+			logger.Errorf("[CheckHealth] Endpoint %v is behind or on another network (topoheight %v / %v) - failing over", n.Endpoint, infos[i].TopoHeight, info.TopoHeight)
+		} else if !n.Healthy && healthy && len(nodes) > 1 {
+			logger.Printf("[CheckHealth] Endpoint %v is healthy (topoheight %v / %v)", n.Endpoint, infos[i].TopoHeight, info.TopoHeight)
+		}
+		n.Healthy = healthy
+		n.Height = infos[i].TopoHeight
+		n.Unlock()
+	}
+
+	return info, nil
+}
+
+// Calls method against one of the healthy endpoints. Transport failures fail over to the next endpoint and are retried with exponential backoff once all have been tried, reconnecting in between. Pruned/not found are only returned once every endpoint has said the same. Other daemon errs are returned right away
+func (client *Client) Call(method string, params interface{}, result interface{}) (err error) {
+	return client.call(method, params, result, nil)
+}
+
+// Same as Call() with check run against the result, for calls where the daemon reports not found within the result rather than as an err
+func (client *Client) call(method string, params interface{}, result interface{}, check func() error) (err error) {
+	backoff := rpc_backoff_min
+	tried := make(map[*rpcNode]bool)
+	var transportErr error
+	for retries := 0; ; {
+		n := client.pick(tried)
+		if n == nil {
+			// Every endpoint has been tried. If any of them could not be reached it may still have what was asked for, so retry
+			if err == nil {
+				transportErr = &RPCError{Method: method, Kind: ErrRPCTransport, Err: errors.New("not connected")}
+			}
+			if transportErr == nil {
+				return
+			}
+			err = transportErr
+
+			if retries >= rpc_max_retries || client.isClosed() {
+				logger.Errorf("[Call] %v failed: %v . (%v / %v times)", method, err, retries, rpc_max_retries)
+				return
+			}
+
+			logger.Debugf("[Call] %v failed: %v . Trying again in %v (%v / %v)", method, err, backoff, retries+1, rpc_max_retries)
+			time.Sleep(backoff)
+			backoff *= 2
+			if backoff > rpc_backoff_max {
+				backoff = rpc_backoff_max
+			}
+
+			retries++
+			tried = make(map[*rpcNode]bool)
+			transportErr = nil
+			continue
+		}
+		tried[n] = true
+
+		n.RLock()
+		rpcClient := n.RPC
+		n.RUnlock()
+
+		err = callOnce(rpcClient, method, params, result)
+		if err == nil && check != nil {
+			err = check()
+		}
+
+		switch {
+		case err == nil:
+			return
+		case errors.Is(err, ErrRPCTransport):
+			transportErr = err
+			logger.Debugf("[Call] %v failed on %v: %v", method, n.Endpoint, err)
+			n.setHealthy(false)
+			if rpcClient != nil && !client.isClosed() {
+				go n.reconnect(rpcClient)
+			}
+		case errors.Is(err, ErrRPCPruned) || errors.Is(err, ErrRPCNotFound):
+			// Another endpoint may be further along or not pruned, try the rest before returning it
+			logger.Debugf("[Call] %v on %v: %v", method, n.Endpoint, err)
+		default:
+			return
+		}
+	}
+}
+
+// Picks the next endpoint that has not been tried, round robin. Healthy endpoints first, then any connected one so that a lone behind endpoint is still used
+func (client *Client) pick(tried map[*rpcNode]bool) *rpcNode {
+	client.Lock()
+	defer client.Unlock()
+
+	if client.closed {
+		return nil
+	}
+
+	var fallback *rpcNode
+	for i := 0; i < len(client.nodes); i++ {
+		n := client.nodes[(client.next+i)%len(client.nodes)]
+		if tried[n] {
+			continue
+		}
+
+		n.RLock()
+		connected, healthy := n.RPC != nil, n.Healthy
+		n.RUnlock()
+
+		if connected && healthy {
+			client.next = (client.next + i + 1) % len(client.nodes)
+			return n
+		}
+		if connected && fallback == nil {
+			fallback = n
+		}
+	}
+
+	return fallback
+}
+
+func (client *Client) isClosed() bool {
+	client.RLock()
+	defer client.RUnlock()
+
+	return client.closed
+}
+
+// Lock must be held
+func (client *Client) sameEndpoints(endpoints []string) bool {
+	if len(endpoints) != len(client.nodes) {
+		return false
+	}
+	for i, e := range endpoints {
+		if client.nodes[i].Endpoint != e {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Single attempt of method against rpcClient, bounded by rpc_call_timeout
+func callOnce(rpcClient *jrpc2.Client, method string, params interface{}, result interface{}) (err error) {
+	if rpcClient == nil {
+		return &RPCError{Method: method, Kind: ErrRPCTransport, Err: errors.New("not connected")}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), rpc_call_timeout)
+	defer cancel()
+
+	if err = rpcClient.CallResult(ctx, method, params, result); err != nil {
+		return classifyRPCError(method, err)
+	}
+
+	return
+}
+
+// Re-dials the endpoint after a transport failure on failed. If another call has already replaced that connection, nothing is done
+func (n *rpcNode) reconnect(failed *jrpc2.Client) (err error) {
+	n.Lock()
+	defer n.Unlock()
+
+	if n.RPC != failed {
+		return
+	}
+
+	return n.dial()
+}
+
+// Dials the endpoint and replaces the current connection. Lock must be held
+func (n *rpcNode) dial() (err error) {
+	if n.WS != nil {
+		n.WS.Close()
+	}
+	n.RPC = nil
+	n.Healthy = false
+
+	n.WS, _, err = websocket.DefaultDialer.Dial("ws://"+n.Endpoint+"/ws", nil)
+	if err != nil {
+		logger.Errorf("[Connect] ERROR - Connection to RPC server Failed - ws://%s/ws : %v", n.Endpoint, err)
+		n.WS = nil
+		return
+	}
+
+	logger.Printf("[Connect] Connection to RPC server successful - ws://%s/ws", n.Endpoint)
+
+	input_output := rwc.New(n.WS)
+	n.RPC = jrpc2.NewClient(channel.RawJSON(input_output, input_output), nil)
+	// Healthy until the next health check says otherwise
+	n.Healthy = true
+
+	return
+}
+
+func (n *rpcNode) close() {
+	n.Lock()
+	defer n.Unlock()
+
+	if n.WS != nil {
+		n.WS.Close()
+	}
+	n.WS = nil
+	n.RPC = nil
+	n.Healthy = false
+}
+
+func (n *rpcNode) setHealthy(healthy bool) {
+	n.Lock()
+	n.Healthy = healthy
+	n.Unlock()
+}
+
+// Splits an endpoint string into its endpoints
+func splitEndpoints(endpoint string) (endpoints []string) {
+	for _, e := range strings.Split(endpoint, endpoint_separator) {
+		e = strings.TrimSpace(e)
+		if e != "" {
+			endpoints = append(endpoints, e)
+		}
+	}
+
+	return
+}
+
+// Sorts err from a call into pruned, not found, transport or a plain daemon err
+func classifyRPCError(method string, err error) error {
+	msg := err.Error()
+
+	switch {
+	case strings.Contains(msg, "err occured empty block") || strings.Contains(msg, "err occured file does not exist"):
+		return &RPCError{Method: method, Kind: ErrRPCPruned, Err: err}
+	case strings.Contains(msg, "Too big topo height") || strings.Contains(msg, "more than chain topoheight"):
+		return &RPCError{Method: method, Kind: ErrRPCNotFound, Err: err}
+	}
+
+	// Errs returned by the daemon come back as jrpc2 errors. InternalError is used by jrpc2 itself when the connection fails
+	var jerr *jrpc2.Error
+	if errors.As(err, &jerr) && jerr.Code != code.InternalError {
+		return &RPCError{Method: method, Err: err}
+	}
+
+	return &RPCError{Method: method, Kind: ErrRPCTransport, Err: err}
+}
@@ -0,0 +1,242 @@
+package indexer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/civilware/Gnomon/storage"
+	"github.com/deroproject/derohe/dvm"
+)
+
+// TemplateMatcher classifies SC code against a set of reference (.bas) templates, e.g. to find every deployment of a given dApp.
+// Code is compared on its parsed functions rather than its text. Whitespace, comments and line numbering (GOTO/THEN/ELSE targets included) do not matter, and any functions in ignore (such as InitializePrivate(), which is often changed per deploy) are left out
+type TemplateMatcher struct {
+	templates []*scTemplate
+	ignore    map[string]bool
+}
+
+type scTemplate struct {
+	ID          string
+	fingerprint [32]byte
+}
+
+// Loads the templates at paths. The template id of each is its file name without the extension
+func NewTemplateMatcher(paths []string, ignoreFuncs []string) (tm *TemplateMatcher, err error) {
+	tm = &TemplateMatcher{ignore: make(map[string]bool)}
+	for _, f := range ignoreFuncs {
+		if f = strings.TrimSpace(f); f != "" {
+			tm.ignore[f] = true
+		}
+	}
+
+	for _, path := range paths {
+		var code []byte
+		code, err = os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not read template '%v': %v", path, err)
+		}
+
+		id := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		for _, t := range tm.templates {
+			if t.ID == id {
+				return nil, fmt.Errorf("duplicate template id '%v' (%v)", id, path)
+			}
+		}
+
+		fingerprint, ferr := tm.fingerprint(string(code))
+		if ferr != nil {
+			return nil, fmt.Errorf("could not parse template '%v': %v", path, ferr)
+		}
+
+		tm.templates = append(tm.templates, &scTemplate{ID: id, fingerprint: fingerprint})
+	}
+
+	return
+}
+
+// Returns the id of the template code matches, or "" if it does not match any
+func (tm *TemplateMatcher) Match(code string) (id string) {
+	if tm == nil || len(tm.templates) == 0 || code == "" {
+		return
+	}
+
+	fingerprint, err := tm.fingerprint(code)
+	if err != nil {
+		return
+	}
+
+	for _, t := range tm.templates {
+		if t.fingerprint == fingerprint {
+			return t.ID
+		}
+	}
+
+	return
+}
+
+// Returns the ids of the loaded templates
+func (tm *TemplateMatcher) IDs() (ids []string) {
+	if tm == nil {
+		return
+	}
+
+	for _, t := range tm.templates {
+		ids = append(ids, t.ID)
+	}
+
+	return
+}
+
+// Returns a hash of the loaded templates and ignored functions, which changes whenever classifying code against them could give a different result
+func (tm *TemplateMatcher) Set() (set string) {
+	if tm == nil || len(tm.templates) == 0 {
+		return
+	}
+
+	var ids []string
+	for _, t := range tm.templates {
+		ids = append(ids, t.ID+":"+hex.EncodeToString(t.fingerprint[:]))
+	}
+	sort.Strings(ids)
+
+	var ignore []string
+	for f := range tm.ignore {
+		ignore = append(ignore, f)
+	}
+	sort.Strings(ignore)
+
+	hash := sha256.Sum256([]byte(strings.Join(ids, ",") + "|" + strings.Join(ignore, ",")))
+
+	return hex.EncodeToString(hash[:])
+}
+
+// Hashes the normalized form of the SC functions, less the ignored ones
+func (tm *TemplateMatcher) fingerprint(code string) (fingerprint [32]byte, err error) {
+	SC, _, err := dvm.ParseSmartContract(code)
+	if err != nil {
+		return
+	}
+
+	var names []string
+	for name := range SC.Functions {
+		if !tm.ignore[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var normalized strings.Builder
+	for _, name := range names {
+		normalizeFunction(&normalized, SC.Functions[name])
+	}
+
+	return sha256.Sum256([]byte(normalized.String())), nil
+}
+
+// Writes f with its lines renumbered 1..n in order. Line number references are renumbered to match, so a template with lines 10, 20, 30 equals one with lines 1, 2, 3
+func normalizeFunction(w *strings.Builder, f dvm.Function) {
+	fmt.Fprintf(w, "Function %s(", f.Name)
+	for i, p := range f.Params {
+		if i > 0 {
+			w.WriteString(", ")
+		}
+		fmt.Fprintf(w, "%s %d", p.Name, p.Type)
+	}
+	fmt.Fprintf(w, ") %d\n", f.ReturnValue.Type)
+
+	for i, ln := range f.LineNumbers {
+		fmt.Fprintf(w, "%d", i+1)
+		tokens := f.Lines[ln]
+		for j, t := range tokens {
+			if j > 0 && isLineReference(tokens[j-1]) {
+				if target, err := strconv.ParseUint(t, 10, 64); err == nil {
+					if idx, ok := f.LinesNumberIndex[target]; ok {
+						t = strconv.FormatUint(idx+1, 10)
+					}
+				}
+			}
+			w.WriteString(" " + t)
+		}
+		w.WriteString("\n")
+	}
+
+	w.WriteString("End Function\n")
+}
+
+// Tokens that are followed by a line number within the function
+func isLineReference(token string) bool {
+	return strings.EqualFold(token, "GOTO") || strings.EqualFold(token, "THEN") || strings.EqualFold(token, "ELSE")
+}
+
+// Stores the template of scid as classified from code, when it differs from the stored one. Called within a write
+func (indexer *Indexer) storeSCIDTemplate(s storage.Storage, scid string, code string) (err error) {
+	if indexer.Templates == nil || code == "" {
+		return
+	}
+
+	if templateID := indexer.Templates.Match(code); templateID != s.GetSCIDTemplate(scid) {
+		_, err = s.StoreSCIDTemplate(scid, templateID)
+	}
+
+	return
+}
+
+// Classifies the scids already indexed against the loaded templates, once per set of templates, so templates defined after a scid was indexed still cover it
+func (indexer *Indexer) backfillTemplates() {
+	set := indexer.Templates.Set()
+	if set == "" || indexer.Backend.GetTemplateSet() == set {
+		return
+	}
+
+	scids := indexer.Backend.GetAllOwnersAndSCIDs()
+	if len(scids) > 0 {
+		logger.Printf("[backfillTemplates] Classifying %v SCIDs against templates %v...", len(scids), indexer.Templates.IDs())
+	}
+
+	var classified int
+	for scid := range scids {
+		if indexer.Closing {
+			return
+		}
+
+		// Latest stored code version, otherwise as of now from the daemon (e.g. indexed before code versions were kept)
+		var code string
+		if versions := indexer.Backend.GetAllSCIDCode(scid); len(versions) > 0 {
+			code = versions[len(versions)-1].Code
+		} else {
+			_, code, _, _ = indexer.RPC.GetSCVariables(scid, indexer.ChainHeight, nil, nil, nil, false)
+		}
+		if code == "" {
+			continue
+		}
+
+		scid := scid
+		err := indexer.Writer.Write(func(s storage.Storage) error {
+			return indexer.storeSCIDTemplate(s, scid, code)
+		})
+		if err != nil {
+			logger.Errorf("[backfillTemplates] ERR - storing template of '%v': %v", scid, err)
+			return
+		}
+		if indexer.Backend.GetSCIDTemplate(scid) != "" {
+			classified++
+		}
+	}
+
+	err := indexer.Writer.Write(func(s storage.Storage) error {
+		_, err := s.StoreTemplateSet(set)
+		return err
+	})
+	if err != nil {
+		logger.Errorf("[backfillTemplates] ERR - storing template set: %v", err)
+		return
+	}
+
+	logger.Printf("[backfillTemplates] %v SCIDs match a template", classified)
+}
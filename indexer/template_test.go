@@ -0,0 +1,113 @@
+package indexer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const template_test_code = `Function InitializePrivate() Uint64
+10 STORE("owner", SIGNER())
+20 STORE("name", "original")
+30 RETURN 0
+End Function
+
+Function SetName(name String) Uint64
+10 IF SIGNER() != LOAD("owner") THEN GOTO 30
+20 STORE("name", name)
+30 RETURN 0
+End Function
+`
+
+func TestTemplateMatch(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "namer.bas")
+	if err := os.WriteFile(path, []byte(template_test_code), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tm, err := NewTemplateMatcher([]string{path}, []string{"InitializePrivate"})
+	if err != nil {
+		t.Fatalf("NewTemplateMatcher() err = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		code string
+		want string
+	}{
+		{name: "same", code: template_test_code, want: "namer"},
+		{name: "blank", code: "", want: ""},
+		{name: "unparsable", code: "not a smart contract", want: ""},
+		{name: "whitespace and comments", code: `Function InitializePrivate() Uint64
+10 STORE("owner", SIGNER())
+20 RETURN 0
+End Function
+
+// Only the owner can rename
+Function SetName(name String) Uint64
+10   IF SIGNER() != LOAD("owner") THEN GOTO 30
+20   STORE("name", name)
+30   RETURN 0
+End Function
+`, want: "namer"},
+		{name: "renumbered lines", code: `Function SetName(name String) Uint64
+100 IF SIGNER() != LOAD("owner") THEN GOTO 300
+200 STORE("name", name)
+300 RETURN 0
+End Function
+`, want: "namer"},
+		{name: "different goto target", code: `Function SetName(name String) Uint64
+10 IF SIGNER() != LOAD("owner") THEN GOTO 20
+20 STORE("name", name)
+30 RETURN 0
+End Function
+`, want: ""},
+		{name: "different param", code: `Function SetName(title String) Uint64
+10 IF SIGNER() != LOAD("owner") THEN GOTO 30
+20 STORE("name", title)
+30 RETURN 0
+End Function
+`, want: ""},
+		{name: "extra function", code: template_test_code + `
+Function Withdraw() Uint64
+10 RETURN 0
+End Function
+`, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tm.Match(tt.code); got != tt.want {
+				t.Errorf("Match() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplateSet(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "namer.bas")
+	if err := os.WriteFile(path, []byte(template_test_code), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := NewTemplateMatcher([]string{path}, []string{"InitializePrivate"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewTemplateMatcher([]string{path}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if a.Set() == "" || a.Set() == b.Set() {
+		t.Errorf("Set() should change with the ignored functions, got %q and %q", a.Set(), b.Set())
+	}
+	if _, err := NewTemplateMatcher([]string{path, path}, nil); err == nil {
+		t.Errorf("NewTemplateMatcher() allowed a duplicate template id")
+	}
+	if (*TemplateMatcher)(nil).Match(template_test_code) != "" {
+		t.Errorf("Match() on a nil matcher should not match")
+	}
+}
@@ -0,0 +1,51 @@
+package indexer
+
+import (
+	"github.com/civilware/Gnomon/storage"
+)
+
+// Converts SC variables stored prior to storage.SCIDVariableFormat to keyframes and changes. Runs once per db, each scid within its own write so that the migration can be stopped and picked up again on the next start
+func (indexer *Indexer) migrateSCIDVariables() {
+	if indexer.Backend.GetVariableFormat() >= storage.SCIDVariableFormat {
+		return
+	}
+
+	scids := indexer.Backend.GetAllOwnersAndSCIDs()
+	if len(scids) > 0 {
+		logger.Printf("[migrateSCIDVariables] Migrating the stored variables of %v SCIDs...", len(scids))
+	}
+
+	var migrated int
+	for scid := range scids {
+		if indexer.Closing {
+			return
+		}
+
+		scid := scid
+		var changes bool
+		err := indexer.Writer.Write(func(s storage.Storage) (err error) {
+			changes, err = s.MigrateSCIDVariableDetails(scid)
+			return
+		})
+		if err != nil {
+			logger.Errorf("[migrateSCIDVariables] ERR - migrating variables of '%v': %v", scid, err)
+			return
+		}
+		if changes {
+			migrated++
+		}
+	}
+
+	err := indexer.Writer.Write(func(s storage.Storage) error {
+		_, err := s.StoreVariableFormat(storage.SCIDVariableFormat)
+		return err
+	})
+	if err != nil {
+		logger.Errorf("[migrateSCIDVariables] ERR - storing variable format: %v", err)
+		return
+	}
+
+	if migrated > 0 {
+		logger.Printf("[migrateSCIDVariables] Migrated the stored variables of %v SCIDs", migrated)
+	}
+}
@@ -0,0 +1,145 @@
+package indexer
+
+import (
+	"sort"
+
+	"github.com/civilware/Gnomon/storage"
+	"github.com/civilware/Gnomon/structures"
+	"github.com/deroproject/derohe/cryptography/crypto"
+	"github.com/deroproject/derohe/rpc"
+)
+
+// WALLET.GetTransfers rpc call for returning the wallet's incoming and outgoing entries between min_height and max_height (inclusive)
+func (indexer *Indexer) getWalletTransfers(min_height uint64, max_height uint64) (entries []rpc.Entry, err error) {
+	var io rpc.Get_Transfers_Result
+	var ip = rpc.Get_Transfers_Params{In: true, Out: true, Min_Height: min_height, Max_Height: max_height}
+
+	if err = indexer.WalletRPC.Call("WALLET.GetTransfers", ip, &io); err != nil {
+		return
+	}
+
+	entries = io.Entries
+
+	return
+}
+
+// WALLET.GetAddress rpc call for returning the wallet's address
+func (indexer *Indexer) getWalletAddress() (address string, err error) {
+	var io rpc.GetAddress_Result
+
+	if err = indexer.WalletRPC.Call("WALLET.GetAddress", nil, &io); err != nil {
+		return
+	}
+
+	address = io.Address
+
+	return
+}
+
+// Indexes the txns of the wallet entries through the same paths as daemon mode, one topoheight at a time, and commits them together with lastHeight as the last indexed height
+func (indexer *Indexer) indexWalletEntries(entries []rpc.Entry, lastHeight int64) (err error) {
+	wb := &storage.WriteBatch{}
+
+	var address string
+	if len(entries) > 0 {
+		address, err = indexer.getWalletAddress()
+		if err != nil {
+			return
+		}
+	}
+
+	// A txn can show up as multiple entries (one per payload), only index it once. Coinbase entries have no txn
+	txns := make(map[int64][]crypto.Hash)
+	seen := make(map[string]bool)
+	outgoing := make(map[string]bool)
+	for _, e := range entries {
+		if e.Coinbase || e.TXID == "" {
+			continue
+		}
+
+		if !e.Incoming {
+			outgoing[e.TXID] = true
+		}
+
+		if seen[e.TXID] {
+			continue
+		}
+		seen[e.TXID] = true
+
+		thash := crypto.HashHexToHash(e.TXID)
+		if thash == (crypto.Hash{}) {
+			logger.Errorf("[indexWalletEntries] ERROR - Could not decode txid '%v'", e.TXID)
+			continue
+		}
+		txns[e.TopoHeight] = append(txns[e.TopoHeight], thash)
+	}
+
+	var heights []int64
+	for k := range txns {
+		heights = append(heights, k)
+	}
+	sort.Slice(heights, func(i, j int) bool {
+		return heights[i] < heights[j]
+	})
+
+	// Keep the validated scids from before, so they can be put back if the entries are not committed
+	indexer.RLock()
+	validatedSCs := append([]string(nil), indexer.ValidatedSCs...)
+	indexer.RUnlock()
+
+	for _, height := range heights {
+		blTxns := &structures.BlockTxns{Topoheight: height, Tx_hashes: txns[height]}
+
+		var bl_sctxs []structures.SCTXParse
+		bl_sctxs, _, _, _, err = indexer.indexTxn(blTxns, false, wb)
+		if err != nil {
+			break
+		}
+
+		// The daemon can only tell the signer of ringsize 2 txns. Anything the wallet sent was signed by the wallet, regardless of ringsize
+		for i := range bl_sctxs {
+			if bl_sctxs[i].Sender == "" && outgoing[bl_sctxs[i].Txid] {
+				bl_sctxs[i].Sender = address
+			}
+		}
+
+		err = indexer.indexInvokes(bl_sctxs, blTxns, wb)
+		if err != nil {
+			break
+		}
+	}
+	if err != nil {
+		indexer.Lock()
+		indexer.ValidatedSCs = validatedSCs
+		indexer.Unlock()
+		return
+	}
+
+	wb.Add(func(s storage.Storage) error {
+		_, err := s.StoreLastIndexHeight(lastHeight)
+		return err
+	})
+	indexer.stageEvent(wb, func() *Event {
+		return &Event{Type: EventHeightIndexed, Topoheight: lastHeight}
+	})
+
+	err = indexer.Writer.Write(wb.Run)
+	if err != nil {
+		indexer.Lock()
+		indexer.ValidatedSCs = validatedSCs
+		indexer.Unlock()
+		return
+	}
+
+	indexer.Lock()
+	indexer.LastIndexedHeight = lastHeight
+	indexer.Unlock()
+
+	wb.Committed()
+
+	if len(heights) > 0 {
+		logger.Printf("[indexWalletEntries] Indexed %v wallet txn(s) through wallet height %v", len(seen), lastHeight)
+	}
+
+	return
+}
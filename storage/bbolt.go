@@ -3,9 +3,9 @@ package storage
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
-	"reflect"
 	"sort"
 	"strconv"
 	"strings"
@@ -20,10 +20,9 @@ import (
 type BboltStore struct {
 	DB      *bolt.DB
 	DBPath  string
-	Writing int
-	//Writer  string
 	Closing bool
 	Buckets []string
+	tx      *bolt.Tx
 }
 
 // local logger
@@ -53,7 +52,7 @@ func NewBBoltDB(dbPath, dbName string) (*BboltStore, error) {
 func (bbs *BboltStore) StoreLastIndexHeight(last_indexedheight int64) (changes bool, err error) {
 	bName := "stats"
 
-	err = bbs.DB.Update(func(tx *bolt.Tx) (err error) {
+	err = bbs.update(func(tx *bolt.Tx) (err error) {
 		b, err := tx.CreateBucketIfNotExists([]byte(bName))
 		if err != nil {
 			return fmt.Errorf("bucket: %s", err)
@@ -70,11 +69,114 @@ func (bbs *BboltStore) StoreLastIndexHeight(last_indexedheight int64) (changes b
 	return
 }
 
+// Stores the earliest topoheight the index holds data from, i.e. the first block available on a pruned daemon
+func (bbs *BboltStore) StoreEarliestHeight(topoheight int64) (changes bool, err error) {
+	bName := "stats"
+
+	err = bbs.update(func(tx *bolt.Tx) (err error) {
+		b, err := tx.CreateBucketIfNotExists([]byte(bName))
+		if err != nil {
+			return fmt.Errorf("bucket: %s", err)
+		}
+
+		err = b.Put([]byte("earliestheight"), []byte(strconv.FormatInt(topoheight, 10)))
+		changes = true
+		return
+	})
+
+	return
+}
+
+// Gets the earliest topoheight the index holds data from, 0 if the index is not bounded by a pruned daemon
+func (bbs *BboltStore) GetEarliestHeight() (topoheight int64) {
+	bName := "stats"
+
+	bbs.view(func(tx *bolt.Tx) (err error) {
+		b := tx.Bucket([]byte(bName))
+		if b != nil {
+			if v := b.Get([]byte("earliestheight")); v != nil {
+				topoheight, _ = strconv.ParseInt(string(v), 10, 64)
+			}
+		}
+		return
+	})
+
+	return
+}
+
+// Stores the format of the stored SC variable history, see SCIDVariableFormat
+func (bbs *BboltStore) StoreVariableFormat(format int64) (changes bool, err error) {
+	bName := "stats"
+
+	err = bbs.update(func(tx *bolt.Tx) (err error) {
+		b, err := tx.CreateBucketIfNotExists([]byte(bName))
+		if err != nil {
+			return fmt.Errorf("bucket: %s", err)
+		}
+
+		err = b.Put([]byte("varsformat"), []byte(strconv.FormatInt(format, 10)))
+		changes = true
+		return
+	})
+
+	return
+}
+
+// Gets the format of the stored SC variable history, 0 if it has not been stored (prior to SCIDVariableFormat)
+func (bbs *BboltStore) GetVariableFormat() (format int64) {
+	bName := "stats"
+
+	bbs.view(func(tx *bolt.Tx) (err error) {
+		b := tx.Bucket([]byte(bName))
+		if b != nil {
+			if v := b.Get([]byte("varsformat")); v != nil {
+				format, _ = strconv.ParseInt(string(v), 10, 64)
+			}
+		}
+		return
+	})
+
+	return
+}
+
+// Stores the set of templates the indexed scids were last classified against
+func (bbs *BboltStore) StoreTemplateSet(set string) (changes bool, err error) {
+	bName := "stats"
+
+	err = bbs.update(func(tx *bolt.Tx) (err error) {
+		b, err := tx.CreateBucketIfNotExists([]byte(bName))
+		if err != nil {
+			return fmt.Errorf("bucket: %s", err)
+		}
+
+		err = b.Put([]byte("templateset"), []byte(set))
+		changes = true
+		return
+	})
+
+	return
+}
+
+// Gets the set of templates the indexed scids were last classified against, "" if they have not been
+func (bbs *BboltStore) GetTemplateSet() (set string) {
+	bName := "stats"
+
+	bbs.view(func(tx *bolt.Tx) (err error) {
+		b := tx.Bucket([]byte(bName))
+		if b != nil {
+			set = string(b.Get([]byte("templateset")))
+		}
+		return
+	})
+
+	return
+}
+
 // Gets bbolt's last indexed height - this is for stateful stores on close and reference on open
 func (bbs *BboltStore) GetLastIndexHeight() (topoheight int64, err error) {
 	bName := "stats"
 
-	bbs.DB.View(func(tx *bolt.Tx) (err error) {
+	bbs.view(func(tx *bolt.Tx) (err error) {
 		b := tx.Bucket([]byte(bName))
 		if b != nil {
 			key := "lastindexedheight"
@@ -101,7 +203,7 @@ func (bbs *BboltStore) GetLastIndexHeight() (topoheight int64, err error) {
 func (bbs *BboltStore) StoreTxCount(count int64, txType string) (changes bool, err error) {
 	bName := "stats"
 
-	err = bbs.DB.Update(func(tx *bolt.Tx) (err error) {
+	err = bbs.update(func(tx *bolt.Tx) (err error) {
 		b, err := tx.CreateBucketIfNotExists([]byte(bName))
 		if err != nil {
 			return fmt.Errorf("bucket: %s", err)
@@ -123,7 +225,7 @@ func (bbs *BboltStore) StoreTxCount(count int64, txType string) (changes bool, e
 func (bbs *BboltStore) GetTxCount(txType string) (txCount int64) {
 	bName := "stats"
 
-	bbs.DB.View(func(tx *bolt.Tx) (err error) {
+	bbs.view(func(tx *bolt.Tx) (err error) {
 		b := tx.Bucket([]byte(bName))
 		if b != nil {
 			key := txType + "txcount"
@@ -142,11 +244,57 @@ func (bbs *BboltStore) GetTxCount(txType string) (txCount int64) {
 	return
 }
 
+// Stores the tx counts added by the blocks at height, so they can be taken back off the totals if the blocks are rolled back. Counts more than block_hashes_kept below height are trimmed, as with block hashes
+func (bbs *BboltStore) StoreTxCountsByHeight(height int64, counts map[string]int64) (changes bool, err error) {
+	bName := "txcountheights"
+
+	err = bbs.update(func(tx *bolt.Tx) (err error) {
+		b, err := tx.CreateBucketIfNotExists([]byte(bName))
+		if err != nil {
+			return fmt.Errorf("bucket: %s", err)
+		}
+
+		key := []byte(strconv.FormatInt(height, 10))
+		currCounts := make(map[string]int64)
+		if v := b.Get(key); v != nil {
+			_ = json.Unmarshal(v, &currCounts)
+		}
+		for txType, count := range counts {
+			currCounts[txType] += count
+		}
+		newCounts, err := json.Marshal(currCounts)
+		if err != nil {
+			return fmt.Errorf("[bbs-StoreTxCountsByHeight] could not marshal tx counts: %v", err)
+		}
+		if err = b.Put(key, newCounts); err != nil {
+			return
+		}
+		changes = true
+
+		var trim [][]byte
+		c := b.Cursor()
+		for k, _ := c.First(); k != nil; k, _ = c.Next() {
+			if h, perr := strconv.ParseInt(string(k), 10, 64); perr == nil && h < height-block_hashes_kept {
+				trim = append(trim, k)
+			}
+		}
+		for _, k := range trim {
+			if err = b.Delete(k); err != nil {
+				return
+			}
+		}
+
+		return
+	})
+
+	return
+}
+
 // Stores the owner (who deployed it) of a given scid
 func (bbs *BboltStore) StoreOwner(scid string, owner string) (changes bool, err error) {
 	bName := "scowner"
 
-	err = bbs.DB.Update(func(tx *bolt.Tx) (err error) {
+	err = bbs.update(func(tx *bolt.Tx) (err error) {
 		b, err := tx.CreateBucketIfNotExists([]byte(bName))
 		if err != nil {
 			return fmt.Errorf("bucket: %s", err)
@@ -165,7 +313,7 @@ func (bbs *BboltStore) GetOwner(scid string) string {
 	var v []byte
 	bName := "scowner"
 
-	bbs.DB.View(func(tx *bolt.Tx) (err error) {
+	bbs.view(func(tx *bolt.Tx) (err error) {
 		b := tx.Bucket([]byte(bName))
 		if b != nil {
 			key := scid
@@ -190,7 +338,7 @@ func (bbs *BboltStore) GetAllOwnersAndSCIDs() map[string]string {
 
 	bName := "scowner"
 
-	bbs.DB.View(func(tx *bolt.Tx) (err error) {
+	bbs.view(func(tx *bolt.Tx) (err error) {
 		b := tx.Bucket([]byte(bName))
 		if b != nil {
 			c := b.Cursor()
@@ -210,6 +358,62 @@ func (bbs *BboltStore) GetAllOwnersAndSCIDs() map[string]string {
 	return results
 }
 
+// Stores the id of the template a given scid's code matches
+func (bbs *BboltStore) StoreSCIDTemplate(scid string, templateID string) (changes bool, err error) {
+	bName := "templates"
+
+	err = bbs.update(func(tx *bolt.Tx) (err error) {
+		b, err := tx.CreateBucketIfNotExists([]byte(bName))
+		if err != nil {
+			return fmt.Errorf("bucket: %s", err)
+		}
+
+		err = b.Put([]byte(scid), []byte(templateID))
+		changes = true
+		return
+	})
+
+	return
+}
+
+// Returns the id of the template a given scid's code matches, or "" if it did not match one
+func (bbs *BboltStore) GetSCIDTemplate(scid string) (templateID string) {
+	bName := "templates"
+
+	bbs.view(func(tx *bolt.Tx) (err error) {
+		b := tx.Bucket([]byte(bName))
+		if b != nil {
+			templateID = string(b.Get([]byte(scid)))
+		}
+
+		return
+	})
+
+	return
+}
+
+// Returns all scids whose code matches a given template id
+func (bbs *BboltStore) GetSCIDsByTemplate(templateID string) (scids []string) {
+	bName := "templates"
+
+	bbs.view(func(tx *bolt.Tx) (err error) {
+		b := tx.Bucket([]byte(bName))
+		if b != nil {
+			c := b.Cursor()
+
+			for k, v := c.First(); k != nil; k, v = c.Next() {
+				if string(v) == templateID {
+					scids = append(scids, string(k))
+				}
+			}
+		}
+
+		return
+	})
+
+	return
+}
+
 // Stores all normal txs with SCIDs and their respective ring members for future balance/interaction reference
 func (bbs *BboltStore) StoreNormalTxWithSCIDByAddr(addr string, normTxWithSCID *structures.NormalTXWithSCIDParse) (changes bool, err error) {
 	var newNormTxsWithSCID []byte
@@ -218,7 +422,7 @@ func (bbs *BboltStore) StoreNormalTxWithSCIDByAddr(addr string, normTxWithSCID *
 	bName := "normaltxwithscid"
 	key := addr
 
-	err = bbs.DB.View(func(tx *bolt.Tx) (err error) {
+	err = bbs.view(func(tx *bolt.Tx) (err error) {
 		b := tx.Bucket([]byte(bName))
 		if b != nil {
 			currNormTxsWithSCID = b.Get([]byte(key))
@@ -226,7 +430,7 @@ func (bbs *BboltStore) StoreNormalTxWithSCIDByAddr(addr string, normTxWithSCID *
 		return
 	})
 
-	err = bbs.DB.Update(func(tx *bolt.Tx) (err error) {
+	err = bbs.update(func(tx *bolt.Tx) (err error) {
 		b, err := tx.CreateBucketIfNotExists([]byte(bName))
 		if err != nil {
 			return fmt.Errorf("bucket: %s", err)
@@ -242,7 +446,7 @@ func (bbs *BboltStore) StoreNormalTxWithSCIDByAddr(addr string, normTxWithSCID *
 				if v.Txid == normTxWithSCID.Txid {
 					// Return nil if already exists in array.
 					// Clause for this is in event we pop backwards in time and already have this data stored.
-					// TODO: What if interaction happened on false-chain and pop to retain correct chain. Bad data may be stored here still, as it isn't removed. Need fix for this in future.
+					// Data stored from a false-chain is removed via RollbackToHeight() when a reorg is detected.
 					return
 				}
 			}
@@ -266,7 +470,7 @@ func (bbs *BboltStore) StoreNormalTxWithSCIDByAddr(addr string, normTxWithSCID *
 func (bbs *BboltStore) GetAllNormalTxWithSCIDByAddr(addr string) (normTxsWithSCID []*structures.NormalTXWithSCIDParse) {
 	bName := "normaltxwithscid"
 
-	bbs.DB.View(func(tx *bolt.Tx) (err error) {
+	bbs.view(func(tx *bolt.Tx) (err error) {
 		b := tx.Bucket([]byte(bName))
 		if b != nil {
 			key := addr
@@ -288,7 +492,7 @@ func (bbs *BboltStore) GetAllNormalTxWithSCIDBySCID(scid string) (normTxsWithSCI
 
 	bName := "normaltxwithscid"
 
-	bbs.DB.View(func(tx *bolt.Tx) (err error) {
+	bbs.view(func(tx *bolt.Tx) (err error) {
 		b := tx.Bucket([]byte(bName))
 		if b != nil {
 
@@ -316,118 +520,105 @@ func (bbs *BboltStore) GetAllNormalTxWithSCIDBySCID(scid string) (normTxsWithSCI
 	return
 }
 
-// Stores all scinvoke details of a given scid
-func (bbs *BboltStore) StoreInvokeDetails(scid string, signer string, entrypoint string, topoheight int64, invokedetails *structures.SCTXParse) (changes bool, err error) {
-	confBytes, err := json.Marshal(invokedetails)
-	if err != nil {
-		return changes, fmt.Errorf("[StoreInvokeDetails] could not marshal invokedetails info: %v", err)
-	}
+// Gets all normal txs with scid payloads by address
+func (bbs *BboltStore) GetAllNormalTxWithSCID() map[string][]*structures.NormalTXWithSCIDParse {
+	results := make(map[string][]*structures.NormalTXWithSCIDParse)
 
-	bName := scid
+	bName := "normaltxwithscid"
 
-	txidLen := len(invokedetails.Txid)
-	key := signer + ":" + invokedetails.Txid[0:3] + invokedetails.Txid[txidLen-3:txidLen] + ":" + strconv.FormatInt(topoheight, 10) + ":" + entrypoint
+	bbs.view(func(tx *bolt.Tx) (err error) {
+		b := tx.Bucket([]byte(bName))
+		if b != nil {
+			c := b.Cursor()
 
-	err = bbs.DB.Update(func(tx *bolt.Tx) (err error) {
-		b, err := tx.CreateBucketIfNotExists([]byte(bName))
-		if err != nil {
-			return fmt.Errorf("bucket: %s", err)
+			for k, v := c.First(); k != nil; k, v = c.Next() {
+				var currdetails []*structures.NormalTXWithSCIDParse
+				_ = json.Unmarshal(v, &currdetails)
+				results[string(k)] = currdetails
+			}
 		}
 
-		err = b.Put([]byte(key), confBytes)
-		changes = true
 		return
 	})
 
-	return
+	return results
 }
 
-// Returns all scinvoke calls from a given scid
-func (bbs *BboltStore) GetAllSCIDInvokeDetails(scid string) (invokedetails []*structures.SCTXParse) {
-	bName := scid
+// Stores the registration tx of an address. Only the first seen (lowest height) registration of an address is kept
+func (bbs *BboltStore) StoreRegTx(regtx *structures.RegTXParse) (changes bool, err error) {
+	var currRegTx []byte
+	bName := "registrations"
+	key := regtx.Address
 
-	bbs.DB.View(func(tx *bolt.Tx) (err error) {
+	err = bbs.view(func(tx *bolt.Tx) (err error) {
 		b := tx.Bucket([]byte(bName))
 		if b != nil {
+			currRegTx = b.Get([]byte(key))
+		}
+		return
+	})
 
-			c := b.Cursor()
+	if currRegTx != nil {
+		var curr *structures.RegTXParse
+		_ = json.Unmarshal(currRegTx, &curr)
+		if curr != nil && curr.Height <= regtx.Height {
+			return
+		}
+	}
 
-			for _, v := c.First(); err == nil; _, v = c.Next() {
-				if v != nil {
-					var currdetails *structures.SCTXParse
-					_ = json.Unmarshal(v, &currdetails)
-					invokedetails = append(invokedetails, currdetails)
-				} else {
-					break
-				}
-			}
+	newRegTx, err := json.Marshal(regtx)
+	if err != nil {
+		return changes, fmt.Errorf("[BBolt] could not marshal regtx info: %v", err)
+	}
+
+	err = bbs.update(func(tx *bolt.Tx) (err error) {
+		b, err := tx.CreateBucketIfNotExists([]byte(bName))
+		if err != nil {
+			return fmt.Errorf("bucket: %s", err)
 		}
 
+		err = b.Put([]byte(key), newRegTx)
+		changes = true
 		return
 	})
 
-	// Sort heights so most recent is index 0 [if preferred reverse, just swap > with <]
-	sort.SliceStable(invokedetails, func(i, j int) bool {
-		return invokedetails[i].Height < invokedetails[j].Height
-	})
-
-	return invokedetails
+	return
 }
 
-// Retruns all scinvoke calls from a given scid that match a given entrypoint
-func (bbs *BboltStore) GetAllSCIDInvokeDetailsByEntrypoint(scid string, entrypoint string) (invokedetails []*structures.SCTXParse) {
-	bName := scid
+// Returns the registration tx of a given address
+func (bbs *BboltStore) GetRegTxByAddress(addr string) (regtx *structures.RegTXParse) {
+	bName := "registrations"
 
-	bbs.DB.View(func(tx *bolt.Tx) (err error) {
+	bbs.view(func(tx *bolt.Tx) (err error) {
 		b := tx.Bucket([]byte(bName))
 		if b != nil {
+			key := addr
+			v := b.Get([]byte(key))
 
-			c := b.Cursor()
-
-			for _, v := c.First(); err == nil; _, v = c.Next() {
-				if v != nil {
-					var currdetails *structures.SCTXParse
-					_ = json.Unmarshal(v, &currdetails)
-					if currdetails.Entrypoint == entrypoint {
-						invokedetails = append(invokedetails, currdetails)
-					}
-				} else {
-					break
-				}
+			if v != nil {
+				_ = json.Unmarshal(v, &regtx)
 			}
 		}
-
 		return
 	})
 
-	// Sort heights so most recent is index 0 [if preferred reverse, just swap > with <]
-	sort.SliceStable(invokedetails, func(i, j int) bool {
-		return invokedetails[i].Height < invokedetails[j].Height
-	})
-
-	return invokedetails
+	return
 }
 
-// Returns all scinvoke calls from a given scid that match a given signer
-func (bbs *BboltStore) GetAllSCIDInvokeDetailsBySigner(scid string, signerPart string) (invokedetails []*structures.SCTXParse) {
-	bName := scid
+// Returns all registration txs between the given heights (inclusive), in height order
+func (bbs *BboltStore) GetRegTxsByHeightRange(minHeight int64, maxHeight int64) (regTxs []*structures.RegTXParse) {
+	bName := "registrations"
 
-	bbs.DB.View(func(tx *bolt.Tx) (err error) {
+	bbs.view(func(tx *bolt.Tx) (err error) {
 		b := tx.Bucket([]byte(bName))
 		if b != nil {
-
 			c := b.Cursor()
 
-			for _, v := c.First(); err == nil; _, v = c.Next() {
-				if v != nil {
-					var currdetails *structures.SCTXParse
-					_ = json.Unmarshal(v, &currdetails)
-					split := strings.Split(currdetails.Sender, signerPart)
-					if len(split) > 1 {
-						invokedetails = append(invokedetails, currdetails)
-					}
-				} else {
-					break
+			for k, v := c.First(); k != nil; k, v = c.Next() {
+				var currdetails *structures.RegTXParse
+				_ = json.Unmarshal(v, &currdetails)
+				if currdetails != nil && currdetails.Height >= minHeight && currdetails.Height <= maxHeight {
+					regTxs = append(regTxs, currdetails)
 				}
 			}
 		}
@@ -435,109 +626,169 @@ func (bbs *BboltStore) GetAllSCIDInvokeDetailsBySigner(scid string, signerPart s
 		return
 	})
 
-	// Sort heights so most recent is index 0 [if preferred reverse, just swap > with <]
-	sort.SliceStable(invokedetails, func(i, j int) bool {
-		return invokedetails[i].Height < invokedetails[j].Height
-	})
+	sortRegTxs(regTxs)
 
-	return invokedetails
+	return
 }
 
-// Stores simple getinfo polling from the daemon
-func (bbs *BboltStore) StoreGetInfoDetails(getinfo *structures.GetInfo) (changes bool, err error) {
-	confBytes, err := json.Marshal(getinfo)
-	if err != nil {
-		return changes, fmt.Errorf("[StoreGetInfoDetails] could not marshal getinfo info: %v", err)
-	}
-
-	bName := "getinfo"
+// Stores the burn details of a given txid. There is one BurnTXParse per payload (asset) of the txn. The txid is also kept against its height (burnheights) and its scid (<scid>burns), see GetBurnTxsByHeightRange/GetBurnTxsBySCID
+func (bbs *BboltStore) StoreBurnTx(burntx *structures.BurnTXParse) (changes bool, err error) {
+	var newBurnTxs []byte
+	var currBurnTxs []byte
+	var burnTxs []*structures.BurnTXParse
+	bName := "burntxs"
+	key := burntx.Txid
 
-	key := "getinfo"
+	err = bbs.view(func(tx *bolt.Tx) (err error) {
+		b := tx.Bucket([]byte(bName))
+		if b != nil {
+			currBurnTxs = b.Get([]byte(key))
+		}
+		return
+	})
 
-	err = bbs.DB.Update(func(tx *bolt.Tx) (err error) {
+	err = bbs.update(func(tx *bolt.Tx) (err error) {
 		b, err := tx.CreateBucketIfNotExists([]byte(bName))
 		if err != nil {
 			return fmt.Errorf("bucket: %s", err)
 		}
 
-		err = b.Put([]byte(key), confBytes)
-		changes = true
-		return
-	})
+		if currBurnTxs == nil {
+			burnTxs = append(burnTxs, burntx)
+		} else {
+			_ = json.Unmarshal(currBurnTxs, &burnTxs)
+
+			for _, v := range burnTxs {
+				if v.Scid == burntx.Scid {
+					// Return nil if already exists in array.
+					// Clause for this is in event we pop backwards in time and already have this data stored.
+					// Data stored from a false-chain is removed via RollbackToHeight() when a reorg is detected.
+					return
+				}
+			}
+
+			burnTxs = append(burnTxs, burntx)
+		}
+		newBurnTxs, err = json.Marshal(burnTxs)
+		if err != nil {
+			return fmt.Errorf("[BBolt] could not marshal burnTxs info: %v", err)
+		}
+
+		err = b.Put([]byte(key), newBurnTxs)
+		if err != nil {
+			return
+		}
+		changes = true
+
+		hb, err := tx.CreateBucketIfNotExists([]byte("burnheights"))
+		if err != nil {
+			return fmt.Errorf("bucket: %s", err)
+		}
+		hkey := []byte(strconv.FormatInt(burntx.Height, 10))
+		if txids, added := addIndexKey(hb.Get(hkey), burntx.Txid); added {
+			if err = hb.Put(hkey, txids); err != nil {
+				return
+			}
+		}
+
+		sb, err := tx.CreateBucketIfNotExists([]byte(burntx.Scid + "burns"))
+		if err != nil {
+			return fmt.Errorf("bucket: %s", err)
+		}
+
+		return sb.Put([]byte(burntx.Txid), hkey)
+	})
 
 	return
 }
 
-// Returns simple getinfo polling from the daemon
-func (bbs *BboltStore) GetGetInfoDetails() (getinfo *structures.GetInfo) {
-	var v []byte
-	bName := "getinfo"
+// Returns the burn details of a given txid
+func (bbs *BboltStore) GetBurnTxByTxid(txid string) (burnTxs []*structures.BurnTXParse) {
+	bName := "burntxs"
 
-	bbs.DB.View(func(tx *bolt.Tx) (err error) {
+	bbs.view(func(tx *bolt.Tx) (err error) {
 		b := tx.Bucket([]byte(bName))
 		if b != nil {
-			key := "getinfo"
-			v = b.Get([]byte(key))
-		}
+			key := txid
+			v := b.Get([]byte(key))
 
+			if v != nil {
+				_ = json.Unmarshal(v, &burnTxs)
+			}
+		}
 		return
 	})
 
-	if v != nil {
-		_ = json.Unmarshal(v, &getinfo)
-		return
-	}
-
 	return
 }
 
-// Stores SC variables at a given topoheight (called on any new scdeploy or scinvoke actions)
-func (bbs *BboltStore) StoreSCIDVariableDetails(scid string, variables []*structures.SCIDVariable, topoheight int64) (changes bool, err error) {
-	confBytes, err := json.Marshal(variables)
-	if err != nil {
-		return changes, fmt.Errorf("[StoreSCIDVariableDetails] could not marshal getinfo info: %v", err)
-	}
-
-	bName := scid + "vars"
-
-	key := strconv.FormatInt(topoheight, 10)
-
-	err = bbs.DB.Update(func(tx *bolt.Tx) (err error) {
-		b, err := tx.CreateBucketIfNotExists([]byte(bName))
-		if err != nil {
-			return fmt.Errorf("bucket: %s", err)
+// Returns all burn txs of a given asset SCID (DERO burns are stored against the zero hash), in height order
+func (bbs *BboltStore) GetBurnTxsBySCID(scid string) (burnTxs []*structures.BurnTXParse) {
+	bbs.view(func(tx *bolt.Tx) (err error) {
+		b := tx.Bucket([]byte("burntxs"))
+		sb := tx.Bucket([]byte(scid + "burns"))
+		if b == nil || sb == nil {
+			return
 		}
 
-		err = b.Put([]byte(key), confBytes)
-		changes = true
-		return
+		return sb.ForEach(func(k, _ []byte) error {
+			var currdetails []*structures.BurnTXParse
+			if v := b.Get(k); v != nil {
+				_ = json.Unmarshal(v, &currdetails)
+			}
+			for _, cv := range currdetails {
+				if cv.Scid == scid {
+					burnTxs = append(burnTxs, cv)
+				}
+			}
+			return nil
+		})
 	})
 
+	sortBurnTxs(burnTxs)
+
 	return
 }
 
-// Gets SC variables at a given topoheight
-func (bbs *BboltStore) GetSCIDVariableDetailsAtTopoheight(scid string, topoheight int64) (hVars []*structures.SCIDVariable) {
-	results := make(map[int64][]*structures.SCIDVariable)
-	var heights []int64
-
-	bName := scid + "vars"
+// Returns all burn txs between the given heights (inclusive), in height order. The heights are looked up one by one, unless there are more of them than burns
+func (bbs *BboltStore) GetBurnTxsByHeightRange(minHeight int64, maxHeight int64) (burnTxs []*structures.BurnTXParse) {
+	lastHeight, _ := bbs.GetLastIndexHeight()
+	minHeight, maxHeight, byHeight := burnHeightLookup(minHeight, maxHeight, lastHeight, bbs.GetTxCount("burn"))
 
-	bbs.DB.View(func(tx *bolt.Tx) (err error) {
-		b := tx.Bucket([]byte(bName))
-		if b != nil {
+	bbs.view(func(tx *bolt.Tx) (err error) {
+		b := tx.Bucket([]byte("burntxs"))
+		if b == nil {
+			return
+		}
 
-			c := b.Cursor()
+		if byHeight {
+			hb := tx.Bucket([]byte("burnheights"))
+			if hb == nil {
+				return
+			}
+			for h := minHeight; h <= maxHeight; h++ {
+				var txids []string
+				if hv := hb.Get([]byte(strconv.FormatInt(h, 10))); hv != nil {
+					_ = json.Unmarshal(hv, &txids)
+				}
+				for _, txid := range txids {
+					var currdetails []*structures.BurnTXParse
+					if v := b.Get([]byte(txid)); v != nil {
+						_ = json.Unmarshal(v, &currdetails)
+					}
+					burnTxs = append(burnTxs, currdetails...)
+				}
+			}
+			return
+		}
 
-			for k, v := c.First(); err == nil; k, v = c.Next() {
-				if k != nil && v != nil {
-					topoheight, _ := strconv.ParseInt(string(k), 10, 64)
-					heights = append(heights, topoheight)
-					var variables []*structures.SCIDVariable
-					_ = json.Unmarshal(v, &variables)
-					results[topoheight] = variables
-				} else {
-					break
+		c := b.Cursor()
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			var currdetails []*structures.BurnTXParse
+			_ = json.Unmarshal(v, &currdetails)
+			for _, cv := range currdetails {
+				if cv.Height >= minHeight && cv.Height <= maxHeight {
+					burnTxs = append(burnTxs, cv)
 				}
 			}
 		}
@@ -545,471 +796,339 @@ func (bbs *BboltStore) GetSCIDVariableDetailsAtTopoheight(scid string, topoheigh
 		return
 	})
 
-	if results != nil {
-		// Sort heights so most recent is index 0 [if preferred reverse, just swap > with <]
-		sort.SliceStable(heights, func(i, j int) bool {
-			return heights[i] < heights[j]
-		})
+	sortBurnTxs(burnTxs)
 
-		vs2k := make(map[interface{}]interface{})
-		for _, v := range heights {
-			if v > topoheight {
-				break
+	return
+}
+
+// Builds the height and scid indexes of the burn txs stored before they were kept, and keeps the fees of each burn tx on its first payload only. Only runs once, returns the number of burn txs gone through
+func (bbs *BboltStore) IndexBurnTxs() (indexed int, err error) {
+	err = bbs.update(func(tx *bolt.Tx) (err error) {
+		stb, err := tx.CreateBucketIfNotExists([]byte("stats"))
+		if err != nil {
+			return fmt.Errorf("bucket: %s", err)
+		}
+		if stb.Get([]byte(burn_indexed_key)) != nil {
+			return
+		}
+
+		b := tx.Bucket([]byte("burntxs"))
+		if b != nil {
+			var burnTxs [][]*structures.BurnTXParse
+			c := b.Cursor()
+			for k, v := c.First(); k != nil; k, v = c.Next() {
+				var currdetails []*structures.BurnTXParse
+				_ = json.Unmarshal(v, &currdetails)
+				if len(currdetails) > 0 {
+					burnTxs = append(burnTxs, currdetails)
+				}
 			}
-			for _, vs := range results[v] {
-				switch ckey := vs.Key.(type) {
-				case float64:
-					switch cval := vs.Value.(type) {
-					case float64:
-						vs2k[uint64(ckey)] = uint64(cval)
-					case uint64:
-						vs2k[uint64(ckey)] = cval
-					case string:
-						vs2k[uint64(ckey)] = cval
-					default:
-						if cval != nil {
-							logger.Errorf("[GetSCIDVariableDetailsAtTopoheight] Value '%v' does not match string, uint64 or float64.", cval)
-						} else {
-							vs2k[uint64(ckey)] = cval
-						}
+
+			hb, err := tx.CreateBucketIfNotExists([]byte("burnheights"))
+			if err != nil {
+				return fmt.Errorf("bucket: %s", err)
+			}
+			for _, currdetails := range burnTxs {
+				txid := currdetails[0].Txid
+				hkey := []byte(strconv.FormatInt(currdetails[0].Height, 10))
+				if txids, added := addIndexKey(hb.Get(hkey), txid); added {
+					if err = hb.Put(hkey, txids); err != nil {
+						return err
 					}
-				case uint64:
-					switch cval := vs.Value.(type) {
-					case float64:
-						vs2k[ckey] = uint64(cval)
-					case uint64:
-						vs2k[ckey] = cval
-					case string:
-						vs2k[ckey] = cval
-					default:
-						if cval != nil {
-							logger.Errorf("[GetSCIDVariableDetailsAtTopoheight] Value '%v' does not match string, uint64 or float64.", cval)
-						} else {
-							vs2k[ckey] = cval
-						}
+				}
+
+				for i, cv := range currdetails {
+					if i > 0 {
+						cv.Fees = 0
 					}
-				case string:
-					switch cval := vs.Value.(type) {
-					case float64:
-						vs2k[ckey] = uint64(cval)
-					case uint64:
-						vs2k[ckey] = cval
-					case string:
-						vs2k[ckey] = cval
-					default:
-						if cval != nil {
-							logger.Errorf("[GetSCIDVariableDetailsAtTopoheight] Value '%v' does not match string, uint64 or float64.", cval)
-						} else {
-							vs2k[ckey] = cval
-						}
+					sb, err := tx.CreateBucketIfNotExists([]byte(cv.Scid + "burns"))
+					if err != nil {
+						return fmt.Errorf("bucket: %s", err)
 					}
-				default:
-					if ckey != nil {
-						logger.Errorf("[GetSCIDVariableDetailsAtTopoheight] Key '%v' does not match string, uint64 or float64.", ckey)
+					if err = sb.Put([]byte(txid), hkey); err != nil {
+						return err
 					}
 				}
+				if len(currdetails) > 1 {
+					v, err := json.Marshal(currdetails)
+					if err != nil {
+						return fmt.Errorf("[BBolt] could not marshal burnTxs info: %v", err)
+					}
+					if err = b.Put([]byte(txid), v); err != nil {
+						return err
+					}
+				}
+				indexed++
 			}
 		}
 
-		for k, v := range vs2k {
-			// If value is nil, no reason to add.
-			if v == nil || (reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil()) || k == nil || (reflect.ValueOf(k).Kind() == reflect.Ptr && reflect.ValueOf(k).IsNil()) {
-				//logger.Debugf("[GetSCIDVariableDetailsAtTopoheight] Value '%v' or Key '%v' is nil. Continuing.", fmt.Sprintf("%v", v), fmt.Sprintf("%v", k))
-				continue
-			}
-			co := &structures.SCIDVariable{}
+		return stb.Put([]byte(burn_indexed_key), []byte("1"))
+	})
 
-			switch ckey := k.(type) {
-			case float64:
-				switch cval := v.(type) {
-				case float64:
-					co.Key = uint64(ckey)
-					co.Value = uint64(cval)
-				case uint64:
-					co.Key = uint64(ckey)
-					co.Value = cval
-				case string:
-					co.Key = uint64(ckey)
-					co.Value = cval
-				default:
-					logger.Errorf("[GetSCIDVariableDetailsAtTopoheight] Value '%v' or Key '%v' does not match string, uint64 or float64.", fmt.Sprintf("%v", cval), fmt.Sprintf("%v", uint64(ckey)))
-					continue
-				}
-			case uint64:
-				switch cval := v.(type) {
-				case float64:
-					co.Key = ckey
-					co.Value = uint64(cval)
-				case uint64:
-					co.Key = ckey
-					co.Value = cval
-				case string:
-					co.Key = ckey
-					co.Value = cval
-				default:
-					logger.Errorf("[GetSCIDVariableDetailsAtTopoheight] Value '%v' or Key '%v' does not match string, uint64 or float64.", fmt.Sprintf("%v", cval), fmt.Sprintf("%v", ckey))
-					continue
-				}
-			case string:
-				switch cval := v.(type) {
-				case float64:
-					co.Key = ckey
-					co.Value = uint64(cval)
-				case uint64:
-					co.Key = ckey
-					co.Value = cval
-				case string:
-					co.Key = ckey
-					co.Value = cval
-				default:
-					logger.Errorf("[GetSCIDVariableDetailsAtTopoheight] Value '%v' or Key '%v' does not match string, uint64 or float64.", fmt.Sprintf("%v", cval), fmt.Sprintf("%v", ckey))
-					continue
+	return
+}
+
+// Stores a name service registration/transfer as the current record of its name and appends it to the name's history
+func (bbs *BboltStore) StoreNameRecord(record *structures.NameRecord) (changes bool, err error) {
+	confBytes, err := json.Marshal(record)
+	if err != nil {
+		return changes, fmt.Errorf("[StoreNameRecord] could not marshal name record info: %v", err)
+	}
+
+	key := record.Name
+
+	err = bbs.update(func(tx *bolt.Tx) (err error) {
+		b, err := tx.CreateBucketIfNotExists([]byte("names"))
+		if err != nil {
+			return fmt.Errorf("bucket: %s", err)
+		}
+		hb, err := tx.CreateBucketIfNotExists([]byte("namehistory"))
+		if err != nil {
+			return fmt.Errorf("bucket: %s", err)
+		}
+
+		ab, err := tx.CreateBucketIfNotExists([]byte("nameaddrs"))
+		if err != nil {
+			return fmt.Errorf("bucket: %s", err)
+		}
+
+		var history []*structures.NameRecord
+		if currHistory := hb.Get([]byte(key)); currHistory != nil {
+			_ = json.Unmarshal(currHistory, &history)
+		}
+		history, exists := mergeNameHistory(history, record)
+		if exists {
+			// Return nil if already exists in array.
+			// Clause for this is in event we pop backwards in time and already have this data stored.
+			// Data stored from a false-chain is removed via RollbackToHeight() when a reorg is detected.
+			return nil
+		}
+
+		newHistory, err := json.Marshal(history)
+		if err != nil {
+			return fmt.Errorf("[bbolt] could not marshal name history info: %v", err)
+		}
+
+		// Current record is the latest of the history, a record stored out of order does not replace it. The names currently owned by each address are kept along with it
+		if history[len(history)-1] == record {
+			var prev *structures.NameRecord
+			if v := b.Get([]byte(key)); v != nil {
+				_ = json.Unmarshal(v, &prev)
+			}
+			if prev != nil && prev.Address != record.Address {
+				if err = bboltMoveNameAddress(ab, key, prev.Address, ""); err != nil {
+					return
 				}
 			}
+			if err = b.Put([]byte(key), confBytes); err != nil {
+				return
+			}
+			if err = bboltMoveNameAddress(ab, key, "", record.Address); err != nil {
+				return
+			}
+		}
+		err = hb.Put([]byte(key), newHistory)
+		changes = true
+		return
+	})
+
+	return
+}
+
+// Removes name from the names of address from, and adds it to those of address to. Either may be ""
+func bboltMoveNameAddress(ab *bolt.Bucket, name string, from string, to string) (err error) {
+	if from != "" {
+		if v := ab.Get([]byte(from)); v != nil {
+			if names := removeIndexKey(v, name); names != nil {
+				err = ab.Put([]byte(from), names)
+			} else {
+				err = ab.Delete([]byte(from))
+			}
+			if err != nil {
+				return
+			}
+		}
+	}
 
-			hVars = append(hVars, co)
+	if to != "" {
+		if names, added := addIndexKey(ab.Get([]byte(to)), name); added {
+			err = ab.Put([]byte(to), names)
 		}
 	}
 
 	return
 }
 
-// Gets SC variables at all topoheights
-func (bbs *BboltStore) GetAllSCIDVariableDetails(scid string) (hVars []*structures.SCIDVariable) {
-	results := make(map[int64][]*structures.SCIDVariable)
-	var heights []int64
-
-	bName := scid + "vars"
+// Returns the current record (owner) of a given name
+func (bbs *BboltStore) GetNameRecord(name string) (record *structures.NameRecord) {
+	bName := "names"
 
-	bbs.DB.View(func(tx *bolt.Tx) (err error) {
+	bbs.view(func(tx *bolt.Tx) (err error) {
 		b := tx.Bucket([]byte(bName))
 		if b != nil {
+			key := name
+			v := b.Get([]byte(key))
 
-			c := b.Cursor()
-
-			for k, v := c.First(); err == nil; k, v = c.Next() {
-				if k != nil && v != nil {
-					topoheight, _ := strconv.ParseInt(string(k), 10, 64)
-					heights = append(heights, topoheight)
-					var variables []*structures.SCIDVariable
-					_ = json.Unmarshal(v, &variables)
-					results[topoheight] = variables
-				} else {
-					break
-				}
+			if v != nil {
+				_ = json.Unmarshal(v, &record)
 			}
 		}
 
 		return
 	})
 
-	if results != nil {
-		// Sort heights so most recent is index 0 [if preferred reverse, just swap > with <]
-		sort.SliceStable(heights, func(i, j int) bool {
-			return heights[i] < heights[j]
-		})
+	return
+}
 
-		vs2k := make(map[interface{}]interface{})
-		for _, v := range heights {
-			for _, vs := range results[v] {
-				switch ckey := vs.Key.(type) {
-				case float64:
-					switch cval := vs.Value.(type) {
-					case float64:
-						vs2k[uint64(ckey)] = uint64(cval)
-					case uint64:
-						vs2k[uint64(ckey)] = cval
-					case string:
-						vs2k[uint64(ckey)] = cval
-					default:
-						if cval != nil {
-							logger.Errorf("[GetAllSCIDVariableDetails] Value '%v' does not match string, uint64 or float64.", cval)
-						} else {
-							vs2k[uint64(ckey)] = cval
-						}
-					}
-				case uint64:
-					switch cval := vs.Value.(type) {
-					case float64:
-						vs2k[ckey] = uint64(cval)
-					case uint64:
-						vs2k[ckey] = cval
-					case string:
-						vs2k[ckey] = cval
-					default:
-						if cval != nil {
-							logger.Errorf("[GetAllSCIDVariableDetails] Value '%v' does not match string, uint64 or float64.", cval)
-						} else {
-							vs2k[ckey] = cval
-						}
-					}
-				case string:
-					switch cval := vs.Value.(type) {
-					case float64:
-						vs2k[ckey] = uint64(cval)
-					case uint64:
-						vs2k[ckey] = cval
-					case string:
-						vs2k[ckey] = cval
-					default:
-						if cval != nil {
-							logger.Errorf("[GetAllSCIDVariableDetails] Value '%v' does not match string, uint64 or float64.", cval)
-						} else {
-							vs2k[ckey] = cval
-						}
-					}
-				default:
-					if ckey != nil {
-						logger.Errorf("[GetAllSCIDVariableDetails] Key '%v' does not match string, uint64 or float64.", ckey)
-					}
-				}
-			}
+// Returns the current records of the names owned by a given address (reverse lookup)
+func (bbs *BboltStore) GetNamesByAddress(address string) (records []*structures.NameRecord) {
+	bbs.view(func(tx *bolt.Tx) (err error) {
+		b := tx.Bucket([]byte("names"))
+		ab := tx.Bucket([]byte("nameaddrs"))
+		if b == nil || ab == nil {
+			return
 		}
 
-		for k, v := range vs2k {
-			// If value is nil, no reason to add.
-			if v == nil || (reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil()) || k == nil || (reflect.ValueOf(k).Kind() == reflect.Ptr && reflect.ValueOf(k).IsNil()) {
-				//logger.Debugf("[GetAllSCIDVariableDetails] Value '%v' or Key '%v' is nil. Continuing.", fmt.Sprintf("%v", v), fmt.Sprintf("%v", k))
-				continue
+		var names []string
+		if v := ab.Get([]byte(address)); v != nil {
+			_ = json.Unmarshal(v, &names)
+		}
+		for _, name := range names {
+			var record *structures.NameRecord
+			if v := b.Get([]byte(name)); v != nil {
+				_ = json.Unmarshal(v, &record)
+			}
+			if record != nil && record.Address == address {
+				records = append(records, record)
 			}
-			co := &structures.SCIDVariable{}
+		}
 
-			switch ckey := k.(type) {
-			case float64:
-				switch cval := v.(type) {
-				case float64:
-					co.Key = uint64(ckey)
-					co.Value = uint64(cval)
-				case uint64:
-					co.Key = uint64(ckey)
-					co.Value = cval
-				case string:
-					co.Key = uint64(ckey)
-					co.Value = cval
-				default:
-					logger.Errorf("[GetAllSCIDVariableDetails] Value '%v' or Key '%v' is does not match string, uint64 or float64.", fmt.Sprintf("%v", cval), fmt.Sprintf("%v", uint64(ckey)))
-					continue
-				}
-			case uint64:
-				switch cval := v.(type) {
-				case float64:
-					co.Key = ckey
-					co.Value = uint64(cval)
-				case uint64:
-					co.Key = ckey
-					co.Value = cval
-				case string:
-					co.Key = ckey
-					co.Value = cval
-				default:
-					logger.Errorf("[GetAllSCIDVariableDetails] Value '%v' or Key '%v' does not match string, uint64 or float64.", fmt.Sprintf("%v", cval), fmt.Sprintf("%v", ckey))
-					continue
-				}
-			case string:
-				switch cval := v.(type) {
-				case float64:
-					co.Key = ckey
-					co.Value = uint64(cval)
-				case uint64:
-					co.Key = ckey
-					co.Value = cval
-				case string:
-					co.Key = ckey
-					co.Value = cval
-				default:
-					logger.Errorf("[GetAllSCIDVariableDetails] Value '%v' or Key '%v' does not match string, uint64 or float64.", fmt.Sprintf("%v", cval), fmt.Sprintf("%v", ckey))
-					continue
+		return
+	})
+
+	sortNameRecords(records)
+
+	return
+}
+
+// Indexes the names currently owned by each address from the current name records stored before they were kept. Only runs once, returns the number of names gone through
+func (bbs *BboltStore) IndexNameAddresses() (indexed int, err error) {
+	err = bbs.update(func(tx *bolt.Tx) (err error) {
+		stb, err := tx.CreateBucketIfNotExists([]byte("stats"))
+		if err != nil {
+			return fmt.Errorf("bucket: %s", err)
+		}
+		if stb.Get([]byte(name_addrs_indexed_key)) != nil {
+			return
+		}
+
+		if b := tx.Bucket([]byte("names")); b != nil {
+			var records []*structures.NameRecord
+			c := b.Cursor()
+			for k, v := c.First(); k != nil; k, v = c.Next() {
+				var record *structures.NameRecord
+				_ = json.Unmarshal(v, &record)
+				if record != nil {
+					records = append(records, record)
 				}
 			}
 
-			hVars = append(hVars, co)
+			ab, err := tx.CreateBucketIfNotExists([]byte("nameaddrs"))
+			if err != nil {
+				return fmt.Errorf("bucket: %s", err)
+			}
+			for _, record := range records {
+				if err = bboltMoveNameAddress(ab, record.Name, "", record.Address); err != nil {
+					return err
+				}
+				indexed++
+			}
 		}
-	}
+
+		return stb.Put([]byte(name_addrs_indexed_key), []byte("1"))
+	})
 
 	return
 }
 
-// Gets SC variable keys at given topoheight who's value equates to a given interface{} (string/uint64)
-func (bbs *BboltStore) GetSCIDKeysByValue(scid string, val interface{}, height int64, rmax bool) (keysstring []string, keysuint64 []uint64) {
-	scidInteractionHeights := bbs.GetSCIDInteractionHeight(scid)
+// Returns the registration and transfer history of a given name, in height order
+func (bbs *BboltStore) GetNameHistory(name string) (records []*structures.NameRecord) {
+	return bbs.getNameHistory(func(record *structures.NameRecord) bool {
+		return record.Name == name
+	})
+}
 
-	interactionHeight := bbs.GetInteractionIndex(height, scidInteractionHeights, rmax)
+// Returns all name registrations and transfers between the given heights (inclusive)
+func (bbs *BboltStore) GetNameHistoryByHeightRange(minHeight int64, maxHeight int64) (records []*structures.NameRecord) {
+	return bbs.getNameHistory(func(record *structures.NameRecord) bool {
+		return record.Height >= minHeight && record.Height <= maxHeight
+	})
+}
 
-	// TODO: If there's no interaction height, do we go get scvars against daemon and store? Or do we just ignore and return nil
-	variables := bbs.GetSCIDVariableDetailsAtTopoheight(scid, interactionHeight)
+// Returns all name history records that match, in height order
+func (bbs *BboltStore) getNameHistory(match func(record *structures.NameRecord) bool) (records []*structures.NameRecord) {
+	bName := "namehistory"
 
-	// Switch against the value passed. If it's a uint64 or string
-	switch inpvar := val.(type) {
-	case uint64:
-		for _, v := range variables {
-			switch cval := v.Value.(type) {
-			case float64:
-				if inpvar == uint64(cval) {
-					switch ckey := v.Key.(type) {
-					case float64:
-						keysuint64 = append(keysuint64, uint64(ckey))
-					case uint64:
-						keysuint64 = append(keysuint64, ckey)
-					default:
-						// default just store as string. Keys should only ever be strings or uint64, however, but assume default to string
-						keysstring = append(keysstring, v.Key.(string))
-					}
-				}
-			case uint64:
-				if inpvar == cval {
-					switch ckey := v.Key.(type) {
-					case float64:
-						keysuint64 = append(keysuint64, uint64(ckey))
-					case uint64:
-						keysuint64 = append(keysuint64, ckey)
-					default:
-						// default just store as string. Keys should only ever be strings or uint64, however, but assume default to string
-						keysstring = append(keysstring, v.Key.(string))
-					}
-				}
-			default:
-				// Nothing - expect only string/uint64 for value types
-			}
-		}
-	case string:
-		for _, v := range variables {
-			switch cval := v.Value.(type) {
-			case string:
-				if inpvar == cval {
-					switch ckey := v.Key.(type) {
-					case float64:
-						keysuint64 = append(keysuint64, uint64(ckey))
-					case uint64:
-						keysuint64 = append(keysuint64, ckey)
-					default:
-						// default just store as string. Keys should only ever be strings or uint64, however, but assume default to string
-						keysstring = append(keysstring, v.Key.(string))
+	bbs.view(func(tx *bolt.Tx) (err error) {
+		b := tx.Bucket([]byte(bName))
+		if b != nil {
+			c := b.Cursor()
+
+			for k, v := c.First(); k != nil; k, v = c.Next() {
+				var history []*structures.NameRecord
+				_ = json.Unmarshal(v, &history)
+				for _, record := range history {
+					if match(record) {
+						records = append(records, record)
 					}
 				}
-			default:
-				// Nothing - expect only string/uint64 for value types
 			}
 		}
-	default:
-		// Nothing - expect only string/uint64 for value types
-	}
-
-	return keysstring, keysuint64
-}
 
-// Gets SC values by key at given topoheight who's key equates to a given interface{} (string/uint64)
-func (bbs *BboltStore) GetSCIDValuesByKey(scid string, key interface{}, height int64, rmax bool) (valuesstring []string, valuesuint64 []uint64) {
-	scidInteractionHeights := bbs.GetSCIDInteractionHeight(scid)
+		return
+	})
 
-	interactionHeight := bbs.GetInteractionIndex(height, scidInteractionHeights, rmax)
+	sortNameRecords(records)
 
-	// TODO: If there's no interaction height, do we go get scvars against daemon and store? Or do we just ignore and return nil
-	variables := bbs.GetSCIDVariableDetailsAtTopoheight(scid, interactionHeight)
+	return
+}
 
-	// Switch against the value passed. If it's a uint64 or string
-	switch inpvar := key.(type) {
-	case uint64:
-		for _, v := range variables {
-			switch ckey := v.Key.(type) {
-			case float64:
-				if inpvar == uint64(ckey) {
-					switch cval := v.Value.(type) {
-					case float64:
-						valuesuint64 = append(valuesuint64, uint64(cval))
-					case uint64:
-						valuesuint64 = append(valuesuint64, cval)
-					default:
-						// default just store as string. Keys should only ever be strings or uint64, however, but assume default to string
-						valuesstring = append(valuesstring, v.Value.(string))
-					}
-				}
-			case uint64:
-				if inpvar == ckey {
-					switch cval := v.Value.(type) {
-					case float64:
-						valuesuint64 = append(valuesuint64, uint64(cval))
-					case uint64:
-						valuesuint64 = append(valuesuint64, cval)
-					default:
-						// default just store as string. Keys should only ever be strings or uint64, however, but assume default to string
-						valuesstring = append(valuesstring, v.Value.(string))
-					}
-				}
-			default:
-				// Nothing - expect only string/uint64 for value types
-			}
-		}
-	case string:
-		for _, v := range variables {
-			switch ckey := v.Key.(type) {
-			case string:
-				if inpvar == ckey {
-					switch cval := v.Value.(type) {
-					case float64:
-						valuesuint64 = append(valuesuint64, uint64(cval))
-					case uint64:
-						valuesuint64 = append(valuesuint64, cval)
-					default:
-						// default just store as string. Values should only ever be strings or uint64, however, but assume default to string
-						valuesstring = append(valuesstring, v.Value.(string))
-					}
-				}
-			default:
-				// Nothing - expect only string/uint64 for value types
-			}
-		}
-	default:
-		// Nothing - expect only string/uint64 for value types
+// Stores the registry entry of a given asset scid, replacing any previous entry. Previous entries are kept in the asset's history for rolling back reorgs
+func (bbs *BboltStore) StoreSCAsset(asset *structures.SCAsset) (changes bool, err error) {
+	confBytes, err := json.Marshal(asset)
+	if err != nil {
+		return changes, fmt.Errorf("[StoreSCAsset] could not marshal asset info: %v", err)
 	}
 
-	return valuesstring, valuesuint64
-}
-
-// Stores SC interaction height and detail - height invoked upon and type (scinstall/scinvoke). This is separate tree & k/v since we can query it for other things at less data retrieval
-func (bbs *BboltStore) StoreSCIDInteractionHeight(scid string, height int64) (changes bool, err error) {
-	var currSCIDInteractionHeight []byte
-	var interactionHeight []int64
-	var newInteractionHeight []byte
-	bName := scid + "heights"
-	key := scid
+	bName := "assets"
 
-	err = bbs.DB.View(func(tx *bolt.Tx) (err error) {
-		b := tx.Bucket([]byte(bName))
-		if b != nil {
-			currSCIDInteractionHeight = b.Get([]byte(key))
-		}
-		return
-	})
+	key := asset.Scid
 
-	err = bbs.DB.Update(func(tx *bolt.Tx) (err error) {
+	err = bbs.update(func(tx *bolt.Tx) (err error) {
 		b, err := tx.CreateBucketIfNotExists([]byte(bName))
 		if err != nil {
 			return fmt.Errorf("bucket: %s", err)
 		}
 
-		if currSCIDInteractionHeight == nil {
-			interactionHeight = append(interactionHeight, height)
-		} else {
-			// Retrieve value and conovert to SCIDInteractionHeight, so that you can manipulate and update db
-			_ = json.Unmarshal(currSCIDInteractionHeight, &interactionHeight)
-
-			for _, v := range interactionHeight {
-				if v == height {
-					// Return nil if already exists in array.
-					// Clause for this is in event we pop backwards in time and already have this data stored.
-					// TODO: What if interaction happened on false-chain and pop to retain correct chain. Bad data may be stored here still, as it isn't removed. Need fix for this in future.
-					return
-				}
-			}
+		hb, err := tx.CreateBucketIfNotExists([]byte("assethistory"))
+		if err != nil {
+			return fmt.Errorf("bucket: %s", err)
+		}
 
-			interactionHeight = append(interactionHeight, height)
+		var history []*structures.SCAsset
+		if v := hb.Get([]byte(key)); v != nil {
+			_ = json.Unmarshal(v, &history)
 		}
-		newInteractionHeight, err = json.Marshal(interactionHeight)
+		newHistory, err := json.Marshal(mergeAssetHistory(history, asset))
 		if err != nil {
-			return fmt.Errorf("[BBolt] could not marshal interactionHeight info: %v", err)
+			return fmt.Errorf("[StoreSCAsset] could not marshal asset history info: %v", err)
 		}
 
-		err = b.Put([]byte(key), newInteractionHeight)
+		err = b.Put([]byte(key), confBytes)
+		if err != nil {
+			return
+		}
+		err = hb.Put([]byte(key), newHistory)
 		changes = true
 		return
 	})
@@ -1017,276 +1136,2012 @@ func (bbs *BboltStore) StoreSCIDInteractionHeight(scid string, height int64) (ch
 	return
 }
 
-// Gets SC interaction height and detail by a given SCID
-func (bbs *BboltStore) GetSCIDInteractionHeight(scid string) (scidinteractions []int64) {
-	bName := scid + "heights"
+// Returns the registry entry of a given asset scid
+func (bbs *BboltStore) GetSCAsset(scid string) (asset *structures.SCAsset) {
+	bName := "assets"
 
-	bbs.DB.View(func(tx *bolt.Tx) (err error) {
+	bbs.view(func(tx *bolt.Tx) (err error) {
 		b := tx.Bucket([]byte(bName))
 		if b != nil {
 			key := scid
 			v := b.Get([]byte(key))
 
 			if v != nil {
-				_ = json.Unmarshal(v, &scidinteractions)
+				_ = json.Unmarshal(v, &asset)
 			}
 		}
+
 		return
 	})
 
 	return
 }
 
-func (bbs *BboltStore) GetInteractionIndex(topoheight int64, heights []int64, rmax bool) (height int64) {
-	if len(heights) <= 0 {
-		return height
-	}
+// Returns all assets of a given collection scid
+func (bbs *BboltStore) GetSCAssetsByCollection(collection string) (assets []*structures.SCAsset) {
+	return bbs.getSCAssets(func(asset *structures.SCAsset) bool {
+		return asset.Collection == collection
+	})
+}
 
-	// Sort heights so most recent is index 0 [if preferred reverse, just swap > with <]
-	sort.SliceStable(heights, func(i, j int) bool {
-		return heights[i] > heights[j]
+// Returns all assets owned by a given address
+func (bbs *BboltStore) GetSCAssetsByOwner(owner string) (assets []*structures.SCAsset) {
+	return bbs.getSCAssets(func(asset *structures.SCAsset) bool {
+		return asset.Owner == owner
 	})
+}
 
-	if topoheight > heights[0] || rmax {
-		return heights[0]
-	}
+// Returns all assets of a given standard
+func (bbs *BboltStore) GetSCAssetsByStandard(standard string) (assets []*structures.SCAsset) {
+	return bbs.getSCAssets(func(asset *structures.SCAsset) bool {
+		return asset.Standard == standard
+	})
+}
 
-	for i := 1; i < len(heights); i++ {
-		if heights[i] < topoheight {
-			return heights[i]
-		} else if heights[i] == topoheight {
-			return heights[i]
+// Returns all assets that match, in height order
+func (bbs *BboltStore) getSCAssets(match func(asset *structures.SCAsset) bool) (assets []*structures.SCAsset) {
+	bName := "assets"
+
+	bbs.view(func(tx *bolt.Tx) (err error) {
+		b := tx.Bucket([]byte(bName))
+		if b != nil {
+			c := b.Cursor()
+
+			for k, v := c.First(); k != nil; k, v = c.Next() {
+				var asset *structures.SCAsset
+				_ = json.Unmarshal(v, &asset)
+				if asset != nil && match(asset) {
+					assets = append(assets, asset)
+				}
+			}
 		}
+
+		return
+	})
+
+	sortSCAssets(assets)
+
+	return
+}
+
+// Stores all scinvoke details of a given scid
+func (bbs *BboltStore) StoreInvokeDetails(scid string, signer string, entrypoint string, topoheight int64, invokedetails *structures.SCTXParse) (changes bool, err error) {
+	confBytes, err := json.Marshal(invokedetails)
+	if err != nil {
+		return changes, fmt.Errorf("[StoreInvokeDetails] could not marshal invokedetails info: %v", err)
 	}
 
-	return height
+	bName := scid
+
+	key := invokeDetailsKey(signer, invokedetails.Txid, topoheight, entrypoint)
+
+	err = bbs.update(func(tx *bolt.Tx) (err error) {
+		b, err := tx.CreateBucketIfNotExists([]byte(bName))
+		if err != nil {
+			return fmt.Errorf("bucket: %s", err)
+		}
+
+		err = b.Put([]byte(key), confBytes)
+		changes = true
+		return
+	})
+
+	return
 }
 
-// Stores any SCIDs that were attempted to be deployed but not correct - log scid/fees burnt attempting it.
-func (bbs *BboltStore) StoreInvalidSCIDDeploys(scid string, fee uint64) (changes bool, err error) {
-	var currSCIDInteractionHeight []byte
+// Returns all scinvoke calls from a given scid
+func (bbs *BboltStore) GetAllSCIDInvokeDetails(scid string) (invokedetails []*structures.SCTXParse) {
+	bName := scid
 
-	currInvalidSCIDs := make(map[string]uint64)
-	var newInvalidSCIDs []byte
+	bbs.view(func(tx *bolt.Tx) (err error) {
+		b := tx.Bucket([]byte(bName))
+		if b != nil {
+
+			c := b.Cursor()
+
+			for _, v := c.First(); err == nil; _, v = c.Next() {
+				if v != nil {
+					var currdetails *structures.SCTXParse
+					_ = json.Unmarshal(v, &currdetails)
+					invokedetails = append(invokedetails, currdetails)
+				} else {
+					break
+				}
+			}
+		}
+
+		return
+	})
+
+	// Sort heights so most recent is index 0 [if preferred reverse, just swap > with <]
+	sort.SliceStable(invokedetails, func(i, j int) bool {
+		return invokedetails[i].Height < invokedetails[j].Height
+	})
+
+	return invokedetails
+}
+
+// Retruns all scinvoke calls from a given scid that match a given entrypoint
+func (bbs *BboltStore) GetAllSCIDInvokeDetailsByEntrypoint(scid string, entrypoint string) (invokedetails []*structures.SCTXParse) {
+	bName := scid
+
+	bbs.view(func(tx *bolt.Tx) (err error) {
+		b := tx.Bucket([]byte(bName))
+		if b != nil {
+
+			c := b.Cursor()
+
+			for _, v := c.First(); err == nil; _, v = c.Next() {
+				if v != nil {
+					var currdetails *structures.SCTXParse
+					_ = json.Unmarshal(v, &currdetails)
+					if currdetails.Entrypoint == entrypoint {
+						invokedetails = append(invokedetails, currdetails)
+					}
+				} else {
+					break
+				}
+			}
+		}
+
+		return
+	})
+
+	// Sort heights so most recent is index 0 [if preferred reverse, just swap > with <]
+	sort.SliceStable(invokedetails, func(i, j int) bool {
+		return invokedetails[i].Height < invokedetails[j].Height
+	})
+
+	return invokedetails
+}
+
+// Returns all scinvoke calls from a given scid whose arg name matches value (compared as a string). Uses the scid's secondary index on name if one is kept, otherwise all invokes of the scid are checked
+func (bbs *BboltStore) GetAllSCIDInvokeDetailsByArg(scid string, name string, value string) (invokedetails []*structures.SCTXParse) {
+	bName := scid
+
+	bbs.view(func(tx *bolt.Tx) (err error) {
+		b := tx.Bucket([]byte(bName))
+		if b == nil {
+			return
+		}
+
+		var indexed []string
+		ab := tx.Bucket([]byte(scid + "args"))
+		if ab != nil {
+			if v := ab.Get([]byte(indexed_args_key)); v != nil {
+				_ = json.Unmarshal(v, &indexed)
+			}
+		}
+
+		if idExist(indexed, name) {
+			var entries []*argIndexEntry
+			if v := ab.Get([]byte(argIndexKey(name, value))); v != nil {
+				_ = json.Unmarshal(v, &entries)
+			}
+			for _, e := range entries {
+				v := b.Get([]byte(e.Key))
+				if v == nil {
+					continue
+				}
+				var currdetails *structures.SCTXParse
+				_ = json.Unmarshal(v, &currdetails)
+				if currdetails != nil && argMatch(currdetails, name, value) {
+					invokedetails = append(invokedetails, currdetails)
+				}
+			}
+		} else {
+			c := b.Cursor()
+			for k, v := c.First(); k != nil; k, v = c.Next() {
+				var currdetails *structures.SCTXParse
+				_ = json.Unmarshal(v, &currdetails)
+				if currdetails != nil && argMatch(currdetails, name, value) {
+					invokedetails = append(invokedetails, currdetails)
+				}
+			}
+		}
+
+		return
+	})
+
+	sort.SliceStable(invokedetails, func(i, j int) bool {
+		return invokedetails[i].Height < invokedetails[j].Height
+	})
+
+	return
+}
+
+// Returns all scinvoke calls from a given scid that match a given signer
+func (bbs *BboltStore) GetAllSCIDInvokeDetailsBySigner(scid string, signerPart string) (invokedetails []*structures.SCTXParse) {
+	bName := scid
+
+	bbs.view(func(tx *bolt.Tx) (err error) {
+		b := tx.Bucket([]byte(bName))
+		if b != nil {
+
+			c := b.Cursor()
+
+			for _, v := c.First(); err == nil; _, v = c.Next() {
+				if v != nil {
+					var currdetails *structures.SCTXParse
+					_ = json.Unmarshal(v, &currdetails)
+					split := strings.Split(currdetails.Sender, signerPart)
+					if len(split) > 1 {
+						invokedetails = append(invokedetails, currdetails)
+					}
+				} else {
+					break
+				}
+			}
+		}
+
+		return
+	})
+
+	// Sort heights so most recent is index 0 [if preferred reverse, just swap > with <]
+	sort.SliceStable(invokedetails, func(i, j int) bool {
+		return invokedetails[i].Height < invokedetails[j].Height
+	})
+
+	return invokedetails
+}
+
+// Stores simple getinfo polling from the daemon
+func (bbs *BboltStore) StoreGetInfoDetails(getinfo *structures.GetInfo) (changes bool, err error) {
+	confBytes, err := json.Marshal(getinfo)
+	if err != nil {
+		return changes, fmt.Errorf("[StoreGetInfoDetails] could not marshal getinfo info: %v", err)
+	}
+
+	bName := "getinfo"
+
+	key := "getinfo"
+
+	err = bbs.update(func(tx *bolt.Tx) (err error) {
+		b, err := tx.CreateBucketIfNotExists([]byte(bName))
+		if err != nil {
+			return fmt.Errorf("bucket: %s", err)
+		}
+
+		err = b.Put([]byte(key), confBytes)
+		changes = true
+		return
+	})
+
+	return
+}
+
+// Returns simple getinfo polling from the daemon
+func (bbs *BboltStore) GetGetInfoDetails() (getinfo *structures.GetInfo) {
+	var v []byte
+	bName := "getinfo"
+
+	bbs.view(func(tx *bolt.Tx) (err error) {
+		b := tx.Bucket([]byte(bName))
+		if b != nil {
+			key := "getinfo"
+			v = b.Get([]byte(key))
+		}
+
+		return
+	})
+
+	if v != nil {
+		_ = json.Unmarshal(v, &getinfo)
+		return
+	}
+
+	return
+}
+
+// Stores SC variables at a given topoheight (called on any new scdeploy or scinvoke actions). variables is the full variable set at topoheight, only the changes from the variables stored below topoheight are kept (with a keyframe of the full set every var_keyframe_interval heights)
+func (bbs *BboltStore) StoreSCIDVariableDetails(scid string, variables []*structures.SCIDVariable, topoheight int64) (changes bool, err error) {
+	bName := scid + "vars"
+
+	key := strconv.FormatInt(topoheight, 10)
+
+	err = bbs.update(func(tx *bolt.Tx) (err error) {
+		b, err := tx.CreateBucketIfNotExists([]byte(bName))
+		if err != nil {
+			return fmt.Errorf("bucket: %s", err)
+		}
+
+		entries := make(map[int64][]byte)
+		c := b.Cursor()
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			h, _ := strconv.ParseInt(string(k), 10, 64)
+			entries[h] = v
+		}
+
+		confBytes, err := encodeVarEntry(entries, variables, topoheight)
+		if err != nil {
+			return fmt.Errorf("[StoreSCIDVariableDetails] could not marshal variables info: %v", err)
+		}
+		if confBytes == nil {
+			return
+		}
+
+		err = b.Put([]byte(key), confBytes)
+		changes = true
+		return
+	})
+
+	return
+}
+
+// Stores the SC variables changed at each topoheight (deleted variables having a nil Value), as returned by GetSCIDVariableHistory. Replaces any variables already stored for scid at those heights
+func (bbs *BboltStore) StoreSCIDVariableHistory(scid string, history map[int64][]*structures.SCIDVariable) (changes bool, err error) {
+	entries, err := encodeVarHistory(history)
+	if err != nil {
+		return changes, fmt.Errorf("[StoreSCIDVariableHistory] could not marshal variables info: %v", err)
+	}
+
+	bName := scid + "vars"
+
+	err = bbs.update(func(tx *bolt.Tx) (err error) {
+		b, err := tx.CreateBucketIfNotExists([]byte(bName))
+		if err != nil {
+			return fmt.Errorf("bucket: %s", err)
+		}
+
+		for h, confBytes := range entries {
+			if err = b.Put([]byte(strconv.FormatInt(h, 10)), confBytes); err != nil {
+				return
+			}
+			changes = true
+		}
+		return
+	})
+
+	return
+}
+
+// Converts the variables stored for scid in the format prior to SCIDVariableFormat (full sets and diffs kept alike as arrays of variables) to keyframes and changes
+func (bbs *BboltStore) MigrateSCIDVariableDetails(scid string) (changes bool, err error) {
+	entries := bbs.getSCIDVarEntries(scid)
+	if !hasLegacyVarEntries(entries) {
+		return
+	}
+
+	return bbs.StoreSCIDVariableHistory(scid, decodeVarHistory(entries))
+}
+
+// Gets SC variables at a given topoheight
+func (bbs *BboltStore) GetSCIDVariableDetailsAtTopoheight(scid string, topoheight int64) (hVars []*structures.SCIDVariable) {
+	state, _, _ := buildVarState(bbs.getSCIDVarEntries(scid), topoheight)
+
+	return state.variables()
+}
+
+// Gets the SC variables changed at each stored topoheight (deleted variables having a nil Value), rather than the variable state at a height
+func (bbs *BboltStore) GetSCIDVariableHistory(scid string) map[int64][]*structures.SCIDVariable {
+	return decodeVarHistory(bbs.getSCIDVarEntries(scid))
+}
+
+// Gets SC variables at the latest stored topoheight
+func (bbs *BboltStore) GetAllSCIDVariableDetails(scid string) (hVars []*structures.SCIDVariable) {
+	state, _, _ := buildVarState(bbs.getSCIDVarEntries(scid), math.MaxInt64)
+
+	return state.variables()
+}
+
+// Returns the stored variable entries of a given scid by topoheight
+func (bbs *BboltStore) getSCIDVarEntries(scid string) (entries map[int64][]byte) {
+	entries = make(map[int64][]byte)
+
+	bName := scid + "vars"
+
+	bbs.view(func(tx *bolt.Tx) (err error) {
+		b := tx.Bucket([]byte(bName))
+		if b != nil {
+			c := b.Cursor()
+
+			for k, v := c.First(); k != nil; k, v = c.Next() {
+				topoheight, _ := strconv.ParseInt(string(k), 10, 64)
+				// Values are only valid within the tx
+				entries[topoheight] = append([]byte(nil), v...)
+			}
+		}
+
+		return
+	})
+
+	return
+}
+
+// Stores the DERO and asset balances of a given scid at a given topoheight
+func (bbs *BboltStore) StoreSCIDBalanceDetails(scid string, balances map[string]uint64, topoheight int64) (changes bool, err error) {
+	confBytes, err := json.Marshal(balances)
+	if err != nil {
+		return changes, fmt.Errorf("[StoreSCIDBalanceDetails] could not marshal balances info: %v", err)
+	}
+
+	bName := scid + "balances"
+
+	key := strconv.FormatInt(topoheight, 10)
+
+	err = bbs.update(func(tx *bolt.Tx) (err error) {
+		b, err := tx.CreateBucketIfNotExists([]byte(bName))
+		if err != nil {
+			return fmt.Errorf("bucket: %s", err)
+		}
+
+		err = b.Put([]byte(key), confBytes)
+		changes = true
+		return
+	})
+
+	return
+}
+
+// Returns the DERO and asset balances of a given scid as of a given topoheight (the most recent stored balances at or below it)
+func (bbs *BboltStore) GetSCIDBalanceDetailsAtTopoheight(scid string, topoheight int64) (balances map[string]uint64) {
+	for _, v := range bbs.GetAllSCIDBalanceDetails(scid) {
+		if v.Height > topoheight {
+			break
+		}
+		balances = v.Balances
+	}
+
+	return
+}
+
+// Returns all of the stored balances of a given scid, in height order
+func (bbs *BboltStore) GetAllSCIDBalanceDetails(scid string) (hBalances []*structures.SCIDBalances) {
+	bName := scid + "balances"
+
+	bbs.view(func(tx *bolt.Tx) (err error) {
+		b := tx.Bucket([]byte(bName))
+		if b != nil {
+			c := b.Cursor()
+
+			for k, v := c.First(); k != nil; k, v = c.Next() {
+				topoheight, perr := strconv.ParseInt(string(k), 10, 64)
+				if perr != nil {
+					continue
+				}
+				var balances map[string]uint64
+				_ = json.Unmarshal(v, &balances)
+				hBalances = append(hBalances, &structures.SCIDBalances{Height: topoheight, Balances: balances})
+			}
+		}
+
+		return
+	})
+
+	sortSCIDBalances(hBalances)
+
+	return
+}
+
+// Stores a version of the code of a given scid at the height it was installed or updated
+func (bbs *BboltStore) StoreSCIDCode(scid string, code *structures.SCIDCode) (changes bool, err error) {
+	confBytes, err := json.Marshal(code)
+	if err != nil {
+		return changes, fmt.Errorf("[StoreSCIDCode] could not marshal code info: %v", err)
+	}
+
+	bName := scid + "code"
+
+	key := strconv.FormatInt(code.Height, 10)
+
+	err = bbs.update(func(tx *bolt.Tx) (err error) {
+		b, err := tx.CreateBucketIfNotExists([]byte(bName))
+		if err != nil {
+			return fmt.Errorf("bucket: %s", err)
+		}
+
+		err = b.Put([]byte(key), confBytes)
+		changes = true
+		return
+	})
+
+	return
+}
+
+// Returns the code of a given scid as of a given topoheight (the most recent stored version at or below it)
+func (bbs *BboltStore) GetSCIDCodeAtTopoheight(scid string, topoheight int64) (code *structures.SCIDCode) {
+	for _, v := range bbs.GetAllSCIDCode(scid) {
+		if v.Height > topoheight {
+			break
+		}
+		code = v
+	}
+
+	return
+}
+
+// Returns all of the stored code versions of a given scid, in height order
+func (bbs *BboltStore) GetAllSCIDCode(scid string) (hCode []*structures.SCIDCode) {
+	bName := scid + "code"
+
+	bbs.view(func(tx *bolt.Tx) (err error) {
+		b := tx.Bucket([]byte(bName))
+		if b != nil {
+			c := b.Cursor()
+
+			for k, v := c.First(); k != nil; k, v = c.Next() {
+				var code *structures.SCIDCode
+				if err := json.Unmarshal(v, &code); err == nil && code != nil {
+					hCode = append(hCode, code)
+				}
+			}
+		}
+
+		return
+	})
+
+	sortSCIDCode(hCode)
+
+	return
+}
+
+// Gets SC variable keys at given topoheight who's value equates to a given interface{} (string/uint64)
+func (bbs *BboltStore) GetSCIDKeysByValue(scid string, val interface{}, height int64, rmax bool) (keysstring []string, keysuint64 []uint64) {
+	scidInteractionHeights := bbs.GetSCIDInteractionHeight(scid)
+
+	interactionHeight := bbs.GetInteractionIndex(height, scidInteractionHeights, rmax)
+
+	// TODO: If there's no interaction height, do we go get scvars against daemon and store? Or do we just ignore and return nil
+	variables := bbs.GetSCIDVariableDetailsAtTopoheight(scid, interactionHeight)
+
+	// Switch against the value passed. If it's a uint64 or string
+	switch inpvar := val.(type) {
+	case uint64:
+		for _, v := range variables {
+			switch cval := v.Value.(type) {
+			case float64:
+				if inpvar == uint64(cval) {
+					switch ckey := v.Key.(type) {
+					case float64:
+						keysuint64 = append(keysuint64, uint64(ckey))
+					case uint64:
+						keysuint64 = append(keysuint64, ckey)
+					default:
+						// default just store as string. Keys should only ever be strings or uint64, however, but assume default to string
+						keysstring = append(keysstring, v.Key.(string))
+					}
+				}
+			case uint64:
+				if inpvar == cval {
+					switch ckey := v.Key.(type) {
+					case float64:
+						keysuint64 = append(keysuint64, uint64(ckey))
+					case uint64:
+						keysuint64 = append(keysuint64, ckey)
+					default:
+						// default just store as string. Keys should only ever be strings or uint64, however, but assume default to string
+						keysstring = append(keysstring, v.Key.(string))
+					}
+				}
+			default:
+				// Nothing - expect only string/uint64 for value types
+			}
+		}
+	case string:
+		for _, v := range variables {
+			switch cval := v.Value.(type) {
+			case string:
+				if inpvar == cval {
+					switch ckey := v.Key.(type) {
+					case float64:
+						keysuint64 = append(keysuint64, uint64(ckey))
+					case uint64:
+						keysuint64 = append(keysuint64, ckey)
+					default:
+						// default just store as string. Keys should only ever be strings or uint64, however, but assume default to string
+						keysstring = append(keysstring, v.Key.(string))
+					}
+				}
+			default:
+				// Nothing - expect only string/uint64 for value types
+			}
+		}
+	default:
+		// Nothing - expect only string/uint64 for value types
+	}
+
+	return keysstring, keysuint64
+}
+
+// Gets SC values by key at given topoheight who's key equates to a given interface{} (string/uint64)
+func (bbs *BboltStore) GetSCIDValuesByKey(scid string, key interface{}, height int64, rmax bool) (valuesstring []string, valuesuint64 []uint64) {
+	scidInteractionHeights := bbs.GetSCIDInteractionHeight(scid)
+
+	interactionHeight := bbs.GetInteractionIndex(height, scidInteractionHeights, rmax)
+
+	// TODO: If there's no interaction height, do we go get scvars against daemon and store? Or do we just ignore and return nil
+	variables := bbs.GetSCIDVariableDetailsAtTopoheight(scid, interactionHeight)
+
+	// Switch against the value passed. If it's a uint64 or string
+	switch inpvar := key.(type) {
+	case uint64:
+		for _, v := range variables {
+			switch ckey := v.Key.(type) {
+			case float64:
+				if inpvar == uint64(ckey) {
+					switch cval := v.Value.(type) {
+					case float64:
+						valuesuint64 = append(valuesuint64, uint64(cval))
+					case uint64:
+						valuesuint64 = append(valuesuint64, cval)
+					default:
+						// default just store as string. Keys should only ever be strings or uint64, however, but assume default to string
+						valuesstring = append(valuesstring, v.Value.(string))
+					}
+				}
+			case uint64:
+				if inpvar == ckey {
+					switch cval := v.Value.(type) {
+					case float64:
+						valuesuint64 = append(valuesuint64, uint64(cval))
+					case uint64:
+						valuesuint64 = append(valuesuint64, cval)
+					default:
+						// default just store as string. Keys should only ever be strings or uint64, however, but assume default to string
+						valuesstring = append(valuesstring, v.Value.(string))
+					}
+				}
+			default:
+				// Nothing - expect only string/uint64 for value types
+			}
+		}
+	case string:
+		for _, v := range variables {
+			switch ckey := v.Key.(type) {
+			case string:
+				if inpvar == ckey {
+					switch cval := v.Value.(type) {
+					case float64:
+						valuesuint64 = append(valuesuint64, uint64(cval))
+					case uint64:
+						valuesuint64 = append(valuesuint64, cval)
+					default:
+						// default just store as string. Values should only ever be strings or uint64, however, but assume default to string
+						valuesstring = append(valuesstring, v.Value.(string))
+					}
+				}
+			default:
+				// Nothing - expect only string/uint64 for value types
+			}
+		}
+	default:
+		// Nothing - expect only string/uint64 for value types
+	}
+
+	return valuesstring, valuesuint64
+}
+
+// Stores SC interaction height and detail - height invoked upon and type (scinstall/scinvoke). This is separate tree & k/v since we can query it for other things at less data retrieval
+func (bbs *BboltStore) StoreSCIDInteractionHeight(scid string, height int64) (changes bool, err error) {
+	var currSCIDInteractionHeight []byte
+	var interactionHeight []int64
+	var newInteractionHeight []byte
+	bName := scid + "heights"
+	key := scid
+
+	err = bbs.view(func(tx *bolt.Tx) (err error) {
+		b := tx.Bucket([]byte(bName))
+		if b != nil {
+			currSCIDInteractionHeight = b.Get([]byte(key))
+		}
+		return
+	})
+
+	err = bbs.update(func(tx *bolt.Tx) (err error) {
+		b, err := tx.CreateBucketIfNotExists([]byte(bName))
+		if err != nil {
+			return fmt.Errorf("bucket: %s", err)
+		}
+
+		if currSCIDInteractionHeight == nil {
+			interactionHeight = append(interactionHeight, height)
+		} else {
+			// Retrieve value and conovert to SCIDInteractionHeight, so that you can manipulate and update db
+			_ = json.Unmarshal(currSCIDInteractionHeight, &interactionHeight)
+
+			for _, v := range interactionHeight {
+				if v == height {
+					// Return nil if already exists in array.
+					// Clause for this is in event we pop backwards in time and already have this data stored.
+					// Data stored from a false-chain is removed via RollbackToHeight() when a reorg is detected.
+					return
+				}
+			}
+
+			interactionHeight = append(interactionHeight, height)
+		}
+		newInteractionHeight, err = json.Marshal(interactionHeight)
+		if err != nil {
+			return fmt.Errorf("[BBolt] could not marshal interactionHeight info: %v", err)
+		}
+
+		err = b.Put([]byte(key), newInteractionHeight)
+		changes = true
+		return
+	})
+
+	return
+}
+
+// Gets SC interaction height and detail by a given SCID
+func (bbs *BboltStore) GetSCIDInteractionHeight(scid string) (scidinteractions []int64) {
+	bName := scid + "heights"
+
+	bbs.view(func(tx *bolt.Tx) (err error) {
+		b := tx.Bucket([]byte(bName))
+		if b != nil {
+			key := scid
+			v := b.Get([]byte(key))
+
+			if v != nil {
+				_ = json.Unmarshal(v, &scidinteractions)
+			}
+		}
+		return
+	})
+
+	return
+}
+
+func (bbs *BboltStore) GetInteractionIndex(topoheight int64, heights []int64, rmax bool) (height int64) {
+	if len(heights) <= 0 {
+		return height
+	}
+
+	// Sort heights so most recent is index 0 [if preferred reverse, just swap > with <]
+	sort.SliceStable(heights, func(i, j int) bool {
+		return heights[i] > heights[j]
+	})
+
+	if topoheight > heights[0] || rmax {
+		return heights[0]
+	}
+
+	for i := 1; i < len(heights); i++ {
+		if heights[i] < topoheight {
+			return heights[i]
+		} else if heights[i] == topoheight {
+			return heights[i]
+		}
+	}
+
+	return height
+}
+
+// Stores any SCIDs that were attempted to be deployed but not correct - log scid/fees burnt attempting it. The height of the deploy is kept alongside so it can be rolled back, 0 if not known
+func (bbs *BboltStore) StoreInvalidSCIDDeploys(scid string, fee uint64, height int64) (changes bool, err error) {
+	var currSCIDInteractionHeight []byte
+
+	currInvalidSCIDs := make(map[string]uint64)
+	var newInvalidSCIDs []byte
+
+	bName := "invalidscids"
+	key := "invalid"
+
+	err = bbs.view(func(tx *bolt.Tx) (err error) {
+		b := tx.Bucket([]byte(bName))
+		if b != nil {
+			currSCIDInteractionHeight = b.Get([]byte(key))
+		}
+		return
+	})
+
+	err = bbs.update(func(tx *bolt.Tx) (err error) {
+		b, err := tx.CreateBucketIfNotExists([]byte(bName))
+		if err != nil {
+			return fmt.Errorf("bucket: %s", err)
+		}
+
+		if currSCIDInteractionHeight == nil {
+			currInvalidSCIDs[scid] = fee
+		} else {
+			// Retrieve value and conovert to SCIDInteractionHeight, so that you can manipulate and update db
+			_ = json.Unmarshal(currSCIDInteractionHeight, &currInvalidSCIDs)
+
+			currInvalidSCIDs[scid] = fee
+		}
+		newInvalidSCIDs, err = json.Marshal(currInvalidSCIDs)
+		if err != nil {
+			return fmt.Errorf("[bbs-StoreInvalidSCIDDeploys] could not marshal interactionHeight info: %v", err)
+		}
+
+		err = b.Put([]byte(key), newInvalidSCIDs)
+		if err != nil {
+			return
+		}
+
+		if height > 0 {
+			invalidHeights := make(map[string]int64)
+			if v := b.Get([]byte(invalid_scid_heights_key)); v != nil {
+				_ = json.Unmarshal(v, &invalidHeights)
+			}
+			invalidHeights[scid] = height
+			newInvalidHeights, err := json.Marshal(invalidHeights)
+			if err != nil {
+				return fmt.Errorf("[bbs-StoreInvalidSCIDDeploys] could not marshal invalid scid heights: %v", err)
+			}
+			if err = b.Put([]byte(invalid_scid_heights_key), newInvalidHeights); err != nil {
+				return err
+			}
+		}
+		changes = true
+		return
+	})
+
+	return
+}
+
+// Gets any SCIDs that were attempted to be deployed but not correct and their fees
+func (bbs *BboltStore) GetInvalidSCIDDeploys() map[string]uint64 {
+	invalidSCIDs := make(map[string]uint64)
+
+	bName := "invalidscids"
+
+	bbs.view(func(tx *bolt.Tx) (err error) {
+		b := tx.Bucket([]byte(bName))
+		if b != nil {
+			key := "invalid"
+			v := b.Get([]byte(key))
+
+			if v != nil {
+				_ = json.Unmarshal(v, &invalidSCIDs)
+			}
+		}
+		return
+	})
+
+	return invalidSCIDs
+}
+
+// Stores the miniblocks within a given blid, found at topoheight (0 if not known)
+func (bbs *BboltStore) StoreMiniblockDetailsByHash(blid string, topoheight int64, mbldetails []*structures.MBLInfo) (changes bool, err error) {
+	for _, v := range mbldetails {
+		_, err := bbs.StoreMiniblockCountByAddress(v.Miner)
+		if err != nil {
+			logger.Errorf("[Store] ERR - Error adding miniblock count for address '%v'", v.Miner)
+		}
+	}
+
+	confBytes, err := json.Marshal(&miniblockEntry{Height: topoheight, Miniblocks: mbldetails})
+	if err != nil {
+		return changes, fmt.Errorf("[StoreMiniblockDetailsByHash] could not marshal getinfo info: %v", err)
+	}
+
+	bName := "miniblocks"
+
+	key := blid
+
+	err = bbs.update(func(tx *bolt.Tx) (err error) {
+		b, err := tx.CreateBucketIfNotExists([]byte(bName))
+		if err != nil {
+			return fmt.Errorf("bucket: %s", err)
+		}
+
+		err = b.Put([]byte(key), confBytes)
+		if err != nil {
+			return
+		}
+		changes = true
+
+		if topoheight > 0 {
+			sb, err := tx.CreateBucketIfNotExists([]byte("stats"))
+			if err != nil {
+				return fmt.Errorf("bucket: %s", err)
+			}
+			if sb.Get([]byte(mbl_heights_from_key)) == nil {
+				return sb.Put([]byte(mbl_heights_from_key), []byte(strconv.FormatInt(topoheight, 10)))
+			}
+		}
+		return
+	})
+
+	return
+}
+
+// Returns all miniblock details for synced chain
+func (bbs *BboltStore) GetAllMiniblockDetails() map[string][]*structures.MBLInfo {
+	mbldetails := make(map[string][]*structures.MBLInfo)
+
+	bName := "miniblocks"
+
+	bbs.view(func(tx *bolt.Tx) (err error) {
+		b := tx.Bucket([]byte(bName))
+		if b != nil {
+
+			c := b.Cursor()
+
+			for k, v := c.First(); err == nil; k, v = c.Next() {
+				if k != nil && v != nil {
+					mbldetails[string(k)] = decodeMiniblockEntry(v).Miniblocks
+				} else {
+					break
+				}
+			}
+		}
+
+		return
+	})
+
+	return mbldetails
+}
+
+// Returns the miniblocks within a given blid if previously stored
+func (bbs *BboltStore) GetMiniblockDetailsByHash(blid string) (miniblocks []*structures.MBLInfo) {
+	bName := "miniblocks"
+
+	bbs.view(func(tx *bolt.Tx) (err error) {
+		b := tx.Bucket([]byte(bName))
+		if b != nil {
+			key := blid
+			v := b.Get([]byte(key))
+
+			if v != nil {
+				miniblocks = decodeMiniblockEntry(v).Miniblocks
+			}
+		}
+		return
+	})
+
+	return
+}
+
+// Stores counts of miniblock finders by address
+func (bbs *BboltStore) StoreMiniblockCountByAddress(addr string) (changes bool, err error) {
+	currCount := bbs.GetMiniblockCountByAddress(addr)
+
+	// Add 1 to currCount
+	currCount++
+
+	confBytes, err := json.Marshal(currCount)
+	if err != nil {
+		return changes, fmt.Errorf("[StoreMiniblockCountByAddress] could not marshal getinfo info: %v", err)
+	}
+
+	bName := "blockcount"
+
+	key := addr
+
+	err = bbs.update(func(tx *bolt.Tx) (err error) {
+		b, err := tx.CreateBucketIfNotExists([]byte(bName))
+		if err != nil {
+			return fmt.Errorf("bucket: %s", err)
+		}
+
+		err = b.Put([]byte(key), confBytes)
+		changes = true
+		return
+	})
+
+	return
+}
+
+// Gets counts of miniblock finders by address
+func (bbs *BboltStore) GetMiniblockCountByAddress(addr string) (miniblocks int64) {
+	bName := "blockcount"
+
+	bbs.view(func(tx *bolt.Tx) (err error) {
+		b := tx.Bucket([]byte(bName))
+		if b != nil {
+			key := addr
+			v := b.Get([]byte(key))
+
+			if v != nil {
+				_ = json.Unmarshal(v, &miniblocks)
+			}
+		}
+		return
+	})
+
+	return
+}
+
+// Gets all SCID interacts from a given address - non-builtin/name scids.
+func (bbs *BboltStore) GetSCIDInteractionByAddr(addr string) (scids []string) {
+	// Address activity index is built at write time, the scan below is only used for addresses with nothing indexed (e.g. dbs indexed before it was kept)
+	activity, _ := bbs.GetAddressActivity(addr, 0, 0)
+	if len(activity) > 0 {
+		for _, v := range activity {
+			if !idExist(scids, v.Scid) {
+				scids = append(scids, v.Scid)
+			}
+		}
+
+		return scids
+	}
+
+	normTxsWithSCID := bbs.GetAllNormalTxWithSCIDByAddr(addr)
+
+	// Append scids list of normtxs scid interaction
+	for _, v := range normTxsWithSCID {
+		if !idExist(scids, v.Scid) {
+			scids = append(scids, v.Scid)
+		}
+	}
+
+	allSCIDs := bbs.GetAllOwnersAndSCIDs()
+	for k, _ := range allSCIDs {
+		// Skip builtin name registration, no need to waste cursor time on this one since it's not pertinent to goal of function
+		// TODO: Future state, we'll have much more SCID interaction and this will get slower and slower, will need to speedup. Probably will happen with data re-org in future
+		if k == "0000000000000000000000000000000000000000000000000000000000000001" {
+			continue
+		}
+		invokedetails := bbs.GetAllSCIDInvokeDetailsBySigner(k, addr)
+		if len(invokedetails) > 0 {
+			if !idExist(scids, k) {
+				scids = append(scids, k)
+			}
+		}
+	}
+
+	return scids
+}
+
+// Stores an address' interaction with a SC to the address' activity
+func (bbs *BboltStore) StoreAddressActivity(addr string, activity *structures.AddressActivity) (changes bool, err error) {
+	bName := "addressactivity"
+	key := addr
+
+	err = bbs.update(func(tx *bolt.Tx) (err error) {
+		b, err := tx.CreateBucketIfNotExists([]byte(bName))
+		if err != nil {
+			return fmt.Errorf("bucket: %s", err)
+		}
+
+		var activities []*structures.AddressActivity
+		if currActivity := b.Get([]byte(key)); currActivity != nil {
+			_ = json.Unmarshal(currActivity, &activities)
+			for _, v := range activities {
+				if *v == *activity {
+					// Return nil if already exists in array.
+					// Clause for this is in event we pop backwards in time and already have this data stored.
+					// Data stored from a false-chain is removed via RollbackToHeight() when a reorg is detected.
+					return nil
+				}
+			}
+		}
+		activities = append(activities, activity)
+		sortAddressActivity(activities)
+
+		newActivity, err := json.Marshal(activities)
+		if err != nil {
+			return fmt.Errorf("[bbolt] could not marshal address activity info: %v", err)
+		}
+
+		err = b.Put([]byte(key), newActivity)
+		changes = true
+		return
+	})
+
+	return
+}
+
+// Returns a page of an address' SC activity in height order, starting at index start and returning at most limit entries (all if limit <= 0). total is the number of entries across all pages
+func (bbs *BboltStore) GetAddressActivity(addr string, start int, limit int) (activity []*structures.AddressActivity, total int) {
+	bName := "addressactivity"
+
+	var activities []*structures.AddressActivity
+	bbs.view(func(tx *bolt.Tx) (err error) {
+		b := tx.Bucket([]byte(bName))
+		if b != nil {
+			key := addr
+			v := b.Get([]byte(key))
+
+			if v != nil {
+				_ = json.Unmarshal(v, &activities)
+			}
+		}
+
+		return
+	})
+
+	return pageAddressActivity(activities, start, limit)
+}
+
+// Gets the address activity index of all addresses
+func (bbs *BboltStore) GetAllAddressActivity() map[string][]*structures.AddressActivity {
+	results := make(map[string][]*structures.AddressActivity)
+
+	bName := "addressactivity"
+
+	bbs.view(func(tx *bolt.Tx) (err error) {
+		b := tx.Bucket([]byte(bName))
+		if b != nil {
+			c := b.Cursor()
+
+			for k, v := c.First(); k != nil; k, v = c.Next() {
+				var activities []*structures.AddressActivity
+				_ = json.Unmarshal(v, &activities)
+				results[string(k)] = activities
+			}
+		}
+
+		return
+	})
+
+	return results
+}
+
+// Adds invokedetails of a given scid to its secondary index on the invoke arg name, marking name as indexed for the scid. Invokes without the arg are skipped
+func (bbs *BboltStore) StoreSCIDArgIndex(scid string, name string, invokedetails []*structures.SCTXParse) (changes bool, err error) {
+	bName := scid + "args"
+
+	err = bbs.update(func(tx *bolt.Tx) (err error) {
+		b, err := tx.CreateBucketIfNotExists([]byte(bName))
+		if err != nil {
+			return fmt.Errorf("bucket: %s", err)
+		}
+
+		var indexed []string
+		if v := b.Get([]byte(indexed_args_key)); v != nil {
+			_ = json.Unmarshal(v, &indexed)
+		}
+		if !idExist(indexed, name) {
+			indexed = append(indexed, name)
+			ibytes, err := json.Marshal(indexed)
+			if err != nil {
+				return fmt.Errorf("[bbolt] could not marshal indexed args info: %v", err)
+			}
+			if err = b.Put([]byte(indexed_args_key), ibytes); err != nil {
+				return err
+			}
+			changes = true
+		}
+
+		index := make(map[string][]*argIndexEntry)
+		for _, invoke := range invokedetails {
+			if value, ok := invoke.Args[name]; ok {
+				ikey := argIndexKey(name, value)
+				if _, ok := index[ikey]; ok {
+					continue
+				}
+				var entries []*argIndexEntry
+				if v := b.Get([]byte(ikey)); v != nil {
+					_ = json.Unmarshal(v, &entries)
+				}
+				index[ikey] = entries
+			}
+		}
+
+		if addArgIndexEntries(index, name, invokedetails) > 0 {
+			for ikey, entries := range index {
+				ebytes, err := json.Marshal(entries)
+				if err != nil {
+					return fmt.Errorf("[bbolt] could not marshal arg index info: %v", err)
+				}
+				if err = b.Put([]byte(ikey), ebytes); err != nil {
+					return err
+				}
+			}
+			changes = true
+		}
+
+		return
+	})
+
+	return
+}
+
+// Returns the invoke arg names a secondary index is kept on for a given scid
+func (bbs *BboltStore) GetSCIDIndexedArgs(scid string) (names []string) {
+	bName := scid + "args"
+
+	bbs.view(func(tx *bolt.Tx) (err error) {
+		b := tx.Bucket([]byte(bName))
+		if b != nil {
+			if v := b.Get([]byte(indexed_args_key)); v != nil {
+				_ = json.Unmarshal(v, &names)
+			}
+		}
+
+		return
+	})
+
+	return
+}
+
+// Stores the block hash of indexed topoheights, used to detect chain reorgs against the daemon. Hashes more than block_hashes_kept below the highest given are trimmed
+func (bbs *BboltStore) StoreBlockHashes(blockhashes map[int64]string) (changes bool, err error) {
+	bName := "blockhashes"
+
+	err = bbs.update(func(tx *bolt.Tx) (err error) {
+		b, err := tx.CreateBucketIfNotExists([]byte(bName))
+		if err != nil {
+			return fmt.Errorf("bucket: %s", err)
+		}
+
+		var top int64
+		for height, hash := range blockhashes {
+			err = b.Put([]byte(strconv.FormatInt(height, 10)), []byte(hash))
+			if err != nil {
+				return
+			}
+			changes = true
+			if height > top {
+				top = height
+			}
+		}
+		if !changes {
+			return
+		}
+
+		sb, err := tx.CreateBucketIfNotExists([]byte("stats"))
+		if err != nil {
+			return fmt.Errorf("bucket: %s", err)
+		}
+		var trimmed int64
+		if v := sb.Get([]byte(block_hashes_trimmed_key)); v != nil {
+			trimmed, _ = strconv.ParseInt(string(v), 10, 64)
+		}
+		from, to := blockHashTrimRange(top, trimmed)
+		if to <= from {
+			return
+		}
+		for h := from; h < to; h++ {
+			if err = b.Delete([]byte(strconv.FormatInt(h, 10))); err != nil {
+				return
+			}
+		}
+
+		return sb.Put([]byte(block_hashes_trimmed_key), []byte(strconv.FormatInt(to, 10)))
+	})
+
+	return
+}
+
+// Gets the stored block hash of an indexed topoheight
+func (bbs *BboltStore) GetBlockHash(topoheight int64) (hash string) {
+	bName := "blockhashes"
+
+	bbs.view(func(tx *bolt.Tx) (err error) {
+		b := tx.Bucket([]byte(bName))
+		if b != nil {
+			key := strconv.FormatInt(topoheight, 10)
+			v := b.Get([]byte(key))
+
+			if v != nil {
+				hash = string(v)
+			}
+		}
+		return
+	})
+
+	return
+}
+
+// Removes all indexed data above a given topoheight. Used when the chain has reorganized and data from orphaned blocks must be undone before indexing the new branch.
+// Block hashes and miniblocks are stored by topoheight, every other record (along with the tx counts added and invalid scid deploys) is kept by the height of its block so is removed above height.
+// Returns the scids whose install was rolled back so they can be removed from the validated list.
+func (bbs *BboltStore) RollbackToHeight(topoheight int64, height int64) (rolledbackSCIDs []string, err error) {
+	err = bbs.update(func(tx *bolt.Tx) (err error) {
+		// Block hashes above the fork point, along with any miniblock details stored against those blids
+		if bhb := tx.Bucket([]byte("blockhashes")); bhb != nil {
+			var orphaned []*TreeKV
+			c := bhb.Cursor()
+			for k, v := c.First(); k != nil; k, v = c.Next() {
+				h, perr := strconv.ParseInt(string(k), 10, 64)
+				if perr == nil && h > topoheight {
+					orphaned = append(orphaned, &TreeKV{k, v})
+				}
+			}
+
+			mblb := tx.Bucket([]byte("miniblocks"))
+			bcb := tx.Bucket([]byte("blockcount"))
+			for _, kv := range orphaned {
+				if err = bhb.Delete(kv.k); err != nil {
+					return
+				}
+				if mblb == nil {
+					continue
+				}
+				if mblbytes := mblb.Get(kv.v); mblbytes != nil {
+					if bcb != nil {
+						for _, mbl := range decodeMiniblockEntry(mblbytes).Miniblocks {
+							var count int64
+							if cbytes := bcb.Get([]byte(mbl.Miner)); cbytes != nil {
+								_ = json.Unmarshal(cbytes, &count)
+							}
+							if count > 0 {
+								count--
+							}
+							cbytes, _ := json.Marshal(count)
+							if err = bcb.Put([]byte(mbl.Miner), cbytes); err != nil {
+								return
+							}
+						}
+					}
+					if err = mblb.Delete(kv.v); err != nil {
+						return
+					}
+				}
+			}
+		}
+
+		// Invokes, variables and interaction heights of each indexed scid
+		if ob := tx.Bucket([]byte("scowner")); ob != nil {
+			var scids []string
+			c := ob.Cursor()
+			for k, _ := c.First(); k != nil; k, _ = c.Next() {
+				scids = append(scids, string(k))
+			}
+
+			for _, scid := range scids {
+				if ib := tx.Bucket([]byte(scid)); ib != nil {
+					var ikeys [][]byte
+					c = ib.Cursor()
+					for k, v := c.First(); k != nil; k, v = c.Next() {
+						var currdetails *structures.SCTXParse
+						_ = json.Unmarshal(v, &currdetails)
+						if currdetails != nil && currdetails.Height > height {
+							ikeys = append(ikeys, k)
+							if currdetails.Method == "installsc" {
+								rolledbackSCIDs = append(rolledbackSCIDs, scid)
+							}
+						}
+					}
+					for _, k := range ikeys {
+						if err = ib.Delete(k); err != nil {
+							return
+						}
+					}
+				}
+
+				if vb := tx.Bucket([]byte(scid + "vars")); vb != nil {
+					var vkeys [][]byte
+					c = vb.Cursor()
+					for k, _ := c.First(); k != nil; k, _ = c.Next() {
+						h, perr := strconv.ParseInt(string(k), 10, 64)
+						if perr == nil && h > height {
+							vkeys = append(vkeys, k)
+						}
+					}
+					for _, k := range vkeys {
+						if err = vb.Delete(k); err != nil {
+							return
+						}
+					}
+				}
+
+				if bb := tx.Bucket([]byte(scid + "balances")); bb != nil {
+					var bkeys [][]byte
+					c = bb.Cursor()
+					for k, _ := c.First(); k != nil; k, _ = c.Next() {
+						h, perr := strconv.ParseInt(string(k), 10, 64)
+						if perr == nil && h > height {
+							bkeys = append(bkeys, k)
+						}
+					}
+					for _, k := range bkeys {
+						if err = bb.Delete(k); err != nil {
+							return
+						}
+					}
+				}
+
+				if cb := tx.Bucket([]byte(scid + "code")); cb != nil {
+					var ckeys [][]byte
+					c = cb.Cursor()
+					for k, _ := c.First(); k != nil; k, _ = c.Next() {
+						h, perr := strconv.ParseInt(string(k), 10, 64)
+						if perr == nil && h > height {
+							ckeys = append(ckeys, k)
+						}
+					}
+					for _, k := range ckeys {
+						if err = cb.Delete(k); err != nil {
+							return
+						}
+					}
+				}
+
+				if ab := tx.Bucket([]byte(scid + "args")); ab != nil {
+					var argkvs []*TreeKV
+					c := ab.Cursor()
+					for k, v := c.First(); k != nil; k, v = c.Next() {
+						if string(k) == indexed_args_key {
+							continue
+						}
+						var entries, newEntries []*argIndexEntry
+						_ = json.Unmarshal(v, &entries)
+						for _, e := range entries {
+							if e.Height <= height {
+								newEntries = append(newEntries, e)
+							}
+						}
+						if len(newEntries) != len(entries) {
+							var nv []byte
+							if len(newEntries) > 0 {
+								nv, _ = json.Marshal(newEntries)
+							}
+							argkvs = append(argkvs, &TreeKV{k, nv})
+						}
+					}
+					for _, kv := range argkvs {
+						if kv.v == nil {
+							err = ab.Delete(kv.k)
+						} else {
+							err = ab.Put(kv.k, kv.v)
+						}
+						if err != nil {
+							return
+						}
+					}
+				}
+
+				if hb := tx.Bucket([]byte(scid + "heights")); hb != nil {
+					if hbytes := hb.Get([]byte(scid)); hbytes != nil {
+						var interactionHeight, newInteractionHeight []int64
+						_ = json.Unmarshal(hbytes, &interactionHeight)
+						for _, h := range interactionHeight {
+							if h <= height {
+								newInteractionHeight = append(newInteractionHeight, h)
+							}
+						}
+						if len(newInteractionHeight) != len(interactionHeight) {
+							if len(newInteractionHeight) == 0 {
+								err = hb.Delete([]byte(scid))
+							} else {
+								hbytes, _ = json.Marshal(newInteractionHeight)
+								err = hb.Put([]byte(scid), hbytes)
+							}
+							if err != nil {
+								return
+							}
+						}
+					}
+				}
+			}
+
+			for _, scid := range rolledbackSCIDs {
+				if err = ob.Delete([]byte(scid)); err != nil {
+					return
+				}
+			}
+		}
+
+		// Template matches of the rolled back installs
+		if tb := tx.Bucket([]byte("templates")); tb != nil {
+			for _, scid := range rolledbackSCIDs {
+				if err = tb.Delete([]byte(scid)); err != nil {
+					return
+				}
+			}
+		}
+
+		// Normal txs with scid payloads by ring member
+		if nb := tx.Bucket([]byte("normaltxwithscid")); nb != nil {
+			var nkvs []*TreeKV
+			c := nb.Cursor()
+			for k, v := c.First(); k != nil; k, v = c.Next() {
+				var currdetails, newdetails []*structures.NormalTXWithSCIDParse
+				_ = json.Unmarshal(v, &currdetails)
+				for _, cv := range currdetails {
+					if cv.Height <= height {
+						newdetails = append(newdetails, cv)
+					}
+				}
+				if len(newdetails) != len(currdetails) {
+					var nv []byte
+					if len(newdetails) > 0 {
+						nv, _ = json.Marshal(newdetails)
+					}
+					nkvs = append(nkvs, &TreeKV{k, nv})
+				}
+			}
+			for _, kv := range nkvs {
+				if kv.v == nil {
+					err = nb.Delete(kv.k)
+				} else {
+					err = nb.Put(kv.k, kv.v)
+				}
+				if err != nil {
+					return
+				}
+			}
+		}
+
+		// Address activity
+		if ab := tx.Bucket([]byte("addressactivity")); ab != nil {
+			var aakvs []*TreeKV
+			c := ab.Cursor()
+			for k, v := c.First(); k != nil; k, v = c.Next() {
+				var currdetails, newdetails []*structures.AddressActivity
+				_ = json.Unmarshal(v, &currdetails)
+				for _, cv := range currdetails {
+					if cv.Height <= height {
+						newdetails = append(newdetails, cv)
+					}
+				}
+				if len(newdetails) != len(currdetails) {
+					var nv []byte
+					if len(newdetails) > 0 {
+						nv, _ = json.Marshal(newdetails)
+					}
+					aakvs = append(aakvs, &TreeKV{k, nv})
+				}
+			}
+			for _, kv := range aakvs {
+				if kv.v == nil {
+					err = ab.Delete(kv.k)
+				} else {
+					err = ab.Put(kv.k, kv.v)
+				}
+				if err != nil {
+					return
+				}
+			}
+		}
+
+		// Registration txs
+		if rb := tx.Bucket([]byte("registrations")); rb != nil {
+			var rkeys [][]byte
+			c := rb.Cursor()
+			for k, v := c.First(); k != nil; k, v = c.Next() {
+				var currdetails *structures.RegTXParse
+				_ = json.Unmarshal(v, &currdetails)
+				if currdetails != nil && currdetails.Height > height {
+					rkeys = append(rkeys, k)
+				}
+			}
+			for _, k := range rkeys {
+				if err = rb.Delete(k); err != nil {
+					return
+				}
+			}
+		}
+
+		// Burn txs, along with their height and scid index entries
+		if bb := tx.Bucket([]byte("burntxs")); bb != nil {
+			var rolledbackBurns [][]*structures.BurnTXParse
+			c := bb.Cursor()
+			for k, v := c.First(); k != nil; k, v = c.Next() {
+				var currdetails []*structures.BurnTXParse
+				_ = json.Unmarshal(v, &currdetails)
+				if len(currdetails) > 0 && currdetails[0].Height > height {
+					rolledbackBurns = append(rolledbackBurns, currdetails)
+				}
+			}
+			bhb := tx.Bucket([]byte("burnheights"))
+			for _, currdetails := range rolledbackBurns {
+				txid := []byte(currdetails[0].Txid)
+				if err = bb.Delete(txid); err != nil {
+					return
+				}
+				if bhb != nil {
+					hkey := []byte(strconv.FormatInt(currdetails[0].Height, 10))
+					if hv := bhb.Get(hkey); hv != nil {
+						if txids := removeIndexKey(hv, currdetails[0].Txid); txids != nil {
+							err = bhb.Put(hkey, txids)
+						} else {
+							err = bhb.Delete(hkey)
+						}
+						if err != nil {
+							return
+						}
+					}
+				}
+				for _, cv := range currdetails {
+					if bsb := tx.Bucket([]byte(cv.Scid + "burns")); bsb != nil {
+						if err = bsb.Delete(txid); err != nil {
+							return
+						}
+					}
+				}
+			}
+		}
+
+		// Name service history past height is dropped, the current record of each name goes back to the latest one left
+		if nhb := tx.Bucket([]byte("namehistory")); nhb != nil {
+			nb, err := tx.CreateBucketIfNotExists([]byte("names"))
+			if err != nil {
+				return fmt.Errorf("bucket: %s", err)
+			}
+
+			nhkvs := make(map[string][]*structures.NameRecord)
+			c := nhb.Cursor()
+			for k, v := c.First(); k != nil; k, v = c.Next() {
+				var history, newHistory []*structures.NameRecord
+				_ = json.Unmarshal(v, &history)
+				for _, record := range history {
+					if record.Height <= height {
+						newHistory = append(newHistory, record)
+					}
+				}
+				if len(newHistory) != len(history) {
+					nhkvs[string(k)] = newHistory
+				}
+			}
+			nab, err := tx.CreateBucketIfNotExists([]byte("nameaddrs"))
+			if err != nil {
+				return fmt.Errorf("bucket: %s", err)
+			}
+			for k, newHistory := range nhkvs {
+				var prev *structures.NameRecord
+				if v := nb.Get([]byte(k)); v != nil {
+					_ = json.Unmarshal(v, &prev)
+				}
+				var prevAddress string
+				if prev != nil {
+					prevAddress = prev.Address
+				}
+
+				if len(newHistory) == 0 {
+					if err = nhb.Delete([]byte(k)); err != nil {
+						return err
+					}
+					if err = nb.Delete([]byte(k)); err != nil {
+						return err
+					}
+					if err = bboltMoveNameAddress(nab, k, prevAddress, ""); err != nil {
+						return err
+					}
+					continue
+				}
+				hbytes, err := json.Marshal(newHistory)
+				if err != nil {
+					return err
+				}
+				rbytes, err := json.Marshal(newHistory[len(newHistory)-1])
+				if err != nil {
+					return err
+				}
+				if err = nhb.Put([]byte(k), hbytes); err != nil {
+					return err
+				}
+				if err = nb.Put([]byte(k), rbytes); err != nil {
+					return err
+				}
+				if address := newHistory[len(newHistory)-1].Address; address != prevAddress {
+					if err = bboltMoveNameAddress(nab, k, prevAddress, address); err != nil {
+						return err
+					}
+				}
+			}
+		}
 
-	bName := "invalidscids"
-	key := "invalid"
+		// Asset registry entries updated past height go back to their entry as of height from the asset's history. Assets first recognized past height (or stored before history was kept) are dropped and picked back up when their SC is next indexed
+		if ab := tx.Bucket([]byte("assets")); ab != nil {
+			ahb, err := tx.CreateBucketIfNotExists([]byte("assethistory"))
+			if err != nil {
+				return fmt.Errorf("bucket: %s", err)
+			}
 
-	err = bbs.DB.View(func(tx *bolt.Tx) (err error) {
-		b := tx.Bucket([]byte(bName))
-		if b != nil {
-			currSCIDInteractionHeight = b.Get([]byte(key))
+			var akeys [][]byte
+			c := ab.Cursor()
+			for k, v := c.First(); k != nil; k, v = c.Next() {
+				var currdetails *structures.SCAsset
+				_ = json.Unmarshal(v, &currdetails)
+				if currdetails != nil && currdetails.Height > height {
+					akeys = append(akeys, k)
+				}
+			}
+			for _, k := range akeys {
+				var history []*structures.SCAsset
+				if v := ahb.Get(k); v != nil {
+					_ = json.Unmarshal(v, &history)
+				}
+				history = assetHistoryAt(history, height)
+				if len(history) == 0 {
+					if err = ab.Delete(k); err != nil {
+						return err
+					}
+					if err = ahb.Delete(k); err != nil {
+						return err
+					}
+					continue
+				}
+				hbytes, err := json.Marshal(history)
+				if err != nil {
+					return err
+				}
+				abytes, err := json.Marshal(history[len(history)-1])
+				if err != nil {
+					return err
+				}
+				if err = ab.Put(k, abytes); err != nil {
+					return err
+				}
+				if err = ahb.Put(k, hbytes); err != nil {
+					return err
+				}
+			}
 		}
-		return
-	})
 
-	err = bbs.DB.Update(func(tx *bolt.Tx) (err error) {
-		b, err := tx.CreateBucketIfNotExists([]byte(bName))
+		sb, err := tx.CreateBucketIfNotExists([]byte("stats"))
 		if err != nil {
 			return fmt.Errorf("bucket: %s", err)
 		}
 
-		if currSCIDInteractionHeight == nil {
-			currInvalidSCIDs[scid] = fee
-		} else {
-			// Retrieve value and conovert to SCIDInteractionHeight, so that you can manipulate and update db
-			_ = json.Unmarshal(currSCIDInteractionHeight, &currInvalidSCIDs)
-
-			currInvalidSCIDs[scid] = fee
+		// Tx counts added by the blocks above height are taken back off the totals
+		if tcb := tx.Bucket([]byte("txcountheights")); tcb != nil {
+			var tckeys [][]byte
+			rolledbackCounts := make(map[string]int64)
+			c := tcb.Cursor()
+			for k, v := c.First(); k != nil; k, v = c.Next() {
+				h, perr := strconv.ParseInt(string(k), 10, 64)
+				if perr == nil && h > height {
+					var counts map[string]int64
+					_ = json.Unmarshal(v, &counts)
+					for txType, count := range counts {
+						rolledbackCounts[txType] += count
+					}
+					tckeys = append(tckeys, k)
+				}
+			}
+			for _, k := range tckeys {
+				if err = tcb.Delete(k); err != nil {
+					return
+				}
+			}
+			for txType, count := range rolledbackCounts {
+				key := []byte(txType + "txcount")
+				var txCount int64
+				if v := sb.Get(key); v != nil {
+					txCount, _ = strconv.ParseInt(string(v), 10, 64)
+				}
+				txCount -= count
+				if txCount < 0 {
+					txCount = 0
+				}
+				if err = sb.Put(key, []byte(strconv.FormatInt(txCount, 10))); err != nil {
+					return
+				}
+			}
 		}
-		newInvalidSCIDs, err = json.Marshal(currInvalidSCIDs)
-		if err != nil {
-			return fmt.Errorf("[bbs-StoreInvalidSCIDDeploys] could not marshal interactionHeight info: %v", err)
+
+		// Invalid scid deploys above height
+		if ivb := tx.Bucket([]byte("invalidscids")); ivb != nil {
+			if hbytes := ivb.Get([]byte(invalid_scid_heights_key)); hbytes != nil {
+				invalidHeights := make(map[string]int64)
+				invalidSCIDs := make(map[string]uint64)
+				_ = json.Unmarshal(hbytes, &invalidHeights)
+				if v := ivb.Get([]byte("invalid")); v != nil {
+					_ = json.Unmarshal(v, &invalidSCIDs)
+				}
+				var ivchanged bool
+				for scid, h := range invalidHeights {
+					if h > height {
+						delete(invalidHeights, scid)
+						delete(invalidSCIDs, scid)
+						ivchanged = true
+					}
+				}
+				if ivchanged {
+					ibytes, err := json.Marshal(invalidSCIDs)
+					if err != nil {
+						return err
+					}
+					hbytes, err = json.Marshal(invalidHeights)
+					if err != nil {
+						return err
+					}
+					if err = ivb.Put([]byte("invalid"), ibytes); err != nil {
+						return err
+					}
+					if err = ivb.Put([]byte(invalid_scid_heights_key), hbytes); err != nil {
+						return err
+					}
+				}
+			}
 		}
 
-		err = b.Put([]byte(key), newInvalidSCIDs)
-		changes = true
-		return
+		return sb.Put([]byte("lastindexedheight"), []byte(strconv.FormatInt(topoheight, 10)))
 	})
 
 	return
 }
 
-// Gets any SCIDs that were attempted to be deployed but not correct and their fees
-func (bbs *BboltStore) GetInvalidSCIDDeploys() map[string]uint64 {
-	invalidSCIDs := make(map[string]uint64)
-
-	bName := "invalidscids"
+// Removes the SC variables of a given scid stored below belowHeight, keeping the variables as of belowHeight as a keyframe at the most recent stored height below it so that the variables at or above belowHeight are unchanged
+func (bbs *BboltStore) PruneSCIDVariableDetails(scid string, belowHeight int64) (pruned int, err error) {
+	bName := scid + "vars"
 
-	bbs.DB.View(func(tx *bolt.Tx) (err error) {
+	err = bbs.update(func(tx *bolt.Tx) (err error) {
 		b := tx.Bucket([]byte(bName))
-		if b != nil {
-			key := "invalid"
-			v := b.Get([]byte(key))
-
-			if v != nil {
-				_ = json.Unmarshal(v, &invalidSCIDs)
-			}
+		if b == nil {
+			return
 		}
-		return
-	})
 
-	return invalidSCIDs
-}
-
-// Stores the miniblocks within a given blid
-func (bbs *BboltStore) StoreMiniblockDetailsByHash(blid string, mbldetails []*structures.MBLInfo) (changes bool, err error) {
-	for _, v := range mbldetails {
-		_, err := bbs.StoreMiniblockCountByAddress(v.Miner)
-		if err != nil {
-			logger.Errorf("[Store] ERR - Error adding miniblock count for address '%v'", v.Miner)
+		entries := make(map[int64][]byte)
+		c := b.Cursor()
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			h, _ := strconv.ParseInt(string(k), 10, 64)
+			entries[h] = v
 		}
-	}
-
-	confBytes, err := json.Marshal(mbldetails)
-	if err != nil {
-		return changes, fmt.Errorf("[StoreMiniblockDetailsByHash] could not marshal getinfo info: %v", err)
-	}
 
-	bName := "miniblocks"
-
-	key := blid
-
-	err = bbs.DB.Update(func(tx *bolt.Tx) (err error) {
-		b, err := tx.CreateBucketIfNotExists([]byte(bName))
-		if err != nil {
-			return fmt.Errorf("bucket: %s", err)
+		keep, keyframe, err := pruneVarEntries(entries, belowHeight)
+		if err != nil || keyframe == nil {
+			return
 		}
 
-		err = b.Put([]byte(key), confBytes)
-		changes = true
+		if err = b.Put([]byte(strconv.FormatInt(keep, 10)), keyframe); err != nil {
+			return
+		}
+		for h := range entries {
+			if h < keep {
+				if err = b.Delete([]byte(strconv.FormatInt(h, 10))); err != nil {
+					return
+				}
+				pruned++
+			}
+		}
 		return
 	})
 
 	return
 }
 
-// Returns all miniblock details for synced chain
-func (bbs *BboltStore) GetAllMiniblockDetails() map[string][]*structures.MBLInfo {
-	mbldetails := make(map[string][]*structures.MBLInfo)
-
-	bName := "miniblocks"
-
-	bbs.DB.View(func(tx *bolt.Tx) (err error) {
-		b := tx.Bucket([]byte(bName))
-		if b != nil {
+// Removes the invokes of a given scid below belowHeight, along with their arg index entries. The install invoke is kept as the record of the scid's deploy
+func (bbs *BboltStore) PruneSCIDInvokeDetails(scid string, belowHeight int64) (pruned int, err error) {
+	err = bbs.update(func(tx *bolt.Tx) (err error) {
+		ib := tx.Bucket([]byte(scid))
+		if ib == nil {
+			return
+		}
 
-			c := b.Cursor()
+		ikeys := make(map[string]bool)
+		c := ib.Cursor()
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			var currdetails *structures.SCTXParse
+			_ = json.Unmarshal(v, &currdetails)
+			if currdetails != nil && currdetails.Height < belowHeight && currdetails.Method != "installsc" {
+				ikeys[string(k)] = true
+			}
+		}
+		for k := range ikeys {
+			if err = ib.Delete([]byte(k)); err != nil {
+				return
+			}
+		}
+		pruned = len(ikeys)
 
-			for k, v := c.First(); err == nil; k, v = c.Next() {
-				if k != nil && v != nil {
-					var currdetails []*structures.MBLInfo
-					_ = json.Unmarshal(v, &currdetails)
-					mbldetails[string(k)] = currdetails
+		if ab := tx.Bucket([]byte(scid + "args")); ab != nil && pruned > 0 {
+			var argkvs []*TreeKV
+			c := ab.Cursor()
+			for k, v := c.First(); k != nil; k, v = c.Next() {
+				if string(k) == indexed_args_key {
+					continue
+				}
+				var entries, newEntries []*argIndexEntry
+				_ = json.Unmarshal(v, &entries)
+				for _, e := range entries {
+					if !ikeys[e.Key] {
+						newEntries = append(newEntries, e)
+					}
+				}
+				if len(newEntries) != len(entries) {
+					var nv []byte
+					if len(newEntries) > 0 {
+						nv, _ = json.Marshal(newEntries)
+					}
+					argkvs = append(argkvs, &TreeKV{append([]byte(nil), k...), nv})
+				}
+			}
+			for _, kv := range argkvs {
+				if kv.v == nil {
+					err = ab.Delete(kv.k)
 				} else {
-					break
+					err = ab.Put(kv.k, kv.v)
+				}
+				if err != nil {
+					return
 				}
 			}
 		}
-
 		return
 	})
 
-	return mbldetails
+	return
 }
 
-// Returns the miniblocks within a given blid if previously stored
-func (bbs *BboltStore) GetMiniblockDetailsByHash(blid string) (miniblocks []*structures.MBLInfo) {
-	bName := "miniblocks"
-
-	bbs.DB.View(func(tx *bolt.Tx) (err error) {
-		b := tx.Bucket([]byte(bName))
-		if b != nil {
-			key := blid
-			v := b.Get([]byte(key))
+// Removes the miniblock details of the blocks below belowHeight, up to limit of them per call. Miniblock counts by address are kept
+func (bbs *BboltStore) PruneMiniblockDetails(belowHeight int64, limit int) (pruned int, err error) {
+	err = bbs.update(func(tx *bolt.Tx) (err error) {
+		mblb := tx.Bucket([]byte("miniblocks"))
+		if mblb == nil {
+			return
+		}
 
-			if v != nil {
-				_ = json.Unmarshal(v, &miniblocks)
+		var heightsFrom int64
+		if sb := tx.Bucket([]byte("stats")); sb != nil {
+			if v := sb.Get([]byte(mbl_heights_from_key)); v != nil {
+				heightsFrom, _ = strconv.ParseInt(string(v), 10, 64)
 			}
 		}
-		return
-	})
-
-	return
-}
-
-// Stores counts of miniblock finders by address
-func (bbs *BboltStore) StoreMiniblockCountByAddress(addr string) (changes bool, err error) {
-	currCount := bbs.GetMiniblockCountByAddress(addr)
-
-	// Add 1 to currCount
-	currCount++
-
-	confBytes, err := json.Marshal(currCount)
-	if err != nil {
-		return changes, fmt.Errorf("[StoreMiniblockCountByAddress] could not marshal getinfo info: %v", err)
-	}
-
-	bName := "blockcount"
 
-	key := addr
+		// Gather the keys first, deleting while the cursor is walking the bucket skips entries
+		var mkeys [][]byte
+		c := mblb.Cursor()
+		for k, v := c.First(); k != nil && len(mkeys) < limit; k, v = c.Next() {
+			if decodeMiniblockEntry(v).below(belowHeight, heightsFrom) {
+				mkeys = append(mkeys, append([]byte{}, k...))
+			}
+		}
 
-	err = bbs.DB.Update(func(tx *bolt.Tx) (err error) {
-		b, err := tx.CreateBucketIfNotExists([]byte(bName))
-		if err != nil {
-			return fmt.Errorf("bucket: %s", err)
+		for _, k := range mkeys {
+			if err = mblb.Delete(k); err != nil {
+				return
+			}
+			pruned++
 		}
 
-		err = b.Put([]byte(key), confBytes)
-		changes = true
 		return
 	})
 
 	return
 }
 
-// Gets counts of miniblock finders by address
-func (bbs *BboltStore) GetMiniblockCountByAddress(addr string) (miniblocks int64) {
-	bName := "blockcount"
+// Replaces the invokes, variable history, interaction heights, balances and code versions of a given scid with the ones given, e.g. as rebuilt by a rescan. Arg indexes kept on the scid are rebuilt over the new invokes. Done within one tx, so readers see either the old or the new history
+func (bbs *BboltStore) ReplaceSCIDHistory(scid string, history *SCIDHistory) (err error) {
+	return bbs.Batch(func(s Storage) (err error) {
+		bs := s.(*BboltStore)
 
-	bbs.DB.View(func(tx *bolt.Tx) (err error) {
-		b := tx.Bucket([]byte(bName))
-		if b != nil {
-			key := addr
-			v := b.Get([]byte(key))
+		argnames, err := bs.resetSCIDHistory(scid, history.Heights)
+		if err != nil {
+			return
+		}
 
-			if v != nil {
-				_ = json.Unmarshal(v, &miniblocks)
+		for _, invoke := range history.Invokes {
+			if _, err = bs.StoreInvokeDetails(scid, invoke.Sender, invoke.Entrypoint, invoke.Height, invoke); err != nil {
+				return
+			}
+		}
+		if len(history.Variables) > 0 {
+			if _, err = bs.StoreSCIDVariableHistory(scid, history.Variables); err != nil {
+				return
+			}
+		}
+		for _, balances := range history.Balances {
+			if _, err = bs.StoreSCIDBalanceDetails(scid, balances.Balances, balances.Height); err != nil {
+				return
+			}
+		}
+		for _, code := range history.Code {
+			if _, err = bs.StoreSCIDCode(scid, code); err != nil {
+				return
+			}
+		}
+		for _, name := range argnames {
+			if _, err = bs.StoreSCIDArgIndex(scid, name, history.Invokes); err != nil {
+				return
 			}
 		}
+
 		return
 	})
-
-	return
 }
 
-// Gets all SCID interacts from a given address - non-builtin/name scids.
-func (bbs *BboltStore) GetSCIDInteractionByAddr(addr string) (scids []string) {
-	normTxsWithSCID := bbs.GetAllNormalTxWithSCIDByAddr(addr)
-
-	// Append scids list of normtxs scid interaction
-	for _, v := range normTxsWithSCID {
-		if !idExist(scids, v.Scid) {
-			scids = append(scids, v.Scid)
+// Removes the invokes, variables, balances, code versions and arg indexes of a given scid and sets its interaction heights to heights. Returns the arg names that were indexed on the scid
+func (bbs *BboltStore) resetSCIDHistory(scid string, heights []int64) (argnames []string, err error) {
+	err = bbs.update(func(tx *bolt.Tx) (err error) {
+		if ab := tx.Bucket([]byte(scid + "args")); ab != nil {
+			if v := ab.Get([]byte(indexed_args_key)); v != nil {
+				_ = json.Unmarshal(v, &argnames)
+			}
 		}
-	}
 
-	allSCIDs := bbs.GetAllOwnersAndSCIDs()
-	for k, _ := range allSCIDs {
-		// Skip builtin name registration, no need to waste cursor time on this one since it's not pertinent to goal of function
-		// TODO: Future state, we'll have much more SCID interaction and this will get slower and slower, will need to speedup. Probably will happen with data re-org in future
-		if k == "0000000000000000000000000000000000000000000000000000000000000001" {
-			continue
+		for _, bName := range []string{scid, scid + "vars", scid + "heights", scid + "args", scid + "balances", scid + "code"} {
+			if tx.Bucket([]byte(bName)) == nil {
+				continue
+			}
+			if err = tx.DeleteBucket([]byte(bName)); err != nil {
+				return
+			}
 		}
-		invokedetails := bbs.GetAllSCIDInvokeDetailsBySigner(k, addr)
-		if len(invokedetails) > 0 {
-			if !idExist(scids, k) {
-				scids = append(scids, k)
+
+		if len(heights) > 0 {
+			b, err := tx.CreateBucketIfNotExists([]byte(scid + "heights"))
+			if err != nil {
+				return fmt.Errorf("bucket: %s", err)
 			}
+			hbytes, err := json.Marshal(heights)
+			if err != nil {
+				return fmt.Errorf("[resetSCIDHistory] could not marshal interaction heights: %v", err)
+			}
+			return b.Put([]byte(scid), hbytes)
 		}
-	}
+		return
+	})
 
-	return scids
+	return
 }
 
 // Writes to disk RAM-stored data
@@ -1313,7 +3168,7 @@ func (bbs *BboltStore) StoreAltDBInput(treenames []string, altdb *GravitonStore)
 		}
 	}
 
-	err = bbs.DB.Update(func(tx *bolt.Tx) (err error) {
+	err = bbs.update(func(tx *bolt.Tx) (err error) {
 		for tn, v := range tck {
 			b, err := tx.CreateBucketIfNotExists([]byte(tn))
 			if err != nil {
@@ -1330,3 +3185,73 @@ func (bbs *BboltStore) StoreAltDBInput(treenames []string, altdb *GravitonStore)
 
 	return nil
 }
+
+// Runs fn against a batch copy of the store that shares one bbolt write tx. All writes within fn are committed together once fn returns. If fn returns an err, the tx is rolled back
+func (bbs *BboltStore) Batch(fn func(s Storage) error) (err error) {
+	// Already within a batch, writes will be committed by the outer one
+	if bbs.tx != nil {
+		return fn(bbs)
+	}
+
+	return bbs.DB.Update(func(tx *bolt.Tx) (err error) {
+		return fn(&BboltStore{DB: bbs.DB, DBPath: bbs.DBPath, Closing: bbs.Closing, Buckets: bbs.Buckets, tx: tx})
+	})
+}
+
+// Runs fn within a read tx. Each read within fn is of the db as of the tx, whatever is committed meanwhile, and writes return ErrReadOnlyView
+func (bbs *BboltStore) View(fn func(s Storage) error) (err error) {
+	// Already within a view or batch, reads already see a consistent state
+	if bbs.tx != nil {
+		return fn(bbs)
+	}
+
+	return bbs.DB.View(func(tx *bolt.Tx) (err error) {
+		return fn(&BboltStore{DB: bbs.DB, DBPath: bbs.DBPath, Closing: bbs.Closing, Buckets: bbs.Buckets, tx: tx})
+	})
+}
+
+// Runs fn on each key/value of a given bucket
+func (bbs *BboltStore) WalkTree(treename string, fn func(k []byte, v []byte) error) (err error) {
+	return bbs.view(func(tx *bolt.Tx) (err error) {
+		b := tx.Bucket([]byte(treename))
+		if b == nil {
+			return
+		}
+
+		return b.ForEach(fn)
+	})
+}
+
+// Runs a write fn within its own tx, or the batch tx if within a batch
+func (bbs *BboltStore) update(fn func(tx *bolt.Tx) error) (err error) {
+	if bbs.tx != nil {
+		if !bbs.tx.Writable() {
+			return ErrReadOnlyView
+		}
+		return fn(bbs.tx)
+	}
+
+	return bbs.DB.Update(fn)
+}
+
+// Runs a read fn within its own tx, or the batch tx if within a batch so that uncommitted writes are seen
+func (bbs *BboltStore) view(fn func(tx *bolt.Tx) error) (err error) {
+	if bbs.tx != nil {
+		return fn(bbs.tx)
+	}
+
+	return bbs.DB.View(fn)
+}
+
+// Returns whether the store is closing or closed
+func (bbs *BboltStore) IsClosing() bool {
+	return bbs.Closing
+}
+
+// Closes out the db cleanly
+func (bbs *BboltStore) Close() (err error) {
+	bbs.Closing = true
+	bbs.DB.Sync()
+
+	return bbs.DB.Close()
+}
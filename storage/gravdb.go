@@ -1,8 +1,10 @@
 package storage
 
 import (
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"math"
 	"sort"
 	"strconv"
 	"strings"
@@ -20,8 +22,15 @@ type GravitonStore struct {
 	migrating     int
 	DBMaxSnapshot uint64
 	DBMigrateWait time.Duration
-	Writing       int
 	Closing       bool
+	batch         *gravBatch
+	view          *graviton.Snapshot // set within View(), all trees are read from it
+}
+
+// Trees that have been loaded and written to within a Batch(). They are committed together once the batch is done
+type gravBatch struct {
+	trees map[string]*graviton.Tree
+	dirty map[string]bool
 }
 
 type TreeKV struct {
@@ -70,7 +79,7 @@ func NewGravDBRAM(dbmigratewait string) (*GravitonStore, error) {
 }
 
 // Stores gnomon's last indexed height - this is for stateful stores on close and reference on open
-func (g *GravitonStore) StoreLastIndexHeight(last_indexedheight int64, nocommit bool) (tree *graviton.Tree, changes bool, err error) {
+func (g *GravitonStore) StoreLastIndexHeight(last_indexedheight int64) (changes bool, err error) {
 	store := g.DB
 	ss, err := store.LoadSnapshot(0) // load most recent snapshot
 	if err != nil {
@@ -90,7 +99,7 @@ func (g *GravitonStore) StoreLastIndexHeight(last_indexedheight int64, nocommit
 		}
 	}
 
-	tree, _ = ss.GetTree("stats")
+	tree, _ := g.getTree(ss, "stats")
 	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
 	if tree == nil {
 		var terr error
@@ -102,19 +111,243 @@ func (g *GravitonStore) StoreLastIndexHeight(last_indexedheight int64, nocommit
 		tree, terr = prevss.GetTree("stats")
 		if tree == nil {
 			logger.Errorf("[Graviton] ERROR: %v", terr)
-			return tree, changes, terr
+			return changes, terr
 		}
 	}
 	tree.Put([]byte("lastindexedheight"), []byte(topoheight)) // insert a value
 	changes = true
-	if !nocommit {
-		_, cerr := graviton.Commit(tree)
-		if cerr != nil {
-			logger.Errorf("[Graviton] ERROR: %v", cerr)
-			return tree, changes, cerr
+	_, cerr := g.commitTrees(tree)
+	if cerr != nil {
+		logger.Errorf("[Graviton] ERROR: %v", cerr)
+		return changes, cerr
+	}
+	return changes, nil
+}
+
+// Stores the earliest topoheight the index holds data from, i.e. the first block available on a pruned daemon
+func (g *GravitonStore) StoreEarliestHeight(topoheight int64) (changes bool, err error) {
+	store := g.DB
+	ss, err := store.LoadSnapshot(0) // load most recent snapshot
+	if err != nil {
+		return
+	}
+
+	// Check for g.migrating, if so sleep for g.DBMigrateWait ms
+	for g.migrating == 1 {
+		logger.Debugf("[StoreEarliestHeight] G is migrating... sleeping for %v...", g.DBMigrateWait)
+		time.Sleep(g.DBMigrateWait)
+		store = g.DB
+		ss, err = store.LoadSnapshot(0) // load most recent snapshot
+		if err != nil {
+			return
+		}
+	}
+
+	tree, _ := g.getTree(ss, "stats")
+	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
+	if tree == nil {
+		var terr error
+		logger.Errorf("[Graviton-StoreEarliestHeight] ERROR: Tree is nil for 'stats'. Attempting to rollback 1 snapshot")
+		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
+		if preverr != nil {
+			return changes, preverr
+		}
+		tree, terr = prevss.GetTree("stats")
+		if tree == nil {
+			logger.Errorf("[Graviton] ERROR: %v", terr)
+			return changes, terr
+		}
+	}
+	tree.Put([]byte("earliestheight"), []byte(strconv.FormatInt(topoheight, 10)))
+	changes = true
+	_, cerr := g.commitTrees(tree)
+	if cerr != nil {
+		logger.Errorf("[Graviton] ERROR: %v", cerr)
+		return changes, cerr
+	}
+	return changes, nil
+}
+
+// Gets the earliest topoheight the index holds data from, 0 if the index is not bounded by a pruned daemon
+func (g *GravitonStore) GetEarliestHeight() (topoheight int64) {
+	store := g.DB
+	ss, err := store.LoadSnapshot(0) // load most recent snapshot
+	if err != nil {
+		return
+	}
+
+	tree, _ := g.getTree(ss, "stats")
+	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
+	if tree == nil {
+		var terr error
+		logger.Errorf("[Graviton-GetEarliestHeight] ERROR: Tree is nil for 'stats'. Attempting to rollback 1 snapshot")
+		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
+		if preverr != nil {
+			return
+		}
+		tree, terr = prevss.GetTree("stats")
+		if tree == nil {
+			logger.Errorf("[Graviton] ERROR: %v", terr)
+			return
+		}
+	}
+
+	v, _ := tree.Get([]byte("earliestheight"))
+	if v != nil {
+		topoheight, _ = strconv.ParseInt(string(v), 10, 64)
+	}
+
+	return
+}
+
+// Stores the format of the stored SC variable history, see SCIDVariableFormat
+func (g *GravitonStore) StoreVariableFormat(format int64) (changes bool, err error) {
+	store := g.DB
+	ss, err := store.LoadSnapshot(0) // load most recent snapshot
+	if err != nil {
+		return
+	}
+
+	// Check for g.migrating, if so sleep for g.DBMigrateWait ms
+	for g.migrating == 1 {
+		logger.Debugf("[StoreVariableFormat] G is migrating... sleeping for %v...", g.DBMigrateWait)
+		time.Sleep(g.DBMigrateWait)
+		store = g.DB
+		ss, err = store.LoadSnapshot(0) // load most recent snapshot
+		if err != nil {
+			return
+		}
+	}
+
+	tree, _ := g.getTree(ss, "stats")
+	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
+	if tree == nil {
+		var terr error
+		logger.Errorf("[Graviton-StoreVariableFormat] ERROR: Tree is nil for 'stats'. Attempting to rollback 1 snapshot")
+		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
+		if preverr != nil {
+			return changes, preverr
+		}
+		tree, terr = prevss.GetTree("stats")
+		if tree == nil {
+			logger.Errorf("[Graviton] ERROR: %v", terr)
+			return changes, terr
+		}
+	}
+	tree.Put([]byte("varsformat"), []byte(strconv.FormatInt(format, 10)))
+	changes = true
+	_, cerr := g.commitTrees(tree)
+	if cerr != nil {
+		logger.Errorf("[Graviton] ERROR: %v", cerr)
+		return changes, cerr
+	}
+	return changes, nil
+}
+
+// Gets the format of the stored SC variable history, 0 if it has not been stored (prior to SCIDVariableFormat)
+func (g *GravitonStore) GetVariableFormat() (format int64) {
+	store := g.DB
+	ss, err := store.LoadSnapshot(0) // load most recent snapshot
+	if err != nil {
+		return
+	}
+
+	tree, _ := g.getTree(ss, "stats")
+	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
+	if tree == nil {
+		var terr error
+		logger.Errorf("[Graviton-GetVariableFormat] ERROR: Tree is nil for 'stats'. Attempting to rollback 1 snapshot")
+		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
+		if preverr != nil {
+			return
+		}
+		tree, terr = prevss.GetTree("stats")
+		if tree == nil {
+			logger.Errorf("[Graviton] ERROR: %v", terr)
+			return
+		}
+	}
+
+	v, _ := tree.Get([]byte("varsformat"))
+	if v != nil {
+		format, _ = strconv.ParseInt(string(v), 10, 64)
+	}
+
+	return
+}
+
+// Stores the set of templates the indexed scids were last classified against
+func (g *GravitonStore) StoreTemplateSet(set string) (changes bool, err error) {
+	store := g.DB
+	ss, err := store.LoadSnapshot(0) // load most recent snapshot
+	if err != nil {
+		return
+	}
+
+	// Check for g.migrating, if so sleep for g.DBMigrateWait ms
+	for g.migrating == 1 {
+		logger.Debugf("[StoreTemplateSet] G is migrating... sleeping for %v...", g.DBMigrateWait)
+		time.Sleep(g.DBMigrateWait)
+		store = g.DB
+		ss, err = store.LoadSnapshot(0) // load most recent snapshot
+		if err != nil {
+			return
+		}
+	}
+
+	tree, _ := g.getTree(ss, "stats")
+	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
+	if tree == nil {
+		var terr error
+		logger.Errorf("[Graviton-StoreTemplateSet] ERROR: Tree is nil for 'stats'. Attempting to rollback 1 snapshot")
+		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
+		if preverr != nil {
+			return changes, preverr
+		}
+		tree, terr = prevss.GetTree("stats")
+		if tree == nil {
+			logger.Errorf("[Graviton] ERROR: %v", terr)
+			return changes, terr
+		}
+	}
+	tree.Put([]byte("templateset"), []byte(set))
+	changes = true
+	_, cerr := g.commitTrees(tree)
+	if cerr != nil {
+		logger.Errorf("[Graviton] ERROR: %v", cerr)
+		return changes, cerr
+	}
+	return changes, nil
+}
+
+// Gets the set of templates the indexed scids were last classified against, "" if they have not been
+func (g *GravitonStore) GetTemplateSet() (set string) {
+	store := g.DB
+	ss, err := store.LoadSnapshot(0) // load most recent snapshot
+	if err != nil {
+		return
+	}
+
+	tree, _ := g.getTree(ss, "stats")
+	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
+	if tree == nil {
+		var terr error
+		logger.Errorf("[Graviton-GetTemplateSet] ERROR: Tree is nil for 'stats'. Attempting to rollback 1 snapshot")
+		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
+		if preverr != nil {
+			return
+		}
+		tree, terr = prevss.GetTree("stats")
+		if tree == nil {
+			logger.Errorf("[Graviton] ERROR: %v", terr)
+			return
 		}
 	}
-	return tree, changes, nil
+
+	v, _ := tree.Get([]byte("templateset"))
+	set = string(v)
+
+	return
 }
 
 // Gets gnomon's last indexed height - this is for stateful stores on close and reference on open
@@ -136,7 +369,7 @@ func (g *GravitonStore) GetLastIndexHeight() (topoheight int64, err error) {
 		}
 	}
 
-	tree, _ := ss.GetTree("stats") // use or create tree named by poolhost in config
+	tree, _ := g.getTree(ss, "stats") // use or create tree named by poolhost in config
 	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
 	if tree == nil {
 		var terr error
@@ -168,7 +401,7 @@ func (g *GravitonStore) GetLastIndexHeight() (topoheight int64, err error) {
 }
 
 // Stores gnomon's txcount by a given txType - this is for stateful stores on close and reference on open
-func (g *GravitonStore) StoreTxCount(count int64, txType string, nocommit bool) (tree *graviton.Tree, changes bool, err error) {
+func (g *GravitonStore) StoreTxCount(count int64, txType string) (changes bool, err error) {
 	store := g.DB
 	ss, err := store.LoadSnapshot(0) // load most recent snapshot
 	if err != nil {
@@ -190,31 +423,29 @@ func (g *GravitonStore) StoreTxCount(count int64, txType string, nocommit bool)
 
 	key := txType + "txcount"
 
-	tree, _ = ss.GetTree("stats")
+	tree, _ := g.getTree(ss, "stats")
 	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
 	if tree == nil {
 		var terr error
 		logger.Errorf("[Graviton-StoreTxCount] ERROR: Tree is nil for 'stats'. Attempting to rollback 1 snapshot")
 		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
 		if preverr != nil {
-			return tree, changes, preverr
+			return changes, preverr
 		}
 		tree, terr = prevss.GetTree("stats")
 		if tree == nil {
 			logger.Errorf("[Graviton] ERROR: %v", terr)
-			return tree, changes, terr
+			return changes, terr
 		}
 	}
 	tree.Put([]byte(key), []byte(txCount)) // insert a value
 	changes = true
-	if !nocommit {
-		_, cerr := graviton.Commit(tree)
-		if cerr != nil {
-			logger.Errorf("[Graviton] ERROR: %v", cerr)
-			return tree, changes, cerr
-		}
+	_, cerr := g.commitTrees(tree)
+	if cerr != nil {
+		logger.Errorf("[Graviton] ERROR: %v", cerr)
+		return changes, cerr
 	}
-	return tree, changes, nil
+	return changes, nil
 }
 
 // Gets gnomon's txcount by a given txType - this is for stateful stores on close and reference on open
@@ -236,7 +467,7 @@ func (g *GravitonStore) GetTxCount(txType string) int64 {
 		}
 	}
 
-	tree, _ := ss.GetTree("stats") // use or create tree named by poolhost in config
+	tree, _ := g.getTree(ss, "stats") // use or create tree named by poolhost in config
 	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
 	if tree == nil {
 		var terr error
@@ -269,8 +500,78 @@ func (g *GravitonStore) GetTxCount(txType string) int64 {
 	return 0
 }
 
+// Stores the tx counts added by the blocks at height, so they can be taken back off the totals if the blocks are rolled back. Counts more than block_hashes_kept below height are trimmed, as with block hashes
+func (g *GravitonStore) StoreTxCountsByHeight(height int64, counts map[string]int64) (changes bool, err error) {
+	store := g.DB
+	ss, err := store.LoadSnapshot(0) // load most recent snapshot
+	if err != nil {
+		return
+	}
+
+	// Check for g.migrating, if so sleep for g.DBMigrateWait ms
+	for g.migrating == 1 {
+		logger.Debugf("[StoreTxCountsByHeight] G is migrating... sleeping for %v...", g.DBMigrateWait)
+		time.Sleep(g.DBMigrateWait)
+		store = g.DB
+		ss, err = store.LoadSnapshot(0) // load most recent snapshot
+		if err != nil {
+			return
+		}
+	}
+
+	treename := "txcountheights"
+	tree, _ := g.getTree(ss, treename)
+	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
+	if tree == nil {
+		var terr error
+		logger.Errorf("[Graviton-StoreTxCountsByHeight] ERROR: Tree is nil for '%v'. Attempting to rollback 1 snapshot", treename)
+		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
+		if preverr != nil {
+			return changes, preverr
+		}
+		tree, terr = prevss.GetTree(treename)
+		if tree == nil {
+			logger.Errorf("[Graviton] ERROR: %v", terr)
+			return changes, terr
+		}
+	}
+
+	key := []byte(strconv.FormatInt(height, 10))
+	currCounts := make(map[string]int64)
+	if v, gerr := tree.Get(key); gerr == nil {
+		_ = json.Unmarshal(v, &currCounts)
+	}
+	for txType, count := range counts {
+		currCounts[txType] += count
+	}
+	newCounts, err := json.Marshal(currCounts)
+	if err != nil {
+		return changes, fmt.Errorf("[Graviton] could not marshal tx counts: %v", err)
+	}
+	tree.Put(key, newCounts)
+	changes = true
+
+	var trim [][]byte
+	c := tree.Cursor()
+	for k, _, err := c.First(); err == nil; k, _, err = c.Next() {
+		if h, perr := strconv.ParseInt(string(k), 10, 64); perr == nil && h < height-block_hashes_kept {
+			trim = append(trim, k)
+		}
+	}
+	for _, k := range trim {
+		tree.Delete(k)
+	}
+
+	_, cerr := g.commitTrees(tree)
+	if cerr != nil {
+		logger.Errorf("[Graviton] ERROR: %v", cerr)
+		return changes, cerr
+	}
+	return changes, nil
+}
+
 // Stores the owner (who deployed it) of a given scid
-func (g *GravitonStore) StoreOwner(scid string, owner string, nocommit bool) (tree *graviton.Tree, changes bool, err error) {
+func (g *GravitonStore) StoreOwner(scid string, owner string) (changes bool, err error) {
 	store := g.DB
 	ss, err := store.LoadSnapshot(0) // load most recent snapshot
 	if err != nil {
@@ -288,31 +589,29 @@ func (g *GravitonStore) StoreOwner(scid string, owner string, nocommit bool) (tr
 		}
 	}
 
-	tree, _ = ss.GetTree("owner")
+	tree, _ := g.getTree(ss, "owner")
 	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
 	if tree == nil {
 		var terr error
 		logger.Errorf("[Graviton-StoreOwner] ERROR: Tree is nil for 'owner'. Attempting to rollback 1 snapshot")
 		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
 		if preverr != nil {
-			return tree, changes, preverr
+			return changes, preverr
 		}
 		tree, terr = prevss.GetTree("owner")
 		if tree == nil {
 			logger.Errorf("[Graviton] ERROR: %v", terr)
-			return tree, changes, terr
+			return changes, terr
 		}
 	}
 	tree.Put([]byte(scid), []byte(owner)) // insert a value
 	changes = true
-	if !nocommit {
-		_, cerr := graviton.Commit(tree)
-		if cerr != nil {
-			logger.Errorf("[Graviton] ERROR: %v", cerr)
-			return tree, changes, cerr
-		}
+	_, cerr := g.commitTrees(tree)
+	if cerr != nil {
+		logger.Errorf("[Graviton] ERROR: %v", cerr)
+		return changes, cerr
 	}
-	return tree, changes, nil
+	return changes, nil
 }
 
 // Returns the owner (who deployed it) of a given scid
@@ -334,7 +633,7 @@ func (g *GravitonStore) GetOwner(scid string) string {
 		}
 	}
 
-	tree, _ := ss.GetTree("owner") // use or create tree named by poolhost in config
+	tree, _ := g.getTree(ss, "owner") // use or create tree named by poolhost in config
 	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
 	if tree == nil {
 		var terr error
@@ -371,7 +670,7 @@ func (g *GravitonStore) GetAllOwnersAndSCIDs() (results map[string]string) {
 	if err != nil {
 		return
 	}
-	tree, _ := ss.GetTree("owner")
+	tree, _ := g.getTree(ss, "owner")
 	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
 	if tree == nil {
 		var terr error
@@ -396,8 +695,8 @@ func (g *GravitonStore) GetAllOwnersAndSCIDs() (results map[string]string) {
 	return
 }
 
-// Stores all normal txs with SCIDs and their respective ring members for future balance/interaction reference
-func (g *GravitonStore) StoreNormalTxWithSCIDByAddr(addr string, normTxWithSCID *structures.NormalTXWithSCIDParse, nocommit bool) (tree *graviton.Tree, changes bool, err error) {
+// Stores the id of the template a given scid's code matches
+func (g *GravitonStore) StoreSCIDTemplate(scid string, templateID string) (changes bool, err error) {
 	store := g.DB
 	ss, err := store.LoadSnapshot(0) // load most recent snapshot
 	if err != nil {
@@ -406,7 +705,7 @@ func (g *GravitonStore) StoreNormalTxWithSCIDByAddr(addr string, normTxWithSCID
 
 	// Check for g.migrating, if so sleep for g.DBMigrateWait ms
 	for g.migrating == 1 {
-		logger.Debugf("[StoreNormalTxWithSCIDByAddr] G is migrating... sleeping for %v...", g.DBMigrateWait)
+		logger.Debugf("[StoreSCIDTemplate] G is migrating... sleeping for %v...", g.DBMigrateWait)
 		time.Sleep(g.DBMigrateWait)
 		store = g.DB
 		ss, err = store.LoadSnapshot(0) // load most recent snapshot
@@ -415,118 +714,78 @@ func (g *GravitonStore) StoreNormalTxWithSCIDByAddr(addr string, normTxWithSCID
 		}
 	}
 
-	treename := "normaltxwithscid"
-	tree, _ = ss.GetTree(treename)
+	tree, _ := g.getTree(ss, "templates")
 	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
 	if tree == nil {
 		var terr error
-		logger.Errorf("[Graviton-StoreNormalTxWithSCIDByAddr] ERROR: Tree is nil for 'normaltxwithscid'. Attempting to rollback 1 snapshot")
+		logger.Errorf("[Graviton-StoreSCIDTemplate] ERROR: Tree is nil for 'templates'. Attempting to rollback 1 snapshot")
 		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
 		if preverr != nil {
-			return tree, changes, preverr
+			return changes, preverr
 		}
-		tree, terr = prevss.GetTree("normaltxwithscid")
+		tree, terr = prevss.GetTree("templates")
 		if tree == nil {
 			logger.Errorf("[Graviton] ERROR: %v", terr)
-			return tree, changes, terr
+			return changes, terr
 		}
 	}
-	key := addr
-	currNormTxsWithSCID, err := tree.Get([]byte(key))
-	var normTxsWithSCID []*structures.NormalTXWithSCIDParse
-
-	var newNormTxsWithSCID []byte
+	tree.Put([]byte(scid), []byte(templateID)) // insert a value
+	changes = true
+	_, cerr := g.commitTrees(tree)
+	if cerr != nil {
+		logger.Errorf("[Graviton] ERROR: %v", cerr)
+		return changes, cerr
+	}
+	return changes, nil
+}
 
+// Returns the id of the template a given scid's code matches, or "" if it did not match one
+func (g *GravitonStore) GetSCIDTemplate(scid string) string {
+	store := g.DB
+	ss, err := store.LoadSnapshot(0) // load most recent snapshot
 	if err != nil {
-		normTxsWithSCID = append(normTxsWithSCID, normTxWithSCID)
-	} else {
-		// Retrieve value and conovert to SCIDInteractionHeight, so that you can manipulate and update db
-		_ = json.Unmarshal(currNormTxsWithSCID, &normTxsWithSCID)
-
-		for _, v := range normTxsWithSCID {
-			if v.Txid == normTxWithSCID.Txid {
-				// Return nil if already exists in array.
-				// Clause for this is in event we pop backwards in time and already have this data stored.
-				// TODO: What if interaction happened on false-chain and pop to retain correct chain. Bad data may be stored here still, as it isn't removed. Need fix for this in future.
-				return tree, changes, nil
-			}
-		}
-
-		normTxsWithSCID = append(normTxsWithSCID, normTxWithSCID)
-	}
-	newNormTxsWithSCID, err = json.Marshal(normTxsWithSCID)
-	if err != nil {
-		return tree, changes, fmt.Errorf("[Graviton] could not marshal normTxsWithSCID info: %v", err)
-	}
-
-	tree.Put([]byte(key), newNormTxsWithSCID)
-	changes = true
-	if !nocommit {
-		_, cerr := graviton.Commit(tree)
-		if cerr != nil {
-			logger.Errorf("[Graviton] ERROR: %v", cerr)
-			return tree, changes, cerr
-		}
-	}
-	return tree, changes, nil
-}
-
-// Returns all normal txs with SCIDs based on a given address
-func (g *GravitonStore) GetAllNormalTxWithSCIDByAddr(addr string) (normTxsWithSCID []*structures.NormalTXWithSCIDParse) {
-	store := g.DB
-	ss, err := store.LoadSnapshot(0) // load most recent snapshot
-	if err != nil {
-		return
+		return ""
 	}
 
-	treename := "normaltxwithscid"
-	tree, _ := ss.GetTree(treename) // use or create tree named by poolhost in config
+	tree, _ := g.getTree(ss, "templates")
 	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
 	if tree == nil {
 		var terr error
-		logger.Errorf("[Graviton-GetAllNormalTxWithSCIDByAddr] ERROR: Tree is nil for 'normaltxwithscid'. Attempting to rollback 1 snapshot")
+		logger.Errorf("[Graviton-GetSCIDTemplate] ERROR: Tree is nil for 'templates'. Attempting to rollback 1 snapshot")
 		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
 		if preverr != nil {
-			return
+			return ""
 		}
-		tree, terr = prevss.GetTree("normaltxwithscid")
+		tree, terr = prevss.GetTree("templates")
 		if tree == nil {
 			logger.Errorf("[Graviton] ERROR: %v", terr)
-			return
+			return ""
 		}
 	}
-	key := addr
 
-	v, _ := tree.Get([]byte(key))
-
-	if v != nil {
-		_ = json.Unmarshal(v, &normTxsWithSCID)
-		return
-	}
+	v, _ := tree.Get([]byte(scid))
 
-	return nil
+	return string(v)
 }
 
-// Returns all normal txs with SCIDs based on a given SCID
-func (g *GravitonStore) GetAllNormalTxWithSCIDBySCID(scid string) (normTxsWithSCID []*structures.NormalTXWithSCIDParse) {
-	var resultset []string
+// Returns all scids whose code matches a given template id
+func (g *GravitonStore) GetSCIDsByTemplate(templateID string) (scids []string) {
 	store := g.DB
 	ss, err := store.LoadSnapshot(0) // load most recent snapshot
 	if err != nil {
 		return
 	}
 
-	treename := "normaltxwithscid"
-	tree, _ := ss.GetTree(treename) // use or create tree named by poolhost in config
+	tree, _ := g.getTree(ss, "templates")
 	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
 	if tree == nil {
 		var terr error
-		logger.Errorf("[Graviton-GetAllNormalTxWithSCIDBySCID] ERROR: Tree is nil for 'normaltxwithscid'. Attempting to rollback 1 snapshot")
+		logger.Errorf("[Graviton-GetSCIDsByTemplate] ERROR: Tree is nil for 'templates'. Attempting to rollback 1 snapshot")
 		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
 		if preverr != nil {
 			return
 		}
-		tree, terr = prevss.GetTree("normaltxwithscid")
+		tree, terr = prevss.GetTree("templates")
 		if tree == nil {
 			logger.Errorf("[Graviton] ERROR: %v", terr)
 			return
@@ -534,48 +793,26 @@ func (g *GravitonStore) GetAllNormalTxWithSCIDBySCID(scid string) (normTxsWithSC
 	}
 
 	c := tree.Cursor()
-	// Duplicate the LATEST (snapshot 0) to the new DB, this starts the DB over again, but still retaining X number of old DBs for version in future use cases. Here we get the vals before swapping to new db in mem
-	for _, v, err := c.First(); err == nil; _, v, err = c.Next() {
-		var currdetails []*structures.NormalTXWithSCIDParse
-		_ = json.Unmarshal(v, &currdetails)
-		for _, cv := range currdetails {
-			if cv.Scid == scid && !idExist(resultset, cv.Txid) {
-				normTxsWithSCID = append(normTxsWithSCID, cv)
-				resultset = append(resultset, cv.Txid)
-			}
+	for k, v, err := c.First(); err == nil; k, v, err = c.Next() {
+		if string(v) == templateID {
+			scids = append(scids, string(k))
 		}
 	}
 
 	return
 }
 
-// Check if value exists within a string array/slice
-func idExist(s []string, str string) bool {
-	for _, v := range s {
-		if v == str {
-			return true
-		}
-	}
-
-	return false
-}
-
-// Stores all scinvoke details of a given scid
-func (g *GravitonStore) StoreInvokeDetails(scid string, signer string, entrypoint string, topoheight int64, invokedetails *structures.SCTXParse, nocommit bool) (tree *graviton.Tree, changes bool, err error) {
-	confBytes, err := json.Marshal(invokedetails)
-	if err != nil {
-		return &graviton.Tree{}, changes, fmt.Errorf("[StoreInvokeDetails] could not marshal invokedetails info: %v", err)
-	}
-
+// Stores all normal txs with SCIDs and their respective ring members for future balance/interaction reference
+func (g *GravitonStore) StoreNormalTxWithSCIDByAddr(addr string, normTxWithSCID *structures.NormalTXWithSCIDParse) (changes bool, err error) {
 	store := g.DB
 	ss, err := store.LoadSnapshot(0) // load most recent snapshot
 	if err != nil {
 		return
 	}
 
-	// Swap DB at g.DBMaxSnapshot+ commits. Check for g.migrating, if so sleep for g.DBMigrateWait ms
+	// Check for g.migrating, if so sleep for g.DBMigrateWait ms
 	for g.migrating == 1 {
-		logger.Debugf("[StoreInvokeDetails] G is migrating... sleeping for %v...", g.DBMigrateWait)
+		logger.Debugf("[StoreNormalTxWithSCIDByAddr] G is migrating... sleeping for %v...", g.DBMigrateWait)
 		time.Sleep(g.DBMigrateWait)
 		store = g.DB
 		ss, err = store.LoadSnapshot(0) // load most recent snapshot
@@ -584,95 +821,116 @@ func (g *GravitonStore) StoreInvokeDetails(scid string, signer string, entrypoin
 		}
 	}
 
-	// Tree - SCID // (either string or hex - will be []byte in graviton anyways.. may just go with hex)
-	// Key - sender:topoheight:entrypoint // (We know that we can have 1 sender per scid per topoheight - do we need entrypoint appended? does it matter?)
-	tree, _ = ss.GetTree(scid)
+	treename := "normaltxwithscid"
+	tree, _ := g.getTree(ss, treename)
 	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
 	if tree == nil {
 		var terr error
-		logger.Errorf("[Graviton-StoreInvokeDetails] ERROR: Tree is nil for '%v'. Attempting to rollback 1 snapshot", scid)
+		logger.Errorf("[Graviton-StoreNormalTxWithSCIDByAddr] ERROR: Tree is nil for 'normaltxwithscid'. Attempting to rollback 1 snapshot")
 		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
 		if preverr != nil {
-			return tree, changes, preverr
+			return changes, preverr
 		}
-		tree, terr = prevss.GetTree(scid)
+		tree, terr = prevss.GetTree("normaltxwithscid")
 		if tree == nil {
 			logger.Errorf("[Graviton] ERROR: %v", terr)
-			return tree, changes, terr
+			return changes, terr
 		}
 	}
+	key := addr
+	currNormTxsWithSCID, err := tree.Get([]byte(key))
+	var normTxsWithSCID []*structures.NormalTXWithSCIDParse
 
-	txidLen := len(invokedetails.Txid)
-	key := signer + ":" + invokedetails.Txid[0:3] + invokedetails.Txid[txidLen-3:txidLen] + ":" + strconv.FormatInt(topoheight, 10) + ":" + entrypoint
+	var newNormTxsWithSCID []byte
 
-	tree.Put([]byte(key), confBytes) // insert a value
-	changes = true
-	if !nocommit {
-		_, cerr := graviton.Commit(tree)
-		if cerr != nil {
-			logger.Errorf("[Graviton] ERROR: %v", cerr)
-			return tree, changes, cerr
+	if err != nil {
+		normTxsWithSCID = append(normTxsWithSCID, normTxWithSCID)
+	} else {
+		// Retrieve value and conovert to SCIDInteractionHeight, so that you can manipulate and update db
+		_ = json.Unmarshal(currNormTxsWithSCID, &normTxsWithSCID)
+
+		for _, v := range normTxsWithSCID {
+			if v.Txid == normTxWithSCID.Txid {
+				// Return nil if already exists in array.
+				// Clause for this is in event we pop backwards in time and already have this data stored.
+				// Data stored from a false-chain is removed via RollbackToHeight() when a reorg is detected.
+				return changes, nil
+			}
 		}
+
+		normTxsWithSCID = append(normTxsWithSCID, normTxWithSCID)
+	}
+	newNormTxsWithSCID, err = json.Marshal(normTxsWithSCID)
+	if err != nil {
+		return changes, fmt.Errorf("[Graviton] could not marshal normTxsWithSCID info: %v", err)
+	}
+
+	tree.Put([]byte(key), newNormTxsWithSCID)
+	changes = true
+	_, cerr := g.commitTrees(tree)
+	if cerr != nil {
+		logger.Errorf("[Graviton] ERROR: %v", cerr)
+		return changes, cerr
 	}
-	return tree, changes, nil
+	return changes, nil
 }
 
-// Returns all scinvoke calls from a given scid
-func (g *GravitonStore) GetAllSCIDInvokeDetails(scid string) (invokedetails []*structures.SCTXParse) {
+// Returns all normal txs with SCIDs based on a given address
+func (g *GravitonStore) GetAllNormalTxWithSCIDByAddr(addr string) (normTxsWithSCID []*structures.NormalTXWithSCIDParse) {
 	store := g.DB
-	ss, err := store.LoadSnapshot(0)
+	ss, err := store.LoadSnapshot(0) // load most recent snapshot
 	if err != nil {
 		return
 	}
-	tree, _ := ss.GetTree(scid)
+
+	treename := "normaltxwithscid"
+	tree, _ := g.getTree(ss, treename) // use or create tree named by poolhost in config
 	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
 	if tree == nil {
 		var terr error
-		logger.Errorf("[Graviton-GetAllSCIDInvokeDetails] ERROR: Tree is nil for '%v'. Attempting to rollback 1 snapshot", scid)
+		logger.Errorf("[Graviton-GetAllNormalTxWithSCIDByAddr] ERROR: Tree is nil for 'normaltxwithscid'. Attempting to rollback 1 snapshot")
 		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
 		if preverr != nil {
 			return
 		}
-		tree, terr = prevss.GetTree(scid)
+		tree, terr = prevss.GetTree("normaltxwithscid")
 		if tree == nil {
 			logger.Errorf("[Graviton] ERROR: %v", terr)
 			return
 		}
 	}
+	key := addr
 
-	c := tree.Cursor()
-	// Duplicate the LATEST (snapshot 0) to the new DB, this starts the DB over again, but still retaining X number of old DBs for version in future use cases. Here we get the vals before swapping to new db in mem
-	for _, v, err := c.First(); err == nil; _, v, err = c.Next() {
-		var currdetails *structures.SCTXParse
-		_ = json.Unmarshal(v, &currdetails)
-		invokedetails = append(invokedetails, currdetails)
-	}
+	v, _ := tree.Get([]byte(key))
 
-	// Sort heights so most recent is index 0 [if preferred reverse, just swap > with <]
-	sort.SliceStable(invokedetails, func(i, j int) bool {
-		return invokedetails[i].Height < invokedetails[j].Height
-	})
+	if v != nil {
+		_ = json.Unmarshal(v, &normTxsWithSCID)
+		return
+	}
 
-	return
+	return nil
 }
 
-// Retruns all scinvoke calls from a given scid that match a given entrypoint
-func (g *GravitonStore) GetAllSCIDInvokeDetailsByEntrypoint(scid string, entrypoint string) (invokedetails []*structures.SCTXParse) {
+// Returns all normal txs with SCIDs based on a given SCID
+func (g *GravitonStore) GetAllNormalTxWithSCIDBySCID(scid string) (normTxsWithSCID []*structures.NormalTXWithSCIDParse) {
+	var resultset []string
 	store := g.DB
-	ss, err := store.LoadSnapshot(0)
+	ss, err := store.LoadSnapshot(0) // load most recent snapshot
 	if err != nil {
 		return
 	}
-	tree, _ := ss.GetTree(scid)
+
+	treename := "normaltxwithscid"
+	tree, _ := g.getTree(ss, treename) // use or create tree named by poolhost in config
 	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
 	if tree == nil {
 		var terr error
-		logger.Errorf("[Graviton-GetAllSCIDInvokeDetailsByEntrypoint] ERROR: Tree is nil for '%v'. Attempting to rollback 1 snapshot", scid)
+		logger.Errorf("[Graviton-GetAllNormalTxWithSCIDBySCID] ERROR: Tree is nil for 'normaltxwithscid'. Attempting to rollback 1 snapshot")
 		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
 		if preverr != nil {
 			return
 		}
-		tree, terr = prevss.GetTree(scid)
+		tree, terr = prevss.GetTree("normaltxwithscid")
 		if tree == nil {
 			logger.Errorf("[Graviton] ERROR: %v", terr)
 			return
@@ -682,70 +940,58 @@ func (g *GravitonStore) GetAllSCIDInvokeDetailsByEntrypoint(scid string, entrypo
 	c := tree.Cursor()
 	// Duplicate the LATEST (snapshot 0) to the new DB, this starts the DB over again, but still retaining X number of old DBs for version in future use cases. Here we get the vals before swapping to new db in mem
 	for _, v, err := c.First(); err == nil; _, v, err = c.Next() {
-		var currdetails *structures.SCTXParse
+		var currdetails []*structures.NormalTXWithSCIDParse
 		_ = json.Unmarshal(v, &currdetails)
-		if currdetails.Entrypoint == entrypoint {
-			invokedetails = append(invokedetails, currdetails)
+		for _, cv := range currdetails {
+			if cv.Scid == scid && !idExist(resultset, cv.Txid) {
+				normTxsWithSCID = append(normTxsWithSCID, cv)
+				resultset = append(resultset, cv.Txid)
+			}
 		}
 	}
 
-	// Sort heights so most recent is index 0 [if preferred reverse, just swap > with <]
-	sort.SliceStable(invokedetails, func(i, j int) bool {
-		return invokedetails[i].Height < invokedetails[j].Height
-	})
-
 	return
 }
 
-// Returns all scinvoke calls from a given scid that match a given signer
-func (g *GravitonStore) GetAllSCIDInvokeDetailsBySigner(scid string, signerPart string) (invokedetails []*structures.SCTXParse) {
+// Gets all normal txs with scid payloads by address
+func (g *GravitonStore) GetAllNormalTxWithSCID() map[string][]*structures.NormalTXWithSCIDParse {
+	results := make(map[string][]*structures.NormalTXWithSCIDParse)
+
 	store := g.DB
-	ss, err := store.LoadSnapshot(0)
+	ss, err := store.LoadSnapshot(0) // load most recent snapshot
 	if err != nil {
-		return
+		return results
 	}
-	tree, _ := ss.GetTree(scid)
+
+	treename := "normaltxwithscid"
+	tree, _ := g.getTree(ss, treename)
 	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
 	if tree == nil {
 		var terr error
-		logger.Errorf("[Graviton-GetAllSCIDInvokeDetailsBySigner] ERROR: Tree is nil for '%v'. Attempting to rollback 1 snapshot", scid)
+		logger.Errorf("[Graviton-GetAllNormalTxWithSCID] ERROR: Tree is nil for 'normaltxwithscid'. Attempting to rollback 1 snapshot")
 		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
 		if preverr != nil {
-			return
+			return results
 		}
-		tree, terr = prevss.GetTree(scid)
+		tree, terr = prevss.GetTree("normaltxwithscid")
 		if tree == nil {
 			logger.Errorf("[Graviton] ERROR: %v", terr)
-			return
+			return results
 		}
 	}
 
 	c := tree.Cursor()
-	// Duplicate the LATEST (snapshot 0) to the new DB, this starts the DB over again, but still retaining X number of old DBs for version in future use cases. Here we get the vals before swapping to new db in mem
-	for _, v, err := c.First(); err == nil; _, v, err = c.Next() {
-		var currdetails *structures.SCTXParse
+	for k, v, err := c.First(); err == nil; k, v, err = c.Next() {
+		var currdetails []*structures.NormalTXWithSCIDParse
 		_ = json.Unmarshal(v, &currdetails)
-		split := strings.Split(currdetails.Sender, signerPart)
-		if len(split) > 1 {
-			invokedetails = append(invokedetails, currdetails)
-		}
+		results[string(k)] = currdetails
 	}
 
-	// Sort heights so most recent is index 0 [if preferred reverse, just swap > with <]
-	sort.SliceStable(invokedetails, func(i, j int) bool {
-		return invokedetails[i].Height < invokedetails[j].Height
-	})
-
-	return
+	return results
 }
 
-// Stores simple getinfo polling from the daemon
-func (g *GravitonStore) StoreGetInfoDetails(getinfo *structures.GetInfo, nocommit bool) (tree *graviton.Tree, changes bool, err error) {
-	confBytes, err := json.Marshal(getinfo)
-	if err != nil {
-		return &graviton.Tree{}, changes, fmt.Errorf("[StoreGetInfoDetails] could not marshal getinfo info: %v", err)
-	}
-
+// Stores the registration tx of an address. Only the first seen (lowest height) registration of an address is kept
+func (g *GravitonStore) StoreRegTx(regtx *structures.RegTXParse) (changes bool, err error) {
 	store := g.DB
 	ss, err := store.LoadSnapshot(0) // load most recent snapshot
 	if err != nil {
@@ -754,7 +1000,7 @@ func (g *GravitonStore) StoreGetInfoDetails(getinfo *structures.GetInfo, nocommi
 
 	// Check for g.migrating, if so sleep for g.DBMigrateWait ms
 	for g.migrating == 1 {
-		logger.Debugf("[StoreGetInfoDetails] G is migrating... sleeping for %v...", g.DBMigrateWait)
+		logger.Debugf("[StoreRegTx] G is migrating... sleeping for %v...", g.DBMigrateWait)
 		time.Sleep(g.DBMigrateWait)
 		store = g.DB
 		ss, err = store.LoadSnapshot(0) // load most recent snapshot
@@ -763,519 +1009,1959 @@ func (g *GravitonStore) StoreGetInfoDetails(getinfo *structures.GetInfo, nocommi
 		}
 	}
 
-	tree, _ = ss.GetTree("getinfo")
+	treename := "registrations"
+	tree, _ := g.getTree(ss, treename)
 	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
 	if tree == nil {
 		var terr error
-		logger.Errorf("[Graviton-StoreGetInfoDetails] ERROR: Tree is nil for 'getinfo'. Attempting to rollback 1 snapshot")
+		logger.Errorf("[Graviton-StoreRegTx] ERROR: Tree is nil for 'registrations'. Attempting to rollback 1 snapshot")
 		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
 		if preverr != nil {
-			return tree, changes, preverr
+			return changes, preverr
 		}
-		tree, terr = prevss.GetTree("getinfo")
+		tree, terr = prevss.GetTree("registrations")
 		if tree == nil {
 			logger.Errorf("[Graviton] ERROR: %v", terr)
-			return tree, changes, terr
+			return changes, terr
 		}
 	}
-	key := "getinfo"
-	tree.Put([]byte(key), confBytes) // insert a value
-	changes = true
-	if !nocommit {
-		_, cerr := graviton.Commit(tree)
-		if cerr != nil {
-			logger.Errorf("[Graviton] ERROR: %v", cerr)
-			return tree, changes, cerr
+	key := regtx.Address
+
+	if currRegTx, gerr := tree.Get([]byte(key)); gerr == nil {
+		var curr *structures.RegTXParse
+		_ = json.Unmarshal(currRegTx, &curr)
+		if curr != nil && curr.Height <= regtx.Height {
+			return changes, nil
 		}
 	}
-	return tree, changes, nil
+
+	newRegTx, err := json.Marshal(regtx)
+	if err != nil {
+		return changes, fmt.Errorf("[Graviton] could not marshal regtx info: %v", err)
+	}
+
+	tree.Put([]byte(key), newRegTx)
+	changes = true
+	_, cerr := g.commitTrees(tree)
+	if cerr != nil {
+		logger.Errorf("[Graviton] ERROR: %v", cerr)
+		return changes, cerr
+	}
+	return changes, nil
 }
 
-// Returns simple getinfo polling from the daemon
-func (g *GravitonStore) GetGetInfoDetails() (getinfo *structures.GetInfo) {
+// Returns the registration tx of a given address
+func (g *GravitonStore) GetRegTxByAddress(addr string) (regtx *structures.RegTXParse) {
 	store := g.DB
 	ss, err := store.LoadSnapshot(0) // load most recent snapshot
 	if err != nil {
 		return
 	}
 
-	tree, _ := ss.GetTree("getinfo") // use or create tree named by poolhost in config
+	treename := "registrations"
+	tree, _ := g.getTree(ss, treename)
 	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
 	if tree == nil {
 		var terr error
-		logger.Errorf("[Graviton-GetGetInfoDetails] ERROR: Tree is nil for 'getinfo'. Attempting to rollback 1 snapshot")
+		logger.Errorf("[Graviton-GetRegTxByAddress] ERROR: Tree is nil for 'registrations'. Attempting to rollback 1 snapshot")
 		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
 		if preverr != nil {
 			return
 		}
-		tree, terr = prevss.GetTree("getinfo")
+		tree, terr = prevss.GetTree("registrations")
 		if tree == nil {
 			logger.Errorf("[Graviton] ERROR: %v", terr)
 			return
 		}
 	}
-	key := "getinfo"
+	key := addr
 
 	v, _ := tree.Get([]byte(key))
 
 	if v != nil {
-		_ = json.Unmarshal(v, &getinfo)
+		_ = json.Unmarshal(v, &regtx)
 		return
 	}
 
 	return nil
 }
 
-// Stores SC variables at a given topoheight (called on any new scdeploy or scinvoke actions)
-func (g *GravitonStore) StoreSCIDVariableDetails(scid string, variables []*structures.SCIDVariable, topoheight int64, nocommit bool) (tree *graviton.Tree, changes bool, err error) {
-	confBytes, err := json.Marshal(variables)
-	if err != nil {
-		return &graviton.Tree{}, changes, fmt.Errorf("[StoreSCIDVariableDetails] could not marshal getinfo info: %v", err)
-	}
-
+// Returns all registration txs between the given heights (inclusive), in height order
+func (g *GravitonStore) GetRegTxsByHeightRange(minHeight int64, maxHeight int64) (regTxs []*structures.RegTXParse) {
 	store := g.DB
 	ss, err := store.LoadSnapshot(0) // load most recent snapshot
 	if err != nil {
 		return
 	}
 
-	// Check for g.migrating, if so sleep for g.DBMigrateWait ms
-	for g.migrating == 1 {
-		logger.Debugf("[StoreSCIDVariableDetails] G is migrating... sleeping for %v...", g.DBMigrateWait)
-		time.Sleep(g.DBMigrateWait)
-		store = g.DB
-		ss, err = store.LoadSnapshot(0) // load most recent snapshot
-		if err != nil {
-			return
-		}
-	}
-
-	treename := scid + "vars"
-	tree, _ = ss.GetTree(treename)
+	treename := "registrations"
+	tree, _ := g.getTree(ss, treename)
 	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
 	if tree == nil {
 		var terr error
-		logger.Errorf("[Graviton-StoreSCIDVariableDetails] ERROR: Tree is nil for '%v'. Attempting to rollback 1 snapshot", treename)
+		logger.Errorf("[Graviton-GetRegTxsByHeightRange] ERROR: Tree is nil for 'registrations'. Attempting to rollback 1 snapshot")
 		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
 		if preverr != nil {
-			return tree, changes, preverr
+			return
 		}
-		tree, terr = prevss.GetTree(treename)
+		tree, terr = prevss.GetTree("registrations")
 		if tree == nil {
 			logger.Errorf("[Graviton] ERROR: %v", terr)
-			return tree, changes, terr
+			return
 		}
 	}
-	key := strconv.FormatInt(topoheight, 10)
-	tree.Put([]byte(key), confBytes) // insert a value
-	changes = true
-	if !nocommit {
-		_, cerr := graviton.Commit(tree)
-		if cerr != nil {
-			logger.Errorf("[Graviton] ERROR: %v", cerr)
-			return tree, changes, cerr
+
+	c := tree.Cursor()
+	for _, v, err := c.First(); err == nil; _, v, err = c.Next() {
+		var currdetails *structures.RegTXParse
+		_ = json.Unmarshal(v, &currdetails)
+		if currdetails != nil && currdetails.Height >= minHeight && currdetails.Height <= maxHeight {
+			regTxs = append(regTxs, currdetails)
 		}
 	}
-	return tree, changes, nil
-}
 
-// Gets SC variables at a given topoheight
-func (g *GravitonStore) GetSCIDVariableDetailsAtTopoheight(scid string, topoheight int64) (hVars []*structures.SCIDVariable) {
-	results := make(map[int64][]*structures.SCIDVariable)
-	var heights []int64
+	sortRegTxs(regTxs)
+
+	return
+}
 
+// Stores the burn details of a given txid. There is one BurnTXParse per payload (asset) of the txn. The txid is also kept against its height (burnheights) and its scid (<scid>burns), see GetBurnTxsByHeightRange/GetBurnTxsBySCID
+func (g *GravitonStore) StoreBurnTx(burntx *structures.BurnTXParse) (changes bool, err error) {
 	store := g.DB
-	ss, err := store.LoadSnapshot(0)
+	ss, err := store.LoadSnapshot(0) // load most recent snapshot
 	if err != nil {
 		return
 	}
-	treename := scid + "vars"
-	tree, _ := ss.GetTree(treename)
-	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
-	if tree == nil {
-		var terr error
-		logger.Errorf("[Graviton-GetAllSCIDVariableDetails] ERROR: Tree is nil for '%v'. Attempting to rollback 1 snapshot", treename)
-		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
-		if preverr != nil {
+
+	// Check for g.migrating, if so sleep for g.DBMigrateWait ms
+	for g.migrating == 1 {
+		logger.Debugf("[StoreBurnTx] G is migrating... sleeping for %v...", g.DBMigrateWait)
+		time.Sleep(g.DBMigrateWait)
+		store = g.DB
+		ss, err = store.LoadSnapshot(0) // load most recent snapshot
+		if err != nil {
 			return
 		}
-		tree, terr = prevss.GetTree(treename)
+	}
+
+	getTree := func(treename string) (tree *graviton.Tree, terr error) {
+		tree, _ = g.getTree(ss, treename)
+		// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
 		if tree == nil {
-			logger.Errorf("[Graviton] ERROR: %v", terr)
-			return
+			logger.Errorf("[Graviton-StoreBurnTx] ERROR: Tree is nil for '%v'. Attempting to rollback 1 snapshot", treename)
+			prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
+			if preverr != nil {
+				return tree, preverr
+			}
+			tree, terr = prevss.GetTree(treename)
+			if tree == nil {
+				logger.Errorf("[Graviton] ERROR: %v", terr)
+				return tree, terr
+			}
 		}
+		return tree, nil
 	}
 
-	c := tree.Cursor()
-	// Duplicate the LATEST (snapshot 0) to the new DB, this starts the DB over again, but still retaining X number of old DBs for version in future use cases. Here we get the vals before swapping to new db in mem
-	for k, v, err := c.First(); err == nil; k, v, err = c.Next() {
-		topoheight, _ := strconv.ParseInt(string(k), 10, 64)
-		heights = append(heights, topoheight)
-		var variables []*structures.SCIDVariable
-		_ = json.Unmarshal(v, &variables)
-		results[topoheight] = variables
+	tree, err := getTree("burntxs")
+	if err != nil {
+		return
 	}
+	key := burntx.Txid
+	currBurnTxs, err := tree.Get([]byte(key))
+	var burnTxs []*structures.BurnTXParse
 
-	if results != nil {
-		// Sort heights so most recent is index 0 [if preferred reverse, just swap > with <]
-		sort.SliceStable(heights, func(i, j int) bool {
-			return heights[i] < heights[j]
-		})
+	var newBurnTxs []byte
 
-		vs2k := make(map[interface{}]interface{})
-		for _, v := range heights {
-			if v > topoheight {
-				break
-			}
-			for _, vs := range results[v] {
-				switch ckey := vs.Key.(type) {
-				case float64:
-					switch cval := vs.Value.(type) {
-					case float64:
-						vs2k[uint64(ckey)] = uint64(cval)
-					case uint64:
-						vs2k[uint64(ckey)] = cval
-					case string:
-						vs2k[uint64(ckey)] = cval
-					default:
-						if cval != nil {
-							logger.Errorf("[GetAllSCIDVariableDetails] Value '%v' does not match string, uint64 or float64.", cval)
-						}
-					}
-				case uint64:
-					switch cval := vs.Value.(type) {
-					case float64:
-						vs2k[ckey] = uint64(cval)
-					case uint64:
-						vs2k[ckey] = cval
-					case string:
-						vs2k[ckey] = cval
-					default:
-						if cval != nil {
-							logger.Errorf("[GetAllSCIDVariableDetails] Value '%v' does not match string, uint64 or float64.", cval)
-						}
-					}
-				case string:
-					switch cval := vs.Value.(type) {
-					case float64:
-						vs2k[ckey] = uint64(cval)
-					case uint64:
-						vs2k[ckey] = cval
-					case string:
-						vs2k[ckey] = cval
-					default:
-						if cval != nil {
-							logger.Errorf("[GetAllSCIDVariableDetails] Value '%v' does not match string, uint64 or float64.", cval)
-						}
-					}
-				default:
-					if ckey != nil {
-						logger.Errorf("[GetAllSCIDVariableDetails] Key '%v' does not match string, uint64 or float64.", ckey)
-					}
-				}
+	if err != nil {
+		burnTxs = append(burnTxs, burntx)
+	} else {
+		_ = json.Unmarshal(currBurnTxs, &burnTxs)
+
+		for _, v := range burnTxs {
+			if v.Scid == burntx.Scid {
+				// Return nil if already exists in array.
+				// Clause for this is in event we pop backwards in time and already have this data stored.
+				// Data stored from a false-chain is removed via RollbackToHeight() when a reorg is detected.
+				return changes, nil
 			}
 		}
 
-		for k, v := range vs2k {
-			// If value is nil, no reason to add.
-			if v == nil || k == nil {
-				logger.Debugf("[GetAllSCIDVariableDetails] Value '%v' or Key '%v' is nil. Continuing.", fmt.Sprintf("%v", v), fmt.Sprintf("%v", k))
-				continue
-			}
-			co := &structures.SCIDVariable{}
+		burnTxs = append(burnTxs, burntx)
+	}
+	newBurnTxs, err = json.Marshal(burnTxs)
+	if err != nil {
+		return changes, fmt.Errorf("[Graviton] could not marshal burnTxs info: %v", err)
+	}
 
-			switch ckey := k.(type) {
-			case float64:
-				switch cval := v.(type) {
-				case float64:
-					co.Key = uint64(ckey)
-					co.Value = uint64(cval)
-				case uint64:
-					co.Key = uint64(ckey)
-					co.Value = cval
-				case string:
-					co.Key = uint64(ckey)
-					co.Value = cval
-				default:
-					logger.Errorf("[GetAllSCIDVariableDetails] Value '%v' or Key '%v' does not match string, uint64 or float64.", fmt.Sprintf("%v", cval), fmt.Sprintf("%v", uint64(ckey)))
-				}
-			case uint64:
-				switch cval := v.(type) {
-				case float64:
-					co.Key = ckey
-					co.Value = uint64(cval)
-				case uint64:
-					co.Key = ckey
-					co.Value = cval
-				case string:
-					co.Key = ckey
-					co.Value = cval
-				default:
-					logger.Errorf("[GetAllSCIDVariableDetails] Value '%v' or Key '%v' does not match string, uint64 or float64.", fmt.Sprintf("%v", cval), fmt.Sprintf("%v", ckey))
-				}
-			case string:
-				switch cval := v.(type) {
-				case float64:
-					co.Key = ckey
-					co.Value = uint64(cval)
-				case uint64:
-					co.Key = ckey
-					co.Value = cval
-				case string:
-					co.Key = ckey
-					co.Value = cval
-				default:
-					logger.Errorf("[GetAllSCIDVariableDetails] Value '%v' or Key '%v' does not match string, uint64 or float64.", fmt.Sprintf("%v", cval), fmt.Sprintf("%v", ckey))
-				}
-			}
+	tree.Put([]byte(key), newBurnTxs)
+	changes = true
 
-			hVars = append(hVars, co)
-		}
+	htree, err := getTree("burnheights")
+	if err != nil {
+		return
+	}
+	hkey := []byte(strconv.FormatInt(burntx.Height, 10))
+	hv, _ := htree.Get(hkey)
+	ctrees := []*graviton.Tree{tree}
+	if txids, added := addIndexKey(hv, burntx.Txid); added {
+		htree.Put(hkey, txids)
+		ctrees = append(ctrees, htree)
 	}
 
-	return
-}
+	stree, err := getTree(burntx.Scid + "burns")
+	if err != nil {
+		return
+	}
+	stree.Put([]byte(burntx.Txid), hkey)
+	ctrees = append(ctrees, stree)
 
-// Gets SC variables at all topoheights
-func (g *GravitonStore) GetAllSCIDVariableDetails(scid string) (hVars []*structures.SCIDVariable) {
-	results := make(map[int64][]*structures.SCIDVariable)
-	var heights []int64
+	_, cerr := g.commitTrees(ctrees...)
+	if cerr != nil {
+		logger.Errorf("[Graviton] ERROR: %v", cerr)
+		return changes, cerr
+	}
+	return changes, nil
+}
 
+// Returns the burn details of a given txid
+func (g *GravitonStore) GetBurnTxByTxid(txid string) (burnTxs []*structures.BurnTXParse) {
 	store := g.DB
-	ss, err := store.LoadSnapshot(0)
+	ss, err := store.LoadSnapshot(0) // load most recent snapshot
 	if err != nil {
 		return
 	}
-	treename := scid + "vars"
-	tree, _ := ss.GetTree(treename)
+
+	treename := "burntxs"
+	tree, _ := g.getTree(ss, treename)
 	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
 	if tree == nil {
 		var terr error
-		logger.Errorf("[Graviton-GetAllSCIDVariableDetails] ERROR: Tree is nil for '%v'. Attempting to rollback 1 snapshot", treename)
+		logger.Errorf("[Graviton-GetBurnTxByTxid] ERROR: Tree is nil for 'burntxs'. Attempting to rollback 1 snapshot")
 		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
 		if preverr != nil {
 			return
 		}
-		tree, terr = prevss.GetTree(treename)
+		tree, terr = prevss.GetTree("burntxs")
 		if tree == nil {
 			logger.Errorf("[Graviton] ERROR: %v", terr)
 			return
 		}
 	}
+	key := txid
 
-	c := tree.Cursor()
-	// Duplicate the LATEST (snapshot 0) to the new DB, this starts the DB over again, but still retaining X number of old DBs for version in future use cases. Here we get the vals before swapping to new db in mem
-	for k, v, err := c.First(); err == nil; k, v, err = c.Next() {
-		topoheight, _ := strconv.ParseInt(string(k), 10, 64)
-		heights = append(heights, topoheight)
-		var variables []*structures.SCIDVariable
-		_ = json.Unmarshal(v, &variables)
-		results[topoheight] = variables
-	}
-
-	if results != nil {
-		// Sort heights so most recent is index 0 [if preferred reverse, just swap > with <]
-		sort.SliceStable(heights, func(i, j int) bool {
-			return heights[i] < heights[j]
-		})
-
-		vs2k := make(map[interface{}]interface{})
-		for _, v := range heights {
-			for _, vs := range results[v] {
-				switch ckey := vs.Key.(type) {
-				case float64:
-					switch cval := vs.Value.(type) {
-					case float64:
-						vs2k[uint64(ckey)] = uint64(cval)
-					case uint64:
-						vs2k[uint64(ckey)] = cval
-					case string:
-						vs2k[uint64(ckey)] = cval
-					default:
-						if cval != nil {
-							logger.Errorf("[GetAllSCIDVariableDetails] Value '%v' does not match string, uint64 or float64.", cval)
-						}
-					}
-				case uint64:
-					switch cval := vs.Value.(type) {
-					case float64:
-						vs2k[ckey] = uint64(cval)
-					case uint64:
-						vs2k[ckey] = cval
-					case string:
-						vs2k[ckey] = cval
-					default:
-						if cval != nil {
-							logger.Errorf("[GetAllSCIDVariableDetails] Value '%v' does not match string, uint64 or float64.", cval)
-						}
-					}
-				case string:
-					switch cval := vs.Value.(type) {
-					case float64:
-						vs2k[ckey] = uint64(cval)
-					case uint64:
-						vs2k[ckey] = cval
-					case string:
-						vs2k[ckey] = cval
-					default:
-						if cval != nil {
-							logger.Errorf("[GetAllSCIDVariableDetails] Value '%v' does not match string, uint64 or float64.", cval)
-						}
-					}
-				default:
-					if ckey != nil {
-						logger.Errorf("[GetAllSCIDVariableDetails] Key '%v' does not match string, uint64 or float64.", ckey)
-					}
-				}
-			}
-		}
+	v, _ := tree.Get([]byte(key))
 
-		for k, v := range vs2k {
-			// If value is nil, no reason to add.
-			if v == nil || k == nil {
-				logger.Debugf("[GetAllSCIDVariableDetails] Value '%v' or Key '%v' is nil. Continuing.", fmt.Sprintf("%v", v), fmt.Sprintf("%v", k))
-				continue
-			}
-			co := &structures.SCIDVariable{}
+	if v != nil {
+		_ = json.Unmarshal(v, &burnTxs)
+		return
+	}
 
-			switch ckey := k.(type) {
-			case float64:
-				switch cval := v.(type) {
-				case float64:
-					co.Key = uint64(ckey)
-					co.Value = uint64(cval)
-				case uint64:
-					co.Key = uint64(ckey)
-					co.Value = cval
-				case string:
-					co.Key = uint64(ckey)
-					co.Value = cval
-				default:
-					logger.Errorf("[GetAllSCIDVariableDetails] Value '%v' or Key '%v' does not match string, uint64 or float64.", fmt.Sprintf("%v", cval), fmt.Sprintf("%v", uint64(ckey)))
-				}
-			case uint64:
-				switch cval := v.(type) {
-				case float64:
-					co.Key = ckey
-					co.Value = uint64(cval)
-				case uint64:
-					co.Key = ckey
-					co.Value = cval
-				case string:
-					co.Key = ckey
-					co.Value = cval
-				default:
-					logger.Errorf("[GetAllSCIDVariableDetails] Value '%v' or Key '%v' does not match string, uint64 or float64.", fmt.Sprintf("%v", cval), fmt.Sprintf("%v", ckey))
-				}
-			case string:
-				switch cval := v.(type) {
-				case float64:
-					co.Key = ckey
-					co.Value = uint64(cval)
-				case uint64:
-					co.Key = ckey
-					co.Value = cval
-				case string:
-					co.Key = ckey
-					co.Value = cval
-				default:
-					logger.Errorf("[GetAllSCIDVariableDetails] Value '%v' or Key '%v' does not match string, uint64 or float64.", fmt.Sprintf("%v", cval), fmt.Sprintf("%v", ckey))
-				}
-			}
+	return nil
+}
+
+// Returns all burn txs of a given asset SCID (DERO burns are stored against the zero hash), in height order
+func (g *GravitonStore) GetBurnTxsBySCID(scid string) (burnTxs []*structures.BurnTXParse) {
+	store := g.DB
+	ss, err := store.LoadSnapshot(0) // load most recent snapshot
+	if err != nil {
+		return
+	}
+
+	btree, err := g.getTree(ss, "burntxs")
+	if err != nil {
+		return
+	}
+	stree, err := g.getTree(ss, scid+"burns")
+	if err != nil {
+		return
+	}
 
-			hVars = append(hVars, co)
+	c := stree.Cursor()
+	for k, _, err := c.First(); err == nil; k, _, err = c.Next() {
+		v, berr := btree.Get(k)
+		if berr != nil {
+			continue
+		}
+		var currdetails []*structures.BurnTXParse
+		_ = json.Unmarshal(v, &currdetails)
+		for _, cv := range currdetails {
+			if cv.Scid == scid {
+				burnTxs = append(burnTxs, cv)
+			}
 		}
 	}
 
+	sortBurnTxs(burnTxs)
+
 	return
 }
 
-// Gets SC variable keys at given topoheight who's value equates to a given interface{} (string/uint64)
-func (g *GravitonStore) GetSCIDKeysByValue(scid string, val interface{}, height int64, rmax bool) (keysstring []string, keysuint64 []uint64) {
-	scidInteractionHeights := g.GetSCIDInteractionHeight(scid)
-
-	interactionHeight := g.GetInteractionIndex(height, scidInteractionHeights, rmax)
+// Returns all burn txs between the given heights (inclusive), in height order. The heights are looked up one by one, unless there are more of them than burns
+func (g *GravitonStore) GetBurnTxsByHeightRange(minHeight int64, maxHeight int64) (burnTxs []*structures.BurnTXParse) {
+	store := g.DB
+	ss, err := store.LoadSnapshot(0) // load most recent snapshot
+	if err != nil {
+		return
+	}
 
-	// TODO: If there's no interaction height, do we go get scvars against daemon and store? Or do we just ignore and return nil
-	variables := g.GetSCIDVariableDetailsAtTopoheight(scid, interactionHeight)
+	btree, err := g.getTree(ss, "burntxs")
+	if err != nil {
+		return
+	}
 
-	// Switch against the value passed. If it's a uint64 or string
-	switch inpvar := val.(type) {
-	case uint64:
-		for _, v := range variables {
-			switch cval := v.Value.(type) {
-			case float64:
-				if inpvar == uint64(cval) {
-					switch ckey := v.Key.(type) {
-					case float64:
-						keysuint64 = append(keysuint64, uint64(ckey))
-					case uint64:
-						keysuint64 = append(keysuint64, ckey)
-					default:
-						// default just store as string. Keys should only ever be strings or uint64, however, but assume default to string
-						keysstring = append(keysstring, v.Key.(string))
-					}
-				}
-			case uint64:
-				if inpvar == cval {
-					switch ckey := v.Key.(type) {
-					case float64:
-						keysuint64 = append(keysuint64, uint64(ckey))
-					case uint64:
-						keysuint64 = append(keysuint64, ckey)
-					default:
-						// default just store as string. Keys should only ever be strings or uint64, however, but assume default to string
-						keysstring = append(keysstring, v.Key.(string))
-					}
+	lastHeight, _ := g.GetLastIndexHeight()
+	minHeight, maxHeight, byHeight := burnHeightLookup(minHeight, maxHeight, lastHeight, g.GetTxCount("burn"))
+	if byHeight {
+		htree, err := g.getTree(ss, "burnheights")
+		if err != nil {
+			return
+		}
+		for h := minHeight; h <= maxHeight; h++ {
+			hv, herr := htree.Get([]byte(strconv.FormatInt(h, 10)))
+			if herr != nil {
+				continue
+			}
+			var txids []string
+			_ = json.Unmarshal(hv, &txids)
+			for _, txid := range txids {
+				v, berr := btree.Get([]byte(txid))
+				if berr != nil {
+					continue
 				}
-			default:
-				// Nothing - expect only string/uint64 for value types
+				var currdetails []*structures.BurnTXParse
+				_ = json.Unmarshal(v, &currdetails)
+				burnTxs = append(burnTxs, currdetails...)
 			}
 		}
-	case string:
-		for _, v := range variables {
-			switch cval := v.Value.(type) {
-			case string:
-				if inpvar == cval {
-					switch ckey := v.Key.(type) {
-					case float64:
-						keysuint64 = append(keysuint64, uint64(ckey))
-					case uint64:
-						keysuint64 = append(keysuint64, ckey)
-					default:
-						// default just store as string. Keys should only ever be strings or uint64, however, but assume default to string
-						keysstring = append(keysstring, v.Key.(string))
-					}
+	} else {
+		c := btree.Cursor()
+		for _, v, err := c.First(); err == nil; _, v, err = c.Next() {
+			var currdetails []*structures.BurnTXParse
+			_ = json.Unmarshal(v, &currdetails)
+			for _, cv := range currdetails {
+				if cv.Height >= minHeight && cv.Height <= maxHeight {
+					burnTxs = append(burnTxs, cv)
 				}
-			default:
-				// Nothing - expect only string/uint64 for value types
 			}
 		}
-	default:
-		// Nothing - expect only string/uint64 for value types
 	}
 
-	return keysstring, keysuint64
+	sortBurnTxs(burnTxs)
+
+	return
 }
 
-// Gets SC values by key at given topoheight who's key equates to a given interface{} (string/uint64)
-func (g *GravitonStore) GetSCIDValuesByKey(scid string, key interface{}, height int64, rmax bool) (valuesstring []string, valuesuint64 []uint64) {
-	scidInteractionHeights := g.GetSCIDInteractionHeight(scid)
+// Builds the height and scid indexes of the burn txs stored before they were kept, and keeps the fees of each burn tx on its first payload only. Only runs once, returns the number of burn txs gone through
+func (g *GravitonStore) IndexBurnTxs() (indexed int, err error) {
+	store := g.DB
+	ss, err := store.LoadSnapshot(0) // load most recent snapshot
+	if err != nil {
+		return
+	}
 
-	interactionHeight := g.GetInteractionIndex(height, scidInteractionHeights, rmax)
+	// Check for g.migrating, if so sleep for g.DBMigrateWait ms
+	for g.migrating == 1 {
+		logger.Debugf("[IndexBurnTxs] G is migrating... sleeping for %v...", g.DBMigrateWait)
+		time.Sleep(g.DBMigrateWait)
+		store = g.DB
+		ss, err = store.LoadSnapshot(0) // load most recent snapshot
+		if err != nil {
+			return
+		}
+	}
 
-	// TODO: If there's no interaction height, do we go get scvars against daemon and store? Or do we just ignore and return nil
-	variables := g.GetSCIDVariableDetailsAtTopoheight(scid, interactionHeight)
+	stree, err := g.getTree(ss, "stats")
+	if err != nil {
+		return
+	}
+	if v, _ := stree.Get([]byte(burn_indexed_key)); v != nil {
+		return
+	}
 
-	// Switch against the value passed. If it's a uint64 or string
-	switch inpvar := key.(type) {
-	case uint64:
-		for _, v := range variables {
-			switch ckey := v.Key.(type) {
-			case float64:
-				if inpvar == uint64(ckey) {
-					switch cval := v.Value.(type) {
-					case float64:
+	btree, err := g.getTree(ss, "burntxs")
+	if err != nil {
+		return
+	}
+	var burnTxs [][]*structures.BurnTXParse
+	c := btree.Cursor()
+	for _, v, err := c.First(); err == nil; _, v, err = c.Next() {
+		var currdetails []*structures.BurnTXParse
+		_ = json.Unmarshal(v, &currdetails)
+		if len(currdetails) > 0 {
+			burnTxs = append(burnTxs, currdetails)
+		}
+	}
+
+	htree, err := g.getTree(ss, "burnheights")
+	if err != nil {
+		return
+	}
+	ctrees := []*graviton.Tree{stree, btree, htree}
+	scidtrees := make(map[string]*graviton.Tree)
+	for _, currdetails := range burnTxs {
+		txid := currdetails[0].Txid
+		hkey := []byte(strconv.FormatInt(currdetails[0].Height, 10))
+		hv, _ := htree.Get(hkey)
+		if txids, added := addIndexKey(hv, txid); added {
+			htree.Put(hkey, txids)
+		}
+
+		for i, cv := range currdetails {
+			if i > 0 {
+				cv.Fees = 0
+			}
+			sctree, ok := scidtrees[cv.Scid]
+			if !ok {
+				if sctree, err = g.getTree(ss, cv.Scid+"burns"); err != nil {
+					return
+				}
+				scidtrees[cv.Scid] = sctree
+				ctrees = append(ctrees, sctree)
+			}
+			sctree.Put([]byte(txid), hkey)
+		}
+		if len(currdetails) > 1 {
+			v, merr := json.Marshal(currdetails)
+			if merr != nil {
+				return indexed, fmt.Errorf("[Graviton] could not marshal burnTxs info: %v", merr)
+			}
+			btree.Put([]byte(txid), v)
+		}
+		indexed++
+	}
+
+	stree.Put([]byte(burn_indexed_key), []byte("1"))
+	_, cerr := g.commitTrees(ctrees...)
+	if cerr != nil {
+		logger.Errorf("[Graviton] ERROR: %v", cerr)
+		return indexed, cerr
+	}
+
+	return
+}
+
+// Stores the registry entry of a given asset scid, replacing any previous entry. Previous entries are kept in the asset's history for rolling back reorgs
+func (g *GravitonStore) StoreSCAsset(asset *structures.SCAsset) (changes bool, err error) {
+	confBytes, err := json.Marshal(asset)
+	if err != nil {
+		return changes, fmt.Errorf("[StoreSCAsset] could not marshal asset info: %v", err)
+	}
+
+	store := g.DB
+	ss, err := store.LoadSnapshot(0) // load most recent snapshot
+	if err != nil {
+		return
+	}
+
+	// Check for g.migrating, if so sleep for g.DBMigrateWait ms
+	for g.migrating == 1 {
+		logger.Debugf("[StoreSCAsset] G is migrating... sleeping for %v...", g.DBMigrateWait)
+		time.Sleep(g.DBMigrateWait)
+		store = g.DB
+		ss, err = store.LoadSnapshot(0) // load most recent snapshot
+		if err != nil {
+			return
+		}
+	}
+
+	treename := "assets"
+	tree, _ := g.getTree(ss, treename)
+	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
+	if tree == nil {
+		var terr error
+		logger.Errorf("[Graviton-StoreSCAsset] ERROR: Tree is nil for 'assets'. Attempting to rollback 1 snapshot")
+		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
+		if preverr != nil {
+			return changes, preverr
+		}
+		tree, terr = prevss.GetTree("assets")
+		if tree == nil {
+			logger.Errorf("[Graviton] ERROR: %v", terr)
+			return changes, terr
+		}
+	}
+	htree, terr := g.getTree(ss, "assethistory")
+	if htree == nil {
+		logger.Errorf("[Graviton-StoreSCAsset] ERROR: %v", terr)
+		return changes, terr
+	}
+
+	key := asset.Scid
+	var history []*structures.SCAsset
+	if currHistory, herr := htree.Get([]byte(key)); herr == nil {
+		_ = json.Unmarshal(currHistory, &history)
+	}
+	newHistory, err := json.Marshal(mergeAssetHistory(history, asset))
+	if err != nil {
+		return changes, fmt.Errorf("[Graviton] could not marshal asset history info: %v", err)
+	}
+
+	tree.Put([]byte(key), confBytes) // insert a value
+	htree.Put([]byte(key), newHistory)
+	changes = true
+	_, cerr := g.commitTrees(tree, htree)
+	if cerr != nil {
+		logger.Errorf("[Graviton] ERROR: %v", cerr)
+		return changes, cerr
+	}
+	return changes, nil
+}
+
+// Returns the registry entry of a given asset scid
+func (g *GravitonStore) GetSCAsset(scid string) (asset *structures.SCAsset) {
+	store := g.DB
+	ss, err := store.LoadSnapshot(0) // load most recent snapshot
+	if err != nil {
+		return
+	}
+
+	treename := "assets"
+	tree, _ := g.getTree(ss, treename)
+	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
+	if tree == nil {
+		var terr error
+		logger.Errorf("[Graviton-GetSCAsset] ERROR: Tree is nil for 'assets'. Attempting to rollback 1 snapshot")
+		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
+		if preverr != nil {
+			return
+		}
+		tree, terr = prevss.GetTree("assets")
+		if tree == nil {
+			logger.Errorf("[Graviton] ERROR: %v", terr)
+			return
+		}
+	}
+	key := scid
+
+	v, _ := tree.Get([]byte(key))
+
+	if v != nil {
+		_ = json.Unmarshal(v, &asset)
+		return
+	}
+
+	return nil
+}
+
+// Returns all assets of a given collection scid
+func (g *GravitonStore) GetSCAssetsByCollection(collection string) (assets []*structures.SCAsset) {
+	return g.getSCAssets(func(asset *structures.SCAsset) bool {
+		return asset.Collection == collection
+	})
+}
+
+// Returns all assets owned by a given address
+func (g *GravitonStore) GetSCAssetsByOwner(owner string) (assets []*structures.SCAsset) {
+	return g.getSCAssets(func(asset *structures.SCAsset) bool {
+		return asset.Owner == owner
+	})
+}
+
+// Returns all assets of a given standard
+func (g *GravitonStore) GetSCAssetsByStandard(standard string) (assets []*structures.SCAsset) {
+	return g.getSCAssets(func(asset *structures.SCAsset) bool {
+		return asset.Standard == standard
+	})
+}
+
+// Returns all assets that match, in height order
+func (g *GravitonStore) getSCAssets(match func(asset *structures.SCAsset) bool) (assets []*structures.SCAsset) {
+	store := g.DB
+	ss, err := store.LoadSnapshot(0) // load most recent snapshot
+	if err != nil {
+		return
+	}
+
+	treename := "assets"
+	tree, _ := g.getTree(ss, treename)
+	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
+	if tree == nil {
+		var terr error
+		logger.Errorf("[Graviton-getSCAssets] ERROR: Tree is nil for 'assets'. Attempting to rollback 1 snapshot")
+		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
+		if preverr != nil {
+			return
+		}
+		tree, terr = prevss.GetTree("assets")
+		if tree == nil {
+			logger.Errorf("[Graviton] ERROR: %v", terr)
+			return
+		}
+	}
+
+	c := tree.Cursor()
+	for _, v, err := c.First(); err == nil; _, v, err = c.Next() {
+		var asset *structures.SCAsset
+		_ = json.Unmarshal(v, &asset)
+		if asset != nil && match(asset) {
+			assets = append(assets, asset)
+		}
+	}
+
+	sortSCAssets(assets)
+
+	return
+}
+
+// Stores a name service registration/transfer as the current record of its name and appends it to the name's history
+func (g *GravitonStore) StoreNameRecord(record *structures.NameRecord) (changes bool, err error) {
+	confBytes, err := json.Marshal(record)
+	if err != nil {
+		return changes, fmt.Errorf("[StoreNameRecord] could not marshal name record info: %v", err)
+	}
+
+	store := g.DB
+	ss, err := store.LoadSnapshot(0) // load most recent snapshot
+	if err != nil {
+		return
+	}
+
+	// Check for g.migrating, if so sleep for g.DBMigrateWait ms
+	for g.migrating == 1 {
+		logger.Debugf("[StoreNameRecord] G is migrating... sleeping for %v...", g.DBMigrateWait)
+		time.Sleep(g.DBMigrateWait)
+		store = g.DB
+		ss, err = store.LoadSnapshot(0) // load most recent snapshot
+		if err != nil {
+			return
+		}
+	}
+
+	tree, terr := g.getTree(ss, "names")
+	if tree == nil {
+		logger.Errorf("[Graviton-StoreNameRecord] ERROR: %v", terr)
+		return changes, terr
+	}
+	htree, terr := g.getTree(ss, "namehistory")
+	if htree == nil {
+		logger.Errorf("[Graviton-StoreNameRecord] ERROR: %v", terr)
+		return changes, terr
+	}
+
+	atree, terr := g.getTree(ss, "nameaddrs")
+	if atree == nil {
+		logger.Errorf("[Graviton-StoreNameRecord] ERROR: %v", terr)
+		return changes, terr
+	}
+
+	key := record.Name
+	var history []*structures.NameRecord
+	if currHistory, herr := htree.Get([]byte(key)); herr == nil {
+		_ = json.Unmarshal(currHistory, &history)
+	}
+	history, exists := mergeNameHistory(history, record)
+	if exists {
+		// Return nil if already exists in array.
+		// Clause for this is in event we pop backwards in time and already have this data stored.
+		// Data stored from a false-chain is removed via RollbackToHeight() when a reorg is detected.
+		return changes, nil
+	}
+
+	newHistory, err := json.Marshal(history)
+	if err != nil {
+		return changes, fmt.Errorf("[Graviton] could not marshal name history info: %v", err)
+	}
+
+	// Current record is the latest of the history, a record stored out of order does not replace it. The names currently owned by each address are kept along with it
+	if history[len(history)-1] == record {
+		if v, perr := tree.Get([]byte(key)); perr == nil {
+			var prev *structures.NameRecord
+			_ = json.Unmarshal(v, &prev)
+			if prev != nil && prev.Address != record.Address {
+				moveNameAddress(atree, key, prev.Address, "")
+			}
+		}
+		tree.Put([]byte(key), confBytes)
+		moveNameAddress(atree, key, "", record.Address)
+	}
+	htree.Put([]byte(key), newHistory)
+	changes = true
+	_, cerr := g.commitTrees(tree, htree, atree)
+	if cerr != nil {
+		logger.Errorf("[Graviton] ERROR: %v", cerr)
+		return changes, cerr
+	}
+	return changes, nil
+}
+
+// Removes name from the names of address from, and adds it to those of address to. Either may be ""
+func moveNameAddress(atree *graviton.Tree, name string, from string, to string) {
+	if from != "" {
+		if v, err := atree.Get([]byte(from)); err == nil {
+			if names := removeIndexKey(v, name); names != nil {
+				atree.Put([]byte(from), names)
+			} else {
+				atree.Delete([]byte(from))
+			}
+		}
+	}
+
+	if to != "" {
+		v, _ := atree.Get([]byte(to))
+		if names, added := addIndexKey(v, name); added {
+			atree.Put([]byte(to), names)
+		}
+	}
+}
+
+// Returns the current record (owner) of a given name
+func (g *GravitonStore) GetNameRecord(name string) (record *structures.NameRecord) {
+	store := g.DB
+	ss, err := store.LoadSnapshot(0) // load most recent snapshot
+	if err != nil {
+		return
+	}
+
+	treename := "names"
+	tree, _ := g.getTree(ss, treename)
+	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
+	if tree == nil {
+		var terr error
+		logger.Errorf("[Graviton-GetNameRecord] ERROR: Tree is nil for 'names'. Attempting to rollback 1 snapshot")
+		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
+		if preverr != nil {
+			return
+		}
+		tree, terr = prevss.GetTree("names")
+		if tree == nil {
+			logger.Errorf("[Graviton] ERROR: %v", terr)
+			return
+		}
+	}
+	key := name
+
+	v, _ := tree.Get([]byte(key))
+
+	if v != nil {
+		_ = json.Unmarshal(v, &record)
+		return
+	}
+
+	return nil
+}
+
+// Returns the current records of the names owned by a given address (reverse lookup)
+func (g *GravitonStore) GetNamesByAddress(address string) (records []*structures.NameRecord) {
+	store := g.DB
+	ss, err := store.LoadSnapshot(0) // load most recent snapshot
+	if err != nil {
+		return
+	}
+
+	tree, err := g.getTree(ss, "names")
+	if err != nil {
+		return
+	}
+	atree, err := g.getTree(ss, "nameaddrs")
+	if err != nil {
+		return
+	}
+
+	v, err := atree.Get([]byte(address))
+	if err != nil {
+		return
+	}
+	var names []string
+	_ = json.Unmarshal(v, &names)
+	for _, name := range names {
+		rv, rerr := tree.Get([]byte(name))
+		if rerr != nil {
+			continue
+		}
+		var record *structures.NameRecord
+		_ = json.Unmarshal(rv, &record)
+		if record != nil && record.Address == address {
+			records = append(records, record)
+		}
+	}
+
+	sortNameRecords(records)
+
+	return
+}
+
+// Indexes the names currently owned by each address from the current name records stored before they were kept. Only runs once, returns the number of names gone through
+func (g *GravitonStore) IndexNameAddresses() (indexed int, err error) {
+	store := g.DB
+	ss, err := store.LoadSnapshot(0) // load most recent snapshot
+	if err != nil {
+		return
+	}
+
+	// Check for g.migrating, if so sleep for g.DBMigrateWait ms
+	for g.migrating == 1 {
+		logger.Debugf("[IndexNameAddresses] G is migrating... sleeping for %v...", g.DBMigrateWait)
+		time.Sleep(g.DBMigrateWait)
+		store = g.DB
+		ss, err = store.LoadSnapshot(0) // load most recent snapshot
+		if err != nil {
+			return
+		}
+	}
+
+	stree, err := g.getTree(ss, "stats")
+	if err != nil {
+		return
+	}
+	if v, _ := stree.Get([]byte(name_addrs_indexed_key)); v != nil {
+		return
+	}
+
+	tree, err := g.getTree(ss, "names")
+	if err != nil {
+		return
+	}
+	atree, err := g.getTree(ss, "nameaddrs")
+	if err != nil {
+		return
+	}
+
+	var records []*structures.NameRecord
+	c := tree.Cursor()
+	for _, v, err := c.First(); err == nil; _, v, err = c.Next() {
+		var record *structures.NameRecord
+		_ = json.Unmarshal(v, &record)
+		if record != nil {
+			records = append(records, record)
+		}
+	}
+	for _, record := range records {
+		moveNameAddress(atree, record.Name, "", record.Address)
+		indexed++
+	}
+
+	stree.Put([]byte(name_addrs_indexed_key), []byte("1"))
+	_, cerr := g.commitTrees(stree, atree)
+	if cerr != nil {
+		logger.Errorf("[Graviton] ERROR: %v", cerr)
+		return indexed, cerr
+	}
+
+	return
+}
+
+// Returns the registration and transfer history of a given name, in height order
+func (g *GravitonStore) GetNameHistory(name string) (records []*structures.NameRecord) {
+	return g.getNameHistory(func(record *structures.NameRecord) bool {
+		return record.Name == name
+	})
+}
+
+// Returns all name registrations and transfers between the given heights (inclusive)
+func (g *GravitonStore) GetNameHistoryByHeightRange(minHeight int64, maxHeight int64) (records []*structures.NameRecord) {
+	return g.getNameHistory(func(record *structures.NameRecord) bool {
+		return record.Height >= minHeight && record.Height <= maxHeight
+	})
+}
+
+// Returns all name history records that match, in height order
+func (g *GravitonStore) getNameHistory(match func(record *structures.NameRecord) bool) (records []*structures.NameRecord) {
+	store := g.DB
+	ss, err := store.LoadSnapshot(0) // load most recent snapshot
+	if err != nil {
+		return
+	}
+
+	treename := "namehistory"
+	tree, _ := g.getTree(ss, treename)
+	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
+	if tree == nil {
+		var terr error
+		logger.Errorf("[Graviton-getNameHistory] ERROR: Tree is nil for 'namehistory'. Attempting to rollback 1 snapshot")
+		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
+		if preverr != nil {
+			return
+		}
+		tree, terr = prevss.GetTree("namehistory")
+		if tree == nil {
+			logger.Errorf("[Graviton] ERROR: %v", terr)
+			return
+		}
+	}
+
+	c := tree.Cursor()
+	for _, v, err := c.First(); err == nil; _, v, err = c.Next() {
+		var history []*structures.NameRecord
+		_ = json.Unmarshal(v, &history)
+		for _, record := range history {
+			if match(record) {
+				records = append(records, record)
+			}
+		}
+	}
+
+	sortNameRecords(records)
+
+	return
+}
+
+// Check if value exists within a string array/slice
+func idExist(s []string, str string) bool {
+	for _, v := range s {
+		if v == str {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Key of an invoke within its scid tree, sender:txid[0:3]txid[-3:]:topoheight:entrypoint
+func invokeDetailsKey(signer string, txid string, topoheight int64, entrypoint string) string {
+	txidLen := len(txid)
+	return signer + ":" + txid[0:3] + txid[txidLen-3:txidLen] + ":" + strconv.FormatInt(topoheight, 10) + ":" + entrypoint
+}
+
+// Invokes indexed under an arg name:value are stored as the key of the invoke within its scid tree, along with its height for rollbacks
+type argIndexEntry struct {
+	Key    string
+	Height int64
+}
+
+// Key of the list of arg names indexed for a scid, arg index keys are always name:value so this cannot collide
+const indexed_args_key = "indexedargs"
+
+// Index key of an arg name:value. Long values (e.g. a whole document passed as a string arg) are hashed to stay within graviton's key size, lookups re-check the value of each invoke so this cannot return false matches
+func argIndexKey(name string, value interface{}) string {
+	svalue := fmt.Sprintf("%v", value)
+	if len(svalue) > max_arg_index_value {
+		svalue = fmt.Sprintf("%x", sha256.Sum256([]byte(svalue)))
+	}
+
+	return name + ":" + svalue
+}
+
+// Max length of an arg value kept as is within its index key
+const max_arg_index_value = 256
+
+// Adds invokedetails to the arg index entries held within index (name:value -> entries), returns the number of entries added
+func addArgIndexEntries(index map[string][]*argIndexEntry, name string, invokedetails []*structures.SCTXParse) (added int) {
+	for _, invoke := range invokedetails {
+		value, ok := invoke.Args[name]
+		if !ok || invoke.Txid == "" {
+			continue
+		}
+
+		ikey := argIndexKey(name, value)
+		entry := &argIndexEntry{Key: invokeDetailsKey(invoke.Sender, invoke.Txid, invoke.Height, invoke.Entrypoint), Height: invoke.Height}
+
+		var exists bool
+		for _, e := range index[ikey] {
+			if *e == *entry {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			index[ikey] = append(index[ikey], entry)
+			added++
+		}
+	}
+
+	return
+}
+
+// Returns true if the arg of name within invoke matches value (compared as a string, e.g. "100" matches a uint64 arg of 100)
+func argMatch(invoke *structures.SCTXParse, name string, value string) bool {
+	v, ok := invoke.Args[name]
+	return ok && fmt.Sprintf("%v", v) == value
+}
+
+// Sorts scid balances by height
+func sortSCIDBalances(hBalances []*structures.SCIDBalances) {
+	sort.SliceStable(hBalances, func(i, j int) bool {
+		return hBalances[i].Height < hBalances[j].Height
+	})
+}
+
+// Sorts code versions by height
+func sortSCIDCode(hCode []*structures.SCIDCode) {
+	sort.SliceStable(hCode, func(i, j int) bool {
+		return hCode[i].Height < hCode[j].Height
+	})
+}
+
+// Sorts registration txs by height, then address
+func sortRegTxs(regTxs []*structures.RegTXParse) {
+	sort.SliceStable(regTxs, func(i, j int) bool {
+		if regTxs[i].Height == regTxs[j].Height {
+			return regTxs[i].Address < regTxs[j].Address
+		}
+		return regTxs[i].Height < regTxs[j].Height
+	})
+}
+
+// Adds asset to the history of its scid. Versions at or above its height are replaced by it, and versions superseded more than block_hashes_kept below it are trimmed as reorgs are not rolled back that far
+func mergeAssetHistory(history []*structures.SCAsset, asset *structures.SCAsset) (merged []*structures.SCAsset) {
+	for _, v := range history {
+		if v.Height < asset.Height {
+			merged = append(merged, v)
+		}
+	}
+	merged = append(merged, asset)
+
+	keep := 0
+	for i, v := range merged {
+		if v.Height <= asset.Height-block_hashes_kept {
+			keep = i
+		}
+	}
+
+	return merged[keep:]
+}
+
+// Returns the versions of an asset's history at or below height, the last being its entry as of height
+func assetHistoryAt(history []*structures.SCAsset, height int64) (kept []*structures.SCAsset) {
+	for _, v := range history {
+		if v.Height <= height {
+			kept = append(kept, v)
+		}
+	}
+
+	return
+}
+
+// Sorts assets by height, then scid
+func sortSCAssets(assets []*structures.SCAsset) {
+	sort.SliceStable(assets, func(i, j int) bool {
+		if assets[i].Height == assets[j].Height {
+			return assets[i].Scid < assets[j].Scid
+		}
+		return assets[i].Height < assets[j].Height
+	})
+}
+
+// Adds record to a name's history, in height order. An entry of the same txid and height is replaced, so each invoke has one entry. exists is true if the record is already there as is
+func mergeNameHistory(history []*structures.NameRecord, record *structures.NameRecord) (merged []*structures.NameRecord, exists bool) {
+	for _, v := range history {
+		if v.Txid == record.Txid && v.Height == record.Height {
+			if *v == *record {
+				return history, true
+			}
+			continue
+		}
+		merged = append(merged, v)
+	}
+	merged = append(merged, record)
+	sortNameRecords(merged)
+
+	return
+}
+
+// Sorts name records by height, then name
+func sortNameRecords(records []*structures.NameRecord) {
+	sort.SliceStable(records, func(i, j int) bool {
+		if records[i].Height == records[j].Height {
+			return records[i].Name < records[j].Name
+		}
+		return records[i].Height < records[j].Height
+	})
+}
+
+// Sorts address activity by height, then txid
+func sortAddressActivity(activity []*structures.AddressActivity) {
+	sort.SliceStable(activity, func(i, j int) bool {
+		if activity[i].Height == activity[j].Height {
+			return activity[i].Txid < activity[j].Txid
+		}
+		return activity[i].Height < activity[j].Height
+	})
+}
+
+// Returns the page of activity starting at index start with at most limit entries (all if limit <= 0), along with the total number of entries
+func pageAddressActivity(activity []*structures.AddressActivity, start int, limit int) (page []*structures.AddressActivity, total int) {
+	total = len(activity)
+	if start < 0 {
+		start = 0
+	}
+	if start >= total {
+		return nil, total
+	}
+
+	end := total
+	if limit > 0 && start+limit < total {
+		end = start + limit
+	}
+
+	return activity[start:end], total
+}
+
+// Stats key set once the burn txs stored before their height and scid indexes were kept are indexed, see IndexBurnTxs
+const burn_indexed_key = "burnindexed"
+
+// Adds key to the json array of keys v (e.g. the txids at a height), added is false if it was already there
+func addIndexKey(v []byte, key string) (keys []byte, added bool) {
+	var curr []string
+	if v != nil {
+		_ = json.Unmarshal(v, &curr)
+	}
+	for _, k := range curr {
+		if k == key {
+			return v, false
+		}
+	}
+
+	keys, _ = json.Marshal(append(curr, key))
+
+	return keys, true
+}
+
+// Removes key from the json array of keys v, returns nil if none are left
+func removeIndexKey(v []byte, key string) (keys []byte) {
+	var curr, left []string
+	_ = json.Unmarshal(v, &curr)
+	for _, k := range curr {
+		if k != key {
+			left = append(left, k)
+		}
+	}
+	if len(left) == 0 {
+		return nil
+	}
+
+	keys, _ = json.Marshal(left)
+
+	return
+}
+
+// Stats key set once the current name records stored before the names of each address were kept are indexed, see IndexNameAddresses
+const name_addrs_indexed_key = "nameaddrsindexed"
+
+// Bounds a burn tx height range to the indexed heights. byHeight is whether it is less work to look up each height than to go through all of the burns
+func burnHeightLookup(minHeight, maxHeight, lastHeight, burns int64) (from, to int64, byHeight bool) {
+	if minHeight < 0 {
+		minHeight = 0
+	}
+	if lastHeight > 0 && maxHeight > lastHeight {
+		maxHeight = lastHeight
+	}
+
+	return minHeight, maxHeight, maxHeight-minHeight < burns
+}
+
+// Sorts burn txs by height, then txid
+func sortBurnTxs(burnTxs []*structures.BurnTXParse) {
+	sort.SliceStable(burnTxs, func(i, j int) bool {
+		if burnTxs[i].Height == burnTxs[j].Height {
+			return burnTxs[i].Txid < burnTxs[j].Txid
+		}
+		return burnTxs[i].Height < burnTxs[j].Height
+	})
+}
+
+// Stores all scinvoke details of a given scid
+func (g *GravitonStore) StoreInvokeDetails(scid string, signer string, entrypoint string, topoheight int64, invokedetails *structures.SCTXParse) (changes bool, err error) {
+	confBytes, err := json.Marshal(invokedetails)
+	if err != nil {
+		return changes, fmt.Errorf("[StoreInvokeDetails] could not marshal invokedetails info: %v", err)
+	}
+
+	store := g.DB
+	ss, err := store.LoadSnapshot(0) // load most recent snapshot
+	if err != nil {
+		return
+	}
+
+	// Swap DB at g.DBMaxSnapshot+ commits. Check for g.migrating, if so sleep for g.DBMigrateWait ms
+	for g.migrating == 1 {
+		logger.Debugf("[StoreInvokeDetails] G is migrating... sleeping for %v...", g.DBMigrateWait)
+		time.Sleep(g.DBMigrateWait)
+		store = g.DB
+		ss, err = store.LoadSnapshot(0) // load most recent snapshot
+		if err != nil {
+			return
+		}
+	}
+
+	// Tree - SCID // (either string or hex - will be []byte in graviton anyways.. may just go with hex)
+	// Key - sender:topoheight:entrypoint // (We know that we can have 1 sender per scid per topoheight - do we need entrypoint appended? does it matter?)
+	tree, _ := g.getTree(ss, scid)
+	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
+	if tree == nil {
+		var terr error
+		logger.Errorf("[Graviton-StoreInvokeDetails] ERROR: Tree is nil for '%v'. Attempting to rollback 1 snapshot", scid)
+		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
+		if preverr != nil {
+			return changes, preverr
+		}
+		tree, terr = prevss.GetTree(scid)
+		if tree == nil {
+			logger.Errorf("[Graviton] ERROR: %v", terr)
+			return changes, terr
+		}
+	}
+
+	key := invokeDetailsKey(signer, invokedetails.Txid, topoheight, entrypoint)
+
+	tree.Put([]byte(key), confBytes) // insert a value
+	changes = true
+	_, cerr := g.commitTrees(tree)
+	if cerr != nil {
+		logger.Errorf("[Graviton] ERROR: %v", cerr)
+		return changes, cerr
+	}
+	return changes, nil
+}
+
+// Returns all scinvoke calls from a given scid
+func (g *GravitonStore) GetAllSCIDInvokeDetails(scid string) (invokedetails []*structures.SCTXParse) {
+	store := g.DB
+	ss, err := store.LoadSnapshot(0)
+	if err != nil {
+		return
+	}
+	tree, _ := g.getTree(ss, scid)
+	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
+	if tree == nil {
+		var terr error
+		logger.Errorf("[Graviton-GetAllSCIDInvokeDetails] ERROR: Tree is nil for '%v'. Attempting to rollback 1 snapshot", scid)
+		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
+		if preverr != nil {
+			return
+		}
+		tree, terr = prevss.GetTree(scid)
+		if tree == nil {
+			logger.Errorf("[Graviton] ERROR: %v", terr)
+			return
+		}
+	}
+
+	c := tree.Cursor()
+	// Duplicate the LATEST (snapshot 0) to the new DB, this starts the DB over again, but still retaining X number of old DBs for version in future use cases. Here we get the vals before swapping to new db in mem
+	for _, v, err := c.First(); err == nil; _, v, err = c.Next() {
+		var currdetails *structures.SCTXParse
+		_ = json.Unmarshal(v, &currdetails)
+		invokedetails = append(invokedetails, currdetails)
+	}
+
+	// Sort heights so most recent is index 0 [if preferred reverse, just swap > with <]
+	sort.SliceStable(invokedetails, func(i, j int) bool {
+		return invokedetails[i].Height < invokedetails[j].Height
+	})
+
+	return
+}
+
+// Retruns all scinvoke calls from a given scid that match a given entrypoint
+func (g *GravitonStore) GetAllSCIDInvokeDetailsByEntrypoint(scid string, entrypoint string) (invokedetails []*structures.SCTXParse) {
+	store := g.DB
+	ss, err := store.LoadSnapshot(0)
+	if err != nil {
+		return
+	}
+	tree, _ := g.getTree(ss, scid)
+	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
+	if tree == nil {
+		var terr error
+		logger.Errorf("[Graviton-GetAllSCIDInvokeDetailsByEntrypoint] ERROR: Tree is nil for '%v'. Attempting to rollback 1 snapshot", scid)
+		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
+		if preverr != nil {
+			return
+		}
+		tree, terr = prevss.GetTree(scid)
+		if tree == nil {
+			logger.Errorf("[Graviton] ERROR: %v", terr)
+			return
+		}
+	}
+
+	c := tree.Cursor()
+	// Duplicate the LATEST (snapshot 0) to the new DB, this starts the DB over again, but still retaining X number of old DBs for version in future use cases. Here we get the vals before swapping to new db in mem
+	for _, v, err := c.First(); err == nil; _, v, err = c.Next() {
+		var currdetails *structures.SCTXParse
+		_ = json.Unmarshal(v, &currdetails)
+		if currdetails.Entrypoint == entrypoint {
+			invokedetails = append(invokedetails, currdetails)
+		}
+	}
+
+	// Sort heights so most recent is index 0 [if preferred reverse, just swap > with <]
+	sort.SliceStable(invokedetails, func(i, j int) bool {
+		return invokedetails[i].Height < invokedetails[j].Height
+	})
+
+	return
+}
+
+// Returns all scinvoke calls from a given scid whose arg name matches value (compared as a string). Uses the scid's secondary index on name if one is kept, otherwise all invokes of the scid are checked
+func (g *GravitonStore) GetAllSCIDInvokeDetailsByArg(scid string, name string, value string) (invokedetails []*structures.SCTXParse) {
+	store := g.DB
+	ss, err := store.LoadSnapshot(0)
+	if err != nil {
+		return
+	}
+	tree, _ := g.getTree(ss, scid)
+	atree, _ := g.getTree(ss, scid+"args")
+	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
+	if tree == nil || atree == nil {
+		var terr error
+		logger.Errorf("[Graviton-GetAllSCIDInvokeDetailsByArg] ERROR: Tree is nil for '%v'. Attempting to rollback 1 snapshot", scid)
+		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
+		if preverr != nil {
+			return
+		}
+		tree, terr = prevss.GetTree(scid)
+		if tree == nil {
+			logger.Errorf("[Graviton] ERROR: %v", terr)
+			return
+		}
+		atree, terr = prevss.GetTree(scid + "args")
+		if atree == nil {
+			logger.Errorf("[Graviton] ERROR: %v", terr)
+			return
+		}
+	}
+
+	var indexed []string
+	if v, ierr := atree.Get([]byte(indexed_args_key)); ierr == nil {
+		_ = json.Unmarshal(v, &indexed)
+	}
+
+	if idExist(indexed, name) {
+		var entries []*argIndexEntry
+		if v, ierr := atree.Get([]byte(argIndexKey(name, value))); ierr == nil {
+			_ = json.Unmarshal(v, &entries)
+		}
+		for _, e := range entries {
+			v, ierr := tree.Get([]byte(e.Key))
+			if ierr != nil {
+				continue
+			}
+			var currdetails *structures.SCTXParse
+			_ = json.Unmarshal(v, &currdetails)
+			if currdetails != nil && argMatch(currdetails, name, value) {
+				invokedetails = append(invokedetails, currdetails)
+			}
+		}
+	} else {
+		c := tree.Cursor()
+		for _, v, err := c.First(); err == nil; _, v, err = c.Next() {
+			var currdetails *structures.SCTXParse
+			_ = json.Unmarshal(v, &currdetails)
+			if currdetails != nil && argMatch(currdetails, name, value) {
+				invokedetails = append(invokedetails, currdetails)
+			}
+		}
+	}
+
+	sort.SliceStable(invokedetails, func(i, j int) bool {
+		return invokedetails[i].Height < invokedetails[j].Height
+	})
+
+	return
+}
+
+// Returns all scinvoke calls from a given scid that match a given signer
+func (g *GravitonStore) GetAllSCIDInvokeDetailsBySigner(scid string, signerPart string) (invokedetails []*structures.SCTXParse) {
+	store := g.DB
+	ss, err := store.LoadSnapshot(0)
+	if err != nil {
+		return
+	}
+	tree, _ := g.getTree(ss, scid)
+	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
+	if tree == nil {
+		var terr error
+		logger.Errorf("[Graviton-GetAllSCIDInvokeDetailsBySigner] ERROR: Tree is nil for '%v'. Attempting to rollback 1 snapshot", scid)
+		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
+		if preverr != nil {
+			return
+		}
+		tree, terr = prevss.GetTree(scid)
+		if tree == nil {
+			logger.Errorf("[Graviton] ERROR: %v", terr)
+			return
+		}
+	}
+
+	c := tree.Cursor()
+	// Duplicate the LATEST (snapshot 0) to the new DB, this starts the DB over again, but still retaining X number of old DBs for version in future use cases. Here we get the vals before swapping to new db in mem
+	for _, v, err := c.First(); err == nil; _, v, err = c.Next() {
+		var currdetails *structures.SCTXParse
+		_ = json.Unmarshal(v, &currdetails)
+		split := strings.Split(currdetails.Sender, signerPart)
+		if len(split) > 1 {
+			invokedetails = append(invokedetails, currdetails)
+		}
+	}
+
+	// Sort heights so most recent is index 0 [if preferred reverse, just swap > with <]
+	sort.SliceStable(invokedetails, func(i, j int) bool {
+		return invokedetails[i].Height < invokedetails[j].Height
+	})
+
+	return
+}
+
+// Stores simple getinfo polling from the daemon
+func (g *GravitonStore) StoreGetInfoDetails(getinfo *structures.GetInfo) (changes bool, err error) {
+	confBytes, err := json.Marshal(getinfo)
+	if err != nil {
+		return changes, fmt.Errorf("[StoreGetInfoDetails] could not marshal getinfo info: %v", err)
+	}
+
+	store := g.DB
+	ss, err := store.LoadSnapshot(0) // load most recent snapshot
+	if err != nil {
+		return
+	}
+
+	// Check for g.migrating, if so sleep for g.DBMigrateWait ms
+	for g.migrating == 1 {
+		logger.Debugf("[StoreGetInfoDetails] G is migrating... sleeping for %v...", g.DBMigrateWait)
+		time.Sleep(g.DBMigrateWait)
+		store = g.DB
+		ss, err = store.LoadSnapshot(0) // load most recent snapshot
+		if err != nil {
+			return
+		}
+	}
+
+	tree, _ := g.getTree(ss, "getinfo")
+	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
+	if tree == nil {
+		var terr error
+		logger.Errorf("[Graviton-StoreGetInfoDetails] ERROR: Tree is nil for 'getinfo'. Attempting to rollback 1 snapshot")
+		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
+		if preverr != nil {
+			return changes, preverr
+		}
+		tree, terr = prevss.GetTree("getinfo")
+		if tree == nil {
+			logger.Errorf("[Graviton] ERROR: %v", terr)
+			return changes, terr
+		}
+	}
+	key := "getinfo"
+	tree.Put([]byte(key), confBytes) // insert a value
+	changes = true
+	_, cerr := g.commitTrees(tree)
+	if cerr != nil {
+		logger.Errorf("[Graviton] ERROR: %v", cerr)
+		return changes, cerr
+	}
+	return changes, nil
+}
+
+// Returns simple getinfo polling from the daemon
+func (g *GravitonStore) GetGetInfoDetails() (getinfo *structures.GetInfo) {
+	store := g.DB
+	ss, err := store.LoadSnapshot(0) // load most recent snapshot
+	if err != nil {
+		return
+	}
+
+	tree, _ := g.getTree(ss, "getinfo") // use or create tree named by poolhost in config
+	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
+	if tree == nil {
+		var terr error
+		logger.Errorf("[Graviton-GetGetInfoDetails] ERROR: Tree is nil for 'getinfo'. Attempting to rollback 1 snapshot")
+		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
+		if preverr != nil {
+			return
+		}
+		tree, terr = prevss.GetTree("getinfo")
+		if tree == nil {
+			logger.Errorf("[Graviton] ERROR: %v", terr)
+			return
+		}
+	}
+	key := "getinfo"
+
+	v, _ := tree.Get([]byte(key))
+
+	if v != nil {
+		_ = json.Unmarshal(v, &getinfo)
+		return
+	}
+
+	return nil
+}
+
+// Stores SC variables at a given topoheight (called on any new scdeploy or scinvoke actions). variables is the full variable set at topoheight, only the changes from the variables stored below topoheight are kept (with a keyframe of the full set every var_keyframe_interval heights)
+func (g *GravitonStore) StoreSCIDVariableDetails(scid string, variables []*structures.SCIDVariable, topoheight int64) (changes bool, err error) {
+	store := g.DB
+	ss, err := store.LoadSnapshot(0) // load most recent snapshot
+	if err != nil {
+		return
+	}
+
+	// Check for g.migrating, if so sleep for g.DBMigrateWait ms
+	for g.migrating == 1 {
+		logger.Debugf("[StoreSCIDVariableDetails] G is migrating... sleeping for %v...", g.DBMigrateWait)
+		time.Sleep(g.DBMigrateWait)
+		store = g.DB
+		ss, err = store.LoadSnapshot(0) // load most recent snapshot
+		if err != nil {
+			return
+		}
+	}
+
+	treename := scid + "vars"
+	tree, _ := g.getTree(ss, treename)
+	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
+	if tree == nil {
+		var terr error
+		logger.Errorf("[Graviton-StoreSCIDVariableDetails] ERROR: Tree is nil for '%v'. Attempting to rollback 1 snapshot", treename)
+		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
+		if preverr != nil {
+			return changes, preverr
+		}
+		tree, terr = prevss.GetTree(treename)
+		if tree == nil {
+			logger.Errorf("[Graviton] ERROR: %v", terr)
+			return changes, terr
+		}
+	}
+
+	entries := make(map[int64][]byte)
+	c := tree.Cursor()
+	for k, v, err := c.First(); err == nil; k, v, err = c.Next() {
+		h, _ := strconv.ParseInt(string(k), 10, 64)
+		entries[h] = v
+	}
+
+	confBytes, err := encodeVarEntry(entries, variables, topoheight)
+	if err != nil {
+		return changes, fmt.Errorf("[StoreSCIDVariableDetails] could not marshal variables info: %v", err)
+	}
+	if confBytes == nil {
+		return
+	}
+
+	key := strconv.FormatInt(topoheight, 10)
+	tree.Put([]byte(key), confBytes) // insert a value
+	changes = true
+	_, cerr := g.commitTrees(tree)
+	if cerr != nil {
+		logger.Errorf("[Graviton] ERROR: %v", cerr)
+		return changes, cerr
+	}
+	return changes, nil
+}
+
+// Stores the SC variables changed at each topoheight (deleted variables having a nil Value), as returned by GetSCIDVariableHistory. Replaces any variables already stored for scid at those heights
+func (g *GravitonStore) StoreSCIDVariableHistory(scid string, history map[int64][]*structures.SCIDVariable) (changes bool, err error) {
+	entries, err := encodeVarHistory(history)
+	if err != nil {
+		return changes, fmt.Errorf("[StoreSCIDVariableHistory] could not marshal variables info: %v", err)
+	}
+
+	store := g.DB
+	ss, err := store.LoadSnapshot(0) // load most recent snapshot
+	if err != nil {
+		return
+	}
+
+	// Check for g.migrating, if so sleep for g.DBMigrateWait ms
+	for g.migrating == 1 {
+		logger.Debugf("[StoreSCIDVariableHistory] G is migrating... sleeping for %v...", g.DBMigrateWait)
+		time.Sleep(g.DBMigrateWait)
+		store = g.DB
+		ss, err = store.LoadSnapshot(0) // load most recent snapshot
+		if err != nil {
+			return
+		}
+	}
+
+	treename := scid + "vars"
+	tree, _ := g.getTree(ss, treename)
+	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
+	if tree == nil {
+		var terr error
+		logger.Errorf("[Graviton-StoreSCIDVariableHistory] ERROR: Tree is nil for '%v'. Attempting to rollback 1 snapshot", treename)
+		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
+		if preverr != nil {
+			return changes, preverr
+		}
+		tree, terr = prevss.GetTree(treename)
+		if tree == nil {
+			logger.Errorf("[Graviton] ERROR: %v", terr)
+			return changes, terr
+		}
+	}
+
+	for h, confBytes := range entries {
+		tree.Put([]byte(strconv.FormatInt(h, 10)), confBytes)
+		changes = true
+	}
+	if !changes {
+		return
+	}
+
+	_, cerr := g.commitTrees(tree)
+	if cerr != nil {
+		logger.Errorf("[Graviton] ERROR: %v", cerr)
+		return changes, cerr
+	}
+	return changes, nil
+}
+
+// Converts the variables stored for scid in the format prior to SCIDVariableFormat (full sets and diffs kept alike as arrays of variables) to keyframes and changes
+func (g *GravitonStore) MigrateSCIDVariableDetails(scid string) (changes bool, err error) {
+	entries := g.getSCIDVarEntries(scid)
+	if !hasLegacyVarEntries(entries) {
+		return
+	}
+
+	return g.StoreSCIDVariableHistory(scid, decodeVarHistory(entries))
+}
+
+// Gets SC variables at a given topoheight
+func (g *GravitonStore) GetSCIDVariableDetailsAtTopoheight(scid string, topoheight int64) (hVars []*structures.SCIDVariable) {
+	state, _, _ := buildVarState(g.getSCIDVarEntries(scid), topoheight)
+
+	return state.variables()
+}
+
+// Gets the SC variables changed at each stored topoheight (deleted variables having a nil Value), rather than the variable state at a height
+func (g *GravitonStore) GetSCIDVariableHistory(scid string) map[int64][]*structures.SCIDVariable {
+	return decodeVarHistory(g.getSCIDVarEntries(scid))
+}
+
+// Gets SC variables at the latest stored topoheight
+func (g *GravitonStore) GetAllSCIDVariableDetails(scid string) (hVars []*structures.SCIDVariable) {
+	state, _, _ := buildVarState(g.getSCIDVarEntries(scid), math.MaxInt64)
+
+	return state.variables()
+}
+
+// Returns the stored variable entries of a given scid by topoheight
+func (g *GravitonStore) getSCIDVarEntries(scid string) (entries map[int64][]byte) {
+	entries = make(map[int64][]byte)
+
+	store := g.DB
+	ss, err := store.LoadSnapshot(0)
+	if err != nil {
+		return
+	}
+	treename := scid + "vars"
+	tree, _ := g.getTree(ss, treename)
+	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
+	if tree == nil {
+		var terr error
+		logger.Errorf("[Graviton-getSCIDVarEntries] ERROR: Tree is nil for '%v'. Attempting to rollback 1 snapshot", treename)
+		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
+		if preverr != nil {
+			return
+		}
+		tree, terr = prevss.GetTree(treename)
+		if tree == nil {
+			logger.Errorf("[Graviton] ERROR: %v", terr)
+			return
+		}
+	}
+
+	c := tree.Cursor()
+	for k, v, err := c.First(); err == nil; k, v, err = c.Next() {
+		topoheight, _ := strconv.ParseInt(string(k), 10, 64)
+		entries[topoheight] = v
+	}
+
+	return
+}
+
+// Stores the DERO and asset balances of a given scid at a given topoheight
+func (g *GravitonStore) StoreSCIDBalanceDetails(scid string, balances map[string]uint64, topoheight int64) (changes bool, err error) {
+	confBytes, err := json.Marshal(balances)
+	if err != nil {
+		return changes, fmt.Errorf("[StoreSCIDBalanceDetails] could not marshal balances info: %v", err)
+	}
+
+	store := g.DB
+	ss, err := store.LoadSnapshot(0) // load most recent snapshot
+	if err != nil {
+		return
+	}
+
+	// Check for g.migrating, if so sleep for g.DBMigrateWait ms
+	for g.migrating == 1 {
+		logger.Debugf("[StoreSCIDBalanceDetails] G is migrating... sleeping for %v...", g.DBMigrateWait)
+		time.Sleep(g.DBMigrateWait)
+		store = g.DB
+		ss, err = store.LoadSnapshot(0) // load most recent snapshot
+		if err != nil {
+			return
+		}
+	}
+
+	treename := scid + "balances"
+	tree, _ := g.getTree(ss, treename)
+	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
+	if tree == nil {
+		var terr error
+		logger.Errorf("[Graviton-StoreSCIDBalanceDetails] ERROR: Tree is nil for '%v'. Attempting to rollback 1 snapshot", treename)
+		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
+		if preverr != nil {
+			return changes, preverr
+		}
+		tree, terr = prevss.GetTree(treename)
+		if tree == nil {
+			logger.Errorf("[Graviton] ERROR: %v", terr)
+			return changes, terr
+		}
+	}
+	key := strconv.FormatInt(topoheight, 10)
+	tree.Put([]byte(key), confBytes) // insert a value
+	changes = true
+	_, cerr := g.commitTrees(tree)
+	if cerr != nil {
+		logger.Errorf("[Graviton] ERROR: %v", cerr)
+		return changes, cerr
+	}
+	return changes, nil
+}
+
+// Returns the DERO and asset balances of a given scid as of a given topoheight (the most recent stored balances at or below it)
+func (g *GravitonStore) GetSCIDBalanceDetailsAtTopoheight(scid string, topoheight int64) (balances map[string]uint64) {
+	for _, v := range g.GetAllSCIDBalanceDetails(scid) {
+		if v.Height > topoheight {
+			break
+		}
+		balances = v.Balances
+	}
+
+	return
+}
+
+// Returns all of the stored balances of a given scid, in height order
+func (g *GravitonStore) GetAllSCIDBalanceDetails(scid string) (hBalances []*structures.SCIDBalances) {
+	store := g.DB
+	ss, err := store.LoadSnapshot(0)
+	if err != nil {
+		return
+	}
+	treename := scid + "balances"
+	tree, _ := g.getTree(ss, treename)
+	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
+	if tree == nil {
+		var terr error
+		logger.Errorf("[Graviton-GetAllSCIDBalanceDetails] ERROR: Tree is nil for '%v'. Attempting to rollback 1 snapshot", treename)
+		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
+		if preverr != nil {
+			return
+		}
+		tree, terr = prevss.GetTree(treename)
+		if tree == nil {
+			logger.Errorf("[Graviton] ERROR: %v", terr)
+			return
+		}
+	}
+
+	c := tree.Cursor()
+	for k, v, err := c.First(); err == nil; k, v, err = c.Next() {
+		topoheight, perr := strconv.ParseInt(string(k), 10, 64)
+		if perr != nil {
+			continue
+		}
+		var balances map[string]uint64
+		_ = json.Unmarshal(v, &balances)
+		hBalances = append(hBalances, &structures.SCIDBalances{Height: topoheight, Balances: balances})
+	}
+
+	sortSCIDBalances(hBalances)
+
+	return
+}
+
+// Stores a version of the code of a given scid at the height it was installed or updated
+func (g *GravitonStore) StoreSCIDCode(scid string, code *structures.SCIDCode) (changes bool, err error) {
+	confBytes, err := json.Marshal(code)
+	if err != nil {
+		return changes, fmt.Errorf("[StoreSCIDCode] could not marshal code info: %v", err)
+	}
+
+	store := g.DB
+	ss, err := store.LoadSnapshot(0) // load most recent snapshot
+	if err != nil {
+		return
+	}
+
+	// Check for g.migrating, if so sleep for g.DBMigrateWait ms
+	for g.migrating == 1 {
+		logger.Debugf("[StoreSCIDCode] G is migrating... sleeping for %v...", g.DBMigrateWait)
+		time.Sleep(g.DBMigrateWait)
+		store = g.DB
+		ss, err = store.LoadSnapshot(0) // load most recent snapshot
+		if err != nil {
+			return
+		}
+	}
+
+	treename := scid + "code"
+	tree, _ := g.getTree(ss, treename)
+	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
+	if tree == nil {
+		var terr error
+		logger.Errorf("[Graviton-StoreSCIDCode] ERROR: Tree is nil for '%v'. Attempting to rollback 1 snapshot", treename)
+		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
+		if preverr != nil {
+			return changes, preverr
+		}
+		tree, terr = prevss.GetTree(treename)
+		if tree == nil {
+			logger.Errorf("[Graviton] ERROR: %v", terr)
+			return changes, terr
+		}
+	}
+	key := strconv.FormatInt(code.Height, 10)
+	tree.Put([]byte(key), confBytes) // insert a value
+	changes = true
+	_, cerr := g.commitTrees(tree)
+	if cerr != nil {
+		logger.Errorf("[Graviton] ERROR: %v", cerr)
+		return changes, cerr
+	}
+	return changes, nil
+}
+
+// Returns the code of a given scid as of a given topoheight (the most recent stored version at or below it)
+func (g *GravitonStore) GetSCIDCodeAtTopoheight(scid string, topoheight int64) (code *structures.SCIDCode) {
+	for _, v := range g.GetAllSCIDCode(scid) {
+		if v.Height > topoheight {
+			break
+		}
+		code = v
+	}
+
+	return
+}
+
+// Returns all of the stored code versions of a given scid, in height order
+func (g *GravitonStore) GetAllSCIDCode(scid string) (hCode []*structures.SCIDCode) {
+	store := g.DB
+	ss, err := store.LoadSnapshot(0)
+	if err != nil {
+		return
+	}
+	treename := scid + "code"
+	tree, _ := g.getTree(ss, treename)
+	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
+	if tree == nil {
+		var terr error
+		logger.Errorf("[Graviton-GetAllSCIDCode] ERROR: Tree is nil for '%v'. Attempting to rollback 1 snapshot", treename)
+		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
+		if preverr != nil {
+			return
+		}
+		tree, terr = prevss.GetTree(treename)
+		if tree == nil {
+			logger.Errorf("[Graviton] ERROR: %v", terr)
+			return
+		}
+	}
+
+	c := tree.Cursor()
+	for _, v, err := c.First(); err == nil; _, v, err = c.Next() {
+		var code *structures.SCIDCode
+		if err := json.Unmarshal(v, &code); err == nil && code != nil {
+			hCode = append(hCode, code)
+		}
+	}
+
+	sortSCIDCode(hCode)
+
+	return
+}
+
+// Gets SC variable keys at given topoheight who's value equates to a given interface{} (string/uint64)
+func (g *GravitonStore) GetSCIDKeysByValue(scid string, val interface{}, height int64, rmax bool) (keysstring []string, keysuint64 []uint64) {
+	scidInteractionHeights := g.GetSCIDInteractionHeight(scid)
+
+	interactionHeight := g.GetInteractionIndex(height, scidInteractionHeights, rmax)
+
+	// TODO: If there's no interaction height, do we go get scvars against daemon and store? Or do we just ignore and return nil
+	variables := g.GetSCIDVariableDetailsAtTopoheight(scid, interactionHeight)
+
+	// Switch against the value passed. If it's a uint64 or string
+	switch inpvar := val.(type) {
+	case uint64:
+		for _, v := range variables {
+			switch cval := v.Value.(type) {
+			case float64:
+				if inpvar == uint64(cval) {
+					switch ckey := v.Key.(type) {
+					case float64:
+						keysuint64 = append(keysuint64, uint64(ckey))
+					case uint64:
+						keysuint64 = append(keysuint64, ckey)
+					default:
+						// default just store as string. Keys should only ever be strings or uint64, however, but assume default to string
+						keysstring = append(keysstring, v.Key.(string))
+					}
+				}
+			case uint64:
+				if inpvar == cval {
+					switch ckey := v.Key.(type) {
+					case float64:
+						keysuint64 = append(keysuint64, uint64(ckey))
+					case uint64:
+						keysuint64 = append(keysuint64, ckey)
+					default:
+						// default just store as string. Keys should only ever be strings or uint64, however, but assume default to string
+						keysstring = append(keysstring, v.Key.(string))
+					}
+				}
+			default:
+				// Nothing - expect only string/uint64 for value types
+			}
+		}
+	case string:
+		for _, v := range variables {
+			switch cval := v.Value.(type) {
+			case string:
+				if inpvar == cval {
+					switch ckey := v.Key.(type) {
+					case float64:
+						keysuint64 = append(keysuint64, uint64(ckey))
+					case uint64:
+						keysuint64 = append(keysuint64, ckey)
+					default:
+						// default just store as string. Keys should only ever be strings or uint64, however, but assume default to string
+						keysstring = append(keysstring, v.Key.(string))
+					}
+				}
+			default:
+				// Nothing - expect only string/uint64 for value types
+			}
+		}
+	default:
+		// Nothing - expect only string/uint64 for value types
+	}
+
+	return keysstring, keysuint64
+}
+
+// Gets SC values by key at given topoheight who's key equates to a given interface{} (string/uint64)
+func (g *GravitonStore) GetSCIDValuesByKey(scid string, key interface{}, height int64, rmax bool) (valuesstring []string, valuesuint64 []uint64) {
+	scidInteractionHeights := g.GetSCIDInteractionHeight(scid)
+
+	interactionHeight := g.GetInteractionIndex(height, scidInteractionHeights, rmax)
+
+	// TODO: If there's no interaction height, do we go get scvars against daemon and store? Or do we just ignore and return nil
+	variables := g.GetSCIDVariableDetailsAtTopoheight(scid, interactionHeight)
+
+	// Switch against the value passed. If it's a uint64 or string
+	switch inpvar := key.(type) {
+	case uint64:
+		for _, v := range variables {
+			switch ckey := v.Key.(type) {
+			case float64:
+				if inpvar == uint64(ckey) {
+					switch cval := v.Value.(type) {
+					case float64:
+						valuesuint64 = append(valuesuint64, uint64(cval))
+					case uint64:
+						valuesuint64 = append(valuesuint64, cval)
+					default:
+						// default just store as string. Keys should only ever be strings or uint64, however, but assume default to string
+						valuesstring = append(valuesstring, v.Value.(string))
+					}
+				}
+			case uint64:
+				if inpvar == ckey {
+					switch cval := v.Value.(type) {
+					case float64:
 						valuesuint64 = append(valuesuint64, uint64(cval))
 					case uint64:
 						valuesuint64 = append(valuesuint64, cval)
@@ -1284,185 +2970,1580 @@ func (g *GravitonStore) GetSCIDValuesByKey(scid string, key interface{}, height
 						valuesstring = append(valuesstring, v.Value.(string))
 					}
 				}
-			case uint64:
-				if inpvar == ckey {
-					switch cval := v.Value.(type) {
-					case float64:
-						valuesuint64 = append(valuesuint64, uint64(cval))
-					case uint64:
-						valuesuint64 = append(valuesuint64, cval)
-					default:
-						// default just store as string. Keys should only ever be strings or uint64, however, but assume default to string
-						valuesstring = append(valuesstring, v.Value.(string))
-					}
+			default:
+				// Nothing - expect only string/uint64 for value types
+			}
+		}
+	case string:
+		for _, v := range variables {
+			switch ckey := v.Key.(type) {
+			case string:
+				if inpvar == ckey {
+					switch cval := v.Value.(type) {
+					case float64:
+						valuesuint64 = append(valuesuint64, uint64(cval))
+					case uint64:
+						valuesuint64 = append(valuesuint64, cval)
+					default:
+						// default just store as string. Values should only ever be strings or uint64, however, but assume default to string
+						valuesstring = append(valuesstring, v.Value.(string))
+					}
+				}
+			default:
+				// Nothing - expect only string/uint64 for value types
+			}
+		}
+	default:
+		// Nothing - expect only string/uint64 for value types
+	}
+
+	return valuesstring, valuesuint64
+}
+
+// Stores SC interaction height and detail - height invoked upon and type (scinstall/scinvoke). This is separate tree & k/v since we can query it for other things at less data retrieval
+func (g *GravitonStore) StoreSCIDInteractionHeight(scid string, height int64) (changes bool, err error) {
+	store := g.DB
+	ss, err := store.LoadSnapshot(0) // load most recent snapshot
+	if err != nil {
+		return
+	}
+
+	// Check for g.migrating, if so sleep for g.DBMigrateWait ms
+	for g.migrating == 1 {
+		logger.Debugf("[StoreSCIDInteractionHeight] G is migrating... sleeping for %v...", g.DBMigrateWait)
+		time.Sleep(g.DBMigrateWait)
+		store = g.DB
+		ss, err = store.LoadSnapshot(0) // load most recent snapshot
+		if err != nil {
+			return
+		}
+	}
+
+	treename := scid + "heights"
+	tree, _ := g.getTree(ss, treename)
+	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
+	if tree == nil {
+		var terr error
+		logger.Errorf("[Graviton-StoreSCIDInteractionHeight] ERROR: Tree is nil for '%v'. Attempting to rollback 1 snapshot", treename)
+		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
+		if preverr != nil {
+			return changes, preverr
+		}
+		tree, terr = prevss.GetTree(treename)
+		if tree == nil {
+			logger.Errorf("[Graviton] ERROR: %v", terr)
+			return changes, terr
+		}
+	}
+	key := scid
+	currSCIDInteractionHeight, err := tree.Get([]byte(key))
+	var interactionHeight []int64
+
+	var newInteractionHeight []byte
+
+	if err != nil {
+		interactionHeight = append(interactionHeight, height)
+	} else {
+		// Retrieve value and conovert to SCIDInteractionHeight, so that you can manipulate and update db
+		_ = json.Unmarshal(currSCIDInteractionHeight, &interactionHeight)
+
+		for _, v := range interactionHeight {
+			if v == height {
+				// Return nil if already exists in array.
+				// Clause for this is in event we pop backwards in time and already have this data stored.
+				// Data stored from a false-chain is removed via RollbackToHeight() when a reorg is detected.
+				return changes, nil
+			}
+		}
+		interactionHeight = append(interactionHeight, height)
+	}
+	newInteractionHeight, err = json.Marshal(interactionHeight)
+	if err != nil {
+		return changes, fmt.Errorf("[Graviton] could not marshal interactionHeight info: %v", err)
+	}
+
+	tree.Put([]byte(key), newInteractionHeight)
+	changes = true
+	_, cerr := g.commitTrees(tree)
+	if cerr != nil {
+		logger.Errorf("[Graviton] ERROR: %v", cerr)
+		return changes, cerr
+	}
+	return changes, nil
+}
+
+// Gets SC interaction height and detail by a given SCID
+func (g *GravitonStore) GetSCIDInteractionHeight(scid string) (scidinteractions []int64) {
+	store := g.DB
+	ss, err := store.LoadSnapshot(0) // load most recent snapshot
+	if err != nil {
+		return
+	}
+
+	treename := scid + "heights"
+	tree, _ := g.getTree(ss, treename) // use or create tree named by poolhost in config
+	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
+	if tree == nil {
+		var terr error
+		logger.Errorf("[Graviton-GetSCIDInteractionHeight] ERROR: Tree is nil for '%v'. Attempting to rollback 1 snapshot", treename)
+		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
+		if preverr != nil {
+			return
+		}
+		tree, terr = prevss.GetTree(treename)
+		if tree == nil {
+			logger.Errorf("[Graviton] ERROR: %v", terr)
+			return
+		}
+	}
+	key := scid
+
+	v, _ := tree.Get([]byte(key))
+
+	if v != nil {
+		_ = json.Unmarshal(v, &scidinteractions)
+		return
+	}
+
+	return nil
+}
+
+func (g *GravitonStore) GetInteractionIndex(topoheight int64, heights []int64, rmax bool) (height int64) {
+	if len(heights) <= 0 {
+		return height
+	}
+
+	// Sort heights so most recent is index 0 [if preferred reverse, just swap > with <]
+	sort.SliceStable(heights, func(i, j int) bool {
+		return heights[i] > heights[j]
+	})
+
+	if topoheight > heights[0] || rmax {
+		return heights[0]
+	}
+
+	for i := 1; i < len(heights); i++ {
+		if heights[i] < topoheight {
+			return heights[i]
+		} else if heights[i] == topoheight {
+			return heights[i]
+		}
+	}
+
+	return height
+}
+
+// Stores any SCIDs that were attempted to be deployed but not correct - log scid/fees burnt attempting it. The height of the deploy is kept alongside so it can be rolled back, 0 if not known
+func (g *GravitonStore) StoreInvalidSCIDDeploys(scid string, fee uint64, height int64) (changes bool, err error) {
+	store := g.DB
+	ss, err := store.LoadSnapshot(0) // load most recent snapshot
+	if err != nil {
+		return
+	}
+
+	// Check for g.migrating, if so sleep for g.DBMigrateWait ms
+	for g.migrating == 1 {
+		logger.Debugf("[StoreInvalidSCIDDeploys] G is migrating... sleeping for %v...", g.DBMigrateWait)
+		time.Sleep(g.DBMigrateWait)
+		store = g.DB
+		ss, err = store.LoadSnapshot(0) // load most recent snapshot
+		if err != nil {
+			return
+		}
+	}
+
+	treename := "invalidscids"
+	tree, _ := g.getTree(ss, treename)
+	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
+	if tree == nil {
+		var terr error
+		logger.Errorf("[Graviton-StoreInvalidSCIDDeploys] ERROR: Tree is nil for '%v'. Attempting to rollback 1 snapshot", treename)
+		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
+		if preverr != nil {
+			return changes, preverr
+		}
+		tree, terr = prevss.GetTree(treename)
+		if tree == nil {
+			logger.Errorf("[Graviton] ERROR: %v", terr)
+			return changes, terr
+		}
+	}
+	key := "invalid"
+	currSCIDInteractionHeight, err := tree.Get([]byte(key))
+	currInvalidSCIDs := make(map[string]uint64)
+
+	var newInvalidSCIDs []byte
+
+	if err != nil {
+		currInvalidSCIDs[scid] = fee
+	} else {
+		// Retrieve value and conovert to SCIDInteractionHeight, so that you can manipulate and update db
+		_ = json.Unmarshal(currSCIDInteractionHeight, &currInvalidSCIDs)
+
+		currInvalidSCIDs[scid] = fee
+	}
+	newInvalidSCIDs, err = json.Marshal(currInvalidSCIDs)
+	if err != nil {
+		return changes, fmt.Errorf("[Graviton] could not marshal interactionHeight info: %v", err)
+	}
+
+	tree.Put([]byte(key), newInvalidSCIDs)
+
+	if height > 0 {
+		invalidHeights := make(map[string]int64)
+		if v, gerr := tree.Get([]byte(invalid_scid_heights_key)); gerr == nil {
+			_ = json.Unmarshal(v, &invalidHeights)
+		}
+		invalidHeights[scid] = height
+		newInvalidHeights, err := json.Marshal(invalidHeights)
+		if err != nil {
+			return changes, fmt.Errorf("[Graviton] could not marshal invalid scid heights: %v", err)
+		}
+		tree.Put([]byte(invalid_scid_heights_key), newInvalidHeights)
+	}
+	changes = true
+	_, cerr := g.commitTrees(tree)
+	if cerr != nil {
+		logger.Errorf("[Graviton] ERROR: %v", cerr)
+		return changes, cerr
+	}
+	return changes, nil
+}
+
+// Key within invalidscids of the deploy height of each invalid scid
+const invalid_scid_heights_key = "heights"
+
+// Gets any SCIDs that were attempted to be deployed but not correct and their fees
+func (g *GravitonStore) GetInvalidSCIDDeploys() (invalidSCIDs map[string]uint64) {
+	invalidSCIDs = make(map[string]uint64)
+
+	store := g.DB
+	ss, err := store.LoadSnapshot(0) // load most recent snapshot
+	if err != nil {
+		return
+	}
+
+	treename := "invalidscids"
+	tree, _ := g.getTree(ss, treename) // use or create tree named by poolhost in config
+	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
+	if tree == nil {
+		var terr error
+		logger.Errorf("[Graviton-GetInvalidSCIDDeploys] ERROR: Tree is nil for 'invalidscids'. Attempting to rollback 1 snapshot")
+		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
+		if preverr != nil {
+			return
+		}
+		tree, terr = prevss.GetTree("invalidscids")
+		if tree == nil {
+			logger.Errorf("[Graviton] ERROR: %v", terr)
+			return
+		}
+	}
+	key := "invalid"
+
+	v, _ := tree.Get([]byte(key))
+
+	if v != nil {
+		_ = json.Unmarshal(v, &invalidSCIDs)
+		return
+	}
+
+	return nil
+}
+
+// Miniblock details are stored along with the topoheight of their block, so they can be pruned by height. Details stored before this (or imported without a height) are an array of miniblocks alone, which are taken to be below mbl_heights_from_key
+type miniblockEntry struct {
+	Height     int64
+	Miniblocks []*structures.MBLInfo
+}
+
+// Stats key of the first topoheight miniblock details were stored with their height at
+const mbl_heights_from_key = "mblheightsfrom"
+
+func decodeMiniblockEntry(v []byte) (entry *miniblockEntry) {
+	entry = &miniblockEntry{}
+	if len(v) > 0 && v[0] == '[' {
+		_ = json.Unmarshal(v, &entry.Miniblocks)
+		return
+	}
+
+	_ = json.Unmarshal(v, entry)
+
+	return
+}
+
+// Whether the miniblock details of entry are below belowHeight. Entries without a height are only known to be below heightsFrom, the stored mbl_heights_from_key (0 if not set)
+func (entry *miniblockEntry) below(belowHeight, heightsFrom int64) bool {
+	if entry.Height > 0 {
+		return entry.Height < belowHeight
+	}
+
+	return heightsFrom > 0 && heightsFrom <= belowHeight
+}
+
+// Stores the miniblocks within a given blid, found at topoheight (0 if not known)
+func (g *GravitonStore) StoreMiniblockDetailsByHash(blid string, topoheight int64, mbldetails []*structures.MBLInfo) (changes bool, err error) {
+	for _, v := range mbldetails {
+		_, err := g.StoreMiniblockCountByAddress(v.Miner)
+		if err != nil {
+			logger.Errorf("[Store] ERR - Error adding miniblock count for address '%v'", v.Miner)
+		}
+	}
+
+	confBytes, err := json.Marshal(&miniblockEntry{Height: topoheight, Miniblocks: mbldetails})
+	if err != nil {
+		return changes, fmt.Errorf("[StoreMiniblockDetailsByHash] could not marshal getinfo info: %v", err)
+	}
+
+	store := g.DB
+	ss, err := store.LoadSnapshot(0) // load most recent snapshot
+	if err != nil {
+		return
+	}
+
+	// Swap DB at g.DBMaxSnapshot+ commits. Check for g.migrating, if so sleep for g.DBMigrateWait ms
+	for g.migrating == 1 {
+		logger.Debugf("[StoreMiniblockDetailsByHash] G is migrating... sleeping for %v...", g.DBMigrateWait)
+		time.Sleep(g.DBMigrateWait)
+		store = g.DB
+		ss, err = store.LoadSnapshot(0) // load most recent snapshot
+		if err != nil {
+			return
+		}
+	}
+
+	getTree := func(treename string) (tree *graviton.Tree, terr error) {
+		tree, _ = g.getTree(ss, treename)
+		// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
+		if tree == nil {
+			logger.Errorf("[Graviton-StoreMiniblockDetailsByHash] ERROR: Tree is nil for '%v'. Attempting to rollback 1 snapshot", treename)
+			prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
+			if preverr != nil {
+				return tree, preverr
+			}
+			tree, terr = prevss.GetTree(treename)
+			if tree == nil {
+				logger.Errorf("[Graviton] ERROR: %v", terr)
+				return tree, terr
+			}
+		}
+		return tree, nil
+	}
+
+	tree, err := getTree("miniblocks")
+	if err != nil {
+		return
+	}
+	tree.Put([]byte(blid), confBytes) // insert a value
+	changes = true
+	ctrees := []*graviton.Tree{tree}
+
+	if topoheight > 0 {
+		stree, serr := getTree("stats")
+		if serr != nil {
+			return changes, serr
+		}
+		if v, _ := stree.Get([]byte(mbl_heights_from_key)); v == nil {
+			stree.Put([]byte(mbl_heights_from_key), []byte(strconv.FormatInt(topoheight, 10)))
+			ctrees = append(ctrees, stree)
+		}
+	}
+
+	_, cerr := g.commitTrees(ctrees...)
+	if cerr != nil {
+		logger.Errorf("[Graviton] ERROR: %v", cerr)
+		return changes, cerr
+	}
+	return changes, nil
+}
+
+// Returns all miniblock details for synced chain
+func (g *GravitonStore) GetAllMiniblockDetails() (mbldetails map[string][]*structures.MBLInfo) {
+	mbldetails = make(map[string][]*structures.MBLInfo)
+	store := g.DB
+	ss, err := store.LoadSnapshot(0)
+	if err != nil {
+		return
+	}
+	tree, _ := g.getTree(ss, "miniblocks")
+	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
+	if tree == nil {
+		var terr error
+		logger.Errorf("[Graviton-GetAllMiniblockDetails] ERROR: Tree is nil for 'miniblocks'. Attempting to rollback 1 snapshot")
+		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
+		if preverr != nil {
+			return
+		}
+		tree, terr = prevss.GetTree("miniblocks")
+		if tree == nil {
+			logger.Errorf("[Graviton] ERROR: %v", terr)
+			return
+		}
+	}
+
+	c := tree.Cursor()
+	// Duplicate the LATEST (snapshot 0) to the new DB, this starts the DB over again, but still retaining X number of old DBs for version in future use cases. Here we get the vals before swapping to new db in mem
+	for k, v, err := c.First(); err == nil; k, v, err = c.Next() {
+		mbldetails[string(k)] = decodeMiniblockEntry(v).Miniblocks
+	}
+
+	return
+}
+
+// Returns the miniblocks within a given blid if previously stored
+func (g *GravitonStore) GetMiniblockDetailsByHash(blid string) (miniblocks []*structures.MBLInfo) {
+	store := g.DB
+	ss, err := store.LoadSnapshot(0) // load most recent snapshot
+	if err != nil {
+		return
+	}
+
+	// Swap DB at g.DBMaxSnapshot+ commits. Check for g.migrating, if so sleep for g.DBMigrateWait ms
+	for g.migrating == 1 {
+		logger.Debugf("[GetMiniblockDetailsByHash] G is migrating... sleeping for %v...", g.DBMigrateWait)
+		time.Sleep(g.DBMigrateWait)
+		store = g.DB
+		ss, err = store.LoadSnapshot(0) // load most recent snapshot
+		if err != nil {
+			return
+		}
+	}
+
+	tree, _ := g.getTree(ss, "miniblocks") // use or create tree named by poolhost in config
+	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
+	if tree == nil {
+		var terr error
+		logger.Errorf("[Graviton-GetMiniblockDetailsByHash] ERROR: Tree is nil for 'miniblocks'. Attempting to rollback 1 snapshot")
+		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
+		if preverr != nil {
+			return
+		}
+		tree, terr = prevss.GetTree("miniblocks")
+		if tree == nil {
+			logger.Errorf("[Graviton] ERROR: %v", terr)
+			return
+		}
+	}
+	key := blid
+
+	v, _ := tree.Get([]byte(key))
+
+	if v != nil {
+		return decodeMiniblockEntry(v).Miniblocks
+	}
+
+	return nil
+}
+
+// Stores counts of miniblock finders by address
+func (g *GravitonStore) StoreMiniblockCountByAddress(addr string) (changes bool, err error) {
+	currCount := g.GetMiniblockCountByAddress(addr)
+
+	// Add 1 to currCount
+	currCount++
+
+	confBytes, err := json.Marshal(currCount)
+	if err != nil {
+		return changes, fmt.Errorf("[StoreMiniblockCountByAddress] could not marshal getinfo info: %v", err)
+	}
+
+	store := g.DB
+	ss, err := store.LoadSnapshot(0) // load most recent snapshot
+	if err != nil {
+		return
+	}
+
+	// Check for g.migrating, if so sleep for g.DBMigrateWait ms
+	for g.migrating == 1 {
+		logger.Debugf("[StoreMiniblockCountByAddress] G is migrating... sleeping for %v...", g.DBMigrateWait)
+		time.Sleep(g.DBMigrateWait)
+		store = g.DB
+		ss, err = store.LoadSnapshot(0) // load most recent snapshot
+		if err != nil {
+			return
+		}
+	}
+
+	tree, _ := g.getTree(ss, "blockcount")
+	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
+	if tree == nil {
+		var terr error
+		logger.Errorf("[Graviton-StoreMiniblockCountByAddress] ERROR: Tree is nil for 'blockcount'. Attempting to rollback 1 snapshot")
+		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
+		if preverr != nil {
+			return changes, preverr
+		}
+		tree, terr = prevss.GetTree("blockcount")
+		if tree == nil {
+			logger.Errorf("[Graviton] ERROR: %v", terr)
+			return changes, terr
+		}
+	}
+	key := addr
+	tree.Put([]byte(key), confBytes) // insert a value
+	changes = true
+	_, cerr := g.commitTrees(tree)
+	if cerr != nil {
+		logger.Errorf("[Graviton] ERROR: %v", cerr)
+		return changes, cerr
+	}
+	return changes, nil
+}
+
+// Gets counts of miniblock finders by address
+func (g *GravitonStore) GetMiniblockCountByAddress(addr string) (miniblocks int64) {
+	store := g.DB
+	ss, err := store.LoadSnapshot(0) // load most recent snapshot
+	if err != nil {
+		return
+	}
+
+	// Swap DB at g.DBMaxSnapshot+ commits. Check for g.migrating, if so sleep for g.DBMigrateWait ms
+	for g.migrating == 1 {
+		logger.Debugf("[GetMiniblockDetailsByHash] G is migrating... sleeping for %v...", g.DBMigrateWait)
+		time.Sleep(g.DBMigrateWait)
+		store = g.DB
+		ss, err = store.LoadSnapshot(0) // load most recent snapshot
+		if err != nil {
+			return
+		}
+	}
+
+	tree, _ := g.getTree(ss, "blockcount") // use or create tree named by poolhost in config
+	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
+	if tree == nil {
+		var terr error
+		logger.Errorf("[Graviton-GetMiniblockCountByAddress] ERROR: Tree is nil for 'blockcount'. Attempting to rollback 1 snapshot")
+		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
+		if preverr != nil {
+			return
+		}
+		tree, terr = prevss.GetTree("blockcount")
+		if tree == nil {
+			logger.Errorf("[Graviton] ERROR: %v", terr)
+			return
+		}
+	}
+	key := addr
+
+	v, _ := tree.Get([]byte(key))
+
+	if v != nil {
+		_ = json.Unmarshal(v, &miniblocks)
+		return
+	}
+
+	return
+}
+
+// Gets all SCID interacts from a given address - non-builtin/name scids.
+func (g *GravitonStore) GetSCIDInteractionByAddr(addr string) (scids []string) {
+	// Address activity index is built at write time, the scan below is only used for addresses with nothing indexed (e.g. dbs indexed before it was kept)
+	activity, _ := g.GetAddressActivity(addr, 0, 0)
+	if len(activity) > 0 {
+		for _, v := range activity {
+			if !idExist(scids, v.Scid) {
+				scids = append(scids, v.Scid)
+			}
+		}
+
+		return scids
+	}
+
+	normTxsWithSCID := g.GetAllNormalTxWithSCIDByAddr(addr)
+
+	// Append scids list of normtxs scid interaction
+	for _, v := range normTxsWithSCID {
+		if !idExist(scids, v.Scid) {
+			scids = append(scids, v.Scid)
+		}
+	}
+
+	allSCIDs := g.GetAllOwnersAndSCIDs()
+	for k, _ := range allSCIDs {
+		// Skip builtin name registration, no need to waste cursor time on this one since it's not pertinent to goal of function
+		// TODO: Future state, we'll have much more SCID interaction and this will get slower and slower, will need to speedup. Probably will happen with data re-org in future
+		if k == "0000000000000000000000000000000000000000000000000000000000000001" {
+			continue
+		}
+		invokedetails := g.GetAllSCIDInvokeDetailsBySigner(k, addr)
+		if len(invokedetails) > 0 {
+			if !idExist(scids, k) {
+				scids = append(scids, k)
+			}
+		}
+	}
+
+	return scids
+}
+
+// Stores an address' interaction with a SC to the address' activity
+func (g *GravitonStore) StoreAddressActivity(addr string, activity *structures.AddressActivity) (changes bool, err error) {
+	store := g.DB
+	ss, err := store.LoadSnapshot(0) // load most recent snapshot
+	if err != nil {
+		return
+	}
+
+	// Check for g.migrating, if so sleep for g.DBMigrateWait ms
+	for g.migrating == 1 {
+		logger.Debugf("[StoreAddressActivity] G is migrating... sleeping for %v...", g.DBMigrateWait)
+		time.Sleep(g.DBMigrateWait)
+		store = g.DB
+		ss, err = store.LoadSnapshot(0) // load most recent snapshot
+		if err != nil {
+			return
+		}
+	}
+
+	treename := "addressactivity"
+	tree, _ := g.getTree(ss, treename)
+	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
+	if tree == nil {
+		var terr error
+		logger.Errorf("[Graviton-StoreAddressActivity] ERROR: Tree is nil for 'addressactivity'. Attempting to rollback 1 snapshot")
+		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
+		if preverr != nil {
+			return changes, preverr
+		}
+		tree, terr = prevss.GetTree("addressactivity")
+		if tree == nil {
+			logger.Errorf("[Graviton] ERROR: %v", terr)
+			return changes, terr
+		}
+	}
+	key := addr
+
+	var activities []*structures.AddressActivity
+	if currActivity, aerr := tree.Get([]byte(key)); aerr == nil {
+		_ = json.Unmarshal(currActivity, &activities)
+		for _, v := range activities {
+			if *v == *activity {
+				// Return nil if already exists in array.
+				// Clause for this is in event we pop backwards in time and already have this data stored.
+				// Data stored from a false-chain is removed via RollbackToHeight() when a reorg is detected.
+				return changes, nil
+			}
+		}
+	}
+	activities = append(activities, activity)
+	sortAddressActivity(activities)
+
+	newActivity, err := json.Marshal(activities)
+	if err != nil {
+		return changes, fmt.Errorf("[Graviton] could not marshal address activity info: %v", err)
+	}
+
+	tree.Put([]byte(key), newActivity)
+	changes = true
+	_, cerr := g.commitTrees(tree)
+	if cerr != nil {
+		logger.Errorf("[Graviton] ERROR: %v", cerr)
+		return changes, cerr
+	}
+	return changes, nil
+}
+
+// Returns a page of an address' SC activity in height order, starting at index start and returning at most limit entries (all if limit <= 0). total is the number of entries across all pages
+func (g *GravitonStore) GetAddressActivity(addr string, start int, limit int) (activity []*structures.AddressActivity, total int) {
+	store := g.DB
+	ss, err := store.LoadSnapshot(0) // load most recent snapshot
+	if err != nil {
+		return
+	}
+
+	treename := "addressactivity"
+	tree, _ := g.getTree(ss, treename)
+	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
+	if tree == nil {
+		var terr error
+		logger.Errorf("[Graviton-GetAddressActivity] ERROR: Tree is nil for 'addressactivity'. Attempting to rollback 1 snapshot")
+		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
+		if preverr != nil {
+			return
+		}
+		tree, terr = prevss.GetTree("addressactivity")
+		if tree == nil {
+			logger.Errorf("[Graviton] ERROR: %v", terr)
+			return
+		}
+	}
+	key := addr
+
+	v, _ := tree.Get([]byte(key))
+	if v == nil {
+		return
+	}
+
+	var activities []*structures.AddressActivity
+	_ = json.Unmarshal(v, &activities)
+
+	return pageAddressActivity(activities, start, limit)
+}
+
+// Gets the address activity index of all addresses
+func (g *GravitonStore) GetAllAddressActivity() map[string][]*structures.AddressActivity {
+	results := make(map[string][]*structures.AddressActivity)
+
+	store := g.DB
+	ss, err := store.LoadSnapshot(0) // load most recent snapshot
+	if err != nil {
+		return results
+	}
+
+	treename := "addressactivity"
+	tree, _ := g.getTree(ss, treename)
+	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
+	if tree == nil {
+		var terr error
+		logger.Errorf("[Graviton-GetAllAddressActivity] ERROR: Tree is nil for 'addressactivity'. Attempting to rollback 1 snapshot")
+		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
+		if preverr != nil {
+			return results
+		}
+		tree, terr = prevss.GetTree("addressactivity")
+		if tree == nil {
+			logger.Errorf("[Graviton] ERROR: %v", terr)
+			return results
+		}
+	}
+
+	c := tree.Cursor()
+	for k, v, err := c.First(); err == nil; k, v, err = c.Next() {
+		var activities []*structures.AddressActivity
+		_ = json.Unmarshal(v, &activities)
+		results[string(k)] = activities
+	}
+
+	return results
+}
+
+// Adds invokedetails of a given scid to its secondary index on the invoke arg name, marking name as indexed for the scid. Invokes without the arg are skipped
+func (g *GravitonStore) StoreSCIDArgIndex(scid string, name string, invokedetails []*structures.SCTXParse) (changes bool, err error) {
+	store := g.DB
+	ss, err := store.LoadSnapshot(0) // load most recent snapshot
+	if err != nil {
+		return
+	}
+
+	// Check for g.migrating, if so sleep for g.DBMigrateWait ms
+	for g.migrating == 1 {
+		logger.Debugf("[StoreSCIDArgIndex] G is migrating... sleeping for %v...", g.DBMigrateWait)
+		time.Sleep(g.DBMigrateWait)
+		store = g.DB
+		ss, err = store.LoadSnapshot(0) // load most recent snapshot
+		if err != nil {
+			return
+		}
+	}
+
+	treename := scid + "args"
+	tree, _ := g.getTree(ss, treename)
+	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
+	if tree == nil {
+		var terr error
+		logger.Errorf("[Graviton-StoreSCIDArgIndex] ERROR: Tree is nil for '%v'. Attempting to rollback 1 snapshot", treename)
+		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
+		if preverr != nil {
+			return changes, preverr
+		}
+		tree, terr = prevss.GetTree(treename)
+		if tree == nil {
+			logger.Errorf("[Graviton] ERROR: %v", terr)
+			return changes, terr
+		}
+	}
+
+	var indexed []string
+	if v, ierr := tree.Get([]byte(indexed_args_key)); ierr == nil {
+		_ = json.Unmarshal(v, &indexed)
+	}
+	if !idExist(indexed, name) {
+		indexed = append(indexed, name)
+		ibytes, err := json.Marshal(indexed)
+		if err != nil {
+			return changes, fmt.Errorf("[Graviton] could not marshal indexed args info: %v", err)
+		}
+		tree.Put([]byte(indexed_args_key), ibytes)
+		changes = true
+	}
+
+	index := make(map[string][]*argIndexEntry)
+	for _, invoke := range invokedetails {
+		if value, ok := invoke.Args[name]; ok {
+			ikey := argIndexKey(name, value)
+			if _, ok := index[ikey]; ok {
+				continue
+			}
+			var entries []*argIndexEntry
+			if v, ierr := tree.Get([]byte(ikey)); ierr == nil {
+				_ = json.Unmarshal(v, &entries)
+			}
+			index[ikey] = entries
+		}
+	}
+
+	if addArgIndexEntries(index, name, invokedetails) > 0 {
+		for ikey, entries := range index {
+			ebytes, err := json.Marshal(entries)
+			if err != nil {
+				return changes, fmt.Errorf("[Graviton] could not marshal arg index info: %v", err)
+			}
+			tree.Put([]byte(ikey), ebytes)
+		}
+		changes = true
+	}
+
+	if !changes {
+		return
+	}
+
+	_, cerr := g.commitTrees(tree)
+	if cerr != nil {
+		logger.Errorf("[Graviton] ERROR: %v", cerr)
+		return changes, cerr
+	}
+	return changes, nil
+}
+
+// Returns the invoke arg names a secondary index is kept on for a given scid
+func (g *GravitonStore) GetSCIDIndexedArgs(scid string) (names []string) {
+	store := g.DB
+	ss, err := store.LoadSnapshot(0) // load most recent snapshot
+	if err != nil {
+		return
+	}
+
+	treename := scid + "args"
+	tree, _ := g.getTree(ss, treename)
+	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
+	if tree == nil {
+		var terr error
+		logger.Errorf("[Graviton-GetSCIDIndexedArgs] ERROR: Tree is nil for '%v'. Attempting to rollback 1 snapshot", treename)
+		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
+		if preverr != nil {
+			return
+		}
+		tree, terr = prevss.GetTree(treename)
+		if tree == nil {
+			logger.Errorf("[Graviton] ERROR: %v", terr)
+			return
+		}
+	}
+
+	v, _ := tree.Get([]byte(indexed_args_key))
+	if v != nil {
+		_ = json.Unmarshal(v, &names)
+	}
+
+	return
+}
+
+// Number of block hashes kept below the highest stored one, enough to walk back to a fork point within the indexer max_reorg_depth
+const block_hashes_kept = int64(100)
+
+// Max number of older block hashes trimmed per store, so that trimming the hashes of an index from before they were trimmed is spread out
+const block_hashes_trim_chunk = int64(1000)
+
+// Stats key of the height block hashes have been trimmed below
+const block_hashes_trimmed_key = "bhtrimmedheight"
+
+// Returns the heights from, to that the block hashes below to should be trimmed over, given the top stored height and the height trimmed below so far
+func blockHashTrimRange(top, trimmed int64) (from, to int64) {
+	to = top - block_hashes_kept
+	if to-trimmed > block_hashes_trim_chunk {
+		to = trimmed + block_hashes_trim_chunk
+	}
+
+	return trimmed, to
+}
+
+// Stores the block hash of indexed topoheights, used to detect chain reorgs against the daemon. Hashes more than block_hashes_kept below the highest given are trimmed
+func (g *GravitonStore) StoreBlockHashes(blockhashes map[int64]string) (changes bool, err error) {
+	store := g.DB
+	ss, err := store.LoadSnapshot(0) // load most recent snapshot
+	if err != nil {
+		return
+	}
+
+	// Check for g.migrating, if so sleep for g.DBMigrateWait ms
+	for g.migrating == 1 {
+		logger.Debugf("[StoreBlockHashes] G is migrating... sleeping for %v...", g.DBMigrateWait)
+		time.Sleep(g.DBMigrateWait)
+		store = g.DB
+		ss, err = store.LoadSnapshot(0) // load most recent snapshot
+		if err != nil {
+			return
+		}
+	}
+
+	tree, _ := g.getTree(ss, "blockhashes")
+	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
+	if tree == nil {
+		var terr error
+		logger.Errorf("[Graviton-StoreBlockHashes] ERROR: Tree is nil for 'blockhashes'. Attempting to rollback 1 snapshot")
+		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
+		if preverr != nil {
+			return changes, preverr
+		}
+		tree, terr = prevss.GetTree("blockhashes")
+		if tree == nil {
+			logger.Errorf("[Graviton] ERROR: %v", terr)
+			return changes, terr
+		}
+	}
+	var top int64
+	for height, hash := range blockhashes {
+		tree.Put([]byte(strconv.FormatInt(height, 10)), []byte(hash)) // insert a value
+		changes = true
+		if height > top {
+			top = height
+		}
+	}
+	if changes {
+		ctrees := []*graviton.Tree{tree}
+
+		stree, _ := g.getTree(ss, "stats")
+		if stree != nil {
+			var trimmed int64
+			if v, _ := stree.Get([]byte(block_hashes_trimmed_key)); v != nil {
+				trimmed, _ = strconv.ParseInt(string(v), 10, 64)
+			}
+			if from, to := blockHashTrimRange(top, trimmed); to > from {
+				for h := from; h < to; h++ {
+					tree.Delete([]byte(strconv.FormatInt(h, 10)))
+				}
+				stree.Put([]byte(block_hashes_trimmed_key), []byte(strconv.FormatInt(to, 10)))
+				ctrees = append(ctrees, stree)
+			}
+		}
+
+		_, cerr := g.commitTrees(ctrees...)
+		if cerr != nil {
+			logger.Errorf("[Graviton] ERROR: %v", cerr)
+			return changes, cerr
+		}
+	}
+	return changes, nil
+}
+
+// Gets the stored block hash of an indexed topoheight
+func (g *GravitonStore) GetBlockHash(topoheight int64) (hash string) {
+	store := g.DB
+	ss, err := store.LoadSnapshot(0) // load most recent snapshot
+	if err != nil {
+		return
+	}
+
+	tree, _ := g.getTree(ss, "blockhashes") // use or create tree named by poolhost in config
+	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
+	if tree == nil {
+		var terr error
+		logger.Errorf("[Graviton-GetBlockHash] ERROR: Tree is nil for 'blockhashes'. Attempting to rollback 1 snapshot")
+		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
+		if preverr != nil {
+			return
+		}
+		tree, terr = prevss.GetTree("blockhashes")
+		if tree == nil {
+			logger.Errorf("[Graviton] ERROR: %v", terr)
+			return
+		}
+	}
+	key := strconv.FormatInt(topoheight, 10)
+
+	v, _ := tree.Get([]byte(key))
+
+	if v != nil {
+		return string(v)
+	}
+
+	return
+}
+
+// Removes all indexed data above a given topoheight. Used when the chain has reorganized and data from orphaned blocks must be undone before indexing the new branch.
+// Block hashes and miniblocks are stored by topoheight, every other record (along with the tx counts added and invalid scid deploys) is kept by the height of its block so is removed above height.
+// Returns the scids whose install was rolled back so they can be removed from the validated list.
+func (g *GravitonStore) RollbackToHeight(topoheight int64, height int64) (rolledbackSCIDs []string, err error) {
+	store := g.DB
+	ss, err := store.LoadSnapshot(0) // load most recent snapshot
+	if err != nil {
+		return
+	}
+
+	// Check for g.migrating, if so sleep for g.DBMigrateWait ms
+	for g.migrating == 1 {
+		logger.Debugf("[RollbackToHeight] G is migrating... sleeping for %v...", g.DBMigrateWait)
+		time.Sleep(g.DBMigrateWait)
+		store = g.DB
+		ss, err = store.LoadSnapshot(0) // load most recent snapshot
+		if err != nil {
+			return
+		}
+	}
+
+	getTree := func(treename string) (tree *graviton.Tree, terr error) {
+		tree, _ = g.getTree(ss, treename)
+		// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
+		if tree == nil {
+			logger.Errorf("[Graviton-RollbackToHeight] ERROR: Tree is nil for '%v'. Attempting to rollback 1 snapshot", treename)
+			prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
+			if preverr != nil {
+				return tree, preverr
+			}
+			tree, terr = prevss.GetTree(treename)
+			if tree == nil {
+				logger.Errorf("[Graviton] ERROR: %v", terr)
+				return tree, terr
+			}
+		}
+		return tree, nil
+	}
+
+	var ctrees []*graviton.Tree
+
+	// Block hashes above the fork point, along with any miniblock details stored against those blids
+	bhtree, err := getTree("blockhashes")
+	if err != nil {
+		return
+	}
+	mbltree, err := getTree("miniblocks")
+	if err != nil {
+		return
+	}
+	bctree, err := getTree("blockcount")
+	if err != nil {
+		return
+	}
+	var orphaned []*TreeKV
+	c := bhtree.Cursor()
+	for k, v, err := c.First(); err == nil; k, v, err = c.Next() {
+		h, perr := strconv.ParseInt(string(k), 10, 64)
+		if perr == nil && h > topoheight {
+			orphaned = append(orphaned, &TreeKV{k, v})
+		}
+	}
+	for _, kv := range orphaned {
+		bhtree.Delete(kv.k)
+		if mblbytes, merr := mbltree.Get(kv.v); merr == nil {
+			for _, mbl := range decodeMiniblockEntry(mblbytes).Miniblocks {
+				var count int64
+				if cbytes, cerr := bctree.Get([]byte(mbl.Miner)); cerr == nil {
+					_ = json.Unmarshal(cbytes, &count)
 				}
-			default:
-				// Nothing - expect only string/uint64 for value types
+				if count > 0 {
+					count--
+				}
+				cbytes, _ := json.Marshal(count)
+				bctree.Put([]byte(mbl.Miner), cbytes)
 			}
+			mbltree.Delete(kv.v)
 		}
-	case string:
-		for _, v := range variables {
-			switch ckey := v.Key.(type) {
-			case string:
-				if inpvar == ckey {
-					switch cval := v.Value.(type) {
-					case float64:
-						valuesuint64 = append(valuesuint64, uint64(cval))
-					case uint64:
-						valuesuint64 = append(valuesuint64, cval)
-					default:
-						// default just store as string. Values should only ever be strings or uint64, however, but assume default to string
-						valuesstring = append(valuesstring, v.Value.(string))
-					}
+	}
+	if len(orphaned) > 0 {
+		ctrees = append(ctrees, bhtree, mbltree, bctree)
+	}
+
+	// Invokes, variables and interaction heights of each indexed scid
+	otree, err := getTree("owner")
+	if err != nil {
+		return
+	}
+	var scids []string
+	c = otree.Cursor()
+	for k, _, err := c.First(); err == nil; k, _, err = c.Next() {
+		scids = append(scids, string(k))
+	}
+	var ochanges bool
+	for _, scid := range scids {
+		itree, err := getTree(scid)
+		if err != nil {
+			return rolledbackSCIDs, err
+		}
+		var ikeys [][]byte
+		c = itree.Cursor()
+		for k, v, err := c.First(); err == nil; k, v, err = c.Next() {
+			var currdetails *structures.SCTXParse
+			_ = json.Unmarshal(v, &currdetails)
+			if currdetails != nil && currdetails.Height > height {
+				ikeys = append(ikeys, k)
+				if currdetails.Method == "installsc" {
+					otree.Delete([]byte(scid))
+					ochanges = true
+					rolledbackSCIDs = append(rolledbackSCIDs, scid)
+				}
+			}
+		}
+		for _, k := range ikeys {
+			itree.Delete(k)
+		}
+		if len(ikeys) > 0 {
+			ctrees = append(ctrees, itree)
+		}
+
+		vtree, err := getTree(scid + "vars")
+		if err != nil {
+			return rolledbackSCIDs, err
+		}
+		var vkeys [][]byte
+		c = vtree.Cursor()
+		for k, _, err := c.First(); err == nil; k, _, err = c.Next() {
+			h, perr := strconv.ParseInt(string(k), 10, 64)
+			if perr == nil && h > height {
+				vkeys = append(vkeys, k)
+			}
+		}
+		for _, k := range vkeys {
+			vtree.Delete(k)
+		}
+		if len(vkeys) > 0 {
+			ctrees = append(ctrees, vtree)
+		}
+
+		btree, err := getTree(scid + "balances")
+		if err != nil {
+			return rolledbackSCIDs, err
+		}
+		var bkeys [][]byte
+		c = btree.Cursor()
+		for k, _, err := c.First(); err == nil; k, _, err = c.Next() {
+			h, perr := strconv.ParseInt(string(k), 10, 64)
+			if perr == nil && h > height {
+				bkeys = append(bkeys, k)
+			}
+		}
+		for _, k := range bkeys {
+			btree.Delete(k)
+		}
+		if len(bkeys) > 0 {
+			ctrees = append(ctrees, btree)
+		}
+
+		cdtree, err := getTree(scid + "code")
+		if err != nil {
+			return rolledbackSCIDs, err
+		}
+		var cdkeys [][]byte
+		c = cdtree.Cursor()
+		for k, _, err := c.First(); err == nil; k, _, err = c.Next() {
+			h, perr := strconv.ParseInt(string(k), 10, 64)
+			if perr == nil && h > height {
+				cdkeys = append(cdkeys, k)
+			}
+		}
+		for _, k := range cdkeys {
+			cdtree.Delete(k)
+		}
+		if len(cdkeys) > 0 {
+			ctrees = append(ctrees, cdtree)
+		}
+
+		argtree, err := getTree(scid + "args")
+		if err != nil {
+			return rolledbackSCIDs, err
+		}
+		var argkvs []*TreeKV
+		c = argtree.Cursor()
+		for k, v, err := c.First(); err == nil; k, v, err = c.Next() {
+			if string(k) == indexed_args_key {
+				continue
+			}
+			var entries, newEntries []*argIndexEntry
+			_ = json.Unmarshal(v, &entries)
+			for _, e := range entries {
+				if e.Height <= height {
+					newEntries = append(newEntries, e)
+				}
+			}
+			if len(newEntries) != len(entries) {
+				var nv []byte
+				if len(newEntries) > 0 {
+					nv, _ = json.Marshal(newEntries)
+				}
+				argkvs = append(argkvs, &TreeKV{k, nv})
+			}
+		}
+		for _, kv := range argkvs {
+			if kv.v == nil {
+				argtree.Delete(kv.k)
+			} else {
+				argtree.Put(kv.k, kv.v)
+			}
+		}
+		if len(argkvs) > 0 {
+			ctrees = append(ctrees, argtree)
+		}
+
+		htree, err := getTree(scid + "heights")
+		if err != nil {
+			return rolledbackSCIDs, err
+		}
+		if hbytes, herr := htree.Get([]byte(scid)); herr == nil {
+			var interactionHeight, newInteractionHeight []int64
+			_ = json.Unmarshal(hbytes, &interactionHeight)
+			for _, h := range interactionHeight {
+				if h <= height {
+					newInteractionHeight = append(newInteractionHeight, h)
+				}
+			}
+			if len(newInteractionHeight) != len(interactionHeight) {
+				if len(newInteractionHeight) == 0 {
+					htree.Delete([]byte(scid))
+				} else {
+					hbytes, _ = json.Marshal(newInteractionHeight)
+					htree.Put([]byte(scid), hbytes)
+				}
+				ctrees = append(ctrees, htree)
+			}
+		}
+	}
+	if ochanges {
+		ctrees = append(ctrees, otree)
+
+		// Template matches of the rolled back installs
+		ttree, err := getTree("templates")
+		if err != nil {
+			return rolledbackSCIDs, err
+		}
+		for _, scid := range rolledbackSCIDs {
+			ttree.Delete([]byte(scid))
+		}
+		ctrees = append(ctrees, ttree)
+	}
+
+	// Normal txs with scid payloads by ring member
+	ntree, err := getTree("normaltxwithscid")
+	if err != nil {
+		return
+	}
+	var nkvs []*TreeKV
+	c = ntree.Cursor()
+	for k, v, err := c.First(); err == nil; k, v, err = c.Next() {
+		var currdetails, newdetails []*structures.NormalTXWithSCIDParse
+		_ = json.Unmarshal(v, &currdetails)
+		for _, cv := range currdetails {
+			if cv.Height <= height {
+				newdetails = append(newdetails, cv)
+			}
+		}
+		if len(newdetails) != len(currdetails) {
+			var nv []byte
+			if len(newdetails) > 0 {
+				nv, _ = json.Marshal(newdetails)
+			}
+			nkvs = append(nkvs, &TreeKV{k, nv})
+		}
+	}
+	for _, kv := range nkvs {
+		if kv.v == nil {
+			ntree.Delete(kv.k)
+		} else {
+			ntree.Put(kv.k, kv.v)
+		}
+	}
+	if len(nkvs) > 0 {
+		ctrees = append(ctrees, ntree)
+	}
+
+	// Address activity
+	aatree, err := getTree("addressactivity")
+	if err != nil {
+		return
+	}
+	var aakvs []*TreeKV
+	c = aatree.Cursor()
+	for k, v, err := c.First(); err == nil; k, v, err = c.Next() {
+		var currdetails, newdetails []*structures.AddressActivity
+		_ = json.Unmarshal(v, &currdetails)
+		for _, cv := range currdetails {
+			if cv.Height <= height {
+				newdetails = append(newdetails, cv)
+			}
+		}
+		if len(newdetails) != len(currdetails) {
+			var nv []byte
+			if len(newdetails) > 0 {
+				nv, _ = json.Marshal(newdetails)
+			}
+			aakvs = append(aakvs, &TreeKV{k, nv})
+		}
+	}
+	for _, kv := range aakvs {
+		if kv.v == nil {
+			aatree.Delete(kv.k)
+		} else {
+			aatree.Put(kv.k, kv.v)
+		}
+	}
+	if len(aakvs) > 0 {
+		ctrees = append(ctrees, aatree)
+	}
+
+	// Registration txs
+	rtree, err := getTree("registrations")
+	if err != nil {
+		return
+	}
+	var rkeys [][]byte
+	c = rtree.Cursor()
+	for k, v, err := c.First(); err == nil; k, v, err = c.Next() {
+		var currdetails *structures.RegTXParse
+		_ = json.Unmarshal(v, &currdetails)
+		if currdetails != nil && currdetails.Height > height {
+			rkeys = append(rkeys, k)
+		}
+	}
+	for _, k := range rkeys {
+		rtree.Delete(k)
+	}
+	if len(rkeys) > 0 {
+		ctrees = append(ctrees, rtree)
+	}
+
+	// Burn txs
+	btree, err := getTree("burntxs")
+	if err != nil {
+		return
+	}
+	burnhtree, err := getTree("burnheights")
+	if err != nil {
+		return
+	}
+	var rolledbackBurns [][]*structures.BurnTXParse
+	c = btree.Cursor()
+	for _, v, err := c.First(); err == nil; _, v, err = c.Next() {
+		var currdetails []*structures.BurnTXParse
+		_ = json.Unmarshal(v, &currdetails)
+		if len(currdetails) > 0 && currdetails[0].Height > height {
+			rolledbackBurns = append(rolledbackBurns, currdetails)
+		}
+	}
+	bscidtrees := make(map[string]*graviton.Tree)
+	for _, currdetails := range rolledbackBurns {
+		txid := currdetails[0].Txid
+		btree.Delete([]byte(txid))
+
+		// Along with its height and scid index entries
+		hkey := []byte(strconv.FormatInt(currdetails[0].Height, 10))
+		if hv, herr := burnhtree.Get(hkey); herr == nil {
+			if txids := removeIndexKey(hv, txid); txids != nil {
+				burnhtree.Put(hkey, txids)
+			} else {
+				burnhtree.Delete(hkey)
+			}
+		}
+		for _, cv := range currdetails {
+			bstree, ok := bscidtrees[cv.Scid]
+			if !ok {
+				if bstree, err = getTree(cv.Scid + "burns"); err != nil {
+					return
 				}
-			default:
-				// Nothing - expect only string/uint64 for value types
+				bscidtrees[cv.Scid] = bstree
 			}
+			bstree.Delete([]byte(txid))
+		}
+	}
+	if len(rolledbackBurns) > 0 {
+		ctrees = append(ctrees, btree, burnhtree)
+		for _, bstree := range bscidtrees {
+			ctrees = append(ctrees, bstree)
 		}
-	default:
-		// Nothing - expect only string/uint64 for value types
 	}
 
-	return valuesstring, valuesuint64
-}
-
-// Stores SC interaction height and detail - height invoked upon and type (scinstall/scinvoke). This is separate tree & k/v since we can query it for other things at less data retrieval
-func (g *GravitonStore) StoreSCIDInteractionHeight(scid string, height int64, nocommit bool) (tree *graviton.Tree, changes bool, err error) {
-	store := g.DB
-	ss, err := store.LoadSnapshot(0) // load most recent snapshot
+	// Name service history past height is dropped, the current record of each name goes back to the latest one left
+	nstree, err := getTree("names")
 	if err != nil {
 		return
 	}
-
-	// Check for g.migrating, if so sleep for g.DBMigrateWait ms
-	for g.migrating == 1 {
-		logger.Debugf("[StoreSCIDInteractionHeight] G is migrating... sleeping for %v...", g.DBMigrateWait)
-		time.Sleep(g.DBMigrateWait)
-		store = g.DB
-		ss, err = store.LoadSnapshot(0) // load most recent snapshot
-		if err != nil {
-			return
-		}
+	nhtree, err := getTree("namehistory")
+	if err != nil {
+		return
 	}
+	natree, err := getTree("nameaddrs")
+	if err != nil {
+		return
+	}
+	var nchanged bool
+	var nhkvs []*TreeKV
+	c = nhtree.Cursor()
+	for k, v, err := c.First(); err == nil; k, v, err = c.Next() {
+		nhkvs = append(nhkvs, &TreeKV{k, v})
+	}
+	for _, kv := range nhkvs {
+		var history, newHistory []*structures.NameRecord
+		_ = json.Unmarshal(kv.v, &history)
+		for _, record := range history {
+			if record.Height <= height {
+				newHistory = append(newHistory, record)
+			}
+		}
+		if len(newHistory) == len(history) {
+			continue
+		}
 
-	treename := scid + "heights"
-	tree, _ = ss.GetTree(treename)
-	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
-	if tree == nil {
-		var terr error
-		logger.Errorf("[Graviton-StoreSCIDInteractionHeight] ERROR: Tree is nil for '%v'. Attempting to rollback 1 snapshot", treename)
-		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
-		if preverr != nil {
-			return tree, changes, preverr
+		nchanged = true
+		prevAddress := history[len(history)-1].Address
+		if len(newHistory) == 0 {
+			nhtree.Delete(kv.k)
+			nstree.Delete(kv.k)
+			moveNameAddress(natree, string(kv.k), prevAddress, "")
+			continue
 		}
-		tree, terr = prevss.GetTree(treename)
-		if tree == nil {
-			logger.Errorf("[Graviton] ERROR: %v", terr)
-			return tree, changes, terr
+		hbytes, merr := json.Marshal(newHistory)
+		if merr != nil {
+			return rolledbackSCIDs, merr
+		}
+		rbytes, merr := json.Marshal(newHistory[len(newHistory)-1])
+		if merr != nil {
+			return rolledbackSCIDs, merr
+		}
+		nhtree.Put(kv.k, hbytes)
+		nstree.Put(kv.k, rbytes)
+		if address := newHistory[len(newHistory)-1].Address; address != prevAddress {
+			moveNameAddress(natree, string(kv.k), prevAddress, address)
 		}
 	}
-	key := scid
-	currSCIDInteractionHeight, err := tree.Get([]byte(key))
-	var interactionHeight []int64
-
-	var newInteractionHeight []byte
+	if nchanged {
+		ctrees = append(ctrees, nstree, nhtree, natree)
+	}
 
+	// Asset registry entries updated past height go back to their entry as of height from the asset's history. Assets first recognized past height (or stored before history was kept) are dropped and picked back up when their SC is next indexed
+	atree, err := getTree("assets")
 	if err != nil {
-		interactionHeight = append(interactionHeight, height)
-	} else {
-		// Retrieve value and conovert to SCIDInteractionHeight, so that you can manipulate and update db
-		_ = json.Unmarshal(currSCIDInteractionHeight, &interactionHeight)
-
-		for _, v := range interactionHeight {
-			if v == height {
-				// Return nil if already exists in array.
-				// Clause for this is in event we pop backwards in time and already have this data stored.
-				// TODO: What if interaction happened on false-chain and pop to retain correct chain. Bad data may be stored here still, as it isn't removed. Need fix for this in future.
-				return tree, changes, nil
-			}
-		}
-		interactionHeight = append(interactionHeight, height)
+		return
 	}
-	newInteractionHeight, err = json.Marshal(interactionHeight)
+	ahtree, err := getTree("assethistory")
 	if err != nil {
-		return tree, changes, fmt.Errorf("[Graviton] could not marshal interactionHeight info: %v", err)
+		return
 	}
-
-	tree.Put([]byte(key), newInteractionHeight)
-	changes = true
-	if !nocommit {
-		_, cerr := graviton.Commit(tree)
-		if cerr != nil {
-			logger.Errorf("[Graviton] ERROR: %v", cerr)
-			return tree, changes, cerr
+	var akeys [][]byte
+	c = atree.Cursor()
+	for k, v, err := c.First(); err == nil; k, v, err = c.Next() {
+		var currdetails *structures.SCAsset
+		_ = json.Unmarshal(v, &currdetails)
+		if currdetails != nil && currdetails.Height > height {
+			akeys = append(akeys, k)
 		}
 	}
-	return tree, changes, nil
-}
+	for _, k := range akeys {
+		var history []*structures.SCAsset
+		if v, herr := ahtree.Get(k); herr == nil {
+			_ = json.Unmarshal(v, &history)
+		}
+		history = assetHistoryAt(history, height)
+		if len(history) == 0 {
+			atree.Delete(k)
+			ahtree.Delete(k)
+			continue
+		}
+		hbytes, merr := json.Marshal(history)
+		if merr != nil {
+			return rolledbackSCIDs, merr
+		}
+		abytes, merr := json.Marshal(history[len(history)-1])
+		if merr != nil {
+			return rolledbackSCIDs, merr
+		}
+		atree.Put(k, abytes)
+		ahtree.Put(k, hbytes)
+	}
+	if len(akeys) > 0 {
+		ctrees = append(ctrees, atree, ahtree)
+	}
 
-// Gets SC interaction height and detail by a given SCID
-func (g *GravitonStore) GetSCIDInteractionHeight(scid string) (scidinteractions []int64) {
-	store := g.DB
-	ss, err := store.LoadSnapshot(0) // load most recent snapshot
+	stree, err := getTree("stats")
 	if err != nil {
 		return
 	}
 
-	treename := scid + "heights"
-	tree, _ := ss.GetTree(treename) // use or create tree named by poolhost in config
-	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
-	if tree == nil {
-		var terr error
-		logger.Errorf("[Graviton-GetSCIDInteractionHeight] ERROR: Tree is nil for '%v'. Attempting to rollback 1 snapshot", treename)
-		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
-		if preverr != nil {
-			return
+	// Tx counts added by the blocks above height are taken back off the totals
+	tctree, err := getTree("txcountheights")
+	if err != nil {
+		return
+	}
+	var tckeys [][]byte
+	rolledbackCounts := make(map[string]int64)
+	c = tctree.Cursor()
+	for k, v, err := c.First(); err == nil; k, v, err = c.Next() {
+		h, perr := strconv.ParseInt(string(k), 10, 64)
+		if perr == nil && h > height {
+			var counts map[string]int64
+			_ = json.Unmarshal(v, &counts)
+			for txType, count := range counts {
+				rolledbackCounts[txType] += count
+			}
+			tckeys = append(tckeys, k)
 		}
-		tree, terr = prevss.GetTree(treename)
-		if tree == nil {
-			logger.Errorf("[Graviton] ERROR: %v", terr)
-			return
+	}
+	for _, k := range tckeys {
+		tctree.Delete(k)
+	}
+	for txType, count := range rolledbackCounts {
+		key := []byte(txType + "txcount")
+		var txCount int64
+		if v, gerr := stree.Get(key); gerr == nil {
+			txCount, _ = strconv.ParseInt(string(v), 10, 64)
 		}
+		txCount -= count
+		if txCount < 0 {
+			txCount = 0
+		}
+		stree.Put(key, []byte(strconv.FormatInt(txCount, 10)))
+	}
+	if len(tckeys) > 0 {
+		ctrees = append(ctrees, tctree)
 	}
-	key := scid
-
-	v, _ := tree.Get([]byte(key))
 
-	if v != nil {
-		_ = json.Unmarshal(v, &scidinteractions)
+	// Invalid scid deploys above height
+	ivtree, err := getTree("invalidscids")
+	if err != nil {
 		return
 	}
-
-	return nil
-}
-
-func (g *GravitonStore) GetInteractionIndex(topoheight int64, heights []int64, rmax bool) (height int64) {
-	if len(heights) <= 0 {
-		return height
+	if hbytes, herr := ivtree.Get([]byte(invalid_scid_heights_key)); herr == nil {
+		invalidHeights := make(map[string]int64)
+		invalidSCIDs := make(map[string]uint64)
+		_ = json.Unmarshal(hbytes, &invalidHeights)
+		if v, gerr := ivtree.Get([]byte("invalid")); gerr == nil {
+			_ = json.Unmarshal(v, &invalidSCIDs)
+		}
+		var ivchanged bool
+		for scid, h := range invalidHeights {
+			if h > height {
+				delete(invalidHeights, scid)
+				delete(invalidSCIDs, scid)
+				ivchanged = true
+			}
+		}
+		if ivchanged {
+			ibytes, merr := json.Marshal(invalidSCIDs)
+			if merr != nil {
+				return rolledbackSCIDs, merr
+			}
+			hbytes, merr = json.Marshal(invalidHeights)
+			if merr != nil {
+				return rolledbackSCIDs, merr
+			}
+			ivtree.Put([]byte("invalid"), ibytes)
+			ivtree.Put([]byte(invalid_scid_heights_key), hbytes)
+			ctrees = append(ctrees, ivtree)
+		}
 	}
 
-	// Sort heights so most recent is index 0 [if preferred reverse, just swap > with <]
-	sort.SliceStable(heights, func(i, j int) bool {
-		return heights[i] > heights[j]
-	})
-
-	if topoheight > heights[0] || rmax {
-		return heights[0]
-	}
+	stree.Put([]byte("lastindexedheight"), []byte(strconv.FormatInt(topoheight, 10)))
+	ctrees = append(ctrees, stree)
 
-	for i := 1; i < len(heights); i++ {
-		if heights[i] < topoheight {
-			return heights[i]
-		} else if heights[i] == topoheight {
-			return heights[i]
-		}
+	// Commit all changed trees at once (single snapshot rather than many)
+	_, cerr := g.commitTrees(ctrees...)
+	if cerr != nil {
+		logger.Errorf("[Graviton] ERROR: %v", cerr)
+		return rolledbackSCIDs, cerr
 	}
 
-	return height
+	return rolledbackSCIDs, nil
 }
 
-// Stores any SCIDs that were attempted to be deployed but not correct - log scid/fees burnt attempting it.
-func (g *GravitonStore) StoreInvalidSCIDDeploys(scid string, fee uint64, nocommit bool) (tree *graviton.Tree, changes bool, err error) {
+// Removes the SC variables of a given scid stored below belowHeight, keeping the variables as of belowHeight as a keyframe at the most recent stored height below it so that the variables at or above belowHeight are unchanged
+func (g *GravitonStore) PruneSCIDVariableDetails(scid string, belowHeight int64) (pruned int, err error) {
 	store := g.DB
 	ss, err := store.LoadSnapshot(0) // load most recent snapshot
 	if err != nil {
@@ -1471,7 +4552,7 @@ func (g *GravitonStore) StoreInvalidSCIDDeploys(scid string, fee uint64, nocommi
 
 	// Check for g.migrating, if so sleep for g.DBMigrateWait ms
 	for g.migrating == 1 {
-		logger.Debugf("[StoreInvalidSCIDDeploys] G is migrating... sleeping for %v...", g.DBMigrateWait)
+		logger.Debugf("[PruneSCIDVariableDetails] G is migrating... sleeping for %v...", g.DBMigrateWait)
 		time.Sleep(g.DBMigrateWait)
 		store = g.DB
 		ss, err = store.LoadSnapshot(0) // load most recent snapshot
@@ -1480,114 +4561,63 @@ func (g *GravitonStore) StoreInvalidSCIDDeploys(scid string, fee uint64, nocommi
 		}
 	}
 
-	treename := "invalidscids"
-	tree, _ = ss.GetTree(treename)
+	treename := scid + "vars"
+	tree, _ := g.getTree(ss, treename)
 	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
 	if tree == nil {
 		var terr error
-		logger.Errorf("[Graviton-StoreInvalidSCIDDeploys] ERROR: Tree is nil for '%v'. Attempting to rollback 1 snapshot", treename)
+		logger.Errorf("[Graviton-PruneSCIDVariableDetails] ERROR: Tree is nil for '%v'. Attempting to rollback 1 snapshot", treename)
 		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
 		if preverr != nil {
-			return tree, changes, preverr
+			return pruned, preverr
 		}
 		tree, terr = prevss.GetTree(treename)
 		if tree == nil {
 			logger.Errorf("[Graviton] ERROR: %v", terr)
-			return tree, changes, terr
+			return pruned, terr
 		}
 	}
-	key := "invalid"
-	currSCIDInteractionHeight, err := tree.Get([]byte(key))
-	currInvalidSCIDs := make(map[string]uint64)
-
-	var newInvalidSCIDs []byte
-
-	if err != nil {
-		currInvalidSCIDs[scid] = fee
-	} else {
-		// Retrieve value and conovert to SCIDInteractionHeight, so that you can manipulate and update db
-		_ = json.Unmarshal(currSCIDInteractionHeight, &currInvalidSCIDs)
-
-		currInvalidSCIDs[scid] = fee
-	}
-	newInvalidSCIDs, err = json.Marshal(currInvalidSCIDs)
-	if err != nil {
-		return tree, changes, fmt.Errorf("[Graviton] could not marshal interactionHeight info: %v", err)
-	}
 
-	tree.Put([]byte(key), newInvalidSCIDs)
-	changes = true
-	if !nocommit {
-		_, cerr := graviton.Commit(tree)
-		if cerr != nil {
-			logger.Errorf("[Graviton] ERROR: %v", cerr)
-			return tree, changes, cerr
-		}
+	entries := make(map[int64][]byte)
+	c := tree.Cursor()
+	for k, v, err := c.First(); err == nil; k, v, err = c.Next() {
+		h, _ := strconv.ParseInt(string(k), 10, 64)
+		entries[h] = v
 	}
-	return tree, changes, nil
-}
-
-// Gets any SCIDs that were attempted to be deployed but not correct and their fees
-func (g *GravitonStore) GetInvalidSCIDDeploys() (invalidSCIDs map[string]uint64) {
-	invalidSCIDs = make(map[string]uint64)
 
-	store := g.DB
-	ss, err := store.LoadSnapshot(0) // load most recent snapshot
-	if err != nil {
+	keep, keyframe, err := pruneVarEntries(entries, belowHeight)
+	if err != nil || keyframe == nil {
 		return
 	}
 
-	treename := "invalidscids"
-	tree, _ := ss.GetTree(treename) // use or create tree named by poolhost in config
-	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
-	if tree == nil {
-		var terr error
-		logger.Errorf("[Graviton-GetInvalidSCIDDeploys] ERROR: Tree is nil for 'invalidscids'. Attempting to rollback 1 snapshot")
-		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
-		if preverr != nil {
-			return
-		}
-		tree, terr = prevss.GetTree("invalidscids")
-		if tree == nil {
-			logger.Errorf("[Graviton] ERROR: %v", terr)
-			return
+	tree.Put([]byte(strconv.FormatInt(keep, 10)), keyframe)
+	for h := range entries {
+		if h < keep {
+			tree.Delete([]byte(strconv.FormatInt(h, 10)))
+			pruned++
 		}
 	}
-	key := "invalid"
-
-	v, _ := tree.Get([]byte(key))
 
-	if v != nil {
-		_ = json.Unmarshal(v, &invalidSCIDs)
-		return
+	_, cerr := g.commitTrees(tree)
+	if cerr != nil {
+		logger.Errorf("[Graviton] ERROR: %v", cerr)
+		return pruned, cerr
 	}
 
-	return nil
+	return
 }
 
-// Stores the miniblocks within a given blid
-func (g *GravitonStore) StoreMiniblockDetailsByHash(blid string, mbldetails []*structures.MBLInfo, nocommit bool) (tree *graviton.Tree, changes bool, err error) {
-	for _, v := range mbldetails {
-		_, _, err := g.StoreMiniblockCountByAddress(v.Miner, false)
-		if err != nil {
-			logger.Errorf("[Store] ERR - Error adding miniblock count for address '%v'", v.Miner)
-		}
-	}
-
-	confBytes, err := json.Marshal(mbldetails)
-	if err != nil {
-		return &graviton.Tree{}, changes, fmt.Errorf("[StoreMiniblockDetailsByHash] could not marshal getinfo info: %v", err)
-	}
-
+// Removes the invokes of a given scid below belowHeight, along with their arg index entries. The install invoke is kept as the record of the scid's deploy
+func (g *GravitonStore) PruneSCIDInvokeDetails(scid string, belowHeight int64) (pruned int, err error) {
 	store := g.DB
 	ss, err := store.LoadSnapshot(0) // load most recent snapshot
 	if err != nil {
 		return
 	}
 
-	// Swap DB at g.DBMaxSnapshot+ commits. Check for g.migrating, if so sleep for g.DBMigrateWait ms
+	// Check for g.migrating, if so sleep for g.DBMigrateWait ms
 	for g.migrating == 1 {
-		logger.Debugf("[StoreMiniblockDetailsByHash] G is migrating... sleeping for %v...", g.DBMigrateWait)
+		logger.Debugf("[PruneSCIDInvokeDetails] G is migrating... sleeping for %v...", g.DBMigrateWait)
 		time.Sleep(g.DBMigrateWait)
 		store = g.DB
 		ss, err = store.LoadSnapshot(0) // load most recent snapshot
@@ -1596,79 +4626,101 @@ func (g *GravitonStore) StoreMiniblockDetailsByHash(blid string, mbldetails []*s
 		}
 	}
 
-	tree, _ = ss.GetTree("miniblocks")
-	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
-	if tree == nil {
-		var terr error
-		logger.Errorf("[Graviton-StoreMiniblockDetailsByHash] ERROR: Tree is nil for 'miniblocks'. Attempting to rollback 1 snapshot")
-		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
-		if preverr != nil {
-			return tree, changes, preverr
-		}
-		tree, terr = prevss.GetTree("miniblocks")
+	getTree := func(treename string) (tree *graviton.Tree, terr error) {
+		tree, _ = g.getTree(ss, treename)
+		// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
 		if tree == nil {
-			logger.Errorf("[Graviton] ERROR: %v", terr)
-			return tree, changes, terr
+			logger.Errorf("[Graviton-PruneSCIDInvokeDetails] ERROR: Tree is nil for '%v'. Attempting to rollback 1 snapshot", treename)
+			prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
+			if preverr != nil {
+				return tree, preverr
+			}
+			tree, terr = prevss.GetTree(treename)
+			if tree == nil {
+				logger.Errorf("[Graviton] ERROR: %v", terr)
+				return tree, terr
+			}
 		}
+		return tree, nil
 	}
-	tree.Put([]byte(blid), confBytes) // insert a value
-	changes = true
-	if !nocommit {
-		_, cerr := graviton.Commit(tree)
-		if cerr != nil {
-			logger.Errorf("[Graviton] ERROR: %v", cerr)
-			return tree, changes, cerr
+
+	itree, err := getTree(scid)
+	if err != nil {
+		return
+	}
+	ikeys := make(map[string]bool)
+	c := itree.Cursor()
+	for k, v, err := c.First(); err == nil; k, v, err = c.Next() {
+		var currdetails *structures.SCTXParse
+		_ = json.Unmarshal(v, &currdetails)
+		if currdetails != nil && currdetails.Height < belowHeight && currdetails.Method != "installsc" {
+			ikeys[string(k)] = true
 		}
 	}
-	return tree, changes, nil
-}
-
-// Returns all miniblock details for synced chain
-func (g *GravitonStore) GetAllMiniblockDetails() (mbldetails map[string][]*structures.MBLInfo) {
-	mbldetails = make(map[string][]*structures.MBLInfo)
-	store := g.DB
-	ss, err := store.LoadSnapshot(0)
+	if len(ikeys) == 0 {
+		return
+	}
+	for k := range ikeys {
+		itree.Delete([]byte(k))
+	}
+	ctrees := []*graviton.Tree{itree}
+
+	argtree, err := getTree(scid + "args")
 	if err != nil {
 		return
 	}
-	tree, _ := ss.GetTree("miniblocks")
-	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
-	if tree == nil {
-		var terr error
-		logger.Errorf("[Graviton-GetAllMiniblockDetails] ERROR: Tree is nil for 'miniblocks'. Attempting to rollback 1 snapshot")
-		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
-		if preverr != nil {
-			return
+	var argkvs []*TreeKV
+	c = argtree.Cursor()
+	for k, v, err := c.First(); err == nil; k, v, err = c.Next() {
+		if string(k) == indexed_args_key {
+			continue
 		}
-		tree, terr = prevss.GetTree("miniblocks")
-		if tree == nil {
-			logger.Errorf("[Graviton] ERROR: %v", terr)
-			return
+		var entries, newEntries []*argIndexEntry
+		_ = json.Unmarshal(v, &entries)
+		for _, e := range entries {
+			if !ikeys[e.Key] {
+				newEntries = append(newEntries, e)
+			}
+		}
+		if len(newEntries) != len(entries) {
+			var nv []byte
+			if len(newEntries) > 0 {
+				nv, _ = json.Marshal(newEntries)
+			}
+			argkvs = append(argkvs, &TreeKV{k, nv})
+		}
+	}
+	for _, kv := range argkvs {
+		if kv.v == nil {
+			argtree.Delete(kv.k)
+		} else {
+			argtree.Put(kv.k, kv.v)
 		}
 	}
+	if len(argkvs) > 0 {
+		ctrees = append(ctrees, argtree)
+	}
 
-	c := tree.Cursor()
-	// Duplicate the LATEST (snapshot 0) to the new DB, this starts the DB over again, but still retaining X number of old DBs for version in future use cases. Here we get the vals before swapping to new db in mem
-	for k, v, err := c.First(); err == nil; k, v, err = c.Next() {
-		var currdetails []*structures.MBLInfo
-		_ = json.Unmarshal(v, &currdetails)
-		mbldetails[string(k)] = currdetails
+	_, cerr := g.commitTrees(ctrees...)
+	if cerr != nil {
+		logger.Errorf("[Graviton] ERROR: %v", cerr)
+		return pruned, cerr
 	}
 
-	return
+	return len(ikeys), nil
 }
 
-// Returns the miniblocks within a given blid if previously stored
-func (g *GravitonStore) GetMiniblockDetailsByHash(blid string) (miniblocks []*structures.MBLInfo) {
+// Removes the miniblock details of the blocks below belowHeight, up to limit of them per call. Miniblock counts by address are kept
+func (g *GravitonStore) PruneMiniblockDetails(belowHeight int64, limit int) (pruned int, err error) {
 	store := g.DB
 	ss, err := store.LoadSnapshot(0) // load most recent snapshot
 	if err != nil {
 		return
 	}
 
-	// Swap DB at g.DBMaxSnapshot+ commits. Check for g.migrating, if so sleep for g.DBMigrateWait ms
+	// Check for g.migrating, if so sleep for g.DBMigrateWait ms
 	for g.migrating == 1 {
-		logger.Debugf("[GetMiniblockDetailsByHash] G is migrating... sleeping for %v...", g.DBMigrateWait)
+		logger.Debugf("[PruneMiniblockDetails] G is migrating... sleeping for %v...", g.DBMigrateWait)
 		time.Sleep(g.DBMigrateWait)
 		store = g.DB
 		ss, err = store.LoadSnapshot(0) // load most recent snapshot
@@ -1677,101 +4729,114 @@ func (g *GravitonStore) GetMiniblockDetailsByHash(blid string) (miniblocks []*st
 		}
 	}
 
-	tree, _ := ss.GetTree("miniblocks") // use or create tree named by poolhost in config
-	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
-	if tree == nil {
-		var terr error
-		logger.Errorf("[Graviton-GetMiniblockDetailsByHash] ERROR: Tree is nil for 'miniblocks'. Attempting to rollback 1 snapshot")
-		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
-		if preverr != nil {
-			return
-		}
-		tree, terr = prevss.GetTree("miniblocks")
+	getTree := func(treename string) (tree *graviton.Tree, terr error) {
+		tree, _ = g.getTree(ss, treename)
+		// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
 		if tree == nil {
-			logger.Errorf("[Graviton] ERROR: %v", terr)
-			return
+			logger.Errorf("[Graviton-PruneMiniblockDetails] ERROR: Tree is nil for '%v'. Attempting to rollback 1 snapshot", treename)
+			prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
+			if preverr != nil {
+				return tree, preverr
+			}
+			tree, terr = prevss.GetTree(treename)
+			if tree == nil {
+				logger.Errorf("[Graviton] ERROR: %v", terr)
+				return tree, terr
+			}
 		}
+		return tree, nil
 	}
-	key := blid
-
-	v, _ := tree.Get([]byte(key))
 
-	if v != nil {
-		_ = json.Unmarshal(v, &miniblocks)
+	stree, err := getTree("stats")
+	if err != nil {
 		return
 	}
+	var heightsFrom int64
+	if v, _ := stree.Get([]byte(mbl_heights_from_key)); v != nil {
+		heightsFrom, _ = strconv.ParseInt(string(v), 10, 64)
+	}
 
-	return nil
-}
-
-// Stores counts of miniblock finders by address
-func (g *GravitonStore) StoreMiniblockCountByAddress(addr string, nocommit bool) (tree *graviton.Tree, changes bool, err error) {
-	currCount := g.GetMiniblockCountByAddress(addr)
-
-	// Add 1 to currCount
-	currCount++
-
-	confBytes, err := json.Marshal(currCount)
+	mbltree, err := getTree("miniblocks")
 	if err != nil {
-		return &graviton.Tree{}, changes, fmt.Errorf("[StoreMiniblockCountByAddress] could not marshal getinfo info: %v", err)
+		return
 	}
 
-	store := g.DB
-	ss, err := store.LoadSnapshot(0) // load most recent snapshot
-	if err != nil {
+	// Gather the keys first, deleting while the cursor is walking the tree is not safe
+	var mkeys [][]byte
+	c := mbltree.Cursor()
+	for k, v, err := c.First(); err == nil && len(mkeys) < limit; k, v, err = c.Next() {
+		if decodeMiniblockEntry(v).below(belowHeight, heightsFrom) {
+			mkeys = append(mkeys, k)
+		}
+	}
+	if len(mkeys) == 0 {
 		return
 	}
 
-	// Check for g.migrating, if so sleep for g.DBMigrateWait ms
-	for g.migrating == 1 {
-		logger.Debugf("[StoreMiniblockCountByAddress] G is migrating... sleeping for %v...", g.DBMigrateWait)
-		time.Sleep(g.DBMigrateWait)
-		store = g.DB
-		ss, err = store.LoadSnapshot(0) // load most recent snapshot
+	for _, k := range mkeys {
+		mbltree.Delete(k)
+	}
+
+	_, cerr := g.commitTrees(mbltree)
+	if cerr != nil {
+		logger.Errorf("[Graviton] ERROR: %v", cerr)
+		return 0, cerr
+	}
+
+	return len(mkeys), nil
+}
+
+// Replaces the invokes, variable history, interaction heights, balances and code versions of a given scid with the ones given, e.g. as rebuilt by a rescan. Arg indexes kept on the scid are rebuilt over the new invokes. Done within one batch, so readers see either the old or the new history
+func (g *GravitonStore) ReplaceSCIDHistory(scid string, history *SCIDHistory) (err error) {
+	return g.Batch(func(s Storage) (err error) {
+		bg := s.(*GravitonStore)
+
+		argnames, err := bg.resetSCIDHistory(scid, history.Heights)
 		if err != nil {
 			return
 		}
-	}
 
-	tree, _ = ss.GetTree("blockcount")
-	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
-	if tree == nil {
-		var terr error
-		logger.Errorf("[Graviton-StoreMiniblockCountByAddress] ERROR: Tree is nil for 'blockcount'. Attempting to rollback 1 snapshot")
-		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
-		if preverr != nil {
-			return tree, changes, preverr
+		for _, invoke := range history.Invokes {
+			if _, err = bg.StoreInvokeDetails(scid, invoke.Sender, invoke.Entrypoint, invoke.Height, invoke); err != nil {
+				return
+			}
 		}
-		tree, terr = prevss.GetTree("blockcount")
-		if tree == nil {
-			logger.Errorf("[Graviton] ERROR: %v", terr)
-			return tree, changes, terr
+		if len(history.Variables) > 0 {
+			if _, err = bg.StoreSCIDVariableHistory(scid, history.Variables); err != nil {
+				return
+			}
 		}
-	}
-	key := addr
-	tree.Put([]byte(key), confBytes) // insert a value
-	changes = true
-	if !nocommit {
-		_, cerr := graviton.Commit(tree)
-		if cerr != nil {
-			logger.Errorf("[Graviton] ERROR: %v", cerr)
-			return tree, changes, cerr
+		for _, balances := range history.Balances {
+			if _, err = bg.StoreSCIDBalanceDetails(scid, balances.Balances, balances.Height); err != nil {
+				return
+			}
 		}
-	}
-	return tree, changes, nil
+		for _, code := range history.Code {
+			if _, err = bg.StoreSCIDCode(scid, code); err != nil {
+				return
+			}
+		}
+		for _, name := range argnames {
+			if _, err = bg.StoreSCIDArgIndex(scid, name, history.Invokes); err != nil {
+				return
+			}
+		}
+
+		return
+	})
 }
 
-// Gets counts of miniblock finders by address
-func (g *GravitonStore) GetMiniblockCountByAddress(addr string) (miniblocks int64) {
+// Removes the invokes, variables, balances, code versions and arg indexes of a given scid and sets its interaction heights to heights. Returns the arg names that were indexed on the scid
+func (g *GravitonStore) resetSCIDHistory(scid string, heights []int64) (argnames []string, err error) {
 	store := g.DB
 	ss, err := store.LoadSnapshot(0) // load most recent snapshot
 	if err != nil {
 		return
 	}
 
-	// Swap DB at g.DBMaxSnapshot+ commits. Check for g.migrating, if so sleep for g.DBMigrateWait ms
+	// Check for g.migrating, if so sleep for g.DBMigrateWait ms
 	for g.migrating == 1 {
-		logger.Debugf("[GetMiniblockDetailsByHash] G is migrating... sleeping for %v...", g.DBMigrateWait)
+		logger.Debugf("[resetSCIDHistory] G is migrating... sleeping for %v...", g.DBMigrateWait)
 		time.Sleep(g.DBMigrateWait)
 		store = g.DB
 		ss, err = store.LoadSnapshot(0) // load most recent snapshot
@@ -1780,60 +4845,57 @@ func (g *GravitonStore) GetMiniblockCountByAddress(addr string) (miniblocks int6
 		}
 	}
 
-	tree, _ := ss.GetTree("blockcount") // use or create tree named by poolhost in config
-	// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
-	if tree == nil {
-		var terr error
-		logger.Errorf("[Graviton-GetMiniblockCountByAddress] ERROR: Tree is nil for 'blockcount'. Attempting to rollback 1 snapshot")
-		prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
-		if preverr != nil {
-			return
-		}
-		tree, terr = prevss.GetTree("blockcount")
+	var ctrees []*graviton.Tree
+	for _, treename := range []string{scid, scid + "vars", scid + "heights", scid + "args", scid + "balances", scid + "code"} {
+		tree, _ := g.getTree(ss, treename)
+		// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
 		if tree == nil {
-			logger.Errorf("[Graviton] ERROR: %v", terr)
-			return
+			var terr error
+			logger.Errorf("[Graviton-resetSCIDHistory] ERROR: Tree is nil for '%v'. Attempting to rollback 1 snapshot", treename)
+			prevss, preverr := store.LoadSnapshot(ss.GetVersion() - 1)
+			if preverr != nil {
+				return argnames, preverr
+			}
+			tree, terr = prevss.GetTree(treename)
+			if tree == nil {
+				logger.Errorf("[Graviton] ERROR: %v", terr)
+				return argnames, terr
+			}
 		}
-	}
-	key := addr
-
-	v, _ := tree.Get([]byte(key))
-
-	if v != nil {
-		_ = json.Unmarshal(v, &miniblocks)
-		return
-	}
-
-	return
-}
-
-// Gets all SCID interacts from a given address - non-builtin/name scids.
-func (g *GravitonStore) GetSCIDInteractionByAddr(addr string) (scids []string) {
-	normTxsWithSCID := g.GetAllNormalTxWithSCIDByAddr(addr)
 
-	// Append scids list of normtxs scid interaction
-	for _, v := range normTxsWithSCID {
-		if !idExist(scids, v.Scid) {
-			scids = append(scids, v.Scid)
+		if treename == scid+"args" {
+			if v, ierr := tree.Get([]byte(indexed_args_key)); ierr == nil {
+				_ = json.Unmarshal(v, &argnames)
+			}
 		}
-	}
 
-	allSCIDs := g.GetAllOwnersAndSCIDs()
-	for k, _ := range allSCIDs {
-		// Skip builtin name registration, no need to waste cursor time on this one since it's not pertinent to goal of function
-		// TODO: Future state, we'll have much more SCID interaction and this will get slower and slower, will need to speedup. Probably will happen with data re-org in future
-		if k == "0000000000000000000000000000000000000000000000000000000000000001" {
-			continue
+		var keys [][]byte
+		c := tree.Cursor()
+		for k, _, err := c.First(); err == nil; k, _, err = c.Next() {
+			keys = append(keys, k)
 		}
-		invokedetails := g.GetAllSCIDInvokeDetailsBySigner(k, addr)
-		if len(invokedetails) > 0 {
-			if !idExist(scids, k) {
-				scids = append(scids, k)
+		for _, k := range keys {
+			tree.Delete(k)
+		}
+
+		if treename == scid+"heights" && len(heights) > 0 {
+			hbytes, merr := json.Marshal(heights)
+			if merr != nil {
+				return argnames, fmt.Errorf("[resetSCIDHistory] could not marshal interaction heights: %v", merr)
 			}
+			tree.Put([]byte(scid), hbytes)
 		}
+
+		ctrees = append(ctrees, tree)
 	}
 
-	return scids
+	_, cerr := g.commitTrees(ctrees...)
+	if cerr != nil {
+		logger.Errorf("[Graviton] ERROR: %v", cerr)
+		return argnames, cerr
+	}
+
+	return
 }
 
 // Commits multiple trees and returns the commit version and errs
@@ -1844,7 +4906,7 @@ func (g *GravitonStore) CommitTrees(trees []*graviton.Tree) (cv uint64, err erro
 		time.Sleep(g.DBMigrateWait)
 	}
 
-	cv, err = graviton.Commit(trees...)
+	cv, err = g.commitTrees(trees...)
 
 	return
 }
@@ -1880,7 +4942,7 @@ func (g *GravitonStore) StoreAltDBInput(treenames []string, altdb *GravitonStore
 			return err
 		}
 		//logger.Printf("[StoreAltDBInput] Getting storage tree '%v'", v)
-		tree, _ := ss.GetTree(v)
+		tree, _ := g.getTree(ss, v)
 		// Catch/handle a nil tree. TODO: This should gracefully cause shutdown, if we cannot get the previous snapshot data. Also need to handle losing that snapshot, how do we handle.
 		if tree == nil {
 			var terr error
@@ -1919,7 +4981,7 @@ func (g *GravitonStore) StoreAltDBInput(treenames []string, altdb *GravitonStore
 	}
 
 	// Commit all changed trees at once (single snapshot rather than many)
-	_, cerr := graviton.Commit(commitTrees...)
+	_, cerr := g.commitTrees(commitTrees...)
 	if cerr != nil {
 		logger.Errorf("[Graviton] ERROR: %v", cerr)
 		return cerr
@@ -1928,4 +4990,146 @@ func (g *GravitonStore) StoreAltDBInput(treenames []string, altdb *GravitonStore
 	return nil
 }
 
+// Runs fn against a batch copy of the store. All trees written to within fn are committed together in one commit once fn returns. If fn returns an err, nothing is committed
+func (g *GravitonStore) Batch(fn func(s Storage) error) (err error) {
+	if g.view != nil {
+		return ErrReadOnlyView
+	}
+
+	// Already within a batch, writes will be committed by the outer one
+	if g.batch != nil {
+		return fn(g)
+	}
+
+	bg := &GravitonStore{
+		DB:            g.DB,
+		DBPath:        g.DBPath,
+		DBTrees:       g.DBTrees,
+		DBMaxSnapshot: g.DBMaxSnapshot,
+		DBMigrateWait: g.DBMigrateWait,
+		Closing:       g.Closing,
+		batch: &gravBatch{
+			trees: make(map[string]*graviton.Tree),
+			dirty: make(map[string]bool),
+		},
+	}
+
+	err = fn(bg)
+	if err != nil {
+		return
+	}
+
+	var ctrees []*graviton.Tree
+	for treename := range bg.batch.dirty {
+		ctrees = append(ctrees, bg.batch.trees[treename])
+	}
+	if len(ctrees) > 0 {
+		_, err = graviton.Commit(ctrees...)
+		if err != nil {
+			logger.Errorf("[Graviton-Batch] ERROR: %v", err)
+		}
+	}
+
+	return
+}
+
+// Runs fn against the most recent snapshot. Each read within fn is of that snapshot, whatever is committed meanwhile, and writes return ErrReadOnlyView
+func (g *GravitonStore) View(fn func(s Storage) error) (err error) {
+	// Already within a view or batch, reads already see a consistent state
+	if g.view != nil || g.batch != nil {
+		return fn(g)
+	}
+
+	ss, err := g.DB.LoadSnapshot(0) // load most recent snapshot
+	if err != nil {
+		return
+	}
+
+	return fn(&GravitonStore{
+		DB:            g.DB,
+		DBPath:        g.DBPath,
+		DBTrees:       g.DBTrees,
+		DBMaxSnapshot: g.DBMaxSnapshot,
+		DBMigrateWait: g.DBMigrateWait,
+		Closing:       g.Closing,
+		view:          ss,
+	})
+}
+
+// Runs fn on each key/value of a given tree
+func (g *GravitonStore) WalkTree(treename string, fn func(k []byte, v []byte) error) (err error) {
+	ss, err := g.DB.LoadSnapshot(0) // load most recent snapshot
+	if err != nil {
+		return
+	}
+
+	tree, err := g.getTree(ss, treename)
+	if err != nil {
+		return
+	}
+
+	c := tree.Cursor()
+	for k, v, cerr := c.First(); cerr == nil; k, v, cerr = c.Next() {
+		if err = fn(k, v); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// Gets a tree from the given snapshot, or the view's snapshot within a view. Within a batch the same tree is handed back on each call so that later reads/writes see the uncommitted changes
+func (g *GravitonStore) getTree(ss *graviton.Snapshot, treename string) (tree *graviton.Tree, err error) {
+	if g.view != nil {
+		return g.view.GetTree(treename)
+	}
+
+	if g.batch == nil {
+		return ss.GetTree(treename)
+	}
+
+	if tree, ok := g.batch.trees[treename]; ok {
+		return tree, nil
+	}
+	tree, err = ss.GetTree(treename)
+	if tree != nil {
+		g.batch.trees[treename] = tree
+	}
+
+	return
+}
+
+// Commits the given trees, unless within a view. Within a batch the trees are only marked to be committed once the batch is done
+func (g *GravitonStore) commitTrees(trees ...*graviton.Tree) (cv uint64, err error) {
+	if g.view != nil {
+		return cv, ErrReadOnlyView
+	}
+
+	if g.batch == nil {
+		return graviton.Commit(trees...)
+	}
+
+	for _, tree := range trees {
+		if _, ok := g.batch.trees[tree.GetName()]; !ok {
+			g.batch.trees[tree.GetName()] = tree
+		}
+		g.batch.dirty[tree.GetName()] = true
+	}
+
+	return
+}
+
 // ---- End Application Graviton/Backend functions ---- //
+
+// Returns whether the store is closing or closed
+func (g *GravitonStore) IsClosing() bool {
+	return g.Closing
+}
+
+// Closes out the db cleanly
+func (g *GravitonStore) Close() (err error) {
+	g.Closing = true
+	g.DB.Close()
+
+	return
+}
@@ -0,0 +1,574 @@
+package storage
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/civilware/Gnomon/structures"
+)
+
+// Snapshot archives are a gzip'd stream of json records, one per line. The first is the header and the last is the checksum (sha256 of all of the lines before it). Records are read back through the Storage interface, so an archive exported from one backend can be imported into either.
+const (
+	SnapshotMagic   = "gnomon-snapshot"
+	SnapshotVersion = 2 // miniblock details carry their topoheight
+)
+
+// Number of recent block hashes kept in an archive, enough to detect reorgs past the height it continues from (indexer max_reorg_depth)
+const snapshot_blockhashes = int64(100)
+
+// Tx count types kept in the stats
+var snapshot_txcounts = []string{"registration", "burn", "normal"}
+
+type SnapshotHeader struct {
+	Magic   string
+	Version int
+	Height  int64 // last indexed height, indexing continues from here on import
+	Created int64
+}
+
+type snapshotRecord struct {
+	Type string
+	Key  string `json:",omitempty"`
+	Data json.RawMessage
+}
+
+type snapshotChecksum struct {
+	Records int64
+	Sha256  string
+}
+
+type snapshotStats struct {
+	EarliestHeight int64
+	TxCounts       map[string]int64
+}
+
+// Writes the whole index of s to an archive at path. The index is read from one view of it (see Storage.View) so that the archive is of a single height. On bbolt that is one read tx for the whole export, which writes made meanwhile would stall against, so the indexer should be stopped or its writes paused (Writer.Pause) while exporting. The archive is written to path.tmp and moved to path once complete
+func ExportSnapshot(s Storage, path string) (header *SnapshotHeader, err error) {
+	err = s.View(func(s Storage) (err error) {
+		header, err = exportSnapshot(s, path)
+		return
+	})
+
+	return
+}
+
+func exportSnapshot(s Storage, path string) (header *SnapshotHeader, err error) {
+	height, err := s.GetLastIndexHeight()
+	if err != nil {
+		return
+	}
+	if height <= 1 {
+		return nil, fmt.Errorf("nothing indexed to export")
+	}
+
+	tmppath := path + ".tmp"
+	f, err := os.Create(tmppath)
+	if err != nil {
+		return
+	}
+	defer func() {
+		f.Close()
+		if err != nil {
+			os.Remove(tmppath)
+		}
+	}()
+
+	gz := gzip.NewWriter(f)
+	sw := &snapshotWriter{w: bufio.NewWriter(gz), h: sha256.New()}
+
+	header = &SnapshotHeader{Magic: SnapshotMagic, Version: SnapshotVersion, Height: height, Created: time.Now().Unix()}
+	if err = sw.write("header", "", header); err != nil {
+		return
+	}
+
+	// Stats
+	stats := &snapshotStats{EarliestHeight: s.GetEarliestHeight(), TxCounts: make(map[string]int64)}
+	for _, txType := range snapshot_txcounts {
+		stats.TxCounts[txType] = s.GetTxCount(txType)
+	}
+	if err = sw.write("stats", "", stats); err != nil {
+		return
+	}
+
+	// SCs, the owners list is the list of indexed scids
+	owners := s.GetAllOwnersAndSCIDs()
+	var scids []string
+	for scid := range owners {
+		scids = append(scids, scid)
+	}
+	sort.Strings(scids)
+
+	for _, scid := range scids {
+		if err = sw.write("owner", scid, owners[scid]); err != nil {
+			return
+		}
+		if template := s.GetSCIDTemplate(scid); template != "" {
+			if err = sw.write("template", scid, template); err != nil {
+				return
+			}
+		}
+		if err = sw.write("invokes", scid, s.GetAllSCIDInvokeDetails(scid)); err != nil {
+			return
+		}
+		if err = sw.write("argindex", scid, s.GetSCIDIndexedArgs(scid)); err != nil {
+			return
+		}
+		if err = sw.write("variables", scid, s.GetSCIDVariableHistory(scid)); err != nil {
+			return
+		}
+		if err = sw.write("interactions", scid, s.GetSCIDInteractionHeight(scid)); err != nil {
+			return
+		}
+		if err = sw.write("balances", scid, s.GetAllSCIDBalanceDetails(scid)); err != nil {
+			return
+		}
+		if err = sw.write("code", scid, s.GetAllSCIDCode(scid)); err != nil {
+			return
+		}
+		if asset := s.GetSCAsset(scid); asset != nil {
+			if err = sw.write("asset", scid, asset); err != nil {
+				return
+			}
+		}
+	}
+
+	if err = sw.write("invalidscids", "", s.GetInvalidSCIDDeploys()); err != nil {
+		return
+	}
+
+	// Txs and names are streamed from their trees an entry at a time, stored values are already in the form of their records
+	err = s.WalkTree("normaltxwithscid", func(k, v []byte) error {
+		return sw.write("normaltxs", string(k), json.RawMessage(v))
+	})
+	if err != nil {
+		return
+	}
+	err = s.WalkTree("addressactivity", func(k, v []byte) error {
+		return sw.write("addressactivity", string(k), json.RawMessage(v))
+	})
+	if err != nil {
+		return
+	}
+	err = s.WalkTree("burntxs", func(k, v []byte) error {
+		return sw.write("burntxs", string(k), json.RawMessage(v))
+	})
+	if err != nil {
+		return
+	}
+	err = s.WalkTree("registrations", func(k, v []byte) error {
+		return sw.write("regtxs", string(k), []json.RawMessage{v})
+	})
+	if err != nil {
+		return
+	}
+	// Each name's history is in height order, so its current record ends up being its latest on import
+	err = s.WalkTree("namehistory", func(k, v []byte) error {
+		return sw.write("names", string(k), json.RawMessage(v))
+	})
+	if err != nil {
+		return
+	}
+
+	// Miniblocks and the counts of their miners
+	miners := make(map[string]bool)
+	err = s.WalkTree("miniblocks", func(k, v []byte) error {
+		entry := decodeMiniblockEntry(v)
+		for _, mbl := range entry.Miniblocks {
+			miners[mbl.Miner] = true
+		}
+		return sw.write("miniblocks", string(k), entry)
+	})
+	if err != nil {
+		return
+	}
+	for miner := range miners {
+		if err = sw.write("minerblocks", miner, s.GetMiniblockCountByAddress(miner)); err != nil {
+			return
+		}
+	}
+
+	// Block hashes
+	blockhashes := make(map[int64]string)
+	for h := height - snapshot_blockhashes; h <= height; h++ {
+		if blhash := s.GetBlockHash(h); blhash != "" {
+			blockhashes[h] = blhash
+		}
+	}
+	if err = sw.write("blockhashes", "", blockhashes); err != nil {
+		return
+	}
+
+	if err = sw.close(); err != nil {
+		return
+	}
+	if err = gz.Close(); err != nil {
+		return
+	}
+	if err = f.Close(); err != nil {
+		return
+	}
+
+	err = os.Rename(tmppath, path)
+
+	return
+}
+
+// Loads the archive at path into s, which should be a new (empty) index. The archive is read through once, its records are stored within one batch that is only committed if they match its checksum
+func ImportSnapshot(s Storage, path string) (header *SnapshotHeader, err error) {
+	if height, _ := s.GetLastIndexHeight(); height > 1 {
+		return nil, fmt.Errorf("index already has data up to height %v, snapshots can only be imported into a new index", height)
+	}
+
+	err = s.Batch(func(s Storage) (err error) {
+		header, err = readSnapshot(path, func(record *snapshotRecord) error {
+			if err := importSnapshotRecord(s, record); err != nil {
+				return fmt.Errorf("importing %v record '%v': %v", record.Type, record.Key, err)
+			}
+			return nil
+		})
+		if err != nil {
+			return
+		}
+
+		// Variables are archived as the changes at each height, so are stored in the current format
+		if _, err = s.StoreVariableFormat(SCIDVariableFormat); err != nil {
+			return
+		}
+
+		_, err = s.StoreLastIndexHeight(header.Height)
+
+		return
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return
+}
+
+// Reads through the archive at path and checks it against its checksum, returning its header
+func VerifySnapshot(path string) (header *SnapshotHeader, err error) {
+	return readSnapshot(path, nil)
+}
+
+// Reads through the archive at path, running fn (if set) on each record after the header. Each line is added to the checksum as it is read, so the records fn is run on are the ones checked. Returns an error if they do not match the checksum, in which case anything fn did should be discarded
+func readSnapshot(path string, fn func(record *snapshotRecord) error) (header *SnapshotHeader, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("not a snapshot archive: %v", err)
+	}
+	defer gz.Close()
+
+	h := sha256.New()
+	r := bufio.NewReader(gz)
+	var records int64
+	for {
+		line, rerr := r.ReadBytes('\n')
+		if rerr != nil {
+			if rerr == io.EOF {
+				return nil, fmt.Errorf("snapshot is truncated, no checksum found")
+			}
+			return nil, rerr
+		}
+
+		record := &snapshotRecord{}
+		if err = json.Unmarshal(line, record); err != nil {
+			return nil, fmt.Errorf("snapshot record %v is malformed: %v", records, err)
+		}
+
+		if records == 0 {
+			if record.Type != "header" {
+				return nil, fmt.Errorf("snapshot has no header")
+			}
+			if err = json.Unmarshal(record.Data, &header); err != nil {
+				return nil, fmt.Errorf("snapshot header is malformed: %v", err)
+			}
+			if header.Magic != SnapshotMagic {
+				return nil, fmt.Errorf("not a snapshot archive")
+			}
+			if header.Version < 1 || header.Version > SnapshotVersion {
+				return nil, fmt.Errorf("snapshot version %v is not supported, expected version %v or below", header.Version, SnapshotVersion)
+			}
+		} else if record.Type == "checksum" {
+			var checksum snapshotChecksum
+			if err = json.Unmarshal(record.Data, &checksum); err != nil {
+				return nil, fmt.Errorf("snapshot checksum is malformed: %v", err)
+			}
+			if checksum.Records != records || checksum.Sha256 != hex.EncodeToString(h.Sum(nil)) {
+				return nil, fmt.Errorf("snapshot checksum does not match, the archive is corrupt")
+			}
+
+			return
+		} else if fn != nil {
+			if err = fn(record); err != nil {
+				return nil, err
+			}
+		}
+
+		h.Write(line)
+		records++
+	}
+}
+
+func importSnapshotRecord(s Storage, record *snapshotRecord) (err error) {
+	scid := record.Key
+
+	switch record.Type {
+	case "stats":
+		var stats snapshotStats
+		if err = json.Unmarshal(record.Data, &stats); err != nil {
+			return
+		}
+		if stats.EarliestHeight > 0 {
+			if _, err = s.StoreEarliestHeight(stats.EarliestHeight); err != nil {
+				return
+			}
+		}
+		for txType, count := range stats.TxCounts {
+			if _, err = s.StoreTxCount(count, txType); err != nil {
+				return
+			}
+		}
+	case "owner":
+		var owner string
+		if err = json.Unmarshal(record.Data, &owner); err != nil {
+			return
+		}
+		_, err = s.StoreOwner(scid, owner)
+	case "template":
+		var template string
+		if err = json.Unmarshal(record.Data, &template); err != nil {
+			return
+		}
+		_, err = s.StoreSCIDTemplate(scid, template)
+	case "invokes":
+		var invokes []*structures.SCTXParse
+		if err = json.Unmarshal(record.Data, &invokes); err != nil {
+			return
+		}
+		for _, invoke := range invokes {
+			if _, err = s.StoreInvokeDetails(scid, invoke.Sender, invoke.Entrypoint, invoke.Height, invoke); err != nil {
+				return
+			}
+		}
+	case "argindex":
+		// Invokes of the scid are imported before its arg index, so the index is rebuilt from them
+		var names []string
+		if err = json.Unmarshal(record.Data, &names); err != nil {
+			return
+		}
+		if len(names) == 0 {
+			return
+		}
+		invokes := s.GetAllSCIDInvokeDetails(scid)
+		for _, name := range names {
+			if _, err = s.StoreSCIDArgIndex(scid, name, invokes); err != nil {
+				return
+			}
+		}
+	case "variables":
+		var history map[int64][]*structures.SCIDVariable
+		if err = json.Unmarshal(record.Data, &history); err != nil {
+			return
+		}
+		if len(history) > 0 {
+			_, err = s.StoreSCIDVariableHistory(scid, history)
+		}
+	case "interactions":
+		var heights []int64
+		if err = json.Unmarshal(record.Data, &heights); err != nil {
+			return
+		}
+		for _, h := range heights {
+			if _, err = s.StoreSCIDInteractionHeight(scid, h); err != nil {
+				return
+			}
+		}
+	case "balances":
+		var balances []*structures.SCIDBalances
+		if err = json.Unmarshal(record.Data, &balances); err != nil {
+			return
+		}
+		for _, b := range balances {
+			if _, err = s.StoreSCIDBalanceDetails(scid, b.Balances, b.Height); err != nil {
+				return
+			}
+		}
+	case "code":
+		var codes []*structures.SCIDCode
+		if err = json.Unmarshal(record.Data, &codes); err != nil {
+			return
+		}
+		for _, code := range codes {
+			if _, err = s.StoreSCIDCode(scid, code); err != nil {
+				return
+			}
+		}
+	case "asset":
+		var asset structures.SCAsset
+		if err = json.Unmarshal(record.Data, &asset); err != nil {
+			return
+		}
+		_, err = s.StoreSCAsset(&asset)
+	case "invalidscids":
+		var invalid map[string]uint64
+		if err = json.Unmarshal(record.Data, &invalid); err != nil {
+			return
+		}
+		// Deploy heights are only kept for rolling back reorgs, so are not carried in the archive
+		for k, fee := range invalid {
+			if _, err = s.StoreInvalidSCIDDeploys(k, fee, 0); err != nil {
+				return
+			}
+		}
+	case "normaltxs":
+		var normTxsWithSCID []*structures.NormalTXWithSCIDParse
+		if err = json.Unmarshal(record.Data, &normTxsWithSCID); err != nil {
+			return
+		}
+		for _, normTxWithSCID := range normTxsWithSCID {
+			if _, err = s.StoreNormalTxWithSCIDByAddr(record.Key, normTxWithSCID); err != nil {
+				return
+			}
+		}
+	case "addressactivity":
+		var activities []*structures.AddressActivity
+		if err = json.Unmarshal(record.Data, &activities); err != nil {
+			return
+		}
+		for _, activity := range activities {
+			if _, err = s.StoreAddressActivity(record.Key, activity); err != nil {
+				return
+			}
+		}
+	case "burntxs":
+		var burnTxs []*structures.BurnTXParse
+		if err = json.Unmarshal(record.Data, &burnTxs); err != nil {
+			return
+		}
+		for _, burntx := range burnTxs {
+			if _, err = s.StoreBurnTx(burntx); err != nil {
+				return
+			}
+		}
+	case "regtxs":
+		var regTxs []*structures.RegTXParse
+		if err = json.Unmarshal(record.Data, &regTxs); err != nil {
+			return
+		}
+		for _, regtx := range regTxs {
+			if _, err = s.StoreRegTx(regtx); err != nil {
+				return
+			}
+		}
+	case "names":
+		// History is in height order, so each name's current record ends up being its latest
+		var records []*structures.NameRecord
+		if err = json.Unmarshal(record.Data, &records); err != nil {
+			return
+		}
+		for _, r := range records {
+			if _, err = s.StoreNameRecord(r); err != nil {
+				return
+			}
+		}
+	case "miniblocks":
+		// Details are archived along with their topoheight. Version 1 archives (and details stored before heights were kept) have none, so are stored without one and get pruned once below the first stored height
+		entry := &miniblockEntry{}
+		if len(record.Data) > 0 && record.Data[0] == '[' {
+			err = json.Unmarshal(record.Data, &entry.Miniblocks)
+		} else {
+			err = json.Unmarshal(record.Data, entry)
+		}
+		if err != nil {
+			return
+		}
+		_, err = s.StoreMiniblockDetailsByHash(record.Key, entry.Height, entry.Miniblocks)
+	case "minerblocks":
+		// Counts are only ever incremented, so are brought up to the archived count from what storing the imported miniblocks added
+		var count int64
+		if err = json.Unmarshal(record.Data, &count); err != nil {
+			return
+		}
+		for c := s.GetMiniblockCountByAddress(record.Key); c < count; c++ {
+			if _, err = s.StoreMiniblockCountByAddress(record.Key); err != nil {
+				return
+			}
+		}
+	case "blockhashes":
+		var blockhashes map[int64]string
+		if err = json.Unmarshal(record.Data, &blockhashes); err != nil {
+			return
+		}
+		if len(blockhashes) > 0 {
+			_, err = s.StoreBlockHashes(blockhashes)
+		}
+	default:
+		logger.Printf("[ImportSnapshot] Skipping unknown record type '%v'", record.Type)
+	}
+
+	return
+}
+
+// Writes snapshot records, keeping the running checksum of the lines written
+type snapshotWriter struct {
+	w       *bufio.Writer
+	h       hash.Hash
+	records int64
+}
+
+func (sw *snapshotWriter) write(rtype string, key string, data interface{}) (err error) {
+	line, err := marshalSnapshotRecord(rtype, key, data)
+	if err != nil {
+		return
+	}
+
+	sw.h.Write(line)
+	sw.records++
+	_, err = sw.w.Write(line)
+
+	return
+}
+
+// Writes the checksum record and flushes
+func (sw *snapshotWriter) close() (err error) {
+	line, err := marshalSnapshotRecord("checksum", "", &snapshotChecksum{Records: sw.records, Sha256: hex.EncodeToString(sw.h.Sum(nil))})
+	if err != nil {
+		return
+	}
+	if _, err = sw.w.Write(line); err != nil {
+		return
+	}
+
+	return sw.w.Flush()
+}
+
+// Returns the record as a line of the archive
+func marshalSnapshotRecord(rtype string, key string, data interface{}) (line []byte, err error) {
+	confBytes, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal %v record '%v': %v", rtype, key, err)
+	}
+
+	line, err = json.Marshal(&snapshotRecord{Type: rtype, Key: key, Data: confBytes})
+	if err != nil {
+		return
+	}
+
+	return append(line, '\n'), nil
+}
@@ -0,0 +1,222 @@
+package storage
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/civilware/Gnomon/structures"
+)
+
+const (
+	snapshot_test_scid   = "a0a1a2a3a4a5a6a7a8a9b0b1b2b3b4b5b6b7b8b9c0c1c2c3c4c5c6c7c8c9d0d1"
+	snapshot_test_signer = "dero1qyw4fl3dupcg5qlrcsvcedze507q9u67lxfpu8kgnzp04aq73yheqqg2ctjn4"
+	snapshot_test_txid   = "f0f1f2f3f4f5f6f7f8f9e0e1e2e3e4e5e6e7e8e9d0d1d2d3d4d5d6d7d8d9c0c1"
+	snapshot_test_blid   = "b0b1b2b3b4b5b6b7b8b9c0c1c2c3c4c5c6c7c8c9d0d1d2d3d4d5d6d7d8d9e0e1"
+)
+
+// Stores a small index in s, indexed up to height 50
+func testSnapshotIndex(t *testing.T, s Storage) {
+	t.Helper()
+
+	err := s.Batch(func(s Storage) (err error) {
+		if _, err = s.StoreOwner(snapshot_test_scid, snapshot_test_signer); err != nil {
+			return
+		}
+		if _, err = s.StoreSCIDVariableDetails(snapshot_test_scid, []*structures.SCIDVariable{{Key: "owner", Value: snapshot_test_signer}, {Key: "supply", Value: uint64(100)}}, 10); err != nil {
+			return
+		}
+		if _, err = s.StoreSCIDVariableDetails(snapshot_test_scid, []*structures.SCIDVariable{{Key: "owner", Value: snapshot_test_signer}, {Key: "supply", Value: uint64(90)}}, 20); err != nil {
+			return
+		}
+		for _, h := range []int64{10, 20} {
+			if _, err = s.StoreSCIDInteractionHeight(snapshot_test_scid, h); err != nil {
+				return
+			}
+		}
+		invoke := &structures.SCTXParse{Txid: snapshot_test_txid, Scid: snapshot_test_scid, Entrypoint: "Withdraw", Method: "scinvoke", Sender: snapshot_test_signer, Height: 20}
+		if _, err = s.StoreInvokeDetails(snapshot_test_scid, snapshot_test_signer, "Withdraw", 20, invoke); err != nil {
+			return
+		}
+		if _, err = s.StoreTxCount(7, "normal"); err != nil {
+			return
+		}
+		if _, err = s.StoreMiniblockDetailsByHash(snapshot_test_blid, 40, []*structures.MBLInfo{{Hash: "mbl1", Miner: snapshot_test_signer}}); err != nil {
+			return
+		}
+		if _, err = s.StoreBlockHashes(map[int64]string{49: "hash49", 50: "hash50"}); err != nil {
+			return
+		}
+		_, err = s.StoreLastIndexHeight(50)
+		return
+	})
+	if err != nil {
+		t.Fatalf("storing index: %v", err)
+	}
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	src, dst := testStores(t), testStores(t)
+
+	for from, to := range map[string]string{"gravdb": "boltdb", "boltdb": "gravdb"} {
+		t.Run(from+"->"+to, func(t *testing.T) {
+			testSnapshotIndex(t, src[from])
+
+			path := filepath.Join(t.TempDir(), "gnomon.snapshot")
+			header, err := ExportSnapshot(src[from], path)
+			if err != nil {
+				t.Fatalf("ExportSnapshot() err = %v", err)
+			}
+			if header.Height != 50 {
+				t.Errorf("exported height = %v, want 50", header.Height)
+			}
+
+			s := dst[to]
+			if _, err = ImportSnapshot(s, path); err != nil {
+				t.Fatalf("ImportSnapshot() err = %v", err)
+			}
+
+			if got, _ := s.GetLastIndexHeight(); got != 50 {
+				t.Errorf("GetLastIndexHeight() = %v, want 50", got)
+			}
+			if got := s.GetOwner(snapshot_test_scid); got != snapshot_test_signer {
+				t.Errorf("GetOwner() = %q, want %q", got, snapshot_test_signer)
+			}
+			if got := newVarState(s.GetSCIDVariableDetailsAtTopoheight(snapshot_test_scid, 15)); !reflect.DeepEqual(got, testVarState("owner", snapshot_test_signer, "supply", uint64(100))) {
+				t.Errorf("variables at 15 = %v", got)
+			}
+			if got := newVarState(s.GetSCIDVariableDetailsAtTopoheight(snapshot_test_scid, 20)); !reflect.DeepEqual(got, testVarState("owner", snapshot_test_signer, "supply", uint64(90))) {
+				t.Errorf("variables at 20 = %v", got)
+			}
+			if got := s.GetSCIDInteractionHeight(snapshot_test_scid); !reflect.DeepEqual(got, []int64{10, 20}) {
+				t.Errorf("GetSCIDInteractionHeight() = %v, want [10 20]", got)
+			}
+			if got := s.GetAllSCIDInvokeDetails(snapshot_test_scid); len(got) != 1 || got[0].Txid != snapshot_test_txid {
+				t.Errorf("GetAllSCIDInvokeDetails() = %v, want the one invoke", got)
+			}
+			if got := s.GetTxCount("normal"); got != 7 {
+				t.Errorf("GetTxCount() = %v, want 7", got)
+			}
+			if got := s.GetMiniblockCountByAddress(snapshot_test_signer); got != 1 {
+				t.Errorf("GetMiniblockCountByAddress() = %v, want 1", got)
+			}
+			if got := s.GetBlockHash(50); got != "hash50" {
+				t.Errorf("GetBlockHash() = %q, want hash50", got)
+			}
+
+			// Miniblock details keep their height, so are pruned by it
+			var heights []int64
+			err = s.WalkTree("miniblocks", func(k, v []byte) error {
+				heights = append(heights, decodeMiniblockEntry(v).Height)
+				return nil
+			})
+			if err != nil || !reflect.DeepEqual(heights, []int64{40}) {
+				t.Errorf("miniblock heights = %v (%v), want [40]", heights, err)
+			}
+		})
+	}
+}
+
+func TestSnapshotCorrupt(t *testing.T) {
+	src, err := NewMemStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+	testSnapshotIndex(t, src)
+
+	path := filepath.Join(t.TempDir(), "gnomon.snapshot")
+	if _, err = ExportSnapshot(src, path); err != nil {
+		t.Fatalf("ExportSnapshot() err = %v", err)
+	}
+	lines := readTestSnapshot(t, path)
+
+	tests := []struct {
+		name    string
+		rewrite func(lines []string) []string
+		wantErr string
+	}{
+		{name: "changed record", rewrite: func(lines []string) []string {
+			for i, l := range lines {
+				lines[i] = strings.Replace(l, "hash50", "hash51", 1)
+			}
+			return lines
+		}, wantErr: "checksum does not match"},
+		{name: "dropped record", rewrite: func(lines []string) []string {
+			return append(lines[:2:2], lines[3:]...)
+		}, wantErr: "checksum does not match"},
+		{name: "truncated", rewrite: func(lines []string) []string {
+			return lines[:len(lines)-1]
+		}, wantErr: "truncated"},
+		{name: "newer version", rewrite: func(lines []string) []string {
+			lines[0] = strings.Replace(lines[0], `"Version":2`, `"Version":3`, 1)
+			return lines
+		}, wantErr: "not supported"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tampered := filepath.Join(t.TempDir(), "tampered.snapshot")
+			writeTestSnapshot(t, tampered, tt.rewrite(append([]string(nil), lines...)))
+
+			if _, err := VerifySnapshot(tampered); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("VerifySnapshot() err = %v, want %q", err, tt.wantErr)
+			}
+
+			// Nothing of a corrupt archive is kept
+			for name, s := range testStores(t) {
+				if _, err := ImportSnapshot(s, tampered); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("%v ImportSnapshot() err = %v, want %q", name, err, tt.wantErr)
+				}
+				if got, _ := s.GetLastIndexHeight(); got > 1 {
+					t.Errorf("%v GetLastIndexHeight() = %v after a failed import", name, got)
+				}
+				if got := s.GetOwner(snapshot_test_scid); got != "" {
+					t.Errorf("%v GetOwner() = %q after a failed import", name, got)
+				}
+			}
+		})
+	}
+}
+
+func readTestSnapshot(t *testing.T, path string) (lines []string) {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return strings.SplitAfter(strings.TrimSuffix(string(b), "\n"), "\n")
+}
+
+func writeTestSnapshot(t *testing.T, path string, lines []string) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	for _, l := range lines {
+		if !strings.HasSuffix(l, "\n") {
+			l += "\n"
+		}
+		gz.Write([]byte(l))
+	}
+	gz.Close()
+	if err := os.WriteFile(path, buf.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
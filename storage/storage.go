@@ -0,0 +1,163 @@
+package storage
+
+import (
+	"github.com/civilware/Gnomon/structures"
+)
+
+// Storage is the common set of functions each db backend (gravdb, boltdb, etc.) implements. The indexer, api and cli only reference a backend through this, so new backends can be dropped in without touching every call site.
+type Storage interface {
+	// Stats
+	StoreLastIndexHeight(last_indexedheight int64) (changes bool, err error)
+	GetLastIndexHeight() (topoheight int64, err error)
+	StoreEarliestHeight(topoheight int64) (changes bool, err error)
+	GetEarliestHeight() (topoheight int64)
+	StoreVariableFormat(format int64) (changes bool, err error)
+	GetVariableFormat() (format int64)
+	StoreTemplateSet(set string) (changes bool, err error)
+	GetTemplateSet() (set string)
+	StoreTxCount(count int64, txType string) (changes bool, err error)
+	GetTxCount(txType string) int64
+	StoreTxCountsByHeight(height int64, counts map[string]int64) (changes bool, err error)
+	StoreGetInfoDetails(getinfo *structures.GetInfo) (changes bool, err error)
+	GetGetInfoDetails() (getinfo *structures.GetInfo)
+
+	// SC owners
+	StoreOwner(scid string, owner string) (changes bool, err error)
+	GetOwner(scid string) string
+	GetAllOwnersAndSCIDs() map[string]string
+
+	// SC template matches
+	StoreSCIDTemplate(scid string, templateID string) (changes bool, err error)
+	GetSCIDTemplate(scid string) (templateID string)
+	GetSCIDsByTemplate(templateID string) (scids []string)
+
+	// Normal txs with scid payloads
+	StoreNormalTxWithSCIDByAddr(addr string, normTxWithSCID *structures.NormalTXWithSCIDParse) (changes bool, err error)
+	GetAllNormalTxWithSCIDByAddr(addr string) (normTxsWithSCID []*structures.NormalTXWithSCIDParse)
+	GetAllNormalTxWithSCIDBySCID(scid string) (normTxsWithSCID []*structures.NormalTXWithSCIDParse)
+	GetAllNormalTxWithSCID() map[string][]*structures.NormalTXWithSCIDParse
+	GetSCIDInteractionByAddr(addr string) (scids []string)
+
+	// Address activity
+	StoreAddressActivity(addr string, activity *structures.AddressActivity) (changes bool, err error)
+	GetAddressActivity(addr string, start int, limit int) (activity []*structures.AddressActivity, total int)
+	GetAllAddressActivity() map[string][]*structures.AddressActivity
+
+	// Burn txs
+	StoreBurnTx(burntx *structures.BurnTXParse) (changes bool, err error)
+	GetBurnTxByTxid(txid string) (burnTxs []*structures.BurnTXParse)
+	GetBurnTxsBySCID(scid string) (burnTxs []*structures.BurnTXParse)
+	GetBurnTxsByHeightRange(minHeight int64, maxHeight int64) (burnTxs []*structures.BurnTXParse)
+	IndexBurnTxs() (indexed int, err error)
+
+	// Registration txs
+	StoreRegTx(regtx *structures.RegTXParse) (changes bool, err error)
+	GetRegTxByAddress(addr string) (regtx *structures.RegTXParse)
+	GetRegTxsByHeightRange(minHeight int64, maxHeight int64) (regTxs []*structures.RegTXParse)
+
+	// SC invokes
+	StoreInvokeDetails(scid string, signer string, entrypoint string, topoheight int64, invokedetails *structures.SCTXParse) (changes bool, err error)
+	GetAllSCIDInvokeDetails(scid string) (invokedetails []*structures.SCTXParse)
+	GetAllSCIDInvokeDetailsByEntrypoint(scid string, entrypoint string) (invokedetails []*structures.SCTXParse)
+	GetAllSCIDInvokeDetailsBySigner(scid string, signerPart string) (invokedetails []*structures.SCTXParse)
+	GetAllSCIDInvokeDetailsByArg(scid string, name string, value string) (invokedetails []*structures.SCTXParse)
+
+	// SC invoke arg secondary index
+	StoreSCIDArgIndex(scid string, name string, invokedetails []*structures.SCTXParse) (changes bool, err error)
+	GetSCIDIndexedArgs(scid string) (names []string)
+
+	// SC variables and interaction heights
+	StoreSCIDVariableDetails(scid string, variables []*structures.SCIDVariable, topoheight int64) (changes bool, err error)
+	GetSCIDVariableDetailsAtTopoheight(scid string, topoheight int64) (hVars []*structures.SCIDVariable)
+	GetAllSCIDVariableDetails(scid string) (hVars []*structures.SCIDVariable)
+	GetSCIDVariableHistory(scid string) map[int64][]*structures.SCIDVariable
+	StoreSCIDVariableHistory(scid string, history map[int64][]*structures.SCIDVariable) (changes bool, err error)
+	MigrateSCIDVariableDetails(scid string) (changes bool, err error)
+	GetSCIDKeysByValue(scid string, val interface{}, height int64, rmax bool) (keysstring []string, keysuint64 []uint64)
+	GetSCIDValuesByKey(scid string, key interface{}, height int64, rmax bool) (valuesstring []string, valuesuint64 []uint64)
+	StoreSCIDInteractionHeight(scid string, height int64) (changes bool, err error)
+	GetSCIDInteractionHeight(scid string) (scidinteractions []int64)
+	GetInteractionIndex(topoheight int64, heights []int64, rmax bool) (height int64)
+
+	// SC balances
+	StoreSCIDBalanceDetails(scid string, balances map[string]uint64, topoheight int64) (changes bool, err error)
+	GetSCIDBalanceDetailsAtTopoheight(scid string, topoheight int64) (balances map[string]uint64)
+	GetAllSCIDBalanceDetails(scid string) (hBalances []*structures.SCIDBalances)
+
+	// SC code versions
+	StoreSCIDCode(scid string, code *structures.SCIDCode) (changes bool, err error)
+	GetSCIDCodeAtTopoheight(scid string, topoheight int64) (code *structures.SCIDCode)
+	GetAllSCIDCode(scid string) (hCode []*structures.SCIDCode)
+
+	// Name service
+	StoreNameRecord(record *structures.NameRecord) (changes bool, err error)
+	GetNameRecord(name string) (record *structures.NameRecord)
+	GetNamesByAddress(address string) (records []*structures.NameRecord)
+	GetNameHistory(name string) (records []*structures.NameRecord)
+	GetNameHistoryByHeightRange(minHeight int64, maxHeight int64) (records []*structures.NameRecord)
+	IndexNameAddresses() (indexed int, err error)
+
+	// Asset registry
+	StoreSCAsset(asset *structures.SCAsset) (changes bool, err error)
+	GetSCAsset(scid string) (asset *structures.SCAsset)
+	GetSCAssetsByCollection(collection string) (assets []*structures.SCAsset)
+	GetSCAssetsByOwner(owner string) (assets []*structures.SCAsset)
+	GetSCAssetsByStandard(standard string) (assets []*structures.SCAsset)
+
+	// Invalid SC deploys
+	StoreInvalidSCIDDeploys(scid string, fee uint64, height int64) (changes bool, err error)
+	GetInvalidSCIDDeploys() map[string]uint64
+
+	// Miniblocks
+	StoreMiniblockDetailsByHash(blid string, topoheight int64, mbldetails []*structures.MBLInfo) (changes bool, err error)
+	GetAllMiniblockDetails() map[string][]*structures.MBLInfo
+	GetMiniblockDetailsByHash(blid string) (miniblocks []*structures.MBLInfo)
+	StoreMiniblockCountByAddress(addr string) (changes bool, err error)
+	GetMiniblockCountByAddress(addr string) (miniblocks int64)
+
+	// Block hashes and chain reorgs
+	StoreBlockHashes(blockhashes map[int64]string) (changes bool, err error)
+	GetBlockHash(topoheight int64) (hash string)
+	RollbackToHeight(topoheight int64, height int64) (rolledbackSCIDs []string, err error)
+
+	// Retention, removes history below a height while keeping the latest state
+	PruneSCIDVariableDetails(scid string, belowHeight int64) (pruned int, err error)
+	PruneSCIDInvokeDetails(scid string, belowHeight int64) (pruned int, err error)
+	PruneMiniblockDetails(belowHeight int64, limit int) (pruned int, err error)
+
+	// Rescans, replaces a scid's history with one rebuilt from the chain
+	ReplaceSCIDHistory(scid string, history *SCIDHistory) (err error)
+
+	// Writes to the backend data that was staged within a RAM gravdb store
+	StoreAltDBInput(treenames []string, altdb *GravitonStore) (err error)
+
+	// Runs fn against the backend with all of its writes committed together. Used by Writer
+	Batch(fn func(s Storage) error) (err error)
+
+	// Runs fn against a read-only view of the backend as of the call, which writes made meanwhile do not change. Used by ExportSnapshot
+	View(fn func(s Storage) error) (err error)
+
+	// Runs fn on each key/value of a tree (bucket) as stored, stopping at the first error fn returns
+	WalkTree(treename string, fn func(k []byte, v []byte) error) (err error)
+
+	// Lifecycle
+	IsClosing() bool
+	Close() (err error)
+}
+
+var _ Storage = (*GravitonStore)(nil)
+var _ Storage = (*BboltStore)(nil)
+
+// Returns a new, empty Storage held in memory (a RAM gravdb), for tests and throwaway indexes. Its data is gone once closed
+func NewMemStore() (Storage, error) {
+	return NewGravDBRAM("25ms")
+}
+
+// A scid's history as rebuilt from the chain, swapped in for the stored one by ReplaceSCIDHistory
+type SCIDHistory struct {
+	Invokes   []*structures.SCTXParse
+	Variables map[int64][]*structures.SCIDVariable // variables changed at each height, as returned by GetSCIDVariableHistory
+	Heights   []int64                              // interaction heights, in order
+	Balances  []*structures.SCIDBalances
+	Code      []*structures.SCIDCode
+}
@@ -0,0 +1,181 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/civilware/Gnomon/structures"
+)
+
+// Returns an empty store of each backend, closed at the end of the test
+func testStores(t *testing.T) map[string]Storage {
+	t.Helper()
+
+	mem, err := NewMemStore()
+	if err != nil {
+		t.Fatalf("NewMemStore: %v", err)
+	}
+	bbs, err := NewBBoltDB(t.TempDir(), "gnomon_test.db")
+	if err != nil {
+		t.Fatalf("NewBBoltDB: %v", err)
+	}
+
+	stores := map[string]Storage{"gravdb": mem, "boltdb": bbs}
+	t.Cleanup(func() {
+		for _, s := range stores {
+			s.Close()
+		}
+	})
+
+	return stores
+}
+
+func TestBatch(t *testing.T) {
+	errAbort := errors.New("abort")
+
+	tests := []struct {
+		name    string
+		err     error
+		owner   string
+		indexed int64
+	}{
+		{name: "commit", owner: "owner", indexed: 10},
+		{name: "abort", err: errAbort},
+	}
+
+	for name, s := range testStores(t) {
+		for _, tt := range tests {
+			t.Run(name+"/"+tt.name, func(t *testing.T) {
+				scid := "scid-" + tt.name
+				err := s.Batch(func(s Storage) error {
+					if _, err := s.StoreOwner(scid, "owner"); err != nil {
+						return err
+					}
+					if _, err := s.StoreLastIndexHeight(10); err != nil {
+						return err
+					}
+					return tt.err
+				})
+				if err != tt.err {
+					t.Fatalf("Batch() err = %v, want %v", err, tt.err)
+				}
+
+				if got := s.GetOwner(scid); got != tt.owner {
+					t.Errorf("GetOwner() = %q, want %q", got, tt.owner)
+				}
+				if tt.err == nil {
+					if got, _ := s.GetLastIndexHeight(); got != tt.indexed {
+						t.Errorf("GetLastIndexHeight() = %v, want %v", got, tt.indexed)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestViewReadOnly(t *testing.T) {
+	for name, s := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			err := s.View(func(s Storage) error {
+				_, err := s.StoreOwner("scid", "owner")
+				return err
+			})
+			if err == nil {
+				t.Fatalf("View() allowed a write")
+			}
+			if got := s.GetOwner("scid"); got != "" {
+				t.Errorf("GetOwner() = %q after a write within a view", got)
+			}
+		})
+	}
+}
+
+func TestRollbackAsset(t *testing.T) {
+	const scid = "a0a1a2a3a4a5a6a7a8a9b0b1b2b3b4b5b6b7b8b9c0c1c2c3c4c5c6c7c8c9d0d1"
+
+	tests := []struct {
+		name   string
+		height int64
+		want   string // owner as of height, "" if dropped
+	}{
+		{name: "above all", height: 40, want: "owner30"},
+		{name: "between", height: 25, want: "owner20"},
+		{name: "at update", height: 10, want: "owner10"},
+		{name: "below all", height: 5, want: ""},
+	}
+
+	for _, tt := range tests {
+		for name, s := range testStores(t) {
+			t.Run(name+"/"+tt.name, func(t *testing.T) {
+				for _, h := range []int64{10, 20, 30} {
+					if _, err := s.StoreSCAsset(&structures.SCAsset{Scid: scid, Standard: "G45", Owner: fmt.Sprintf("owner%d", h), Height: h}); err != nil {
+						t.Fatal(err)
+					}
+				}
+
+				if _, err := s.RollbackToHeight(tt.height, tt.height); err != nil {
+					t.Fatalf("RollbackToHeight() err = %v", err)
+				}
+
+				asset := s.GetSCAsset(scid)
+				if tt.want == "" {
+					if asset != nil {
+						t.Errorf("GetSCAsset() = %+v, want nil", asset)
+					}
+					return
+				}
+				if asset == nil || asset.Owner != tt.want {
+					t.Fatalf("GetSCAsset() = %+v, want owner %v", asset, tt.want)
+				}
+
+				// Rolled back again, it goes back to the version before
+				if tt.height > 10 {
+					if _, err := s.RollbackToHeight(tt.height-10, tt.height-10); err != nil {
+						t.Fatal(err)
+					}
+					if asset = s.GetSCAsset(scid); asset == nil || asset.Height > tt.height-10 {
+						t.Errorf("GetSCAsset() after a second rollback = %+v", asset)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestMergeAssetHistory(t *testing.T) {
+	asset := func(h int64) *structures.SCAsset {
+		return &structures.SCAsset{Scid: "scid", Height: h}
+	}
+
+	tests := []struct {
+		name    string
+		history []int64
+		height  int64
+		want    []int64
+	}{
+		{name: "first", height: 10, want: []int64{10}},
+		{name: "append", history: []int64{10, 20}, height: 30, want: []int64{10, 20, 30}},
+		{name: "same height", history: []int64{10, 20}, height: 20, want: []int64{10, 20}},
+		{name: "out of order", history: []int64{10, 20, 30}, height: 15, want: []int64{10, 15}},
+		{name: "trimmed", history: []int64{10, 20, 30}, height: 20 + block_hashes_kept, want: []int64{20, 30, 20 + block_hashes_kept}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var history []*structures.SCAsset
+			for _, h := range tt.history {
+				history = append(history, asset(h))
+			}
+
+			var got []int64
+			for _, v := range mergeAssetHistory(history, asset(tt.height)) {
+				got = append(got, v.Height)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergeAssetHistory() heights = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
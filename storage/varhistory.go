@@ -0,0 +1,265 @@
+package storage
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/civilware/Gnomon/structures"
+)
+
+// SC variable history is stored per interaction height as either a keyframe (the full variable set at that height) or the variables changed since the previous stored height, deleted variables having a nil Value. Databases written before this kept an array of variables per height, which are read as changes and converted by MigrateSCIDVariableDetails.
+const SCIDVariableFormat = int64(2)
+
+// A full keyframe is stored every var_keyframe_interval stored heights of a scid, so rebuilding the variables at a height reads at most this many entries
+const var_keyframe_interval = 100
+
+type varHistoryEntry struct {
+	Keyframe  bool
+	Variables []*structures.SCIDVariable
+}
+
+// Variables by key, keys and values being held as string or uint64
+type varState map[interface{}]interface{}
+
+// Returns k (a SC variable key or value) as a string or uint64. Numbers come back from json as float64
+func normalizeSCIDVar(k interface{}) (interface{}, bool) {
+	switch ck := k.(type) {
+	case float64:
+		return uint64(ck), true
+	case uint64:
+		return ck, true
+	case string:
+		return ck, true
+	}
+
+	return nil, false
+}
+
+// Applies the changes (or keyframe) of an entry to state
+func (state varState) apply(entry *varHistoryEntry) {
+	if entry.Keyframe {
+		for k := range state {
+			delete(state, k)
+		}
+	}
+
+	for _, v := range entry.Variables {
+		key, ok := normalizeSCIDVar(v.Key)
+		if !ok {
+			if v.Key != nil {
+				logger.Errorf("[varState] Key '%v' does not match string, uint64 or float64.", v.Key)
+			}
+			continue
+		}
+
+		if v.Value == nil {
+			delete(state, key)
+			continue
+		}
+
+		value, ok := normalizeSCIDVar(v.Value)
+		if !ok {
+			logger.Errorf("[varState] Value '%v' does not match string, uint64 or float64.", v.Value)
+			continue
+		}
+		state[key] = value
+	}
+}
+
+func (state varState) variables() (hVars []*structures.SCIDVariable) {
+	for k, v := range state {
+		hVars = append(hVars, &structures.SCIDVariable{Key: k, Value: v})
+	}
+
+	return
+}
+
+// Returns the variables of curr that differ from prev, and the variables of prev not in curr with a nil Value
+func (state varState) diff(curr varState) (diffset []*structures.SCIDVariable) {
+	for k, v := range curr {
+		if pv, ok := state[k]; !ok || pv != v {
+			diffset = append(diffset, &structures.SCIDVariable{Key: k, Value: v})
+		}
+	}
+	for k := range state {
+		if _, ok := curr[k]; !ok {
+			diffset = append(diffset, &structures.SCIDVariable{Key: k, Value: nil})
+		}
+	}
+
+	return
+}
+
+func newVarState(variables []*structures.SCIDVariable) varState {
+	state := make(varState)
+	state.apply(&varHistoryEntry{Variables: variables})
+
+	return state
+}
+
+// Decodes a stored entry. Legacy entries (an array of variables) are returned as changes
+func decodeVarEntry(v []byte) (entry *varHistoryEntry) {
+	entry = &varHistoryEntry{}
+	if len(v) > 0 && v[0] == '[' {
+		_ = json.Unmarshal(v, &entry.Variables)
+		return
+	}
+
+	_ = json.Unmarshal(v, entry)
+
+	return
+}
+
+// Returns whether a stored entry is a keyframe, without decoding its variables
+func isVarKeyframe(v []byte) bool {
+	if len(v) == 0 || v[0] == '[' {
+		return false
+	}
+
+	var entry struct{ Keyframe bool }
+	_ = json.Unmarshal(v, &entry)
+
+	return entry.Keyframe
+}
+
+// Returns the heights of the stored entries in order
+func varEntryHeights(entries map[int64][]byte) (heights []int64) {
+	for h := range entries {
+		heights = append(heights, h)
+	}
+	sort.Slice(heights, func(i, j int) bool {
+		return heights[i] < heights[j]
+	})
+
+	return
+}
+
+// Rebuilds the variables at topoheight from the nearest keyframe at or below it plus the changes stored since. Also returns the number of entries read from the keyframe on and whether there were any entries at or below topoheight
+func buildVarState(entries map[int64][]byte, topoheight int64) (state varState, sinceKeyframe int, found bool) {
+	state = make(varState)
+
+	heights := varEntryHeights(entries)
+	end := sort.Search(len(heights), func(i int) bool {
+		return heights[i] > topoheight
+	})
+	if end == 0 {
+		return
+	}
+
+	start := 0
+	for i := end - 1; i >= 0; i-- {
+		if isVarKeyframe(entries[heights[i]]) {
+			start = i
+			break
+		}
+	}
+
+	for _, h := range heights[start:end] {
+		entry := decodeVarEntry(entries[h])
+		state.apply(entry)
+	}
+
+	return state, end - start, true
+}
+
+// Returns the entry to store for the full set of variables at topoheight, against the entries already stored. Returns nil if the variables are unchanged from those stored below topoheight and there is no entry at topoheight to replace
+func encodeVarEntry(entries map[int64][]byte, variables []*structures.SCIDVariable, topoheight int64) (confBytes []byte, err error) {
+	prev, sinceKeyframe, found := buildVarState(entries, topoheight-1)
+	curr := newVarState(variables)
+
+	diffset := prev.diff(curr)
+
+	// Within the same height (e.g. multiple invokes of a scid within a block) the entry is always replaced
+	if _, exists := entries[topoheight]; !exists && len(diffset) == 0 {
+		return nil, nil
+	}
+
+	entry := &varHistoryEntry{Variables: diffset}
+	if !found || sinceKeyframe >= var_keyframe_interval {
+		entry = &varHistoryEntry{Keyframe: true, Variables: curr.variables()}
+	}
+
+	return json.Marshal(entry)
+}
+
+// Returns the variables changed at each stored height, converting keyframes to the changes from the height before
+func decodeVarHistory(entries map[int64][]byte) (history map[int64][]*structures.SCIDVariable) {
+	history = make(map[int64][]*structures.SCIDVariable)
+
+	state := make(varState)
+	for _, h := range varEntryHeights(entries) {
+		entry := decodeVarEntry(entries[h])
+		if !entry.Keyframe {
+			history[h] = entry.Variables
+			state.apply(entry)
+			continue
+		}
+
+		next := newVarState(entry.Variables)
+		history[h] = state.diff(next)
+		state = next
+	}
+
+	return
+}
+
+// Returns the entries to store for the variables changed at each height, with a keyframe every var_keyframe_interval heights
+func encodeVarHistory(history map[int64][]*structures.SCIDVariable) (entries map[int64][]byte, err error) {
+	entries = make(map[int64][]byte)
+
+	var heights []int64
+	for h := range history {
+		heights = append(heights, h)
+	}
+	sort.Slice(heights, func(i, j int) bool {
+		return heights[i] < heights[j]
+	})
+
+	state := make(varState)
+	for i, h := range heights {
+		entry := &varHistoryEntry{Variables: history[h]}
+		state.apply(entry)
+		if i%var_keyframe_interval == 0 {
+			entry = &varHistoryEntry{Keyframe: true, Variables: state.variables()}
+		}
+
+		entries[h], err = json.Marshal(entry)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// Returns whether any of the stored entries are in the legacy format
+func hasLegacyVarEntries(entries map[int64][]byte) bool {
+	for _, v := range entries {
+		if len(v) > 0 && v[0] == '[' {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns the keyframe of the variables as of belowHeight and the height to store it at (the most recent stored height below belowHeight), entries below which can then be removed. keyframe is nil if there is at most one entry below belowHeight, so nothing to remove
+func pruneVarEntries(entries map[int64][]byte, belowHeight int64) (keep int64, keyframe []byte, err error) {
+	var below int
+	for h := range entries {
+		if h < belowHeight {
+			below++
+			if h > keep {
+				keep = h
+			}
+		}
+	}
+	if below <= 1 {
+		return
+	}
+
+	state, _, _ := buildVarState(entries, keep)
+	keyframe, err = json.Marshal(&varHistoryEntry{Keyframe: true, Variables: state.variables()})
+
+	return
+}
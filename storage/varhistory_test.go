@@ -0,0 +1,183 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/civilware/Gnomon/structures"
+)
+
+func testVarState(kv ...interface{}) varState {
+	state := make(varState)
+	for i := 0; i < len(kv); i += 2 {
+		state[kv[i]] = kv[i+1]
+	}
+
+	return state
+}
+
+func testVarEntry(t *testing.T, keyframe bool, kv ...interface{}) []byte {
+	t.Helper()
+
+	entry := &varHistoryEntry{Keyframe: keyframe}
+	for i := 0; i < len(kv); i += 2 {
+		entry.Variables = append(entry.Variables, &structures.SCIDVariable{Key: kv[i], Value: kv[i+1]})
+	}
+	v, err := json.Marshal(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return v
+}
+
+func TestDecodeVarEntry(t *testing.T) {
+	tests := []struct {
+		name     string
+		entry    []byte
+		keyframe bool
+		want     varState
+	}{
+		{name: "keyframe", entry: testVarEntry(t, true, "owner", "abc", "supply", uint64(10)), keyframe: true, want: testVarState("owner", "abc", "supply", uint64(10))},
+		{name: "changes", entry: testVarEntry(t, false, "supply", uint64(5)), want: testVarState("supply", uint64(5))},
+		{name: "legacy", entry: []byte(`[{"Key":"owner","Value":"abc"},{"Key":1,"Value":2}]`), want: testVarState("owner", "abc", uint64(1), uint64(2))},
+		{name: "blank", entry: nil, want: testVarState()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := decodeVarEntry(tt.entry)
+			if entry.Keyframe != tt.keyframe || isVarKeyframe(tt.entry) != tt.keyframe {
+				t.Errorf("keyframe = %v/%v, want %v", entry.Keyframe, isVarKeyframe(tt.entry), tt.keyframe)
+			}
+			state := make(varState)
+			state.apply(entry)
+			if !reflect.DeepEqual(state, tt.want) {
+				t.Errorf("decoded %v, want %v", state, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeVarEntry(t *testing.T) {
+	entries := map[int64][]byte{
+		10: testVarEntry(t, true, "owner", "abc", "supply", uint64(10)),
+		20: testVarEntry(t, false, "supply", uint64(5)),
+	}
+
+	tests := []struct {
+		name       string
+		topoheight int64
+		variables  []*structures.SCIDVariable
+		nochange   bool
+		keyframe   bool
+		want       varState // changes stored
+	}{
+		{name: "unchanged", topoheight: 30, variables: []*structures.SCIDVariable{{Key: "owner", Value: "abc"}, {Key: "supply", Value: uint64(5)}}, nochange: true},
+		{name: "changed", topoheight: 30, variables: []*structures.SCIDVariable{{Key: "owner", Value: "abc"}, {Key: "supply", Value: uint64(1)}}, want: testVarState("supply", uint64(1))},
+		{name: "deleted", topoheight: 30, variables: []*structures.SCIDVariable{{Key: "supply", Value: uint64(5)}}, want: testVarState()},
+		{name: "replaced at same height", topoheight: 20, variables: []*structures.SCIDVariable{{Key: "owner", Value: "abc"}, {Key: "supply", Value: uint64(10)}}, want: testVarState()},
+		{name: "first", topoheight: 5, variables: []*structures.SCIDVariable{{Key: "owner", Value: "abc"}}, keyframe: true, want: testVarState("owner", "abc")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := encodeVarEntry(entries, tt.variables, tt.topoheight)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.nochange {
+				if v != nil {
+					t.Errorf("encodeVarEntry() = %s, want nil", v)
+				}
+				return
+			}
+
+			entry := decodeVarEntry(v)
+			if entry.Keyframe != tt.keyframe {
+				t.Errorf("keyframe = %v, want %v", entry.Keyframe, tt.keyframe)
+			}
+
+			// Storing the entry gives back the variables at its height
+			stored := make(map[int64][]byte)
+			for h, e := range entries {
+				stored[h] = e
+			}
+			stored[tt.topoheight] = v
+			state, _, _ := buildVarState(stored, tt.topoheight)
+			if !reflect.DeepEqual(state, newVarState(tt.variables)) {
+				t.Errorf("buildVarState() = %v, want %v", state, newVarState(tt.variables))
+			}
+
+			if tt.name == "deleted" {
+				if len(entry.Variables) != 1 || entry.Variables[0].Key != "owner" || entry.Variables[0].Value != nil {
+					t.Errorf("deleted variable not stored as nil, got %v", entry.Variables)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildVarStateKeyframes(t *testing.T) {
+	// Supply counts down from 1000 a height at a time, with another variable added every 50 heights
+	history := make(map[int64][]*structures.SCIDVariable)
+	for h := int64(1); h <= 250; h++ {
+		vars := []*structures.SCIDVariable{{Key: "supply", Value: uint64(1000 - h)}}
+		if h%50 == 0 {
+			vars = append(vars, &structures.SCIDVariable{Key: fmt.Sprintf("mark%d", h), Value: uint64(h)})
+		}
+		history[h*10] = vars
+	}
+	// Legacy entries below any keyframe are read as changes
+	history[5] = []*structures.SCIDVariable{{Key: "legacy", Value: "yes"}}
+
+	entries, err := encodeVarHistory(history)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries[5] = []byte(`[{"Key":"legacy","Value":"yes"}]`)
+
+	// The first entry was a keyframe before being swapped for the legacy one, which leaves those at 1000 and 2000
+	for h, v := range entries {
+		if keyframe := h == 1000 || h == 2000; isVarKeyframe(v) != keyframe {
+			t.Errorf("entry at %d keyframe = %v, want %v", h, isVarKeyframe(v), keyframe)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		topoheight int64
+		want       varState
+		found      bool
+	}{
+		{name: "below all", topoheight: 4, want: testVarState()},
+		{name: "legacy", topoheight: 9, want: testVarState("legacy", "yes"), found: true},
+		{name: "before keyframe", topoheight: 995, want: testVarState("legacy", "yes", "supply", uint64(901), "mark50", uint64(50)), found: true},
+		{name: "at keyframe", topoheight: 1000, want: testVarState("legacy", "yes", "supply", uint64(900), "mark50", uint64(50), "mark100", uint64(100)), found: true},
+		{name: "past keyframe", topoheight: 1015, want: testVarState("legacy", "yes", "supply", uint64(899), "mark50", uint64(50), "mark100", uint64(100)), found: true},
+		{name: "past second keyframe", topoheight: 2500, want: testVarState("legacy", "yes", "supply", uint64(750), "mark50", uint64(50), "mark100", uint64(100), "mark150", uint64(150), "mark200", uint64(200), "mark250", uint64(250)), found: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state, sinceKeyframe, found := buildVarState(entries, tt.topoheight)
+			if found != tt.found {
+				t.Errorf("found = %v, want %v", found, tt.found)
+			}
+			if sinceKeyframe > var_keyframe_interval {
+				t.Errorf("read %d entries, want at most %d", sinceKeyframe, var_keyframe_interval)
+			}
+			if !reflect.DeepEqual(state, tt.want) {
+				t.Errorf("buildVarState() = %v, want %v", state, tt.want)
+			}
+		})
+	}
+
+	// Keyframes decode back to the changes at their height
+	decoded := decodeVarHistory(entries)
+	if got := newVarState(decoded[1000]); !reflect.DeepEqual(got, testVarState("supply", uint64(900), "mark100", uint64(100))) {
+		t.Errorf("decodeVarHistory() at keyframe = %v, want the changes at 1000", got)
+	}
+}
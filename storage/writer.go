@@ -0,0 +1,160 @@
+package storage
+
+import (
+	"errors"
+	"sync"
+)
+
+// Writer is the single owner of writes to a Storage backend. Batches of writes are handed to it over a channel and each batch is committed within one transaction (bbolt) or one commit (gravdb) by the writer goroutine.
+type Writer struct {
+	Backend  Storage
+	requests chan *writeRequest
+	done     chan struct{}
+	closed   bool
+	sync.RWMutex
+}
+
+type writeRequest struct {
+	fn     func(s Storage) error
+	pause  func() error // run in place of a batch, see Pause
+	result chan error
+}
+
+// Returned to callers trying to write after the writer has been closed
+var ErrWriterClosed = errors.New("storage writer is closed")
+
+// Returned by writes made within Storage.View
+var ErrReadOnlyView = errors.New("storage view is read-only")
+
+// Defines the number of write batches that can be queued before callers block
+const writer_queue_size = 128
+
+// Builds a new writer for the given backend and starts its write loop
+func NewWriter(backend Storage) *Writer {
+	w := &Writer{
+		Backend:  backend,
+		requests: make(chan *writeRequest, writer_queue_size),
+		done:     make(chan struct{}),
+	}
+
+	go w.run()
+
+	return w
+}
+
+// Write loop - the only place writes to the backend happen. Each request is run and committed as one batch, and the result is passed back to the caller
+func (w *Writer) run() {
+	for req := range w.requests {
+		if req.pause != nil {
+			req.result <- req.pause()
+			continue
+		}
+		req.result <- w.Backend.Batch(req.fn)
+	}
+	close(w.done)
+}
+
+// Queues fn to be run as one batch and returns a channel that receives the commit result once it is done
+func (w *Writer) Submit(fn func(s Storage) error) <-chan error {
+	result := make(chan error, 1)
+
+	w.RLock()
+	defer w.RUnlock()
+	if w.closed {
+		result <- ErrWriterClosed
+		return result
+	}
+	w.requests <- &writeRequest{fn: fn, result: result}
+
+	return result
+}
+
+// Queues fn to be run as one batch and waits for it to be committed
+func (w *Writer) Write(fn func(s Storage) error) (err error) {
+	return <-w.Submit(fn)
+}
+
+// Waits for the queued writes to be committed and runs fn with no writes in progress, writes queued meanwhile being committed once it returns. Used for long reads (e.g. ExportSnapshot) that would otherwise hold a bbolt read tx open against the writes
+func (w *Writer) Pause(fn func() error) (err error) {
+	result := make(chan error, 1)
+
+	w.RLock()
+	if w.closed {
+		w.RUnlock()
+		return ErrWriterClosed
+	}
+	w.requests <- &writeRequest{pause: fn, result: result}
+	w.RUnlock()
+
+	return <-result
+}
+
+// Stops accepting new writes and waits for the queued writes to be committed
+func (w *Writer) Close() {
+	w.Lock()
+	if w.closed {
+		w.Unlock()
+		return
+	}
+	w.closed = true
+	close(w.requests)
+	w.Unlock()
+
+	<-w.done
+}
+
+// WriteBatch stages writes (e.g. all of the writes for a block or parallel batch of blocks) so that they can be committed together through a Writer
+type WriteBatch struct {
+	fns   []func(s Storage) error
+	hooks []func()
+	sync.Mutex
+}
+
+// Stages fn to be run when the batch is committed. Safe to call from multiple go routines
+func (wb *WriteBatch) Add(fn func(s Storage) error) {
+	wb.Lock()
+	wb.fns = append(wb.fns, fn)
+	wb.Unlock()
+}
+
+// Returns the number of staged writes
+func (wb *WriteBatch) Len() int {
+	wb.Lock()
+	defer wb.Unlock()
+
+	return len(wb.fns)
+}
+
+// Runs all of the staged writes in the order they were added, stopping on the first err. Passed to Writer.Write() to commit the batch
+func (wb *WriteBatch) Run(s Storage) (err error) {
+	wb.Lock()
+	defer wb.Unlock()
+
+	for _, fn := range wb.fns {
+		err = fn(s)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// Stages fn to be run once the batch has been committed (e.g. notifying of what was written). Hooks run in the order they were added
+func (wb *WriteBatch) OnCommit(fn func()) {
+	wb.Lock()
+	wb.hooks = append(wb.hooks, fn)
+	wb.Unlock()
+}
+
+// Runs the OnCommit hooks. To be called once Writer.Write(wb.Run) has returned without err
+func (wb *WriteBatch) Committed() {
+	wb.Lock()
+	hooks := wb.hooks
+	wb.hooks = nil
+	wb.Unlock()
+
+	for _, fn := range hooks {
+		fn()
+	}
+}
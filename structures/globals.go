@@ -18,6 +18,9 @@ const MAX_API_VAR_RETURN = 1024
 // Major.Minor.Patch-Iteration
 var Version = semver.MustParse("2.0.0-alpha.1")
 
+// Name service SCID of DERO Network
+const NAMESERVICE_SCID = "0000000000000000000000000000000000000000000000000000000000000001"
+
 // Hardcoded Smart Contracts of DERO Network
 // TODO: Possibly in future we can pull this from derohe codebase
-var Hardcoded_SCIDS = []string{"0000000000000000000000000000000000000000000000000000000000000001"}
+var Hardcoded_SCIDS = []string{NAMESERVICE_SCID}
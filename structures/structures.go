@@ -19,12 +19,63 @@ type SCTXParse struct {
 	Payloads   []transaction.AssetPayload
 	Fees       uint64
 	Height     int64
+	Args       map[string]interface{} // Sc_args by name, decoded by DecodeSCArgs
+}
+
+// Decodes invoke args to their values by name: S and H (hex) args as strings, U args as uint64. The SC_ACTION, SC_ID, SC_CODE and entrypoint args are left out as they are already held by Method, Scid and Entrypoint
+func DecodeSCArgs(args rpc.Arguments) (decoded map[string]interface{}) {
+	decoded = make(map[string]interface{})
+	for _, arg := range args {
+		switch arg.Name {
+		case rpc.SCACTION, rpc.SCID, rpc.SCCODE, "entrypoint":
+			continue
+		}
+
+		switch arg.DataType {
+		case rpc.DataString:
+			if v, ok := arg.Value.(string); ok {
+				decoded[arg.Name] = v
+			}
+		case rpc.DataUint64:
+			if v, ok := arg.Value.(uint64); ok {
+				decoded[arg.Name] = v
+			}
+		case rpc.DataHash:
+			if v, ok := arg.Value.(crypto.Hash); ok {
+				decoded[arg.Name] = v.String()
+			}
+		}
+	}
+
+	return
+}
+
+// Args are always decoded from Sc_args when read back, so invokes stored before Args was kept have them and uint64 args do not come back as float64
+func (sctx *SCTXParse) UnmarshalJSON(data []byte) (err error) {
+	type sctxparse SCTXParse
+	var v sctxparse
+	if err = json.Unmarshal(data, &v); err != nil {
+		return
+	}
+
+	*sctx = SCTXParse(v)
+	sctx.Args = DecodeSCArgs(sctx.Sc_args)
+
+	return
 }
 
 type BurnTXParse struct {
-	Txid string
-	Scid string
-	Fees string
+	Txid   string
+	Scid   string
+	Fees   uint64 // fees of the txn, only kept on its first payload so that they are counted once
+	Burn   uint64
+	Height int64
+}
+
+type RegTXParse struct {
+	Txid    string
+	Address string
+	Height  int64
 }
 
 type NormalTXWithSCIDParse struct {
@@ -54,6 +105,7 @@ type APIConfig struct {
 	KeyFile              string `json:"keyFile"`
 	GetInfoKeyFile       string `json:"getInfoKeyFile"`
 	MBLLookup            bool   `json:"mbblookup"`
+	RegTxLookup          bool   `json:"regtxlookup"`
 	ApiThrottle          bool   `json:"apithrottle"`
 }
 
@@ -62,6 +114,77 @@ type SCIDVariable struct {
 	Value interface{}
 }
 
+type SCIDBalances struct {
+	Height   int64
+	Balances map[string]uint64
+}
+
+// A name service registration or transfer. Action is one of "register", "transfer" or "snapshot" (known from the name service variables rather than an indexed invoke, Txid is then blank)
+type NameRecord struct {
+	Name    string
+	Address string
+	Action  string
+	Txid    string
+	Height  int64
+}
+
+// An address' interaction with a SC. Role is one of "installer" (signer of the installsc), "invoker" (signer of an invoke) or "ringmember" (ring member of a normal tx with a scid payload)
+type AddressActivity struct {
+	Scid   string
+	Txid   string
+	Height int64
+	Role   string
+}
+
+// Metadata of a SC recognized as a token/NFA standard (e.g. G45, artificer NFAs), as of Height
+type SCAsset struct {
+	Scid       string
+	Standard   string
+	Name       string
+	Symbol     string
+	Supply     uint64
+	Owner      string
+	Collection string
+	FileURL    string
+	Height     int64
+}
+
+// A version of a SC's code, Txid being the txn that installed or updated (UPDATE_SC_CODE) it
+type SCIDCode struct {
+	Height int64
+	Txid   string
+	Code   string
+}
+
+// Number of blocks below the last indexed height to keep history of, 0 keeps all of it
+type RetentionPolicy struct {
+	Variables  int64 // SC variables, the variables as of the window start are kept
+	Invokes    int64 // SC invokes other than the install
+	Miniblocks int64 // miniblock details, miniblock counts by address are kept
+}
+
+// Result of a dry-run of an SC entrypoint against indexed state (Indexer.InterpretSC)
+type SCSimulation struct {
+	Scid       string
+	Entrypoint string
+	Height     int64
+	Signer     string
+	Return     interface{} // uint64 or string return value of the entrypoint
+	Committed  bool        // Whether the chain would keep the writes and transfers, i.e. the entrypoint ran without error and returned 0
+	Writes     []*SCIDVariable
+	Deletes    []interface{}
+	Transfers  []*SCSimulationTransfer
+	GasCompute uint64
+	GasStorage uint64
+	Error      string
+}
+
+type SCSimulationTransfer struct {
+	Address string
+	Asset   string
+	Amount  uint64
+}
+
 type FastSyncImport struct {
 	Owner   string
 	Height  uint64